@@ -8,15 +8,17 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"path"
 	"strings"
 	"syscall"
 	"time"
 
-	"github.com/golang/glog"
 	"github.com/nginxinc/kubernetes-ingress/internal/configs"
 	"github.com/nginxinc/kubernetes-ingress/internal/configs/version1"
 	"github.com/nginxinc/kubernetes-ingress/internal/configs/version2"
+	"github.com/nginxinc/kubernetes-ingress/internal/healthcheck"
 	"github.com/nginxinc/kubernetes-ingress/internal/k8s"
+	"github.com/nginxinc/kubernetes-ingress/internal/logging"
 	"github.com/nginxinc/kubernetes-ingress/internal/metrics"
 	"github.com/nginxinc/kubernetes-ingress/internal/metrics/collectors"
 	"github.com/nginxinc/kubernetes-ingress/internal/nginx"
@@ -27,6 +29,7 @@ import (
 	api_v1 "k8s.io/api/core/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
@@ -47,6 +50,13 @@ var (
 		`Use a proxy server to connect to Kubernetes API started by "kubectl proxy" command. For testing purposes only.
 	The Ingress controller does not start NGINX and does not write any generated NGINX configuration files to disk`)
 
+	kubeconfig = flag.String("kubeconfig", "",
+		`Path to a kubeconfig file to connect to Kubernetes API. Allows running the Ingress controller outside of the cluster.
+	By default the Ingress controller uses the in-cluster configuration of the pod`)
+
+	kubeContext = flag.String("context", "",
+		`The name of the kubeconfig context to use. By default the current context of the kubeconfig file is used. Requires -kubeconfig`)
+
 	watchNamespace = flag.String("watch-namespace", api_v1.NamespaceAll,
 		`Namespace to watch for Ingress resources. By default the Ingress controller watches all namespaces`)
 
@@ -68,9 +78,9 @@ var (
 
 	defaultServerSecret = flag.String("default-server-tls-secret", "",
 		`A Secret with a TLS certificate and key for TLS termination of the default server. Format: <namespace>/<name>.
-	If not set, certificate and key in the file "/etc/nginx/secrets/default" are used. If a secret is set,
+	If not set, certificate and key in the file "default" in the -nginx-secrets-path directory are used. If a secret is set,
 	but the Ingress controller is not able to fetch it from Kubernetes API or a secret is not set and
-	the file "/etc/nginx/secrets/default" does not exist, the Ingress controller will fail to start`)
+	the file "default" does not exist, the Ingress controller will fail to start`)
 
 	versionFlag = flag.Bool("version", false, "Print the version and git-commit hash and exit")
 
@@ -96,7 +106,11 @@ The external address of the service is used when reporting the status of Ingress
 		"Enable Leader election to avoid multiple replicas of the controller reporting the status of Ingress resources -- only one replica will report status. See -report-ingress-status flag.")
 
 	leaderElectionLockName = flag.String("leader-election-lock-name", "nginx-ingress-leader-election",
-		`Specifies the name of the ConfigMap, within the same namespace as the controller, used as the lock for leader election. Requires -enable-leader-election.`)
+		`Specifies the name of the ConfigMap and/or the Lease, within the same namespace as the controller, used as the lock for leader election. Requires -enable-leader-election.`)
+
+	leaderElectionLockType = flag.String("leader-election-lock-type", k8s.ConfigMapsLeasesLockType,
+		`Specifies the type of the lock for leader election: 'configmaps', 'leases' or 'configmapsleases'.
+	The 'configmapsleases' type uses both a ConfigMap and a Lease. Use it to migrate from the 'configmaps' to the 'leases' type. Requires -enable-leader-election.`)
 
 	nginxStatusAllowCIDRs = flag.String("nginx-status-allow-cidrs", "127.0.0.1", `Whitelist IPv4 IP/CIDR blocks to allow access to NGINX stub_status or the NGINX Plus API. Separate multiple IP/CIDR by commas.`)
 
@@ -106,14 +120,43 @@ The external address of the service is used when reporting the status of Ingress
 	nginxStatus = flag.Bool("nginx-status", true,
 		"Enable the NGINX stub_status, or the NGINX Plus API.")
 
+	nginxConfPath = flag.String("nginx-conf-path", "/etc/nginx",
+		`Path to the directory with the NGINX configuration files. The Ingress controller writes the main configuration file "nginx.conf"
+	and the configuration files of the resources in the "conf.d" subdirectory. The directory must contain the "mime.types" file`)
+
+	nginxLibPath = flag.String("nginx-lib-path", "/var/lib/nginx",
+		`Path to the directory for the runtime files of NGINX, such as the pid file and the unix sockets`)
+
+	nginxLogPath = flag.String("nginx-log-path", "/var/log/nginx",
+		`Path to the directory where NGINX writes the error log, the access log and the stream access log`)
+
+	nginxCachePath = flag.String("nginx-cache-path", "/var/cache/nginx",
+		`Path to the directory where NGINX stores the caches of the cache zones declared in the proxy-cache-zones ConfigMap key`)
+
+	nginxSecretsPath = flag.String("nginx-secrets-path", "",
+		`Path to the directory where the Ingress controller writes the TLS certificates and keys and other secrets. (default "<nginx-conf-path>/secrets")`)
+
 	nginxDebug = flag.Bool("nginx-debug", false,
 		"Enable debugging for NGINX. Uses the nginx-debug binary. Requires 'error-log-level: debug' in the ConfigMap.")
 
 	wildcardTLSSecret = flag.String("wildcard-tls-secret", "",
-		`A Secret with a TLS certificate and key for TLS termination of every Ingress host for which TLS termination is enabled but the Secret is not specified.
+		`A Secret with a TLS certificate and key for TLS termination of every Ingress host and VirtualServer for which TLS termination is enabled but the Secret is not specified.
 		Format: <namespace>/<name>. If the argument is not set, for such Ingress hosts NGINX will break any attempt to establish a TLS connection.
 		If the argument is set, but the Ingress controller is not able to fetch the Secret from Kubernetes API, the Ingress controller will fail to start.`)
 
+	enableDefaultServerTLSFallback = flag.Bool("enable-default-server-tls-fallback", false,
+		`Use the TLS certificate and key of the default server for TLS termination of a VirtualServer, if its TLS secret is missing or invalid,
+	or if its TLS secret is not specified and the -wildcard-tls-secret argument is not set. By default, NGINX will break any attempt to establish a TLS connection to such VirtualServers.
+	A Warning event is emitted for such VirtualServers.`)
+
+	enableTLSPassthrough = flag.Bool("enable-tls-passthrough", false,
+		`Enable TLS Passthrough on port 443. NGINX routes the TLS connections by the server name (SNI) either to the HTTPS servers of the Ingress and VirtualServer resources
+	or, for the VirtualServers with TLS Passthrough configured, directly to the endpoints of a service, which terminate TLS themselves. Requires -enable-custom-resources`)
+
+	tlsCertificateExpiryWarningPeriod = flag.Duration("tls-certificate-expiry-warning-period", 30*24*time.Hour,
+		`Emit a Warning event for every Ingress and VirtualServer resource that references a TLS Secret with a certificate that expires within this period.
+	The certificates are checked every hour. Set to 0 to disable the events`)
+
 	enablePrometheusMetrics = flag.Bool("enable-prometheus-metrics", false,
 		"Enable exposing NGINX or NGINX Plus metrics in the Prometheus format")
 
@@ -122,14 +165,62 @@ The external address of the service is used when reporting the status of Ingress
 
 	enableCustomResources = flag.Bool("enable-custom-resources", false,
 		"Enable custom resources")
+
+	enableCertManager = flag.Bool("enable-cert-manager", false,
+		`Enable the integration with cert-manager. The Ingress controller creates and owns a cert-manager Certificate for every TLS secret of the Ingress resources
+	with the "cert-manager.io/issuer" or "cert-manager.io/cluster-issuer" annotation and of the VirtualServers with tls.certManager. Requires cert-manager to be installed in the cluster`)
+
+	controllerStatus = flag.Bool("controller-status", false,
+		`Enable the readiness '/readyz' and the liveness '/healthz' endpoints of the Ingress controller.
+	The readiness endpoint reports success once the Ingress controller has synced its caches and applied the configuration for all resources, and reports a failure once the controller starts shutting down.
+	The liveness endpoint reports a failure if the Ingress controller is stuck processing a resource or the NGINX master process is not running.`)
+
+	controllerStatusPort = flag.Int("controller-status-port", 8081,
+		"Set the port where the readiness and the liveness endpoints of the Ingress controller are exposed. Requires -controller-status. [1023 - 65535]")
+
+	preStopDelay = flag.Duration("pre-stop-delay", 0,
+		`The time the Ingress controller waits after receiving SIGTERM before it stops processing resources and shuts down NGINX.
+	During that time the readiness endpoint returns a failure, so that the pod is removed from the endpoints of the services before NGINX stops accepting connections.`)
+
+	logFormat = flag.String("log-format", logging.FormatGlog,
+		`Set the format of the logs of the Ingress controller. The logs of the syncs of resources and of the reloads of NGINX include the kind, the namespace and the name of the resources as fields. Supported values: "glog", "json" and "logfmt".
+	The verbosity of the logs is set by the -v flag`)
+
+	enableLogLevelEndpoint = flag.Bool("enable-log-level-endpoint", false,
+		`Enable the '/log-level' endpoint, which returns the verbosity of the logs for a GET request and changes it for a PUT request with the level query parameter, such as '/log-level?level=3'`)
+
+	logLevelEndpointPort = flag.Int("log-level-endpoint-port", 8082,
+		"Set the port where the log level endpoint is exposed. Requires -enable-log-level-endpoint. [1023 - 65535]")
+
+	logLevelEndpointAddress = flag.String("log-level-endpoint-address", "127.0.0.1",
+		`Set the IP address where the log level endpoint is exposed. The endpoint has no authentication, so by default it is only reachable from inside the pod.
+	Set to 0.0.0.0 to expose it on all interfaces. Requires -enable-log-level-endpoint`)
+)
+
+// The exit codes of the Ingress controller.
+const (
+	// exitCodeNginxError means that NGINX exited with an error before the controller was shutting down.
+	exitCodeNginxError = 1
+	// exitCodeClearStatusError means that the controller failed to clear the status of Ingress resources.
+	exitCodeClearStatusError = 2
+	// exitCodeNginxQuitError means that the controller failed to send the quit signal to NGINX.
+	exitCodeNginxQuitError = 3
+	// exitCodeNginxQuitTimeout means that NGINX didn't exit within the worker-shutdown-timeout.
+	exitCodeNginxQuitTimeout = 4
+	// exitCodeNginxShutdownError means that NGINX exited with an error after the quit signal.
+	exitCodeNginxShutdownError = 5
 )
 
+// nginxQuitGracePeriod is the time on top of the worker-shutdown-timeout the controller waits for NGINX to exit,
+// which allows the NGINX master process to exit after the workers are terminated.
+const nginxQuitGracePeriod = 5 * time.Second
+
 func main() {
 	flag.Parse()
 
 	err := flag.Lookup("logtostderr").Value.Set("true")
 	if err != nil {
-		glog.Fatalf("Error setting logtostderr to true: %v", err)
+		logging.Fatalf("Error setting logtostderr to true: %v", err)
 	}
 
 	if *versionFlag {
@@ -139,25 +230,94 @@ func main() {
 
 	statusLockNameValidationError := validateResourceName(*leaderElectionLockName)
 	if statusLockNameValidationError != nil {
-		glog.Fatalf("Invalid value for leader-election-lock-name: %v", statusLockNameValidationError)
+		logging.Fatalf("Invalid value for leader-election-lock-name: %v", statusLockNameValidationError)
+	}
+
+	lockTypeValidationError := validateLeaderElectionLockType(*leaderElectionLockType)
+	if lockTypeValidationError != nil {
+		logging.Fatalf("Invalid value for leader-election-lock-type: %v", lockTypeValidationError)
 	}
 
 	statusPortValidationError := validatePort(*nginxStatusPort)
 	if statusPortValidationError != nil {
-		glog.Fatalf("Invalid value for nginx-status-port: %v", statusPortValidationError)
+		logging.Fatalf("Invalid value for nginx-status-port: %v", statusPortValidationError)
 	}
 
 	metricsPortValidationError := validatePort(*prometheusMetricsListenPort)
 	if metricsPortValidationError != nil {
-		glog.Fatalf("Invalid value for prometheus-metrics-listen-port: %v", metricsPortValidationError)
+		logging.Fatalf("Invalid value for prometheus-metrics-listen-port: %v", metricsPortValidationError)
+	}
+
+	controllerStatusPortValidationError := validatePort(*controllerStatusPort)
+	if controllerStatusPortValidationError != nil {
+		logging.Fatalf("Invalid value for controller-status-port: %v", controllerStatusPortValidationError)
+	}
+
+	logLevelEndpointPortValidationError := validatePort(*logLevelEndpointPort)
+	if logLevelEndpointPortValidationError != nil {
+		logging.Fatalf("Invalid value for log-level-endpoint-port: %v", logLevelEndpointPortValidationError)
+	}
+
+	if net.ParseIP(*logLevelEndpointAddress) == nil {
+		logging.Fatalf("Invalid value for log-level-endpoint-address: %q is not an IP address", *logLevelEndpointAddress)
+	}
+
+	var listenerPorts []namedPort
+	if *nginxStatus {
+		listenerPorts = append(listenerPorts, namedPort{flag: "nginx-status-port", port: *nginxStatusPort})
+	}
+	if *enablePrometheusMetrics {
+		listenerPorts = append(listenerPorts, namedPort{flag: "prometheus-metrics-listen-port", port: *prometheusMetricsListenPort})
+	}
+	if *controllerStatus {
+		listenerPorts = append(listenerPorts, namedPort{flag: "controller-status-port", port: *controllerStatusPort})
+	}
+	if *enableLogLevelEndpoint {
+		listenerPorts = append(listenerPorts, namedPort{flag: "log-level-endpoint-port", port: *logLevelEndpointPort})
+	}
+	uniquePortsValidationError := validateUniquePorts(listenerPorts)
+	if uniquePortsValidationError != nil {
+		logging.Fatalf("Invalid ports: %v", uniquePortsValidationError)
+	}
+
+	logger, err := logging.NewLogger(*logFormat, os.Stderr)
+	if err != nil {
+		logging.Fatalf("Invalid value for log-format: %v", err)
+	}
+	logging.SetDefaultLogger(logger)
+	if *logFormat != logging.FormatGlog {
+		err = logging.RedirectKlog()
+		if err != nil {
+			logging.Fatalf("Error redirecting the logs of the Kubernetes client: %v", err)
+		}
+	}
+
+	if *enableLogLevelEndpoint {
+		go logging.RunLevelListener(*logLevelEndpointAddress, *logLevelEndpointPort)
 	}
 
 	allowedCIDRs, err := parseNginxStatusAllowCIDRs(*nginxStatusAllowCIDRs)
 	if err != nil {
-		glog.Fatalf(`Invalid value for nginx-status-allow-cidrs: %v`, err)
+		logging.Fatalf(`Invalid value for nginx-status-allow-cidrs: %v`, err)
 	}
 
-	glog.Infof("Starting NGINX Ingress controller Version=%v GitCommit=%v\n", version, gitCommit)
+	if *kubeContext != "" && *kubeconfig == "" {
+		logging.Fatal("The -context flag requires the -kubeconfig flag")
+	}
+
+	if *kubeconfig != "" && *proxyURL != "" {
+		logging.Fatal("The -kubeconfig and -proxy flags cannot be used together")
+	}
+
+	if *enableTLSPassthrough && !*enableCustomResources {
+		logging.Fatal("The -enable-tls-passthrough flag requires the -enable-custom-resources flag")
+	}
+
+	if *tlsCertificateExpiryWarningPeriod < 0 {
+		logging.Fatal("Invalid value for tls-certificate-expiry-warning-period: must not be negative")
+	}
+
+	logging.Infof("Starting NGINX Ingress controller Version=%v GitCommit=%v\n", version, gitCommit)
 
 	var config *rest.Config
 	if *proxyURL != "" {
@@ -169,36 +329,52 @@ func main() {
 				},
 			}).ClientConfig()
 		if err != nil {
-			glog.Fatalf("error creating client configuration: %v", err)
+			logging.Fatalf("error creating client configuration: %v", err)
+		}
+	} else if *kubeconfig != "" {
+		config, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			&clientcmd.ClientConfigLoadingRules{ExplicitPath: *kubeconfig},
+			&clientcmd.ConfigOverrides{CurrentContext: *kubeContext}).ClientConfig()
+		if err != nil {
+			logging.Fatalf("error creating client configuration from %v: %v", *kubeconfig, err)
 		}
 	} else {
 		if config, err = rest.InClusterConfig(); err != nil {
-			glog.Fatalf("error creating client configuration: %v", err)
+			logging.Fatalf("error creating client configuration: %v", err)
 		}
 	}
 
 	kubeClient, err := kubernetes.NewForConfig(config)
 	if err != nil {
-		glog.Fatalf("Failed to create client: %v.", err)
+		logging.Fatalf("Failed to create client: %v.", err)
 	}
 
 	var confClient k8s_nginx.Interface
 	if *enableCustomResources {
 		confClient, err = k8s_nginx.NewForConfig(config)
 		if err != nil {
-			glog.Fatalf("Failed to create a conf client: %v", err)
+			logging.Fatalf("Failed to create a conf client: %v", err)
 		}
 
 		// required for emitting Events for VirtualServer
 		err = conf_scheme.AddToScheme(scheme.Scheme)
 		if err != nil {
-			glog.Fatalf("Failed to add configuration types to the scheme: %v", err)
+			logging.Fatalf("Failed to add configuration types to the scheme: %v", err)
+		}
+	}
+
+	var dynClient dynamic.Interface
+	if *enableCertManager {
+		dynClient, err = dynamic.NewForConfig(config)
+		if err != nil {
+			logging.Fatalf("Failed to create a dynamic client: %v", err)
 		}
 	}
 
 	nginxConfTemplatePath := "nginx.tmpl"
 	nginxIngressTemplatePath := "nginx.ingress.tmpl"
 	nginxVirtualServerTemplatePath := "nginx.virtualserver.tmpl"
+	nginxTLSPassthroughTemplatePath := "nginx.tlspassthrough.tmpl"
 	if *nginxPlus {
 		nginxConfTemplatePath = "nginx-plus.tmpl"
 		nginxIngressTemplatePath = "nginx-plus.ingress.tmpl"
@@ -222,63 +398,73 @@ func main() {
 
 	templateExecutor, err := version1.NewTemplateExecutor(nginxConfTemplatePath, nginxIngressTemplatePath)
 	if err != nil {
-		glog.Fatalf("Error creating TemplateExecutor: %v", err)
+		logging.Fatalf("Error creating TemplateExecutor: %v", err)
 	}
 
-	templateExecutorV2, err := version2.NewTemplateExecutor(nginxVirtualServerTemplatePath)
+	templateExecutorV2, err := version2.NewTemplateExecutor(nginxVirtualServerTemplatePath, nginxTLSPassthroughTemplatePath)
 	if err != nil {
-		glog.Fatalf("Error creating TemplateExecutorV2: %v", err)
+		logging.Fatalf("Error creating TemplateExecutorV2: %v", err)
 	}
 
 	var registry *prometheus.Registry
 	var managerCollector collectors.ManagerCollector
 	var controllerCollector collectors.ControllerCollector
+	var resourceLabels *collectors.ResourceLabels
+	var labelUpdater collectors.LabelUpdater
 	managerCollector = collectors.NewManagerFakeCollector()
 	controllerCollector = collectors.NewControllerFakeCollector()
+	labelUpdater = collectors.NewFakeLabelUpdater()
 
 	if *enablePrometheusMetrics {
 		registry = prometheus.NewRegistry()
 		managerCollector = collectors.NewLocalManagerMetricsCollector()
 		controllerCollector = collectors.NewControllerMetricsCollector()
+		resourceLabels = collectors.NewResourceLabels()
+		labelUpdater = resourceLabels
 
 		err = managerCollector.Register(registry)
 		if err != nil {
-			glog.Errorf("Error registering Manager Prometheus metrics: %v", err)
+			logging.Errorf("Error registering Manager Prometheus metrics: %v", err)
 		}
 
 		err = controllerCollector.Register(registry)
 		if err != nil {
-			glog.Errorf("Error registering Controller Prometheus metrics: %v", err)
+			logging.Errorf("Error registering Controller Prometheus metrics: %v", err)
 		}
 	}
 
+	secretsPath := *nginxSecretsPath
+	if secretsPath == "" {
+		secretsPath = path.Join(*nginxConfPath, "secrets")
+	}
+
 	useFakeNginxManager := *proxyURL != ""
 	var nginxManager nginx.Manager
 	if useFakeNginxManager {
-		nginxManager = nginx.NewFakeManager("/etc/nginx")
+		nginxManager = nginx.NewFakeManager(*nginxConfPath, secretsPath)
 	} else {
-		nginxManager = nginx.NewLocalManager("/etc/nginx/", nginxBinaryPath, managerCollector)
+		nginxManager = nginx.NewLocalManager(*nginxConfPath, *nginxLibPath, secretsPath, nginxBinaryPath, managerCollector, logger)
 	}
 
 	if *defaultServerSecret != "" {
 		secret, err := getAndValidateSecret(kubeClient, *defaultServerSecret)
 		if err != nil {
-			glog.Fatalf("Error trying to get the default server TLS secret %v: %v", *defaultServerSecret, err)
+			logging.Fatalf("Error trying to get the default server TLS secret %v: %v", *defaultServerSecret, err)
 		}
 
 		bytes := configs.GenerateCertAndKeyFileContent(secret)
 		nginxManager.CreateSecret(configs.DefaultServerSecretName, bytes, nginx.TLSSecretFileMode)
 	} else {
-		_, err = os.Stat("/etc/nginx/secrets/default")
+		_, err = os.Stat(nginxManager.GetFilenameForSecret(configs.DefaultServerSecretName))
 		if os.IsNotExist(err) {
-			glog.Fatalf("A TLS cert and key for the default server is not found")
+			logging.Fatalf("A TLS cert and key for the default server is not found")
 		}
 	}
 
 	if *wildcardTLSSecret != "" {
 		secret, err := getAndValidateSecret(kubeClient, *wildcardTLSSecret)
 		if err != nil {
-			glog.Fatalf("Error trying to get the wildcard TLS secret %v: %v", *wildcardTLSSecret, err)
+			logging.Fatalf("Error trying to get the wildcard TLS secret %v: %v", *wildcardTLSSecret, err)
 		}
 
 		bytes := configs.GenerateCertAndKeyFileContent(secret)
@@ -289,17 +475,17 @@ func main() {
 	if *nginxConfigMaps != "" {
 		ns, name, err := k8s.ParseNamespaceName(*nginxConfigMaps)
 		if err != nil {
-			glog.Fatalf("Error parsing the nginx-configmaps argument: %v", err)
+			logging.Fatalf("Error parsing the nginx-configmaps argument: %v", err)
 		}
 		cfm, err := kubeClient.CoreV1().ConfigMaps(ns).Get(name, meta_v1.GetOptions{})
 		if err != nil {
-			glog.Fatalf("Error when getting %v: %v", *nginxConfigMaps, err)
+			logging.Fatalf("Error when getting %v: %v", *nginxConfigMaps, err)
 		}
 		cfgParams = configs.ParseConfigMap(cfm, *nginxPlus)
 		if cfgParams.MainServerSSLDHParamFileContent != nil {
 			fileName, err := nginxManager.CreateDHParam(*cfgParams.MainServerSSLDHParamFileContent)
 			if err != nil {
-				glog.Fatalf("Configmap %s/%s: Could not update dhparams: %v", ns, name, err)
+				logging.Fatalf("Configmap %s/%s: Could not update dhparams: %v", ns, name, err)
 			} else {
 				cfgParams.MainServerSSLDHParam = fileName
 			}
@@ -307,13 +493,13 @@ func main() {
 		if cfgParams.MainTemplate != nil {
 			err = templateExecutor.UpdateMainTemplate(cfgParams.MainTemplate)
 			if err != nil {
-				glog.Fatalf("Error updating NGINX main template: %v", err)
+				logging.Fatalf("Error updating NGINX main template: %v", err)
 			}
 		}
 		if cfgParams.IngressTemplate != nil {
 			err = templateExecutor.UpdateIngressTemplate(cfgParams.IngressTemplate)
 			if err != nil {
-				glog.Fatalf("Error updating ingress template: %v", err)
+				logging.Fatalf("Error updating ingress template: %v", err)
 			}
 		}
 	}
@@ -324,12 +510,18 @@ func main() {
 		NginxStatusAllowCIDRs:          allowedCIDRs,
 		NginxStatusPort:                *nginxStatusPort,
 		StubStatusOverUnixSocketForOSS: *enablePrometheusMetrics,
+		NginxConfPath:                  *nginxConfPath,
+		NginxLibPath:                   *nginxLibPath,
+		NginxLogPath:                   *nginxLogPath,
+		NginxCachePath:                 *nginxCachePath,
+		DefaultServerSecret:            nginxManager.GetFilenameForSecret(configs.DefaultServerSecretName),
+		TLSPassthrough:                 *enableTLSPassthrough,
 	}
 
 	ngxConfig := configs.GenerateNginxMainConfig(staticCfgParams, cfgParams)
 	content, err := templateExecutor.ExecuteMainConfigTemplate(ngxConfig)
 	if err != nil {
-		glog.Fatalf("Error generating NGINX main config: %v", err)
+		logging.Fatalf("Error generating NGINX main config: %v", err)
 	}
 	nginxManager.CreateMainConfig(content)
 
@@ -340,7 +532,7 @@ func main() {
 	if ngxConfig.OpenTracingLoadModule {
 		err := nginxManager.CreateOpenTracingTracerConfig(cfgParams.MainOpenTracingTracerConfig)
 		if err != nil {
-			glog.Fatalf("Error creating OpenTracing tracer config file: %v", err)
+			logging.Fatalf("Error creating OpenTracing tracer config file: %v", err)
 		}
 	}
 
@@ -349,93 +541,142 @@ func main() {
 
 	var plusClient *client.NginxClient
 	if *nginxPlus && !useFakeNginxManager {
-		httpClient := getSocketClient("/var/lib/nginx/nginx-plus-api.sock")
+		httpClient := getSocketClient(path.Join(*nginxLibPath, "nginx-plus-api.sock"))
 		plusClient, err = client.NewNginxClient(httpClient, "http://nginx-plus-api/api")
 		if err != nil {
-			glog.Fatalf("Failed to create NginxClient for Plus: %v", err)
+			logging.Fatalf("Failed to create NginxClient for Plus: %v", err)
 		}
 		nginxManager.SetPlusClients(plusClient, httpClient)
 	}
 
 	if *enablePrometheusMetrics {
 		if *nginxPlus {
-			go metrics.RunPrometheusListenerForNginxPlus(*prometheusMetricsListenPort, plusClient, registry)
+			go metrics.RunPrometheusListenerForNginxPlus(*prometheusMetricsListenPort, plusClient, registry, resourceLabels)
 		} else {
-			httpClient := getSocketClient("/var/lib/nginx/nginx-status.sock")
+			httpClient := getSocketClient(path.Join(*nginxLibPath, "nginx-status.sock"))
 			client, err := metrics.NewNginxMetricsClient(httpClient)
 			if err != nil {
-				glog.Fatalf("Error creating the Nginx client for Prometheus metrics: %v", err)
+				logging.Fatalf("Error creating the Nginx client for Prometheus metrics: %v", err)
 			}
 			go metrics.RunPrometheusListenerForNginx(*prometheusMetricsListenPort, client, registry)
 		}
 	}
 
 	isWildcardEnabled := *wildcardTLSSecret != ""
-	cnf := configs.NewConfigurator(nginxManager, staticCfgParams, cfgParams, templateExecutor, templateExecutorV2, *nginxPlus, isWildcardEnabled)
+	cnf := configs.NewConfigurator(nginxManager, staticCfgParams, cfgParams, templateExecutor, templateExecutorV2, *nginxPlus, isWildcardEnabled, *enableDefaultServerTLSFallback, labelUpdater, logger)
 	controllerNamespace := os.Getenv("POD_NAMESPACE")
 
 	lbcInput := k8s.NewLoadBalancerControllerInput{
-		KubeClient:                kubeClient,
-		ConfClient:                confClient,
-		ResyncPeriod:              30 * time.Second,
-		Namespace:                 *watchNamespace,
-		NginxConfigurator:         cnf,
-		DefaultServerSecret:       *defaultServerSecret,
-		IsNginxPlus:               *nginxPlus,
-		IngressClass:              *ingressClass,
-		UseIngressClassOnly:       *useIngressClassOnly,
-		ExternalServiceName:       *externalService,
-		ControllerNamespace:       controllerNamespace,
-		ReportIngressStatus:       *reportIngressStatus,
-		IsLeaderElectionEnabled:   *leaderElectionEnabled,
-		LeaderElectionLockName:    *leaderElectionLockName,
-		WildcardTLSSecret:         *wildcardTLSSecret,
-		ConfigMaps:                *nginxConfigMaps,
-		AreCustomResourcesEnabled: *enableCustomResources,
-		MetricsCollector:          controllerCollector,
+		KubeClient:                     kubeClient,
+		ConfClient:                     confClient,
+		DynClient:                      dynClient,
+		ResyncPeriod:                   30 * time.Second,
+		Namespace:                      *watchNamespace,
+		NginxConfigurator:              cnf,
+		DefaultServerSecret:            *defaultServerSecret,
+		IsNginxPlus:                    *nginxPlus,
+		IngressClass:                   *ingressClass,
+		UseIngressClassOnly:            *useIngressClassOnly,
+		ExternalServiceName:            *externalService,
+		ControllerNamespace:            controllerNamespace,
+		ReportIngressStatus:            *reportIngressStatus,
+		IsLeaderElectionEnabled:        *leaderElectionEnabled,
+		LeaderElectionLockName:         *leaderElectionLockName,
+		LeaderElectionLockType:         *leaderElectionLockType,
+		WildcardTLSSecret:              *wildcardTLSSecret,
+		ConfigMaps:                     *nginxConfigMaps,
+		AreCustomResourcesEnabled:      *enableCustomResources,
+		MetricsCollector:               controllerCollector,
+		CertificateExpiryWarningPeriod: *tlsCertificateExpiryWarningPeriod,
+		IsCertManagerEnabled:           *enableCertManager,
+		Logger:                         logger,
 	}
 
 	lbc := k8s.NewLoadBalancerController(lbcInput)
 
-	go handleTermination(lbc, nginxManager, nginxDone)
+	if *controllerStatus {
+		go healthcheck.RunHealthCheckListener(*controllerStatusPort, lbc, lbc, nginxManager)
+	}
+
+	go handleTermination(lbc, cnf, nginxManager, nginxDone)
 	lbc.Run()
 
 	for {
-		glog.Info("Waiting for the controller to exit...")
+		logging.Info("Waiting for the controller to exit...")
 		time.Sleep(30 * time.Second)
 	}
 }
 
-func handleTermination(lbc *k8s.LoadBalancerController, nginxManager nginx.Manager, nginxDone chan error) {
+// handleTermination shuts down the controller and NGINX when NGINX exits or the controller receives SIGTERM.
+// On SIGTERM, the controller is marked as not ready, waits for the pre-stop delay, stops processing resources,
+// clears the Ingress status if it is the last replica and gracefully shuts down NGINX.
+func handleTermination(lbc *k8s.LoadBalancerController, cnf *configs.Configurator, nginxManager nginx.Manager, nginxDone chan error) {
 	signalChan := make(chan os.Signal, 1)
 	signal.Notify(signalChan, syscall.SIGTERM)
 
-	exitStatus := 0
-	exited := false
-
 	select {
 	case err := <-nginxDone:
+		exitStatus := 0
 		if err != nil {
-			glog.Errorf("nginx command exited with an error: %v", err)
-			exitStatus = 1
+			logging.Errorf("nginx command exited with an error: %v", err)
+			exitStatus = exitCodeNginxError
 		} else {
-			glog.Info("nginx command exited successfully")
+			logging.Info("nginx command exited successfully")
 		}
-		exited = true
+
+		logging.Infof("Shutting down the controller")
+		lbc.Stop()
+
+		logging.Infof("Exiting with a status: %v", exitStatus)
+		os.Exit(exitStatus)
 	case <-signalChan:
-		glog.Infof("Received SIGTERM, shutting down")
+		logging.Infof("Received SIGTERM, shutting down")
+	}
+
+	lbc.MarkShuttingDown()
+	if *preStopDelay > 0 {
+		logging.Infof("Waiting %v before shutting down", *preStopDelay)
+		time.Sleep(*preStopDelay)
 	}
 
-	glog.Infof("Shutting down the controller")
+	logging.Infof("Shutting down the controller")
 	lbc.Stop()
 
-	if !exited {
-		glog.Infof("Shutting down NGINX")
-		nginxManager.Quit()
-		<-nginxDone
+	exitStatus := 0
+	if err := lbc.ClearIngressStatusIfLastReplica(); err != nil {
+		logging.Errorf("Error clearing the Ingress status: %v", err)
+		exitStatus = exitCodeClearStatusError
 	}
 
-	glog.Infof("Exiting with a status: %v", exitStatus)
+	logging.Infof("Shutting down NGINX")
+	if err := nginxManager.Quit(); err != nil {
+		logging.Errorf("Error shutting down NGINX: %v", err)
+		logging.Infof("Exiting with a status: %v", exitCodeNginxQuitError)
+		os.Exit(exitCodeNginxQuitError)
+	}
+
+	var timeout <-chan time.Time
+	if workerShutdownTimeout := cnf.GetWorkerShutdownTimeout(); workerShutdownTimeout != "" {
+		duration, err := configs.ParseTimeToDuration(workerShutdownTimeout)
+		if err != nil {
+			logging.Warningf("Invalid worker-shutdown-timeout %q, waiting for NGINX to exit without a timeout: %v", workerShutdownTimeout, err)
+		} else {
+			timeout = time.After(duration + nginxQuitGracePeriod)
+		}
+	}
+
+	select {
+	case err := <-nginxDone:
+		if err != nil {
+			logging.Errorf("nginx command exited with an error: %v", err)
+			exitStatus = exitCodeNginxShutdownError
+		}
+	case <-timeout:
+		logging.Errorf("NGINX didn't exit within the worker-shutdown-timeout %v", cnf.GetWorkerShutdownTimeout())
+		exitStatus = exitCodeNginxQuitTimeout
+	}
+
+	logging.Infof("Exiting with a status: %v", exitStatus)
 	os.Exit(exitStatus)
 }
 
@@ -459,6 +700,33 @@ func validateResourceName(lock string) error {
 	return nil
 }
 
+// validateLeaderElectionLockType validates the type of the lock for leader election
+func validateLeaderElectionLockType(lockType string) error {
+	switch lockType {
+	case k8s.ConfigMapsLockType, k8s.LeasesLockType, k8s.ConfigMapsLeasesLockType:
+		return nil
+	}
+	return fmt.Errorf("invalid lock type %v, must be one of %v, %v or %v", lockType, k8s.ConfigMapsLockType, k8s.LeasesLockType, k8s.ConfigMapsLeasesLockType)
+}
+
+// namedPort is a port of a listener of the Ingress controller or NGINX along with the flag that sets the port.
+type namedPort struct {
+	flag string
+	port int
+}
+
+// validateUniquePorts makes sure that the listeners of the Ingress controller and NGINX use different ports
+func validateUniquePorts(ports []namedPort) error {
+	flags := make(map[int]string)
+	for _, p := range ports {
+		if flag, exists := flags[p.port]; exists {
+			return fmt.Errorf("%v and %v cannot use the same port: %v", flag, p.flag, p.port)
+		}
+		flags[p.port] = p.flag
+	}
+	return nil
+}
+
 // validatePort makes sure a given port is inside the valid port range for its usage
 func validatePort(port int) error {
 	if port < 1023 || port > 65535 {
@@ -25,6 +25,44 @@ func TestValidatePort(t *testing.T) {
 
 }
 
+func TestValidateUniquePorts(t *testing.T) {
+	goodPorts := []namedPort{
+		{flag: "nginx-status-port", port: 8080},
+		{flag: "controller-status-port", port: 8081},
+		{flag: "log-level-endpoint-port", port: 8082},
+	}
+	if err := validateUniquePorts(goodPorts); err != nil {
+		t.Errorf("validateUniquePorts(%v) returned an error for unique ports: %v", goodPorts, err)
+	}
+
+	badPorts := []namedPort{
+		{flag: "prometheus-metrics-listen-port", port: 9113},
+		{flag: "controller-status-port", port: 8081},
+		{flag: "log-level-endpoint-port", port: 8081},
+	}
+	if err := validateUniquePorts(badPorts); err == nil {
+		t.Errorf("validateUniquePorts(%v) didn't return an error for duplicate ports", badPorts)
+	}
+}
+
+func TestValidateLeaderElectionLockType(t *testing.T) {
+	badLockTypes := []string{"", "endpoints", "lease"}
+	for _, badLockType := range badLockTypes {
+		err := validateLeaderElectionLockType(badLockType)
+		if err == nil {
+			t.Errorf("Expected error for lock type %v\n", badLockType)
+		}
+	}
+
+	goodLockTypes := []string{"configmaps", "leases", "configmapsleases"}
+	for _, goodLockType := range goodLockTypes {
+		err := validateLeaderElectionLockType(goodLockType)
+		if err != nil {
+			t.Errorf("Error for valid lock type: %v err: %v\n", goodLockType, err)
+		}
+	}
+}
+
 func TestParseNginxStatusAllowCIDRs(t *testing.T) {
 	var badCIDRs = []struct {
 		input         string
@@ -5,12 +5,15 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/golang/glog"
+	"github.com/nginxinc/kubernetes-ingress/internal/logging"
 )
 
 // JWTKeyAnnotation is the annotation where the Secret with a JWK is specified.
 const JWTKeyAnnotation = "nginx.com/jwt-key"
 
+// BasicAuthSecretAnnotation is the annotation where the Secret with an htpasswd file is specified.
+const BasicAuthSecretAnnotation = "nginx.org/basic-auth-secret"
+
 var masterBlacklist = map[string]bool{
 	"nginx.org/rewrites":                      true,
 	"nginx.org/ssl-services":                  true,
@@ -34,24 +37,50 @@ var minionBlacklist = map[string]bool{
 	"nginx.org/listen-ports":             true,
 	"nginx.org/listen-ports-ssl":         true,
 	"nginx.org/server-snippets":          true,
+	"nginx.org/gzip":                     true,
+	"nginx.org/gzip-types":               true,
+	"nginx.org/gzip-min-length":          true,
+	"nginx.org/gzip-comp-level":          true,
+	"nginx.org/gzip-proxied":             true,
 }
 
 var minionInheritanceList = map[string]bool{
-	"nginx.org/proxy-connect-timeout":    true,
-	"nginx.org/proxy-read-timeout":       true,
-	"nginx.org/proxy-send-timeout":       true,
-	"nginx.org/client-max-body-size":     true,
-	"nginx.org/proxy-buffering":          true,
-	"nginx.org/proxy-buffers":            true,
-	"nginx.org/proxy-buffer-size":        true,
-	"nginx.org/proxy-max-temp-file-size": true,
-	"nginx.org/upstream-zone-size":       true,
-	"nginx.org/location-snippets":        true,
-	"nginx.org/lb-method":                true,
-	"nginx.org/keepalive":                true,
-	"nginx.org/max-fails":                true,
-	"nginx.org/max-conns":                true,
-	"nginx.org/fail-timeout":             true,
+	"nginx.org/proxy-connect-timeout":     true,
+	"nginx.org/proxy-read-timeout":        true,
+	"nginx.org/proxy-send-timeout":        true,
+	"nginx.org/client-max-body-size":      true,
+	"nginx.org/proxy-buffering":           true,
+	"nginx.org/proxy-buffers":             true,
+	"nginx.org/proxy-buffer-size":         true,
+	"nginx.org/proxy-max-temp-file-size":  true,
+	"nginx.org/proxy-request-buffering":   true,
+	"nginx.org/proxy-http-version":        true,
+	"nginx.org/proxy-ignore-client-abort": true,
+	"nginx.org/client-body-buffer-size":   true,
+	"nginx.org/client-body-timeout":       true,
+	"nginx.org/upstream-zone-size":        true,
+	"nginx.org/location-snippets":         true,
+	"nginx.org/lb-method":                 true,
+	"nginx.org/keepalive":                 true,
+	"nginx.org/max-fails":                 true,
+	"nginx.org/max-conns":                 true,
+	"nginx.org/fail-timeout":              true,
+	"nginx.org/auth-url":                  true,
+	"nginx.org/auth-response-headers":     true,
+	"nginx.org/auth-signin":               true,
+	"nginx.org/cors-allow-origin":         true,
+	"nginx.org/cors-allow-methods":        true,
+	"nginx.org/cors-allow-headers":        true,
+	"nginx.org/cors-allow-credentials":    true,
+	"nginx.org/cors-max-age":              true,
+	"nginx.org/cors-expose-headers":       true,
+	"nginx.org/proxy-cache-zone":          true,
+	"nginx.org/proxy-cache-valid":         true,
+	"nginx.org/proxy-cache-methods":       true,
+	"nginx.org/proxy-cache-key":           true,
+	"nginx.org/proxy-cache-bypass":        true,
+	"nginx.org/proxy-cache-use-stale":     true,
+	"nginx.org/proxy-cache-status-header": true,
 }
 
 func parseAnnotations(ingEx *IngressEx, baseCfgParams *ConfigParams, isPlus bool) ConfigParams {
@@ -60,13 +89,13 @@ func parseAnnotations(ingEx *IngressEx, baseCfgParams *ConfigParams, isPlus bool
 	if lbMethod, exists := ingEx.Ingress.Annotations["nginx.org/lb-method"]; exists {
 		if isPlus {
 			if parsedMethod, err := ParseLBMethodForPlus(lbMethod); err != nil {
-				glog.Errorf("Ingress %s/%s: Invalid value for the nginx.org/lb-method: got %q: %v", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName(), lbMethod, err)
+				logging.Errorf("Ingress %s/%s: Invalid value for the nginx.org/lb-method: got %q: %v", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName(), lbMethod, err)
 			} else {
 				cfgParams.LBMethod = parsedMethod
 			}
 		} else {
 			if parsedMethod, err := ParseLBMethod(lbMethod); err != nil {
-				glog.Errorf("Ingress %s/%s: Invalid value for the nginx.org/lb-method: got %q: %v", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName(), lbMethod, err)
+				logging.Errorf("Ingress %s/%s: Invalid value for the nginx.org/lb-method: got %q: %v", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName(), lbMethod, err)
 			} else {
 				cfgParams.LBMethod = parsedMethod
 			}
@@ -75,19 +104,19 @@ func parseAnnotations(ingEx *IngressEx, baseCfgParams *ConfigParams, isPlus bool
 
 	if healthCheckEnabled, exists, err := GetMapKeyAsBool(ingEx.Ingress.Annotations, "nginx.com/health-checks", ingEx.Ingress); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		}
 		if isPlus {
 			cfgParams.HealthCheckEnabled = healthCheckEnabled
 		} else {
-			glog.Warning("Annotation 'nginx.com/health-checks' requires NGINX Plus")
+			logging.Warning("Annotation 'nginx.com/health-checks' requires NGINX Plus")
 		}
 	}
 
 	if cfgParams.HealthCheckEnabled {
 		if healthCheckMandatory, exists, err := GetMapKeyAsBool(ingEx.Ingress.Annotations, "nginx.com/health-checks-mandatory", ingEx.Ingress); exists {
 			if err != nil {
-				glog.Error(err)
+				logging.Error(err)
 			}
 			cfgParams.HealthCheckMandatory = healthCheckMandatory
 		}
@@ -96,7 +125,7 @@ func parseAnnotations(ingEx *IngressEx, baseCfgParams *ConfigParams, isPlus bool
 	if cfgParams.HealthCheckMandatory {
 		if healthCheckQueue, exists, err := GetMapKeyAsInt64(ingEx.Ingress.Annotations, "nginx.com/health-checks-mandatory-queue", ingEx.Ingress); exists {
 			if err != nil {
-				glog.Error(err)
+				logging.Error(err)
 			}
 			cfgParams.HealthCheckMandatoryQueue = healthCheckQueue
 		}
@@ -104,12 +133,12 @@ func parseAnnotations(ingEx *IngressEx, baseCfgParams *ConfigParams, isPlus bool
 
 	if slowStart, exists := ingEx.Ingress.Annotations["nginx.com/slow-start"]; exists {
 		if parsedSlowStart, err := ParseTime(slowStart); err != nil {
-			glog.Errorf("Ingress %s/%s: Invalid value nginx.org/slow-start: got %q: %v", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName(), slowStart, err)
+			logging.Errorf("Ingress %s/%s: Invalid value nginx.org/slow-start: got %q: %v", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName(), slowStart, err)
 		} else {
 			if isPlus {
 				cfgParams.SlowStart = parsedSlowStart
 			} else {
-				glog.Warning("Annotation 'nginx.com/slow-start' requires NGINX Plus")
+				logging.Warning("Annotation 'nginx.com/slow-start' requires NGINX Plus")
 			}
 		}
 	}
@@ -119,7 +148,7 @@ func parseAnnotations(ingEx *IngressEx, baseCfgParams *ConfigParams, isPlus bool
 			if isPlus {
 				cfgParams.ServerTokens = ingEx.Ingress.Annotations["nginx.org/server-tokens"]
 			} else {
-				glog.Error(err)
+				logging.Error(err)
 			}
 		} else {
 			cfgParams.ServerTokens = "off"
@@ -131,7 +160,7 @@ func parseAnnotations(ingEx *IngressEx, baseCfgParams *ConfigParams, isPlus bool
 
 	if serverSnippets, exists, err := GetMapKeyAsStringSlice(ingEx.Ingress.Annotations, "nginx.org/server-snippets", ingEx.Ingress, "\n"); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.ServerSnippets = serverSnippets
 		}
@@ -139,7 +168,7 @@ func parseAnnotations(ingEx *IngressEx, baseCfgParams *ConfigParams, isPlus bool
 
 	if locationSnippets, exists, err := GetMapKeyAsStringSlice(ingEx.Ingress.Annotations, "nginx.org/location-snippets", ingEx.Ingress, "\n"); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.LocationSnippets = locationSnippets
 		}
@@ -159,7 +188,7 @@ func parseAnnotations(ingEx *IngressEx, baseCfgParams *ConfigParams, isPlus bool
 
 	if proxyHideHeaders, exists, err := GetMapKeyAsStringSlice(ingEx.Ingress.Annotations, "nginx.org/proxy-hide-headers", ingEx.Ingress, ","); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.ProxyHideHeaders = proxyHideHeaders
 		}
@@ -167,7 +196,7 @@ func parseAnnotations(ingEx *IngressEx, baseCfgParams *ConfigParams, isPlus bool
 
 	if proxyPassHeaders, exists, err := GetMapKeyAsStringSlice(ingEx.Ingress.Annotations, "nginx.org/proxy-pass-headers", ingEx.Ingress, ","); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.ProxyPassHeaders = proxyPassHeaders
 		}
@@ -179,7 +208,7 @@ func parseAnnotations(ingEx *IngressEx, baseCfgParams *ConfigParams, isPlus bool
 
 	if redirectToHTTPS, exists, err := GetMapKeyAsBool(ingEx.Ingress.Annotations, "nginx.org/redirect-to-https", ingEx.Ingress); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.RedirectToHTTPS = redirectToHTTPS
 		}
@@ -187,7 +216,7 @@ func parseAnnotations(ingEx *IngressEx, baseCfgParams *ConfigParams, isPlus bool
 
 	if sslRedirect, exists, err := GetMapKeyAsBool(ingEx.Ingress.Annotations, "ingress.kubernetes.io/ssl-redirect", ingEx.Ingress); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.SSLRedirect = sslRedirect
 		}
@@ -195,7 +224,7 @@ func parseAnnotations(ingEx *IngressEx, baseCfgParams *ConfigParams, isPlus bool
 
 	if proxyBuffering, exists, err := GetMapKeyAsBool(ingEx.Ingress.Annotations, "nginx.org/proxy-buffering", ingEx.Ingress); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.ProxyBuffering = proxyBuffering
 		}
@@ -203,28 +232,28 @@ func parseAnnotations(ingEx *IngressEx, baseCfgParams *ConfigParams, isPlus bool
 
 	if hsts, exists, err := GetMapKeyAsBool(ingEx.Ingress.Annotations, "nginx.org/hsts", ingEx.Ingress); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			parsingErrors := false
 
 			hstsMaxAge, existsMA, err := GetMapKeyAsInt64(ingEx.Ingress.Annotations, "nginx.org/hsts-max-age", ingEx.Ingress)
 			if existsMA && err != nil {
-				glog.Error(err)
+				logging.Error(err)
 				parsingErrors = true
 			}
 			hstsIncludeSubdomains, existsIS, err := GetMapKeyAsBool(ingEx.Ingress.Annotations, "nginx.org/hsts-include-subdomains", ingEx.Ingress)
 			if existsIS && err != nil {
-				glog.Error(err)
+				logging.Error(err)
 				parsingErrors = true
 			}
 			hstsBehindProxy, existsBP, err := GetMapKeyAsBool(ingEx.Ingress.Annotations, "nginx.org/hsts-behind-proxy", ingEx.Ingress)
 			if existsBP && err != nil {
-				glog.Error(err)
+				logging.Error(err)
 				parsingErrors = true
 			}
 
 			if parsingErrors {
-				glog.Errorf("Ingress %s/%s: There are configuration issues with hsts annotations, skipping annotions for all hsts settings", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName())
+				logging.Errorf("Ingress %s/%s: There are configuration issues with hsts annotations, skipping annotions for all hsts settings", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName())
 			} else {
 				cfgParams.HSTS = hsts
 				if existsMA {
@@ -256,6 +285,90 @@ func parseAnnotations(ingEx *IngressEx, baseCfgParams *ConfigParams, isPlus bool
 		cfgParams.ProxyMaxTempFileSize = proxyMaxTempFileSize
 	}
 
+	if proxyRequestBuffering, exists, err := GetMapKeyAsBool(ingEx.Ingress.Annotations, "nginx.org/proxy-request-buffering", ingEx.Ingress); exists {
+		if err != nil {
+			logging.Error(err)
+		} else {
+			cfgParams.ProxyRequestBuffering = proxyRequestBuffering
+		}
+	}
+
+	if proxyHTTPVersion, exists := ingEx.Ingress.Annotations["nginx.org/proxy-http-version"]; exists {
+		if version, err := ParseProxyHTTPVersion(proxyHTTPVersion); err != nil {
+			logging.Errorf("Ingress %s/%s: Invalid value for the nginx.org/proxy-http-version: got %q: %v", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName(), proxyHTTPVersion, err)
+		} else {
+			cfgParams.ProxyHTTPVersion = version
+		}
+	}
+
+	if proxyIgnoreClientAbort, exists, err := GetMapKeyAsBool(ingEx.Ingress.Annotations, "nginx.org/proxy-ignore-client-abort", ingEx.Ingress); exists {
+		if err != nil {
+			logging.Error(err)
+		} else {
+			cfgParams.ProxyIgnoreClientAbort = proxyIgnoreClientAbort
+		}
+	}
+
+	if clientBodyBufferSize, exists := ingEx.Ingress.Annotations["nginx.org/client-body-buffer-size"]; exists {
+		if size, err := ParseSize(clientBodyBufferSize); err != nil {
+			logging.Errorf("Ingress %s/%s: Invalid value for the nginx.org/client-body-buffer-size: got %q: %v", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName(), clientBodyBufferSize, err)
+		} else {
+			cfgParams.ClientBodyBufferSize = size
+		}
+	}
+
+	if clientBodyTimeout, exists := ingEx.Ingress.Annotations["nginx.org/client-body-timeout"]; exists {
+		if timeout, err := ParseTime(clientBodyTimeout); err != nil {
+			logging.Errorf("Ingress %s/%s: Invalid value for the nginx.org/client-body-timeout: got %q: %v", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName(), clientBodyTimeout, err)
+		} else {
+			cfgParams.ClientBodyTimeout = timeout
+		}
+	}
+
+	if gzip, exists, err := GetMapKeyAsBool(ingEx.Ingress.Annotations, "nginx.org/gzip", ingEx.Ingress); exists {
+		if err != nil {
+			logging.Error(err)
+		} else {
+			cfgParams.Gzip = gzip
+		}
+	}
+
+	if gzipTypes, exists := ingEx.Ingress.Annotations["nginx.org/gzip-types"]; exists {
+		if types, err := ParseCommaSeparatedList(gzipTypes, ValidateGzipType); err != nil {
+			logging.Errorf("Ingress %s/%s: Invalid value for the nginx.org/gzip-types: got %q: %v", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName(), gzipTypes, err)
+		} else {
+			cfgParams.GzipTypes = types
+		}
+	}
+
+	if gzipMinLength, exists, err := GetMapKeyAsInt(ingEx.Ingress.Annotations, "nginx.org/gzip-min-length", ingEx.Ingress); exists {
+		if err != nil {
+			logging.Error(err)
+		} else if gzipMinLength < 0 {
+			logging.Errorf("Ingress %s/%s: Invalid value for the nginx.org/gzip-min-length: got %d: must not be negative", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName(), gzipMinLength)
+		} else {
+			cfgParams.GzipMinLength = gzipMinLength
+		}
+	}
+
+	if gzipCompLevel, exists, err := GetMapKeyAsInt(ingEx.Ingress.Annotations, "nginx.org/gzip-comp-level", ingEx.Ingress); exists {
+		if err != nil {
+			logging.Error(err)
+		} else if err := ValidateGzipCompLevel(gzipCompLevel); err != nil {
+			logging.Errorf("Ingress %s/%s: Invalid value for the nginx.org/gzip-comp-level: got %d: %v", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName(), gzipCompLevel, err)
+		} else {
+			cfgParams.GzipCompLevel = gzipCompLevel
+		}
+	}
+
+	if gzipProxied, exists := ingEx.Ingress.Annotations["nginx.org/gzip-proxied"]; exists {
+		if values, err := ParseCommaSeparatedList(gzipProxied, ValidateGzipProxied); err != nil {
+			logging.Errorf("Ingress %s/%s: Invalid value for the nginx.org/gzip-proxied: got %q: %v", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName(), gzipProxied, err)
+		} else {
+			cfgParams.GzipProxied = values
+		}
+	}
+
 	if isPlus {
 		if jwtRealm, exists := ingEx.Ingress.Annotations["nginx.com/jwt-realm"]; exists {
 			cfgParams.JWTRealm = jwtRealm
@@ -271,6 +384,144 @@ func parseAnnotations(ingEx *IngressEx, baseCfgParams *ConfigParams, isPlus bool
 		}
 	}
 
+	if basicAuthRealm, exists := ingEx.Ingress.Annotations["nginx.org/basic-auth-realm"]; exists {
+		if basicAuthRealm == "off" || strings.ContainsAny(basicAuthRealm, `"\`) {
+			logging.Errorf("Ingress %s/%s: Invalid value for the nginx.org/basic-auth-realm: got %q: must not be off and must not include quotes or backslashes", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName(), basicAuthRealm)
+		} else {
+			cfgParams.BasicAuthRealm = basicAuthRealm
+		}
+	}
+
+	if authURL, exists := ingEx.Ingress.Annotations["nginx.org/auth-url"]; exists {
+		if parsedURL, err := ParseExternalAuthURL(authURL); err != nil {
+			logging.Errorf("Ingress %s/%s: Invalid value for the nginx.org/auth-url: got %q: %v", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName(), authURL, err)
+		} else {
+			cfgParams.ExternalAuthURL = parsedURL
+		}
+	}
+
+	if authResponseHeaders, exists := ingEx.Ingress.Annotations["nginx.org/auth-response-headers"]; exists {
+		if headers, err := ParseExternalAuthResponseHeaders(authResponseHeaders); err != nil {
+			logging.Errorf("Ingress %s/%s: Invalid value for the nginx.org/auth-response-headers: got %q: %v", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName(), authResponseHeaders, err)
+		} else {
+			cfgParams.ExternalAuthResponseHeaders = headers
+		}
+	}
+
+	if authSignin, exists := ingEx.Ingress.Annotations["nginx.org/auth-signin"]; exists {
+		if parsedURL, err := ParseExternalAuthURL(authSignin); err != nil {
+			logging.Errorf("Ingress %s/%s: Invalid value for the nginx.org/auth-signin: got %q: %v", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName(), authSignin, err)
+		} else {
+			cfgParams.ExternalAuthSignin = parsedURL
+		}
+	}
+
+	if corsAllowMethods, exists := ingEx.Ingress.Annotations["nginx.org/cors-allow-methods"]; exists {
+		if methods, err := ParseCommaSeparatedList(corsAllowMethods, ValidateCORSMethod); err != nil {
+			logging.Errorf("Ingress %s/%s: Invalid value for the nginx.org/cors-allow-methods: got %q: %v", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName(), corsAllowMethods, err)
+		} else {
+			cfgParams.CORSAllowMethods = methods
+		}
+	}
+
+	if corsAllowHeaders, exists := ingEx.Ingress.Annotations["nginx.org/cors-allow-headers"]; exists {
+		if headers, err := ParseCommaSeparatedList(corsAllowHeaders, ValidateHeaderName); err != nil {
+			logging.Errorf("Ingress %s/%s: Invalid value for the nginx.org/cors-allow-headers: got %q: %v", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName(), corsAllowHeaders, err)
+		} else {
+			cfgParams.CORSAllowHeaders = headers
+		}
+	}
+
+	if corsExposeHeaders, exists := ingEx.Ingress.Annotations["nginx.org/cors-expose-headers"]; exists {
+		if headers, err := ParseCommaSeparatedList(corsExposeHeaders, ValidateHeaderName); err != nil {
+			logging.Errorf("Ingress %s/%s: Invalid value for the nginx.org/cors-expose-headers: got %q: %v", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName(), corsExposeHeaders, err)
+		} else {
+			cfgParams.CORSExposeHeaders = headers
+		}
+	}
+
+	if corsAllowCredentials, exists, err := GetMapKeyAsBool(ingEx.Ingress.Annotations, "nginx.org/cors-allow-credentials", ingEx.Ingress); exists {
+		if err != nil {
+			logging.Error(err)
+		} else {
+			cfgParams.CORSAllowCredentials = corsAllowCredentials
+		}
+	}
+
+	if corsMaxAge, exists, err := GetMapKeyAsInt(ingEx.Ingress.Annotations, "nginx.org/cors-max-age", ingEx.Ingress); exists {
+		if err != nil {
+			logging.Error(err)
+		} else if corsMaxAge < 0 {
+			logging.Errorf("Ingress %s/%s: Invalid value for the nginx.org/cors-max-age: got %d: must not be negative", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName(), corsMaxAge)
+		} else {
+			cfgParams.CORSMaxAge = corsMaxAge
+		}
+	}
+
+	if corsAllowOrigin, exists := ingEx.Ingress.Annotations["nginx.org/cors-allow-origin"]; exists {
+		if origins, err := ParseCORSOrigins(corsAllowOrigin, cfgParams.CORSAllowCredentials); err != nil {
+			logging.Errorf("Ingress %s/%s: Invalid value for the nginx.org/cors-allow-origin: got %q: %v", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName(), corsAllowOrigin, err)
+		} else {
+			cfgParams.CORSAllowOrigin = origins
+		}
+	}
+
+	if proxyCacheZone, exists := ingEx.Ingress.Annotations["nginx.org/proxy-cache-zone"]; exists {
+		if !hasProxyCacheZone(cfgParams.MainProxyCacheZones, proxyCacheZone) {
+			logging.Errorf("Ingress %s/%s: Invalid value for the nginx.org/proxy-cache-zone: got %q: the cache zone is not declared in the proxy-cache-zones ConfigMap key", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName(), proxyCacheZone)
+		} else {
+			cfgParams.ProxyCacheZone = proxyCacheZone
+		}
+	}
+
+	if proxyCacheValid, exists := ingEx.Ingress.Annotations["nginx.org/proxy-cache-valid"]; exists {
+		if validity, err := ParseCommaSeparatedList(proxyCacheValid, ValidateProxyCacheValid); err != nil {
+			logging.Errorf("Ingress %s/%s: Invalid value for the nginx.org/proxy-cache-valid: got %q: %v", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName(), proxyCacheValid, err)
+		} else {
+			cfgParams.ProxyCacheValid = validity
+		}
+	}
+
+	if proxyCacheMethods, exists := ingEx.Ingress.Annotations["nginx.org/proxy-cache-methods"]; exists {
+		if methods, err := ParseCommaSeparatedList(proxyCacheMethods, ValidateProxyCacheMethod); err != nil {
+			logging.Errorf("Ingress %s/%s: Invalid value for the nginx.org/proxy-cache-methods: got %q: %v", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName(), proxyCacheMethods, err)
+		} else {
+			cfgParams.ProxyCacheMethods = methods
+		}
+	}
+
+	if proxyCacheKey, exists := ingEx.Ingress.Annotations["nginx.org/proxy-cache-key"]; exists {
+		if err := ValidateProxyCacheKey(proxyCacheKey); err != nil {
+			logging.Errorf("Ingress %s/%s: Invalid value for the nginx.org/proxy-cache-key: got %q: %v", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName(), proxyCacheKey, err)
+		} else {
+			cfgParams.ProxyCacheKey = proxyCacheKey
+		}
+	}
+
+	if proxyCacheBypass, exists := ingEx.Ingress.Annotations["nginx.org/proxy-cache-bypass"]; exists {
+		if conditions, err := ParseCommaSeparatedList(proxyCacheBypass, ValidateProxyCacheBypass); err != nil {
+			logging.Errorf("Ingress %s/%s: Invalid value for the nginx.org/proxy-cache-bypass: got %q: %v", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName(), proxyCacheBypass, err)
+		} else {
+			cfgParams.ProxyCacheBypass = conditions
+		}
+	}
+
+	if proxyCacheUseStale, exists := ingEx.Ingress.Annotations["nginx.org/proxy-cache-use-stale"]; exists {
+		if values, err := ParseProxyCacheUseStale(proxyCacheUseStale); err != nil {
+			logging.Errorf("Ingress %s/%s: Invalid value for the nginx.org/proxy-cache-use-stale: got %q: %v", ingEx.Ingress.GetNamespace(), ingEx.Ingress.GetName(), proxyCacheUseStale, err)
+		} else {
+			cfgParams.ProxyCacheUseStale = values
+		}
+	}
+
+	if proxyCacheStatusHeader, exists, err := GetMapKeyAsBool(ingEx.Ingress.Annotations, "nginx.org/proxy-cache-status-header", ingEx.Ingress); exists {
+		if err != nil {
+			logging.Error(err)
+		} else {
+			cfgParams.ProxyCacheStatusHeader = proxyCacheStatusHeader
+		}
+	}
+
 	ports, sslPorts := getServicesPorts(ingEx)
 	if len(ports) > 0 {
 		cfgParams.Ports = ports
@@ -282,7 +533,7 @@ func parseAnnotations(ingEx *IngressEx, baseCfgParams *ConfigParams, isPlus bool
 
 	if keepalive, exists, err := GetMapKeyAsInt(ingEx.Ingress.Annotations, "nginx.org/keepalive", ingEx.Ingress); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.Keepalive = keepalive
 		}
@@ -290,7 +541,7 @@ func parseAnnotations(ingEx *IngressEx, baseCfgParams *ConfigParams, isPlus bool
 
 	if maxFails, exists, err := GetMapKeyAsInt(ingEx.Ingress.Annotations, "nginx.org/max-fails", ingEx.Ingress); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.MaxFails = maxFails
 		}
@@ -298,7 +549,7 @@ func parseAnnotations(ingEx *IngressEx, baseCfgParams *ConfigParams, isPlus bool
 
 	if maxConns, exists, err := GetMapKeyAsInt(ingEx.Ingress.Annotations, "nginx.org/max-conns", ingEx.Ingress); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.MaxConns = maxConns
 		}
@@ -329,7 +580,7 @@ func getRewrites(ingEx *IngressEx) map[string]string {
 	if services, exists := ingEx.Ingress.Annotations["nginx.org/rewrites"]; exists {
 		for _, svc := range strings.Split(services, ";") {
 			if serviceName, rewrite, err := parseRewrites(svc); err != nil {
-				glog.Errorf("In %v nginx.org/rewrites contains invalid declaration: %v, ignoring", ingEx.Ingress.Name, err)
+				logging.Errorf("In %v nginx.org/rewrites contains invalid declaration: %v, ignoring", ingEx.Ingress.Name, err)
 			} else {
 				rewrites[serviceName] = rewrite
 			}
@@ -369,7 +620,7 @@ func getSessionPersistenceServices(ingEx *IngressEx) map[string]string {
 	if services, exists := ingEx.Ingress.Annotations["nginx.com/sticky-cookie-services"]; exists {
 		for _, svc := range strings.Split(services, ";") {
 			if serviceName, sticky, err := parseStickyService(svc); err != nil {
-				glog.Errorf("In %v nginx.com/sticky-cookie-services contains invalid declaration: %v, ignoring", ingEx.Ingress.Name, err)
+				logging.Errorf("In %v nginx.com/sticky-cookie-services contains invalid declaration: %v, ignoring", ingEx.Ingress.Name, err)
 			} else {
 				spServices[serviceName] = sticky
 			}
@@ -391,7 +642,7 @@ func getServicesPorts(ingEx *IngressEx) ([]int, []int) {
 		if values, exists := ingEx.Ingress.Annotations[annotation]; exists {
 			for _, value := range strings.Split(values, ",") {
 				if port, err := parsePort(value); err != nil {
-					glog.Errorf(
+					logging.Errorf(
 						"In %v %s contains invalid declaration: %v, ignoring",
 						ingEx.Ingress.Name,
 						annotation,
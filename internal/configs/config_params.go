@@ -20,12 +20,30 @@ type ConfigParams struct {
 	MainServerNamesHashMaxSize    string
 	MainAccessLogOff              bool
 	MainLogFormat                 string
+	MainLogFormatEscaping         string
 	MainErrorLogLevel             string
 	MainStreamLogFormat           string
+	MainStreamLogFormatEscaping   string
+	MainAccessLogSyslogServer     string
+	MainErrorLogSyslogServer      string
+	MainSyslogTag                 string
+	MainSyslogFacility            string
+	MainSyslogSeverity            string
+	MainDefaultServerAccessLogOff bool
 	ProxyBuffering                bool
 	ProxyBuffers                  string
 	ProxyBufferSize               string
 	ProxyMaxTempFileSize          string
+	ProxyRequestBuffering         bool
+	ProxyHTTPVersion              string
+	ProxyIgnoreClientAbort        bool
+	ClientBodyBufferSize          string
+	ClientBodyTimeout             string
+	Gzip                          bool
+	GzipTypes                     []string
+	GzipMinLength                 int
+	GzipCompLevel                 int
+	GzipProxied                   []string
 	ProxyProtocol                 bool
 	ProxyHideHeaders              []string
 	ProxyPassHeaders              []string
@@ -79,10 +97,41 @@ type ConfigParams struct {
 	JWTToken    string
 	JWTLoginURL string
 
+	BasicAuthRealm string
+
+	ExternalAuthURL             string
+	ExternalAuthResponseHeaders []string
+	ExternalAuthSignin          string
+
+	CORSAllowOrigin      []string
+	CORSAllowMethods     []string
+	CORSAllowHeaders     []string
+	CORSAllowCredentials bool
+	CORSMaxAge           int
+	CORSExposeHeaders    []string
+
+	MainProxyCacheZones    []ProxyCacheZone
+	ProxyCacheZone         string
+	ProxyCacheValid        []string
+	ProxyCacheMethods      []string
+	ProxyCacheKey          string
+	ProxyCacheBypass       []string
+	ProxyCacheUseStale     []string
+	ProxyCacheStatusHeader bool
+
 	Ports    []int
 	SSLPorts []int
 }
 
+// ProxyCacheZone holds the parameters of a cache zone declared with the proxy_cache_path directive.
+type ProxyCacheZone struct {
+	Name     string
+	Size     string
+	MaxSize  string
+	MinFree  string
+	Inactive string
+}
+
 // StaticConfigParams holds immutable NGINX configuration parameters that affect the main NGINX config.
 type StaticConfigParams struct {
 	HealthStatus                   bool
@@ -90,6 +139,12 @@ type StaticConfigParams struct {
 	NginxStatusAllowCIDRs          []string
 	NginxStatusPort                int
 	StubStatusOverUnixSocketForOSS bool
+	NginxConfPath                  string
+	NginxLibPath                   string
+	NginxLogPath                   string
+	NginxCachePath                 string
+	DefaultServerSecret            string
+	TLSPassthrough                 bool
 }
 
 // NewDefaultConfigParams creates a ConfigParams with default values.
@@ -104,6 +159,8 @@ func NewDefaultConfigParams() *ConfigParams {
 		MainServerNamesHashBucketSize: "256",
 		MainServerNamesHashMaxSize:    "1024",
 		ProxyBuffering:                true,
+		ProxyRequestBuffering:         true,
+		ProxyHTTPVersion:              "1.1",
 		MainWorkerProcesses:           "auto",
 		MainWorkerConnections:         "1024",
 		HSTSMaxAge:                    2592000,
@@ -115,10 +172,15 @@ func NewDefaultConfigParams() *ConfigParams {
 		FailTimeout:                   "10s",
 		LBMethod:                      "random two least_conn",
 		MainErrorLogLevel:             "notice",
+		MainDefaultServerAccessLogOff: true,
 		ResolverIPV6:                  true,
 		MainKeepaliveTimeout:          "65s",
 		MainKeepaliveRequests:         100,
 		VariablesHashBucketSize:       256,
 		VariablesHashMaxSize:          1024,
+		BasicAuthRealm:                "Restricted",
+		CORSAllowMethods:              []string{"GET", "PUT", "POST", "DELETE", "PATCH", "OPTIONS"},
+		CORSAllowHeaders:              []string{"DNT", "Keep-Alive", "User-Agent", "X-Requested-With", "If-Modified-Since", "Cache-Control", "Content-Type", "Range", "Authorization"},
+		CORSMaxAge:                    86400,
 	}
 }
@@ -1,13 +1,23 @@
 package configs
 
 import (
+	"path"
 	"strings"
 
-	"github.com/golang/glog"
 	"github.com/nginxinc/kubernetes-ingress/internal/configs/version1"
+	"github.com/nginxinc/kubernetes-ingress/internal/logging"
 	v1 "k8s.io/api/core/v1"
 )
 
+// jsonLogFormat is the access log format of the json preset of the log-format-preset key. The resource variables are set
+// in the servers of the Ingress and VirtualServer resources.
+const jsonLogFormat = `{"time":"$time_iso8601","remote_addr":"$remote_addr","remote_user":"$remote_user",` +
+	`"request":"$request","status":"$status","body_bytes_sent":"$body_bytes_sent","request_time":"$request_time",` +
+	`"http_referer":"$http_referer","http_user_agent":"$http_user_agent","http_x_forwarded_for":"$http_x_forwarded_for",` +
+	`"resource_type":"$resource_type","resource_name":"$resource_name","resource_namespace":"$resource_namespace",` +
+	`"upstream":"$proxy_host","upstream_addr":"$upstream_addr","upstream_status":"$upstream_status",` +
+	`"upstream_response_time":"$upstream_response_time","gzip_ratio":"$gzip_ratio"}`
+
 // ParseConfigMap parses ConfigMap into ConfigParams.
 func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool) *ConfigParams {
 	cfgParams := NewDefaultConfigParams()
@@ -17,7 +27,7 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool) *ConfigParams {
 			if nginxPlus {
 				cfgParams.ServerTokens = cfgm.Data["server-tokens"]
 			} else {
-				glog.Error(err)
+				logging.Error(err)
 			}
 		} else {
 			cfgParams.ServerTokens = "off"
@@ -30,13 +40,13 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool) *ConfigParams {
 	if lbMethod, exists := cfgm.Data["lb-method"]; exists {
 		if nginxPlus {
 			if parsedMethod, err := ParseLBMethodForPlus(lbMethod); err != nil {
-				glog.Errorf("Configmap %s/%s: Invalid value for the lb-method key: got %q: %v", cfgm.GetNamespace(), cfgm.GetName(), lbMethod, err)
+				logging.Errorf("Configmap %s/%s: Invalid value for the lb-method key: got %q: %v", cfgm.GetNamespace(), cfgm.GetName(), lbMethod, err)
 			} else {
 				cfgParams.LBMethod = parsedMethod
 			}
 		} else {
 			if parsedMethod, err := ParseLBMethod(lbMethod); err != nil {
-				glog.Errorf("Configmap %s/%s: Invalid value for the lb-method key: got %q: %v", cfgm.GetNamespace(), cfgm.GetName(), lbMethod, err)
+				logging.Errorf("Configmap %s/%s: Invalid value for the lb-method key: got %q: %v", cfgm.GetNamespace(), cfgm.GetName(), lbMethod, err)
 			} else {
 				cfgParams.LBMethod = parsedMethod
 			}
@@ -57,7 +67,7 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool) *ConfigParams {
 
 	if proxyHideHeaders, exists, err := GetMapKeyAsStringSlice(cfgm.Data, "proxy-hide-headers", cfgm, ","); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.ProxyHideHeaders = proxyHideHeaders
 		}
@@ -65,7 +75,7 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool) *ConfigParams {
 
 	if proxyPassHeaders, exists, err := GetMapKeyAsStringSlice(cfgm.Data, "proxy-pass-headers", cfgm, ","); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.ProxyPassHeaders = proxyPassHeaders
 		}
@@ -85,7 +95,7 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool) *ConfigParams {
 
 	if HTTP2, exists, err := GetMapKeyAsBool(cfgm.Data, "http2", cfgm); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.HTTP2 = HTTP2
 		}
@@ -93,7 +103,7 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool) *ConfigParams {
 
 	if redirectToHTTPS, exists, err := GetMapKeyAsBool(cfgm.Data, "redirect-to-https", cfgm); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.RedirectToHTTPS = redirectToHTTPS
 		}
@@ -101,7 +111,7 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool) *ConfigParams {
 
 	if sslRedirect, exists, err := GetMapKeyAsBool(cfgm.Data, "ssl-redirect", cfgm); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.SSLRedirect = sslRedirect
 		}
@@ -109,28 +119,28 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool) *ConfigParams {
 
 	if hsts, exists, err := GetMapKeyAsBool(cfgm.Data, "hsts", cfgm); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			parsingErrors := false
 
 			hstsMaxAge, existsMA, err := GetMapKeyAsInt64(cfgm.Data, "hsts-max-age", cfgm)
 			if existsMA && err != nil {
-				glog.Error(err)
+				logging.Error(err)
 				parsingErrors = true
 			}
 			hstsIncludeSubdomains, existsIS, err := GetMapKeyAsBool(cfgm.Data, "hsts-include-subdomains", cfgm)
 			if existsIS && err != nil {
-				glog.Error(err)
+				logging.Error(err)
 				parsingErrors = true
 			}
 			hstsBehindProxy, existsBP, err := GetMapKeyAsBool(cfgm.Data, "hsts-behind-proxy", cfgm)
 			if existsBP && err != nil {
-				glog.Error(err)
+				logging.Error(err)
 				parsingErrors = true
 			}
 
 			if parsingErrors {
-				glog.Errorf("Configmap %s/%s: There are configuration issues with hsts annotations, skipping options for all hsts settings", cfgm.GetNamespace(), cfgm.GetName())
+				logging.Errorf("Configmap %s/%s: There are configuration issues with hsts annotations, skipping options for all hsts settings", cfgm.GetNamespace(), cfgm.GetName())
 			} else {
 				cfgParams.HSTS = hsts
 				if existsMA {
@@ -148,7 +158,7 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool) *ConfigParams {
 
 	if proxyProtocol, exists, err := GetMapKeyAsBool(cfgm.Data, "proxy-protocol", cfgm); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.ProxyProtocol = proxyProtocol
 		}
@@ -160,7 +170,7 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool) *ConfigParams {
 
 	if setRealIPFrom, exists, err := GetMapKeyAsStringSlice(cfgm.Data, "set-real-ip-from", cfgm, ","); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.SetRealIPFrom = setRealIPFrom
 		}
@@ -168,7 +178,7 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool) *ConfigParams {
 
 	if realIPRecursive, exists, err := GetMapKeyAsBool(cfgm.Data, "real-ip-recursive", cfgm); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.RealIPRecursive = realIPRecursive
 		}
@@ -180,7 +190,7 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool) *ConfigParams {
 
 	if sslPreferServerCiphers, exists, err := GetMapKeyAsBool(cfgm.Data, "ssl-prefer-server-ciphers", cfgm); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.MainServerSSLPreferServerCiphers = sslPreferServerCiphers
 		}
@@ -201,7 +211,7 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool) *ConfigParams {
 
 	if accessLogOff, exists, err := GetMapKeyAsBool(cfgm.Data, "access-log-off", cfgm); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.MainAccessLogOff = accessLogOff
 		}
@@ -211,13 +221,88 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool) *ConfigParams {
 		cfgParams.MainLogFormat = logFormat
 	}
 
+	if logFormatPreset, exists := cfgm.Data["log-format-preset"]; exists {
+		if logFormatPreset != "json" {
+			logging.Errorf("Configmap %s/%s: Invalid value for the log-format-preset key: got %q: the only supported preset is json", cfgm.GetNamespace(), cfgm.GetName(), logFormatPreset)
+		} else if _, exists := cfgm.Data["log-format"]; exists {
+			logging.Errorf("Configmap %s/%s: The log-format-preset key cannot be used together with the log-format key, ignoring", cfgm.GetNamespace(), cfgm.GetName())
+		} else {
+			cfgParams.MainLogFormat = jsonLogFormat
+			cfgParams.MainLogFormatEscaping = "json"
+		}
+	}
+
+	if logFormatEscaping, exists := cfgm.Data["log-format-escaping"]; exists {
+		if err := ValidateLogFormatEscaping(logFormatEscaping); err != nil {
+			logging.Errorf("Configmap %s/%s: Invalid value for the log-format-escaping key: got %q: %v", cfgm.GetNamespace(), cfgm.GetName(), logFormatEscaping, err)
+		} else {
+			cfgParams.MainLogFormatEscaping = logFormatEscaping
+		}
+	}
+
 	if streamLogFormat, exists := cfgm.Data["stream-log-format"]; exists {
 		cfgParams.MainStreamLogFormat = streamLogFormat
 	}
 
+	if streamLogFormatEscaping, exists := cfgm.Data["stream-log-format-escaping"]; exists {
+		if err := ValidateLogFormatEscaping(streamLogFormatEscaping); err != nil {
+			logging.Errorf("Configmap %s/%s: Invalid value for the stream-log-format-escaping key: got %q: %v", cfgm.GetNamespace(), cfgm.GetName(), streamLogFormatEscaping, err)
+		} else {
+			cfgParams.MainStreamLogFormatEscaping = streamLogFormatEscaping
+		}
+	}
+
+	if accessLogSyslogServer, exists := cfgm.Data["access-log-syslog-server"]; exists {
+		if err := ValidateSyslogServer(accessLogSyslogServer); err != nil {
+			logging.Errorf("Configmap %s/%s: Invalid value for the access-log-syslog-server key: got %q: %v", cfgm.GetNamespace(), cfgm.GetName(), accessLogSyslogServer, err)
+		} else {
+			cfgParams.MainAccessLogSyslogServer = accessLogSyslogServer
+		}
+	}
+
+	if errorLogSyslogServer, exists := cfgm.Data["error-log-syslog-server"]; exists {
+		if err := ValidateSyslogServer(errorLogSyslogServer); err != nil {
+			logging.Errorf("Configmap %s/%s: Invalid value for the error-log-syslog-server key: got %q: %v", cfgm.GetNamespace(), cfgm.GetName(), errorLogSyslogServer, err)
+		} else {
+			cfgParams.MainErrorLogSyslogServer = errorLogSyslogServer
+		}
+	}
+
+	if syslogTag, exists := cfgm.Data["syslog-tag"]; exists {
+		if err := ValidateSyslogTag(syslogTag); err != nil {
+			logging.Errorf("Configmap %s/%s: Invalid value for the syslog-tag key: got %q: %v", cfgm.GetNamespace(), cfgm.GetName(), syslogTag, err)
+		} else {
+			cfgParams.MainSyslogTag = syslogTag
+		}
+	}
+
+	if syslogFacility, exists := cfgm.Data["syslog-facility"]; exists {
+		if err := ValidateSyslogFacility(syslogFacility); err != nil {
+			logging.Errorf("Configmap %s/%s: Invalid value for the syslog-facility key: got %q: %v", cfgm.GetNamespace(), cfgm.GetName(), syslogFacility, err)
+		} else {
+			cfgParams.MainSyslogFacility = syslogFacility
+		}
+	}
+
+	if syslogSeverity, exists := cfgm.Data["syslog-severity"]; exists {
+		if err := ValidateSyslogSeverity(syslogSeverity); err != nil {
+			logging.Errorf("Configmap %s/%s: Invalid value for the syslog-severity key: got %q: %v", cfgm.GetNamespace(), cfgm.GetName(), syslogSeverity, err)
+		} else {
+			cfgParams.MainSyslogSeverity = syslogSeverity
+		}
+	}
+
+	if defaultServerAccessLogOff, exists, err := GetMapKeyAsBool(cfgm.Data, "default-server-access-log-off", cfgm); exists {
+		if err != nil {
+			logging.Error(err)
+		} else {
+			cfgParams.MainDefaultServerAccessLogOff = defaultServerAccessLogOff
+		}
+	}
+
 	if proxyBuffering, exists, err := GetMapKeyAsBool(cfgm.Data, "proxy-buffering", cfgm); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.ProxyBuffering = proxyBuffering
 		}
@@ -235,9 +320,93 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool) *ConfigParams {
 		cfgParams.ProxyMaxTempFileSize = proxyMaxTempFileSize
 	}
 
+	if proxyRequestBuffering, exists, err := GetMapKeyAsBool(cfgm.Data, "proxy-request-buffering", cfgm); exists {
+		if err != nil {
+			logging.Error(err)
+		} else {
+			cfgParams.ProxyRequestBuffering = proxyRequestBuffering
+		}
+	}
+
+	if proxyHTTPVersion, exists := cfgm.Data["proxy-http-version"]; exists {
+		if version, err := ParseProxyHTTPVersion(proxyHTTPVersion); err != nil {
+			logging.Errorf("Configmap %s/%s: Invalid value for the proxy-http-version key: got %q: %v", cfgm.GetNamespace(), cfgm.GetName(), proxyHTTPVersion, err)
+		} else {
+			cfgParams.ProxyHTTPVersion = version
+		}
+	}
+
+	if proxyIgnoreClientAbort, exists, err := GetMapKeyAsBool(cfgm.Data, "proxy-ignore-client-abort", cfgm); exists {
+		if err != nil {
+			logging.Error(err)
+		} else {
+			cfgParams.ProxyIgnoreClientAbort = proxyIgnoreClientAbort
+		}
+	}
+
+	if clientBodyBufferSize, exists := cfgm.Data["client-body-buffer-size"]; exists {
+		if size, err := ParseSize(clientBodyBufferSize); err != nil {
+			logging.Errorf("Configmap %s/%s: Invalid value for the client-body-buffer-size key: got %q: %v", cfgm.GetNamespace(), cfgm.GetName(), clientBodyBufferSize, err)
+		} else {
+			cfgParams.ClientBodyBufferSize = size
+		}
+	}
+
+	if clientBodyTimeout, exists := cfgm.Data["client-body-timeout"]; exists {
+		if timeout, err := ParseTime(clientBodyTimeout); err != nil {
+			logging.Errorf("Configmap %s/%s: Invalid value for the client-body-timeout key: got %q: %v", cfgm.GetNamespace(), cfgm.GetName(), clientBodyTimeout, err)
+		} else {
+			cfgParams.ClientBodyTimeout = timeout
+		}
+	}
+
+	if gzip, exists, err := GetMapKeyAsBool(cfgm.Data, "gzip", cfgm); exists {
+		if err != nil {
+			logging.Error(err)
+		} else {
+			cfgParams.Gzip = gzip
+		}
+	}
+
+	if gzipTypes, exists := cfgm.Data["gzip-types"]; exists {
+		if types, err := ParseCommaSeparatedList(gzipTypes, ValidateGzipType); err != nil {
+			logging.Errorf("Configmap %s/%s: Invalid value for the gzip-types key: got %q: %v", cfgm.GetNamespace(), cfgm.GetName(), gzipTypes, err)
+		} else {
+			cfgParams.GzipTypes = types
+		}
+	}
+
+	if gzipMinLength, exists, err := GetMapKeyAsInt(cfgm.Data, "gzip-min-length", cfgm); exists {
+		if err != nil {
+			logging.Error(err)
+		} else if gzipMinLength < 0 {
+			logging.Errorf("Configmap %s/%s: Invalid value for the gzip-min-length key: got %d: must not be negative", cfgm.GetNamespace(), cfgm.GetName(), gzipMinLength)
+		} else {
+			cfgParams.GzipMinLength = gzipMinLength
+		}
+	}
+
+	if gzipCompLevel, exists, err := GetMapKeyAsInt(cfgm.Data, "gzip-comp-level", cfgm); exists {
+		if err != nil {
+			logging.Error(err)
+		} else if err := ValidateGzipCompLevel(gzipCompLevel); err != nil {
+			logging.Errorf("Configmap %s/%s: Invalid value for the gzip-comp-level key: got %d: %v", cfgm.GetNamespace(), cfgm.GetName(), gzipCompLevel, err)
+		} else {
+			cfgParams.GzipCompLevel = gzipCompLevel
+		}
+	}
+
+	if gzipProxied, exists := cfgm.Data["gzip-proxied"]; exists {
+		if values, err := ParseCommaSeparatedList(gzipProxied, ValidateGzipProxied); err != nil {
+			logging.Errorf("Configmap %s/%s: Invalid value for the gzip-proxied key: got %q: %v", cfgm.GetNamespace(), cfgm.GetName(), gzipProxied, err)
+		} else {
+			cfgParams.GzipProxied = values
+		}
+	}
+
 	if mainMainSnippets, exists, err := GetMapKeyAsStringSlice(cfgm.Data, "main-snippets", cfgm, "\n"); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.MainMainSnippets = mainMainSnippets
 		}
@@ -245,7 +414,7 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool) *ConfigParams {
 
 	if mainHTTPSnippets, exists, err := GetMapKeyAsStringSlice(cfgm.Data, "http-snippets", cfgm, "\n"); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.MainHTTPSnippets = mainHTTPSnippets
 		}
@@ -253,7 +422,7 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool) *ConfigParams {
 
 	if locationSnippets, exists, err := GetMapKeyAsStringSlice(cfgm.Data, "location-snippets", cfgm, "\n"); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.LocationSnippets = locationSnippets
 		}
@@ -261,7 +430,7 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool) *ConfigParams {
 
 	if serverSnippets, exists, err := GetMapKeyAsStringSlice(cfgm.Data, "server-snippets", cfgm, "\n"); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.ServerSnippets = serverSnippets
 		}
@@ -269,7 +438,7 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool) *ConfigParams {
 
 	if _, exists, err := GetMapKeyAsInt(cfgm.Data, "worker-processes", cfgm); exists {
 		if err != nil && cfgm.Data["worker-processes"] != "auto" {
-			glog.Errorf("Configmap %s/%s: Invalid value for worker-processes key: must be an integer or the string 'auto', got %q", cfgm.GetNamespace(), cfgm.GetName(), cfgm.Data["worker-processes"])
+			logging.Errorf("Configmap %s/%s: Invalid value for worker-processes key: must be an integer or the string 'auto', got %q", cfgm.GetNamespace(), cfgm.GetName(), cfgm.Data["worker-processes"])
 		} else {
 			cfgParams.MainWorkerProcesses = cfgm.Data["worker-processes"]
 		}
@@ -293,7 +462,7 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool) *ConfigParams {
 
 	if keepalive, exists, err := GetMapKeyAsInt(cfgm.Data, "keepalive", cfgm); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.Keepalive = keepalive
 		}
@@ -301,7 +470,7 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool) *ConfigParams {
 
 	if maxFails, exists, err := GetMapKeyAsInt(cfgm.Data, "max-fails", cfgm); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.MaxFails = maxFails
 		}
@@ -325,7 +494,7 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool) *ConfigParams {
 
 	if mainStreamSnippets, exists, err := GetMapKeyAsStringSlice(cfgm.Data, "stream-snippets", cfgm, "\n"); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.MainStreamSnippets = mainStreamSnippets
 		}
@@ -333,24 +502,24 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool) *ConfigParams {
 
 	if resolverAddresses, exists, err := GetMapKeyAsStringSlice(cfgm.Data, "resolver-addresses", cfgm, ","); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			if nginxPlus {
 				cfgParams.ResolverAddresses = resolverAddresses
 			} else {
-				glog.Warning("ConfigMap key 'resolver-addresses' requires NGINX Plus")
+				logging.Warning("ConfigMap key 'resolver-addresses' requires NGINX Plus")
 			}
 		}
 	}
 
 	if resolverIpv6, exists, err := GetMapKeyAsBool(cfgm.Data, "resolver-ipv6", cfgm); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			if nginxPlus {
 				cfgParams.ResolverIPV6 = resolverIpv6
 			} else {
-				glog.Warning("ConfigMap key 'resolver-ipv6' requires NGINX Plus")
+				logging.Warning("ConfigMap key 'resolver-ipv6' requires NGINX Plus")
 			}
 		}
 	}
@@ -359,7 +528,7 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool) *ConfigParams {
 		if nginxPlus {
 			cfgParams.ResolverValid = resolverValid
 		} else {
-			glog.Warning("ConfigMap key 'resolver-valid' requires NGINX Plus")
+			logging.Warning("ConfigMap key 'resolver-valid' requires NGINX Plus")
 		}
 	}
 
@@ -367,7 +536,7 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool) *ConfigParams {
 		if nginxPlus {
 			cfgParams.ResolverTimeout = resolverTimeout
 		} else {
-			glog.Warning("ConfigMap key 'resolver-timeout' requires NGINX Plus")
+			logging.Warning("ConfigMap key 'resolver-timeout' requires NGINX Plus")
 		}
 	}
 
@@ -377,7 +546,7 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool) *ConfigParams {
 
 	if keepaliveRequests, exists, err := GetMapKeyAsInt64(cfgm.Data, "keepalive-requests", cfgm); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.MainKeepaliveRequests = keepaliveRequests
 		}
@@ -385,7 +554,7 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool) *ConfigParams {
 
 	if varHashBucketSize, exists, err := GetMapKeyAsUint64(cfgm.Data, "variables-hash-bucket-size", cfgm, true); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.VariablesHashBucketSize = varHashBucketSize
 		}
@@ -393,12 +562,20 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool) *ConfigParams {
 
 	if varHashMaxSize, exists, err := GetMapKeyAsUint64(cfgm.Data, "variables-hash-max-size", cfgm, false); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			cfgParams.VariablesHashMaxSize = varHashMaxSize
 		}
 	}
 
+	if proxyCacheZones, exists := cfgm.Data["proxy-cache-zones"]; exists {
+		if zones, err := ParseProxyCacheZones(proxyCacheZones); err != nil {
+			logging.Errorf("Configmap %s/%s: Invalid value for the proxy-cache-zones key: got %q: %v", cfgm.GetNamespace(), cfgm.GetName(), proxyCacheZones, err)
+		} else {
+			cfgParams.MainProxyCacheZones = zones
+		}
+	}
+
 	if openTracingTracer, exists := cfgm.Data["opentracing-tracer"]; exists {
 		cfgParams.MainOpenTracingTracer = openTracingTracer
 	}
@@ -413,12 +590,12 @@ func ParseConfigMap(cfgm *v1.ConfigMap, nginxPlus bool) *ConfigParams {
 
 	if openTracing, exists, err := GetMapKeyAsBool(cfgm.Data, "opentracing", cfgm); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		} else {
 			if cfgParams.MainOpenTracingLoadModule {
 				cfgParams.MainOpenTracingEnabled = openTracing
 			} else {
-				glog.Error("ConfigMap Key 'opentracing' requires both 'opentracing-tracer' and 'opentracing-tracer-config' Keys configured, Opentracing will be disabled")
+				logging.Error("ConfigMap Key 'opentracing' requires both 'opentracing-tracer' and 'opentracing-tracer-config' Keys configured, Opentracing will be disabled")
 			}
 		}
 	}
@@ -441,8 +618,14 @@ func GenerateNginxMainConfig(staticCfgParams *StaticConfigParams, config *Config
 		ServerNamesHashMaxSize:         config.MainServerNamesHashMaxSize,
 		AccessLogOff:                   config.MainAccessLogOff,
 		LogFormat:                      config.MainLogFormat,
+		LogFormatEscaping:              config.MainLogFormatEscaping,
+		LogGzipRatio:                   config.Gzip,
 		ErrorLogLevel:                  config.MainErrorLogLevel,
 		StreamLogFormat:                config.MainStreamLogFormat,
+		StreamLogFormatEscaping:        config.MainStreamLogFormatEscaping,
+		AccessLogSyslog:                generateSyslogTarget(config.MainAccessLogSyslogServer, config.MainSyslogFacility, config.MainSyslogTag, config.MainSyslogSeverity),
+		ErrorLogSyslog:                 generateSyslogTarget(config.MainErrorLogSyslogServer, config.MainSyslogFacility, config.MainSyslogTag, ""),
+		DefaultServerAccessLogOff:      config.MainDefaultServerAccessLogOff,
 		SSLProtocols:                   config.MainServerSSLProtocols,
 		SSLCiphers:                     config.MainServerSSLCiphers,
 		SSLDHParam:                     config.MainServerSSLDHParam,
@@ -467,6 +650,53 @@ func GenerateNginxMainConfig(staticCfgParams *StaticConfigParams, config *Config
 		OpenTracingEnabled:             config.MainOpenTracingEnabled,
 		OpenTracingTracer:              config.MainOpenTracingTracer,
 		OpenTracingTracerConfig:        config.MainOpenTracingTracerConfig,
+		ConfPath:                       staticCfgParams.NginxConfPath,
+		LibPath:                        staticCfgParams.NginxLibPath,
+		LogPath:                        staticCfgParams.NginxLogPath,
+		DefaultServerSecret:            staticCfgParams.DefaultServerSecret,
+		TLSPassthrough:                 staticCfgParams.TLSPassthrough,
+		SetRealIPFrom:                  config.SetRealIPFrom,
+		ProxyCacheZones:                generateProxyCacheZones(config.MainProxyCacheZones, staticCfgParams.NginxCachePath),
 	}
 	return nginxCfg
 }
+
+// proxyCacheDirPrefix is the prefix of the directories of the caches in the cache path. The directories are named
+// after the cache zones.
+const proxyCacheDirPrefix = "cache_"
+
+func generateProxyCacheZones(zones []ProxyCacheZone, cachePath string) []version1.ProxyCacheZone {
+	var result []version1.ProxyCacheZone
+	for _, zone := range zones {
+		result = append(result, version1.ProxyCacheZone{
+			Name:     zone.Name,
+			Path:     path.Join(cachePath, proxyCacheDirPrefix+zone.Name),
+			Size:     zone.Size,
+			MaxSize:  zone.MaxSize,
+			MinFree:  zone.MinFree,
+			Inactive: zone.Inactive,
+		})
+	}
+	return result
+}
+
+// generateSyslogTarget generates the syslog target of the access_log and error_log directives. It returns an empty
+// string if the syslog server is not configured.
+func generateSyslogTarget(server string, facility string, tag string, severity string) string {
+	if server == "" {
+		return ""
+	}
+
+	params := []string{"server=" + server}
+	if facility != "" {
+		params = append(params, "facility="+facility)
+	}
+	if tag != "" {
+		params = append(params, "tag="+tag)
+	}
+	if severity != "" {
+		params = append(params, "severity="+severity)
+	}
+
+	return "syslog:" + strings.Join(params, ",")
+}
@@ -3,12 +3,14 @@ package configs
 import (
 	"bytes"
 	"fmt"
+	"path"
 	"strings"
 
 	"github.com/nginxinc/kubernetes-ingress/internal/configs/version2"
 
-	"github.com/golang/glog"
 	"github.com/nginxinc/kubernetes-ingress/internal/configs/version1"
+	"github.com/nginxinc/kubernetes-ingress/internal/logging"
+	"github.com/nginxinc/kubernetes-ingress/internal/metrics/collectors"
 	"github.com/nginxinc/kubernetes-ingress/internal/nginx"
 	conf_v1alpha1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
 	api_v1 "k8s.io/api/core/v1"
@@ -16,9 +18,6 @@ import (
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-const pemFileNameForMissingTLSSecret = "/etc/nginx/secrets/default"
-const pemFileNameForWildcardTLSSecret = "/etc/nginx/secrets/wildcard"
-
 // DefaultServerSecretName is the filename of the Secret with a TLS cert and a key for the default server.
 const DefaultServerSecretName = "default"
 
@@ -28,32 +27,56 @@ const WildcardSecretName = "wildcard"
 // JWTKeyKey is the key of the data field of a Secret where the JWK must be stored.
 const JWTKeyKey = "jwk"
 
+// HtpasswdKey is the key of the data field of a Secret where the htpasswd file must be stored.
+const HtpasswdKey = "htpasswd"
+
 // Configurator configures NGINX.
 type Configurator struct {
-	nginxManager       nginx.Manager
-	staticCfgParams    *StaticConfigParams
-	cfgParams          *ConfigParams
-	templateExecutor   *version1.TemplateExecutor
-	templateExecutorV2 *version2.TemplateExecutor
-	ingresses          map[string]*IngressEx
-	minions            map[string]map[string]bool
-	isWildcardEnabled  bool
-	isPlus             bool
+	nginxManager         nginx.Manager
+	staticCfgParams      *StaticConfigParams
+	cfgParams            *ConfigParams
+	templateExecutor     *version1.TemplateExecutor
+	templateExecutorV2   *version2.TemplateExecutor
+	ingresses            map[string]*IngressEx
+	minions              map[string]map[string]bool
+	isWildcardEnabled    bool
+	isTLSFallbackEnabled bool
+	isPlus               bool
+	labelUpdater         collectors.LabelUpdater
+	metricLabels         map[string]*metricLabels
+	isLastReloadFailed   bool
+	logger               *logging.Logger
+	// tlsPassthroughVirtualServers holds the names of the VirtualServers with TLS Passthrough configuration.
+	tlsPassthroughVirtualServers map[string]bool
+}
+
+// metricLabels holds the names of the upstreams, upstream server peers and server zones of a resource,
+// for which the labels of the NGINX Plus metrics were updated.
+type metricLabels struct {
+	upstreams   []string
+	peers       []string
+	serverZones []string
 }
 
 // NewConfigurator creates a new Configurator.
 func NewConfigurator(nginxManager nginx.Manager, staticCfgParams *StaticConfigParams, config *ConfigParams, templateExecutor *version1.TemplateExecutor,
-	templateExecutorV2 *version2.TemplateExecutor, isPlus bool, isWildcardEnabled bool) *Configurator {
+	templateExecutorV2 *version2.TemplateExecutor, isPlus bool, isWildcardEnabled bool, isTLSFallbackEnabled bool, labelUpdater collectors.LabelUpdater,
+	logger *logging.Logger) *Configurator {
 	cnf := Configurator{
-		nginxManager:       nginxManager,
-		staticCfgParams:    staticCfgParams,
-		cfgParams:          config,
-		ingresses:          make(map[string]*IngressEx),
-		templateExecutor:   templateExecutor,
-		templateExecutorV2: templateExecutorV2,
-		minions:            make(map[string]map[string]bool),
-		isPlus:             isPlus,
-		isWildcardEnabled:  isWildcardEnabled,
+		nginxManager:                 nginxManager,
+		staticCfgParams:              staticCfgParams,
+		cfgParams:                    config,
+		ingresses:                    make(map[string]*IngressEx),
+		templateExecutor:             templateExecutor,
+		templateExecutorV2:           templateExecutorV2,
+		minions:                      make(map[string]map[string]bool),
+		isPlus:                       isPlus,
+		isWildcardEnabled:            isWildcardEnabled,
+		isTLSFallbackEnabled:         isTLSFallbackEnabled,
+		labelUpdater:                 labelUpdater,
+		metricLabels:                 make(map[string]*metricLabels),
+		tlsPassthroughVirtualServers: make(map[string]bool),
+		logger:                       logger,
 	}
 	return &cnf
 }
@@ -69,8 +92,8 @@ func (cnf *Configurator) AddOrUpdateIngress(ingEx *IngressEx) error {
 		return fmt.Errorf("Error adding or updating ingress %v/%v: %v", ingEx.Ingress.Namespace, ingEx.Ingress.Name, err)
 	}
 
-	if err := cnf.nginxManager.Reload(); err != nil {
-		return fmt.Errorf("Error reloading NGINX for %v/%v: %v", ingEx.Ingress.Namespace, ingEx.Ingress.Name, err)
+	if err := cnf.reload(); err != nil {
+		return newReloadError("Error reloading NGINX for %v/%v: %v", ingEx.Ingress.Namespace, ingEx.Ingress.Name, err)
 	}
 
 	return nil
@@ -79,9 +102,11 @@ func (cnf *Configurator) AddOrUpdateIngress(ingEx *IngressEx) error {
 func (cnf *Configurator) addOrUpdateIngress(ingEx *IngressEx) error {
 	pems := cnf.updateTLSSecrets(ingEx)
 	jwtKeyFileName := cnf.updateJWKSecret(ingEx)
+	basicAuthFileName := cnf.updateHtpasswdSecret(ingEx)
 
 	isMinion := false
-	nginxCfg := generateNginxCfg(ingEx, pems, isMinion, cnf.cfgParams, cnf.isPlus, cnf.IsResolverConfigured(), jwtKeyFileName)
+	nginxCfg := generateNginxCfg(ingEx, pems, cnf.getPemFileNameForMissingTLSSecret(), cnf.getTLSPassthroughSocket(), isMinion, cnf.cfgParams, cnf.isPlus, cnf.IsResolverConfigured(), jwtKeyFileName,
+		basicAuthFileName)
 
 	name := objectMetaToFileName(&ingEx.Ingress.ObjectMeta)
 	content, err := cnf.templateExecutor.ExecuteIngressConfigTemplate(&nginxCfg)
@@ -89,8 +114,11 @@ func (cnf *Configurator) addOrUpdateIngress(ingEx *IngressEx) error {
 		return fmt.Errorf("Error generating Ingress Config %v: %v", name, err)
 	}
 	cnf.nginxManager.CreateConfig(name, content)
+	cnf.logger.V(3).Info("Updated config", "kind", "Ingress", "namespace", ingEx.Ingress.Namespace, "name", ingEx.Ingress.Name,
+		"file", name)
 
 	cnf.ingresses[name] = ingEx
+	cnf.updateIngressMetricsLabels(name, []*IngressEx{ingEx})
 
 	return nil
 }
@@ -101,8 +129,8 @@ func (cnf *Configurator) AddOrUpdateMergeableIngress(mergeableIngs *MergeableIng
 		return fmt.Errorf("Error when adding or updating ingress %v/%v: %v", mergeableIngs.Master.Ingress.Namespace, mergeableIngs.Master.Ingress.Name, err)
 	}
 
-	if err := cnf.nginxManager.Reload(); err != nil {
-		return fmt.Errorf("Error reloading NGINX for %v/%v: %v", mergeableIngs.Master.Ingress.Namespace, mergeableIngs.Master.Ingress.Name, err)
+	if err := cnf.reload(); err != nil {
+		return newReloadError("Error reloading NGINX for %v/%v: %v", mergeableIngs.Master.Ingress.Namespace, mergeableIngs.Master.Ingress.Name, err)
 	}
 
 	return nil
@@ -111,13 +139,17 @@ func (cnf *Configurator) AddOrUpdateMergeableIngress(mergeableIngs *MergeableIng
 func (cnf *Configurator) addOrUpdateMergeableIngress(mergeableIngs *MergeableIngresses) error {
 	masterPems := cnf.updateTLSSecrets(mergeableIngs.Master)
 	masterJwtKeyFileName := cnf.updateJWKSecret(mergeableIngs.Master)
+	masterBasicAuthFileName := cnf.updateHtpasswdSecret(mergeableIngs.Master)
 	minionJwtKeyFileNames := make(map[string]string)
+	minionBasicAuthFileNames := make(map[string]string)
 	for _, minion := range mergeableIngs.Minions {
 		minionName := objectMetaToFileName(&minion.Ingress.ObjectMeta)
 		minionJwtKeyFileNames[minionName] = cnf.updateJWKSecret(minion)
+		minionBasicAuthFileNames[minionName] = cnf.updateHtpasswdSecret(minion)
 	}
 
-	nginxCfg := generateNginxCfgForMergeableIngresses(mergeableIngs, masterPems, masterJwtKeyFileName, minionJwtKeyFileNames, cnf.cfgParams, cnf.isPlus, cnf.IsResolverConfigured())
+	nginxCfg := generateNginxCfgForMergeableIngresses(mergeableIngs, masterPems, cnf.getPemFileNameForMissingTLSSecret(), cnf.getTLSPassthroughSocket(), masterJwtKeyFileName, minionJwtKeyFileNames,
+		masterBasicAuthFileName, minionBasicAuthFileNames, cnf.cfgParams, cnf.isPlus, cnf.IsResolverConfigured())
 
 	name := objectMetaToFileName(&mergeableIngs.Master.Ingress.ObjectMeta)
 	content, err := cnf.templateExecutor.ExecuteIngressConfigTemplate(&nginxCfg)
@@ -125,6 +157,8 @@ func (cnf *Configurator) addOrUpdateMergeableIngress(mergeableIngs *MergeableIng
 		return fmt.Errorf("Error generating Ingress Config %v: %v", name, err)
 	}
 	cnf.nginxManager.CreateConfig(name, content)
+	cnf.logger.V(3).Info("Updated config", "kind", "Ingress", "namespace", mergeableIngs.Master.Ingress.Namespace,
+		"name", mergeableIngs.Master.Ingress.Name, "file", name, "minions", len(mergeableIngs.Minions))
 
 	cnf.ingresses[name] = mergeableIngs.Master
 	cnf.minions[name] = make(map[string]bool)
@@ -132,6 +166,7 @@ func (cnf *Configurator) addOrUpdateMergeableIngress(mergeableIngs *MergeableIng
 		minionName := objectMetaToFileName(&minion.Ingress.ObjectMeta)
 		cnf.minions[name][minionName] = true
 	}
+	cnf.updateIngressMetricsLabels(name, append([]*IngressEx{mergeableIngs.Master}, mergeableIngs.Minions...))
 
 	return nil
 }
@@ -143,8 +178,8 @@ func (cnf *Configurator) AddOrUpdateVirtualServer(virtualServerEx *VirtualServer
 		return warnings, fmt.Errorf("Error adding or updating VirtualServer %v/%v: %v", virtualServerEx.VirtualServer.Namespace, virtualServerEx.VirtualServer.Name, err)
 	}
 
-	if err := cnf.nginxManager.Reload(); err != nil {
-		return warnings, fmt.Errorf("Error reloading NGINX for VirtualServer %v/%v: %v", virtualServerEx.VirtualServer.Namespace, virtualServerEx.VirtualServer.Name, err)
+	if err := cnf.reload(); err != nil {
+		return warnings, newReloadError("Error reloading NGINX for VirtualServer %v/%v: %v", virtualServerEx.VirtualServer.Namespace, virtualServerEx.VirtualServer.Name, err)
 	}
 
 	return warnings, nil
@@ -160,8 +195,10 @@ func (cnf *Configurator) addOrUpdateVirtualServer(virtualServerEx *VirtualServer
 	if virtualServerEx.TLSSecret != nil {
 		tlsPemFileName = cnf.addOrUpdateTLSSecret(virtualServerEx.TLSSecret)
 	}
-	vsc := newVirtualServerConfigurator(cnf.cfgParams, cnf.isPlus, cnf.IsResolverConfigured())
-	vsCfg, warnings := vsc.GenerateVirtualServerConfig(virtualServerEx, tlsPemFileName)
+	htpasswdFileNames := cnf.updateHtpasswdSecretsForVirtualServer(virtualServerEx)
+	vsc := newVirtualServerConfigurator(cnf.cfgParams, cnf.isPlus, cnf.IsResolverConfigured(), cnf.staticCfgParams)
+	vsCfg, warnings := vsc.GenerateVirtualServerConfig(virtualServerEx, tlsPemFileName, cnf.getSpecialTLSSecrets(), cnf.getTLSPassthroughSocket(),
+		htpasswdFileNames)
 
 	name := getFileNameForVirtualServer(virtualServerEx.VirtualServer)
 	content, err := cnf.templateExecutorV2.ExecuteVirtualServerTemplate(&vsCfg)
@@ -169,19 +206,71 @@ func (cnf *Configurator) addOrUpdateVirtualServer(virtualServerEx *VirtualServer
 		return warnings, fmt.Errorf("Error generating VirtualServer config: %v: %v", name, err)
 	}
 	cnf.nginxManager.CreateConfig(name, content)
+	cnf.logger.V(3).Info("Updated config", "kind", "VirtualServer", "namespace", virtualServerEx.VirtualServer.Namespace,
+		"name", virtualServerEx.VirtualServer.Name, "file", name, "warnings", len(warnings))
+	cnf.updateVirtualServerMetricsLabels(name, virtualServerEx)
+
+	if err := cnf.addOrUpdateTLSPassthroughForVirtualServer(name, virtualServerEx); err != nil {
+		return warnings, err
+	}
 
 	return warnings, nil
 }
 
+func (cnf *Configurator) addOrUpdateTLSPassthroughForVirtualServer(name string, virtualServerEx *VirtualServerEx) error {
+	if !cnf.staticCfgParams.TLSPassthrough || virtualServerEx.VirtualServer.Spec.TLSPassthrough == nil {
+		cnf.deleteTLSPassthroughForVirtualServer(name)
+		return nil
+	}
+
+	cfg := generateTLSPassthroughConfig(virtualServerEx, cnf.getTLSPassthroughSocketForVirtualServer(name))
+	content, err := cnf.templateExecutorV2.ExecuteTLSPassthroughTemplate(cfg)
+	if err != nil {
+		return fmt.Errorf("Error generating TLS Passthrough config: %v: %v", name, err)
+	}
+	cnf.nginxManager.CreateStreamConfig(name, content)
+	cnf.nginxManager.CreateTLSPassthroughHostsConfig(name, generateTLSPassthroughHosts(cfg))
+
+	cnf.tlsPassthroughVirtualServers[name] = true
+
+	return nil
+}
+
+func (cnf *Configurator) deleteTLSPassthroughForVirtualServer(name string) {
+	if !cnf.tlsPassthroughVirtualServers[name] {
+		return
+	}
+
+	cnf.nginxManager.DeleteStreamConfig(name)
+	cnf.nginxManager.DeleteTLSPassthroughHostsConfig(name)
+
+	delete(cnf.tlsPassthroughVirtualServers, name)
+}
+
+// getTLSPassthroughSocket returns the unix socket of the HTTPS servers. When TLS Passthrough is enabled, the TLS connections
+// for the hosts without TLS Passthrough are passed to that socket. It returns an empty string if TLS Passthrough is disabled.
+func (cnf *Configurator) getTLSPassthroughSocket() string {
+	if !cnf.staticCfgParams.TLSPassthrough {
+		return ""
+	}
+	return "unix:" + path.Join(cnf.staticCfgParams.NginxLibPath, "passthrough-https.sock")
+}
+
+// getTLSPassthroughSocketForVirtualServer returns the unix socket of the stream server that passes the TLS connections through
+// for the VirtualServer.
+func (cnf *Configurator) getTLSPassthroughSocketForVirtualServer(name string) string {
+	return "unix:" + path.Join(cnf.staticCfgParams.NginxLibPath, fmt.Sprintf("passthrough-%s.sock", name))
+}
+
 func (cnf *Configurator) updateTLSSecrets(ingEx *IngressEx) map[string]string {
 	pems := make(map[string]string)
 
 	for _, tls := range ingEx.Ingress.Spec.TLS {
 		secretName := tls.SecretName
 
-		pemFileName := pemFileNameForMissingTLSSecret
+		pemFileName := cnf.getPemFileNameForMissingTLSSecret()
 		if secretName == "" && cnf.isWildcardEnabled {
-			pemFileName = pemFileNameForWildcardTLSSecret
+			pemFileName = cnf.nginxManager.GetFilenameForSecret(WildcardSecretName)
 		} else if secret, exists := ingEx.TLSSecrets[secretName]; exists {
 			pemFileName = cnf.addOrUpdateTLSSecret(secret)
 		}
@@ -197,6 +286,24 @@ func (cnf *Configurator) updateTLSSecrets(ingEx *IngressEx) map[string]string {
 	return pems
 }
 
+// getSpecialTLSSecrets returns the special TLS secrets for TLS termination of VirtualServers.
+func (cnf *Configurator) getSpecialTLSSecrets() specialTLSSecrets {
+	secrets := specialTLSSecrets{
+		defaultServerPemFileName: cnf.getPemFileNameForMissingTLSSecret(),
+		isDefaultFallbackEnabled: cnf.isTLSFallbackEnabled,
+	}
+	if cnf.isWildcardEnabled {
+		secrets.wildcardPemFileName = cnf.nginxManager.GetFilenameForSecret(WildcardSecretName)
+	}
+	return secrets
+}
+
+// getPemFileNameForMissingTLSSecret returns the filename of the default server secret, which is used
+// for TLS termination when the secret referenced by a resource does not exist.
+func (cnf *Configurator) getPemFileNameForMissingTLSSecret() string {
+	return cnf.nginxManager.GetFilenameForSecret(DefaultServerSecretName)
+}
+
 func (cnf *Configurator) updateJWKSecret(ingEx *IngressEx) string {
 	if !cnf.isPlus || ingEx.JWTKey.Name == "" {
 		return ""
@@ -219,9 +326,51 @@ func (cnf *Configurator) AddOrUpdateJWKSecret(secret *api_v1.Secret) {
 	cnf.addOrUpdateJWKSecret(secret)
 }
 
+func (cnf *Configurator) updateHtpasswdSecret(ingEx *IngressEx) string {
+	if ingEx.BasicAuthSecret.Name == "" {
+		return ""
+	}
+
+	if ingEx.BasicAuthSecret.Secret != nil {
+		cnf.addOrUpdateHtpasswdSecret(ingEx.BasicAuthSecret.Secret)
+	}
+
+	return cnf.nginxManager.GetFilenameForSecret(ingEx.Ingress.Namespace + "-" + ingEx.BasicAuthSecret.Name)
+}
+
+func (cnf *Configurator) updateHtpasswdSecretsForVirtualServer(virtualServerEx *VirtualServerEx) map[string]string {
+	fileNames := make(map[string]string)
+
+	for key, secret := range virtualServerEx.HtpasswdSecrets {
+		if secret != nil {
+			cnf.addOrUpdateHtpasswdSecret(secret)
+		}
+		fileNames[key] = cnf.nginxManager.GetFilenameForSecret(keyToFileName(key))
+	}
+
+	return fileNames
+}
+
+func (cnf *Configurator) addOrUpdateHtpasswdSecret(secret *api_v1.Secret) string {
+	name := objectMetaToFileName(&secret.ObjectMeta)
+	data := secret.Data[HtpasswdKey]
+	return cnf.nginxManager.CreateSecret(name, data, nginx.HtpasswdSecretFileMode)
+}
+
+// AddOrUpdateHtpasswdSecret adds or updates a file with the content of the htpasswd secret and the configuration files
+// for the Ingress and VirtualServer resources that reference it.
+func (cnf *Configurator) AddOrUpdateHtpasswdSecret(secret *api_v1.Secret, ingExes []IngressEx, mergeableIngresses []MergeableIngresses, virtualServerExes []*VirtualServerEx) error {
+	cnf.addOrUpdateHtpasswdSecret(secret)
+	return cnf.addOrUpdateResourcesForSecret(ingExes, mergeableIngresses, virtualServerExes)
+}
+
 // AddOrUpdateTLSSecret adds or updates a file with the content of the TLS secret.
 func (cnf *Configurator) AddOrUpdateTLSSecret(secret *api_v1.Secret, ingExes []IngressEx, mergeableIngresses []MergeableIngresses, virtualServerExes []*VirtualServerEx) error {
 	cnf.addOrUpdateTLSSecret(secret)
+	return cnf.addOrUpdateResourcesForSecret(ingExes, mergeableIngresses, virtualServerExes)
+}
+
+func (cnf *Configurator) addOrUpdateResourcesForSecret(ingExes []IngressEx, mergeableIngresses []MergeableIngresses, virtualServerExes []*VirtualServerEx) error {
 	for i := range ingExes {
 		err := cnf.addOrUpdateIngress(&ingExes[i])
 		if err != nil {
@@ -244,8 +393,8 @@ func (cnf *Configurator) AddOrUpdateTLSSecret(secret *api_v1.Secret, ingExes []I
 		}
 	}
 
-	if err := cnf.nginxManager.Reload(); err != nil {
-		return fmt.Errorf("Error when reloading NGINX when updating Secret: %v", err)
+	if err := cnf.reload(); err != nil {
+		return newReloadError("Error when reloading NGINX when updating Secret: %v", err)
 	}
 
 	return nil
@@ -265,8 +414,8 @@ func (cnf *Configurator) AddOrUpdateSpecialTLSSecrets(secret *api_v1.Secret, sec
 		cnf.nginxManager.CreateSecret(secretName, data, nginx.TLSSecretFileMode)
 	}
 
-	if err := cnf.nginxManager.Reload(); err != nil {
-		return fmt.Errorf("Error when reloading NGINX when updating the special Secrets: %v", err)
+	if err := cnf.reload(); err != nil {
+		return newReloadError("Error when reloading NGINX when updating the special Secrets: %v", err)
 	}
 
 	return nil
@@ -311,8 +460,8 @@ func (cnf *Configurator) DeleteSecret(key string, ingExes []IngressEx, mergeable
 	}
 
 	if len(ingExes)+len(mergeableIngresses)+len(virtualServerExes) > 0 {
-		if err := cnf.nginxManager.Reload(); err != nil {
-			return fmt.Errorf("Error when reloading NGINX when deleting Secret %v: %v", key, err)
+		if err := cnf.reload(); err != nil {
+			return newReloadError("Error when reloading NGINX when deleting Secret %v: %v", key, err)
 		}
 	}
 
@@ -323,12 +472,14 @@ func (cnf *Configurator) DeleteSecret(key string, ingExes []IngressEx, mergeable
 func (cnf *Configurator) DeleteIngress(key string) error {
 	name := keyToFileName(key)
 	cnf.nginxManager.DeleteConfig(name)
+	cnf.logger.V(3).Info("Deleted config", "kind", "Ingress", "key", key, "file", name)
 
 	delete(cnf.ingresses, name)
 	delete(cnf.minions, name)
+	cnf.deleteMetricsLabels(name)
 
-	if err := cnf.nginxManager.Reload(); err != nil {
-		return fmt.Errorf("Error when removing ingress %v: %v", key, err)
+	if err := cnf.reload(); err != nil {
+		return newReloadError("Error when removing ingress %v: %v", key, err)
 	}
 
 	return nil
@@ -338,9 +489,12 @@ func (cnf *Configurator) DeleteIngress(key string) error {
 func (cnf *Configurator) DeleteVirtualServer(key string) error {
 	name := getFileNameForVirtualServerFromKey(key)
 	cnf.nginxManager.DeleteConfig(name)
+	cnf.logger.V(3).Info("Deleted config", "kind", "VirtualServer", "key", key, "file", name)
+	cnf.deleteTLSPassthroughForVirtualServer(name)
+	cnf.deleteMetricsLabels(name)
 
-	if err := cnf.nginxManager.Reload(); err != nil {
-		return fmt.Errorf("Error when removing VirtualServer %v: %v", key, err)
+	if err := cnf.reload(); err != nil {
+		return newReloadError("Error when removing VirtualServer %v: %v", key, err)
 	}
 
 	return nil
@@ -359,19 +513,19 @@ func (cnf *Configurator) UpdateEndpoints(ingExes []*IngressEx) error {
 		if cnf.isPlus {
 			err := cnf.updatePlusEndpoints(ingEx)
 			if err != nil {
-				glog.Warningf("Couldn't update the endpoints via the API: %v; reloading configuration instead", err)
+				logging.Warningf("Couldn't update the endpoints via the API: %v; reloading configuration instead", err)
 				reloadPlus = true
 			}
 		}
 	}
 
 	if cnf.isPlus && !reloadPlus {
-		glog.V(3).Info("No need to reload nginx")
+		logging.V(3).Info("No need to reload nginx")
 		return nil
 	}
 
-	if err := cnf.nginxManager.Reload(); err != nil {
-		return fmt.Errorf("Error reloading NGINX when updating endpoints: %v", err)
+	if err := cnf.reload(); err != nil {
+		return newReloadError("Error reloading NGINX when updating endpoints: %v", err)
 	}
 
 	return nil
@@ -391,7 +545,7 @@ func (cnf *Configurator) UpdateEndpointsMergeableIngress(mergeableIngresses []*M
 			for _, ing := range mergeableIngresses[i].Minions {
 				err = cnf.updatePlusEndpoints(ing)
 				if err != nil {
-					glog.Warningf("Couldn't update the endpoints via the API: %v; reloading configuration instead", err)
+					logging.Warningf("Couldn't update the endpoints via the API: %v; reloading configuration instead", err)
 					reloadPlus = true
 				}
 			}
@@ -399,12 +553,12 @@ func (cnf *Configurator) UpdateEndpointsMergeableIngress(mergeableIngresses []*M
 	}
 
 	if cnf.isPlus && !reloadPlus {
-		glog.V(3).Info("No need to reload nginx")
+		logging.V(3).Info("No need to reload nginx")
 		return nil
 	}
 
-	if err := cnf.nginxManager.Reload(); err != nil {
-		return fmt.Errorf("Error reloading NGINX when updating endpoints for %v: %v", mergeableIngresses, err)
+	if err := cnf.reload(); err != nil {
+		return newReloadError("Error reloading NGINX when updating endpoints for %v: %v", mergeableIngresses, err)
 	}
 
 	return nil
@@ -424,26 +578,31 @@ func (cnf *Configurator) UpdateEndpointsForVirtualServers(virtualServerExes []*V
 		if cnf.isPlus {
 			err := cnf.updatePlusEndpointsForVirtualServer(vs)
 			if err != nil {
-				glog.Warningf("Couldn't update the endpoints via the API: %v; reloading configuration instead", err)
+				logging.Warningf("Couldn't update the endpoints via the API: %v; reloading configuration instead", err)
+				reloadPlus = true
+			}
+
+			// the endpoints of the TLS Passthrough upstreams are updated only via a reload
+			if cnf.tlsPassthroughVirtualServers[getFileNameForVirtualServer(vs.VirtualServer)] {
 				reloadPlus = true
 			}
 		}
 	}
 
 	if cnf.isPlus && !reloadPlus {
-		glog.V(3).Info("No need to reload nginx")
+		logging.V(3).Info("No need to reload nginx")
 		return nil
 	}
 
-	if err := cnf.nginxManager.Reload(); err != nil {
-		return fmt.Errorf("Error reloading NGINX when updating endpoints: %v", err)
+	if err := cnf.reload(); err != nil {
+		return newReloadError("Error reloading NGINX when updating endpoints: %v", err)
 	}
 
 	return nil
 }
 
 func (cnf *Configurator) updatePlusEndpointsForVirtualServer(virtualServerEx *VirtualServerEx) error {
-	upstreams := createUpstreamsForPlus(virtualServerEx, cnf.cfgParams)
+	upstreams := createUpstreamsForPlus(virtualServerEx, cnf.cfgParams, cnf.staticCfgParams)
 	for _, upstream := range upstreams {
 		serverCfg := createUpstreamServersConfigForPlus(upstream)
 
@@ -472,7 +631,7 @@ func (cnf *Configurator) updatePlusEndpoints(ingEx *IngressEx) error {
 		endps, exists := ingEx.Endpoints[ingEx.Ingress.Spec.Backend.ServiceName+ingEx.Ingress.Spec.Backend.ServicePort.String()]
 		if exists {
 			if _, isExternalName := ingEx.ExternalNameSvcs[ingEx.Ingress.Spec.Backend.ServiceName]; isExternalName {
-				glog.V(3).Infof("Service %s is Type ExternalName, skipping NGINX Plus endpoints update via API", ingEx.Ingress.Spec.Backend.ServiceName)
+				logging.V(3).Infof("Service %s is Type ExternalName, skipping NGINX Plus endpoints update via API", ingEx.Ingress.Spec.Backend.ServiceName)
 			} else {
 				name := getNameForUpstream(ingEx.Ingress, emptyHost, ingEx.Ingress.Spec.Backend)
 				err := cnf.nginxManager.UpdateServersInPlus(name, endps, cfg)
@@ -492,7 +651,7 @@ func (cnf *Configurator) updatePlusEndpoints(ingEx *IngressEx) error {
 			endps, exists := ingEx.Endpoints[path.Backend.ServiceName+path.Backend.ServicePort.String()]
 			if exists {
 				if _, isExternalName := ingEx.ExternalNameSvcs[path.Backend.ServiceName]; isExternalName {
-					glog.V(3).Infof("Service %s is Type ExternalName, skipping NGINX Plus endpoints update via API", path.Backend.ServiceName)
+					logging.V(3).Infof("Service %s is Type ExternalName, skipping NGINX Plus endpoints update via API", path.Backend.ServiceName)
 					continue
 				}
 
@@ -567,8 +726,8 @@ func (cnf *Configurator) UpdateConfig(cfgParams *ConfigParams, ingExes []*Ingres
 	}
 
 	cnf.nginxManager.SetOpenTracing(mainCfg.OpenTracingLoadModule)
-	if err := cnf.nginxManager.Reload(); err != nil {
-		return allWarnings, fmt.Errorf("Error when updating config from ConfigMap: %v", err)
+	if err := cnf.reload(); err != nil {
+		return allWarnings, newReloadError("Error when updating config from ConfigMap: %v", err)
 	}
 
 	return allWarnings, nil
@@ -609,6 +768,45 @@ func (cnf *Configurator) HasMinion(master *extensions.Ingress, minion *extension
 	return cnf.minions[masterName][objectMetaToFileName(&minion.ObjectMeta)]
 }
 
+// ReloadError is the error of a failed reload of NGINX. Unlike the other errors of the Configurator, it is not
+// necessarily caused by the config of the resource that is added, updated or deleted: the reload also fails when
+// the main config or the config of another resource is invalid.
+type ReloadError struct {
+	msg string
+}
+
+func (e *ReloadError) Error() string {
+	return e.msg
+}
+
+func newReloadError(format string, a ...interface{}) error {
+	return &ReloadError{msg: fmt.Sprintf(format, a...)}
+}
+
+// IsReloadError returns true if the error is a ReloadError.
+func IsReloadError(err error) bool {
+	_, ok := err.(*ReloadError)
+	return ok
+}
+
+// reload reloads NGINX and records whether the reload succeeded.
+func (cnf *Configurator) reload() error {
+	err := cnf.nginxManager.Reload()
+	cnf.isLastReloadFailed = err != nil
+	return err
+}
+
+// IsLastReloadSuccessful returns true if the last reload of NGINX succeeded or NGINX hasn't been reloaded yet.
+func (cnf *Configurator) IsLastReloadSuccessful() bool {
+	return !cnf.isLastReloadFailed
+}
+
+// GetWorkerShutdownTimeout returns the timeout for a graceful shutdown of NGINX worker processes
+// from the worker-shutdown-timeout ConfigMap key. If the key is not set, it returns an empty string.
+func (cnf *Configurator) GetWorkerShutdownTimeout() string {
+	return cnf.cfgParams.MainWorkerShutdownTimeout
+}
+
 // IsResolverConfigured checks if a DNS resolver is present in NGINX configuration.
 func (cnf *Configurator) IsResolverConfigured() bool {
 	return len(cnf.cfgParams.ResolverAddresses) != 0
@@ -637,3 +835,142 @@ func (cnf *Configurator) GetIngressCounts() map[string]int {
 
 	return counters
 }
+
+// updateIngressMetricsLabels updates the labels of the NGINX Plus metrics for the upstreams, upstream server peers
+// and server zones of the Ingress resources. The first Ingress resource owns the server zones. The name is the name of
+// the configuration file of the Ingress resources.
+func (cnf *Configurator) updateIngressMetricsLabels(name string, ingExes []*IngressEx) {
+	if !cnf.isPlus {
+		return
+	}
+
+	upstreamLabels := make(map[string][]string)
+	peerLabels := make(map[string][]string)
+	zoneLabels := make(map[string][]string)
+
+	for i, ingEx := range ingExes {
+		ing := ingEx.Ingress
+
+		if ing.Spec.Backend != nil {
+			addIngressBackendMetricsLabels(ingEx, emptyHost, ing.Spec.Backend, upstreamLabels, peerLabels)
+		}
+
+		for _, rule := range ing.Spec.Rules {
+			if rule.IngressRuleValue.HTTP == nil {
+				continue
+			}
+
+			if i == 0 {
+				zoneLabels[rule.Host] = []string{"ingress", ing.Name, ing.Namespace}
+			}
+
+			for _, path := range rule.HTTP.Paths {
+				addIngressBackendMetricsLabels(ingEx, rule.Host, &path.Backend, upstreamLabels, peerLabels)
+			}
+		}
+	}
+
+	cnf.updateMetricsLabels(name, upstreamLabels, peerLabels, zoneLabels)
+}
+
+func addIngressBackendMetricsLabels(ingEx *IngressEx, host string, backend *extensions.IngressBackend, upstreamLabels map[string][]string, peerLabels map[string][]string) {
+	upstreamName := getNameForUpstream(ingEx.Ingress, host, backend)
+	upstreamLabels[upstreamName] = []string{backend.ServiceName, "ingress", ingEx.Ingress.Name, ingEx.Ingress.Namespace}
+
+	for _, endp := range ingEx.Endpoints[backend.ServiceName+backend.ServicePort.String()] {
+		peerLabels[collectors.UpstreamServerPeerKey(upstreamName, endp)] = []string{ingEx.PodsByIP[endp]}
+	}
+}
+
+// updateVirtualServerMetricsLabels updates the labels of the NGINX Plus metrics for the upstreams, upstream server peers
+// and server zones of the VirtualServer and its VirtualServerRoutes. The name is the name of the configuration file of the VirtualServer.
+func (cnf *Configurator) updateVirtualServerMetricsLabels(name string, virtualServerEx *VirtualServerEx) {
+	if !cnf.isPlus {
+		return
+	}
+
+	upstreamLabels := make(map[string][]string)
+	peerLabels := make(map[string][]string)
+
+	vs := virtualServerEx.VirtualServer
+	zoneLabels := map[string][]string{
+		vs.Spec.Host: {"virtualserver", vs.Name, vs.Namespace},
+	}
+
+	upstreamNamer := newUpstreamNamerForVirtualServer(vs)
+	for _, u := range vs.Spec.Upstreams {
+		upstreamName := upstreamNamer.GetNameForUpstream(u.Name)
+		upstreamLabels[upstreamName] = []string{u.Service, "virtualserver", vs.Name, vs.Namespace}
+		addVirtualServerPeerMetricsLabels(virtualServerEx, vs.Namespace, upstreamName, u, peerLabels)
+	}
+
+	for _, vsr := range virtualServerEx.VirtualServerRoutes {
+		upstreamNamer = newUpstreamNamerForVirtualServerRoute(vs, vsr)
+		for _, u := range vsr.Spec.Upstreams {
+			upstreamName := upstreamNamer.GetNameForUpstream(u.Name)
+			upstreamLabels[upstreamName] = []string{u.Service, "virtualserverroute", vsr.Name, vsr.Namespace}
+			addVirtualServerPeerMetricsLabels(virtualServerEx, vsr.Namespace, upstreamName, u, peerLabels)
+		}
+	}
+
+	cnf.updateMetricsLabels(name, upstreamLabels, peerLabels, zoneLabels)
+}
+
+func addVirtualServerPeerMetricsLabels(virtualServerEx *VirtualServerEx, namespace string, upstreamName string, upstream conf_v1alpha1.Upstream, peerLabels map[string][]string) {
+	endpointsKey := GenerateEndpointsKey(namespace, upstream.Service, upstream.Subselector, upstream.Port)
+	for _, endp := range virtualServerEx.Endpoints[endpointsKey] {
+		peerLabels[collectors.UpstreamServerPeerKey(upstreamName, endp)] = []string{virtualServerEx.PodsByIP[endp]}
+	}
+}
+
+// updateMetricsLabels updates the labels of the NGINX Plus metrics for the resource with the given name.
+// The labels of the upstreams, upstream server peers and server zones that the resource no longer has are deleted.
+func (cnf *Configurator) updateMetricsLabels(name string, upstreamLabels map[string][]string, peerLabels map[string][]string, zoneLabels map[string][]string) {
+	cnf.labelUpdater.UpdateUpstreamServerLabels(upstreamLabels)
+	cnf.labelUpdater.UpdateUpstreamServerPeerLabels(peerLabels)
+	cnf.labelUpdater.UpdateServerZoneLabels(name, zoneLabels)
+
+	if old, exists := cnf.metricLabels[name]; exists {
+		cnf.labelUpdater.DeleteUpstreamServerLabels(findRemovedKeys(old.upstreams, upstreamLabels))
+		cnf.labelUpdater.DeleteUpstreamServerPeerLabels(findRemovedKeys(old.peers, peerLabels))
+		cnf.labelUpdater.DeleteServerZoneLabels(name, findRemovedKeys(old.serverZones, zoneLabels))
+	}
+
+	cnf.metricLabels[name] = &metricLabels{
+		upstreams:   getMapKeys(upstreamLabels),
+		peers:       getMapKeys(peerLabels),
+		serverZones: getMapKeys(zoneLabels),
+	}
+}
+
+// deleteMetricsLabels deletes the labels of the NGINX Plus metrics for the resource with the given name.
+func (cnf *Configurator) deleteMetricsLabels(name string) {
+	old, exists := cnf.metricLabels[name]
+	if !exists {
+		return
+	}
+
+	cnf.labelUpdater.DeleteUpstreamServerLabels(old.upstreams)
+	cnf.labelUpdater.DeleteUpstreamServerPeerLabels(old.peers)
+	cnf.labelUpdater.DeleteServerZoneLabels(name, old.serverZones)
+
+	delete(cnf.metricLabels, name)
+}
+
+func findRemovedKeys(oldKeys []string, current map[string][]string) []string {
+	var removed []string
+	for _, key := range oldKeys {
+		if _, exists := current[key]; !exists {
+			removed = append(removed, key)
+		}
+	}
+	return removed
+}
+
+func getMapKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
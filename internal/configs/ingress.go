@@ -5,8 +5,8 @@ import (
 	"sort"
 	"strings"
 
-	"github.com/golang/glog"
 	"github.com/nginxinc/kubernetes-ingress/internal/configs/version1"
+	"github.com/nginxinc/kubernetes-ingress/internal/logging"
 	api_v1 "k8s.io/api/core/v1"
 	extensions "k8s.io/api/extensions/v1beta1"
 )
@@ -18,9 +18,11 @@ type IngressEx struct {
 	Ingress          *extensions.Ingress
 	TLSSecrets       map[string]*api_v1.Secret
 	JWTKey           JWTKey
+	BasicAuthSecret  BasicAuthSecret
 	Endpoints        map[string][]string
 	HealthChecks     map[string]*api_v1.Probe
 	ExternalNameSvcs map[string]bool
+	PodsByIP         map[string]string
 }
 
 // JWTKey represents a secret that holds JSON Web Key.
@@ -29,6 +31,12 @@ type JWTKey struct {
 	Secret *api_v1.Secret
 }
 
+// BasicAuthSecret represents a secret that holds an htpasswd file for HTTP basic authentication.
+type BasicAuthSecret struct {
+	Name   string
+	Secret *api_v1.Secret
+}
+
 func (ingEx *IngressEx) String() string {
 	if ingEx.Ingress == nil {
 		return "IngressEx has no Ingress"
@@ -43,7 +51,8 @@ type MergeableIngresses struct {
 	Minions []*IngressEx
 }
 
-func generateNginxCfg(ingEx *IngressEx, pems map[string]string, isMinion bool, baseCfgParams *ConfigParams, isPlus bool, isResolverConfigured bool, jwtKeyFileName string) version1.IngressNginxConfig {
+func generateNginxCfg(ingEx *IngressEx, pems map[string]string, pemFileNameForMissingTLSSecret string, tlsPassthroughSocket string, isMinion bool, baseCfgParams *ConfigParams, isPlus bool, isResolverConfigured bool, jwtKeyFileName string,
+	basicAuthFileName string) version1.IngressNginxConfig {
 	cfgParams := parseAnnotations(ingEx, baseCfgParams, isPlus)
 	wsServices := getWebsocketServices(ingEx)
 	spServices := getSessionPersistenceServices(ingEx)
@@ -56,10 +65,20 @@ func generateNginxCfg(ingEx *IngressEx, pems map[string]string, isMinion bool, b
 
 	// HTTP2 is required for gRPC to function
 	if len(grpcServices) > 0 && !cfgParams.HTTP2 {
-		glog.Errorf("Ingress %s/%s: annotation nginx.org/grpc-services requires HTTP2, ignoring", ingEx.Ingress.Namespace, ingEx.Ingress.Name)
+		logging.Errorf("Ingress %s/%s: annotation nginx.org/grpc-services requires HTTP2, ignoring", ingEx.Ingress.Namespace, ingEx.Ingress.Name)
 		grpcServices = make(map[string]bool)
 	}
 
+	// keepalive connections to the upstreams and websockets require HTTP/1.1
+	if cfgParams.ProxyHTTPVersion == "1.0" {
+		if cfgParams.Keepalive > 0 {
+			logging.Warningf("Ingress %s/%s: proxy-http-version 1.0 disables the keepalive connections to the upstreams", ingEx.Ingress.Namespace, ingEx.Ingress.Name)
+		}
+		if len(wsServices) > 0 {
+			logging.Warningf("Ingress %s/%s: proxy-http-version 1.0 breaks the websocket connections of nginx.org/websocket-services", ingEx.Ingress.Namespace, ingEx.Ingress.Name)
+		}
+	}
+
 	if ingEx.Ingress.Spec.Backend != nil {
 		name := getNameForUpstream(ingEx.Ingress, emptyHost, ingEx.Ingress.Spec.Backend)
 		upstream := createUpstream(ingEx, name, ingEx.Ingress.Spec.Backend, spServices[ingEx.Ingress.Spec.Backend.ServiceName], &cfgParams,
@@ -73,7 +92,11 @@ func generateNginxCfg(ingEx *IngressEx, pems map[string]string, isMinion bool, b
 		}
 	}
 
+	externalAuth, externalAuthLocation := createExternalAuth(ingEx.Ingress, &cfgParams)
+	cors, corsMaps := createCORS(ingEx.Ingress, &cfgParams)
+
 	var servers []version1.Server
+	var maps []version1.Map
 
 	for _, rule := range ingEx.Ingress.Spec.Rules {
 		if rule.IngressRuleValue.HTTP == nil {
@@ -104,6 +127,8 @@ func generateNginxCfg(ingEx *IngressEx, pems map[string]string, isMinion bool, b
 			ServerSnippets:        cfgParams.ServerSnippets,
 			Ports:                 cfgParams.Ports,
 			SSLPorts:              cfgParams.SSLPorts,
+			TLSPassthroughSocket:  tlsPassthroughSocket,
+			Gzip:                  createGzip(&cfgParams),
 		}
 
 		if pemFile, ok := pems[serverName]; ok {
@@ -131,6 +156,13 @@ func generateNginxCfg(ingEx *IngressEx, pems map[string]string, isMinion bool, b
 			}
 		}
 
+		if !isMinion && basicAuthFileName != "" {
+			server.BasicAuth = &version1.BasicAuth{
+				Realm:    cfgParams.BasicAuthRealm,
+				UserFile: basicAuthFileName,
+			}
+		}
+
 		var locations []version1.Location
 		healthChecks := make(map[string]version1.HealthCheck)
 
@@ -179,6 +211,14 @@ func generateNginxCfg(ingEx *IngressEx, pems map[string]string, isMinion bool, b
 					})
 				}
 			}
+			if isMinion && basicAuthFileName != "" {
+				loc.BasicAuth = &version1.BasicAuth{
+					Realm:    cfgParams.BasicAuthRealm,
+					UserFile: basicAuthFileName,
+				}
+			}
+			loc.ExternalAuth = externalAuth
+			loc.CORS = cors
 			locations = append(locations, loc)
 
 			if loc.Path == "/" {
@@ -191,6 +231,8 @@ func generateNginxCfg(ingEx *IngressEx, pems map[string]string, isMinion bool, b
 
 			loc := createLocation(pathOrDefault("/"), upstreams[upsName], &cfgParams, wsServices[ingEx.Ingress.Spec.Backend.ServiceName], rewrites[ingEx.Ingress.Spec.Backend.ServiceName],
 				sslServices[ingEx.Ingress.Spec.Backend.ServiceName], grpcServices[ingEx.Ingress.Spec.Backend.ServiceName])
+			loc.ExternalAuth = externalAuth
+			loc.CORS = cors
 			locations = append(locations, loc)
 
 			if cfgParams.HealthCheckEnabled {
@@ -204,6 +246,14 @@ func generateNginxCfg(ingEx *IngressEx, pems map[string]string, isMinion bool, b
 			}
 		}
 
+		if externalAuthLocation != nil && len(locations) > 0 {
+			server.ExternalAuthLocations = append(server.ExternalAuthLocations, *externalAuthLocation)
+		}
+
+		if len(locations) > 0 && len(maps) == 0 {
+			maps = corsMaps
+		}
+
 		server.Locations = locations
 		server.HealthChecks = healthChecks
 		server.GRPCOnly = grpcOnly
@@ -219,6 +269,7 @@ func generateNginxCfg(ingEx *IngressEx, pems map[string]string, isMinion bool, b
 	return version1.IngressNginxConfig{
 		Upstreams: upstreamMapToSlice(upstreams),
 		Servers:   servers,
+		Maps:      maps,
 		Keepalive: keepalive,
 		Ingress: version1.Ingress{
 			Name:        ingEx.Ingress.Name,
@@ -230,26 +281,63 @@ func generateNginxCfg(ingEx *IngressEx, pems map[string]string, isMinion bool, b
 
 func createLocation(path string, upstream version1.Upstream, cfg *ConfigParams, websocket bool, rewrite string, ssl bool, grpc bool) version1.Location {
 	loc := version1.Location{
-		Path:                 path,
-		Upstream:             upstream,
-		ProxyConnectTimeout:  cfg.ProxyConnectTimeout,
-		ProxyReadTimeout:     cfg.ProxyReadTimeout,
-		ProxySendTimeout:     cfg.ProxySendTimeout,
-		ClientMaxBodySize:    cfg.ClientMaxBodySize,
-		Websocket:            websocket,
-		Rewrite:              rewrite,
-		SSL:                  ssl,
-		GRPC:                 grpc,
-		ProxyBuffering:       cfg.ProxyBuffering,
-		ProxyBuffers:         cfg.ProxyBuffers,
-		ProxyBufferSize:      cfg.ProxyBufferSize,
-		ProxyMaxTempFileSize: cfg.ProxyMaxTempFileSize,
-		LocationSnippets:     cfg.LocationSnippets,
+		Path:                   path,
+		Upstream:               upstream,
+		ProxyConnectTimeout:    cfg.ProxyConnectTimeout,
+		ProxyReadTimeout:       cfg.ProxyReadTimeout,
+		ProxySendTimeout:       cfg.ProxySendTimeout,
+		ClientMaxBodySize:      cfg.ClientMaxBodySize,
+		Websocket:              websocket,
+		Rewrite:                rewrite,
+		SSL:                    ssl,
+		GRPC:                   grpc,
+		ProxyBuffering:         cfg.ProxyBuffering,
+		ProxyBuffers:           cfg.ProxyBuffers,
+		ProxyBufferSize:        cfg.ProxyBufferSize,
+		ProxyMaxTempFileSize:   cfg.ProxyMaxTempFileSize,
+		ProxyRequestBuffering:  cfg.ProxyRequestBuffering,
+		ProxyHTTPVersion:       cfg.ProxyHTTPVersion,
+		ProxyIgnoreClientAbort: cfg.ProxyIgnoreClientAbort,
+		ClientBodyBufferSize:   cfg.ClientBodyBufferSize,
+		ClientBodyTimeout:      cfg.ClientBodyTimeout,
+		LocationSnippets:       cfg.LocationSnippets,
+		ProxyCache:             createProxyCache(cfg),
 	}
 
 	return loc
 }
 
+// createProxyCache creates the caching configuration of a location. It returns nil if caching is not configured.
+func createProxyCache(cfg *ConfigParams) *version1.ProxyCache {
+	if cfg.ProxyCacheZone == "" {
+		return nil
+	}
+
+	return &version1.ProxyCache{
+		Zone:         cfg.ProxyCacheZone,
+		Valid:        cfg.ProxyCacheValid,
+		Methods:      strings.Join(cfg.ProxyCacheMethods, " "),
+		Key:          cfg.ProxyCacheKey,
+		Bypass:       strings.Join(cfg.ProxyCacheBypass, " "),
+		UseStale:     strings.Join(cfg.ProxyCacheUseStale, " "),
+		StatusHeader: cfg.ProxyCacheStatusHeader,
+	}
+}
+
+// createGzip creates the gzip compression configuration of a server. It returns nil if gzip is not enabled.
+func createGzip(cfg *ConfigParams) *version1.Gzip {
+	if !cfg.Gzip {
+		return nil
+	}
+
+	return &version1.Gzip{
+		Types:     strings.Join(cfg.GzipTypes, " "),
+		MinLength: cfg.GzipMinLength,
+		CompLevel: cfg.GzipCompLevel,
+		Proxied:   strings.Join(cfg.GzipProxied, " "),
+	}
+}
+
 // upstreamRequiresQueue checks if the upstream requires a queue.
 // Mandatory Health Checks can cause nginx to return errors on reload, since all Upstreams start
 // Unhealthy. By adding a queue to the Upstream we can avoid returning errors, at the cost of a short delay.
@@ -279,7 +367,7 @@ func createUpstream(ingEx *IngressEx, name string, backend *extensions.IngressBa
 		// Always false for NGINX OSS
 		_, isExternalNameSvc := ingEx.ExternalNameSvcs[backend.ServiceName]
 		if isExternalNameSvc && !isResolverConfigured {
-			glog.Warningf("A resolver must be configured for Type ExternalName service %s, no upstream servers will be created", backend.ServiceName)
+			logging.Warningf("A resolver must be configured for Type ExternalName service %s, no upstream servers will be created", backend.ServiceName)
 			endps = []string{}
 		}
 
@@ -342,6 +430,142 @@ func getNameForRedirectLocation(ing *extensions.Ingress) string {
 	return fmt.Sprintf("@login_url_%v-%v", ing.Namespace, ing.Name)
 }
 
+func getNameForExternalAuthLocation(ing *extensions.Ingress) string {
+	return fmt.Sprintf("/_external_auth_%v-%v", ing.Namespace, ing.Name)
+}
+
+func getNameForExternalAuthSigninLocation(ing *extensions.Ingress) string {
+	return fmt.Sprintf("@external_auth_signin_%v-%v", ing.Namespace, ing.Name)
+}
+
+// createExternalAuth creates the external authentication configuration for the locations of the Ingress along with
+// the internal location for the authentication subrequests. It returns nil values if external authentication is not
+// configured.
+func createExternalAuth(ing *extensions.Ingress, cfg *ConfigParams) (*version1.ExternalAuth, *version1.ExternalAuthLocation) {
+	if cfg.ExternalAuthURL == "" {
+		return nil, nil
+	}
+
+	auth := &version1.ExternalAuth{
+		LocationName: getNameForExternalAuthLocation(ing),
+	}
+	for _, name := range cfg.ExternalAuthResponseHeaders {
+		variable, upstreamVariable := getVariablesForExternalAuthResponseHeader(name)
+		auth.ResponseHeaders = append(auth.ResponseHeaders, version1.ExternalAuthResponseHeader{
+			Name:             name,
+			Variable:         variable,
+			UpstreamVariable: upstreamVariable,
+		})
+	}
+
+	location := &version1.ExternalAuthLocation{
+		Name: auth.LocationName,
+		URL:  cfg.ExternalAuthURL,
+	}
+
+	if cfg.ExternalAuthSignin != "" {
+		auth.SigninLocationName = getNameForExternalAuthSigninLocation(ing)
+		location.SigninLocationName = auth.SigninLocationName
+		location.SigninURL = cfg.ExternalAuthSignin
+	}
+
+	return auth, location
+}
+
+// getVariablesForExternalAuthResponseHeader returns the variable that holds the value of a header of the response of
+// an external authentication service along with the NGINX variable of that header.
+func getVariablesForExternalAuthResponseHeader(name string) (variable string, upstreamVariable string) {
+	suffix := strings.ToLower(strings.Replace(name, "-", "_", -1))
+	return "$external_auth_" + suffix, "$upstream_http_" + suffix
+}
+
+// getNameForCORSVariable returns the name of the NGINX variable with the suffix for the CORS configuration of the Ingress.
+func getNameForCORSVariable(ing *extensions.Ingress, suffix string) string {
+	// the name of an Ingress can include dots, which are not allowed in the names of variables
+	safeNsName := strings.NewReplacer("-", "_", ".", "_").Replace(fmt.Sprintf("%s_%s", ing.Namespace, ing.Name))
+	return fmt.Sprintf("$ingress_%s_cors_%s", safeNsName, suffix)
+}
+
+// createCORS creates the CORS configuration for the locations of the Ingress along with the maps for the preflight
+// requests and the allowed origins. It returns nil values if CORS is not configured.
+func createCORS(ing *extensions.Ingress, cfg *ConfigParams) (*version1.CORS, []version1.Map) {
+	if len(cfg.CORSAllowOrigin) == 0 {
+		return nil, nil
+	}
+
+	cors := &version1.CORS{
+		AllowOrigin:       "*",
+		AllowMethods:      strings.Join(cfg.CORSAllowMethods, ", "),
+		AllowHeaders:      strings.Join(cfg.CORSAllowHeaders, ", "),
+		AllowCredentials:  cfg.CORSAllowCredentials,
+		MaxAge:            cfg.CORSMaxAge,
+		ExposeHeaders:     strings.Join(cfg.CORSExposeHeaders, ", "),
+		PreflightVariable: getNameForCORSVariable(ing, "preflight"),
+	}
+
+	maps := []version1.Map{
+		{
+			Source:   corsPreflightMapSource,
+			Variable: cors.PreflightVariable,
+			Parameters: []version1.Parameter{
+				{
+					Value:  corsPreflightMapValue,
+					Result: "1",
+				},
+				{
+					Value:  "default",
+					Result: "0",
+				},
+			},
+		},
+	}
+
+	if !isCORSAnyOrigin(cfg.CORSAllowOrigin) {
+		cors.AllowOrigin = getNameForCORSVariable(ing, "origin")
+
+		originMap := version1.Map{
+			Source:   "$http_origin",
+			Variable: cors.AllowOrigin,
+		}
+		for _, value := range generateCORSOriginMapValues(cfg.CORSAllowOrigin) {
+			originMap.Parameters = append(originMap.Parameters, version1.Parameter{
+				Value:  value,
+				Result: "$http_origin",
+			})
+		}
+		originMap.Parameters = append(originMap.Parameters, version1.Parameter{
+			Value:  "default",
+			Result: `""`,
+		})
+
+		maps = append(maps, originMap)
+	}
+
+	return cors, maps
+}
+
+// corsPreflightMapSource and corsPreflightMapValue match CORS preflight requests: the OPTIONS requests
+// with the Access-Control-Request-Method header.
+const (
+	corsPreflightMapSource = `"$request_method:$http_access_control_request_method"`
+	corsPreflightMapValue  = `"~^OPTIONS:.+"`
+)
+
+func isCORSAnyOrigin(origins []string) bool {
+	return len(origins) == 1 && origins[0] == "*"
+}
+
+// generateCORSOriginMapValues generates the values for matching the allowed origins in an NGINX map.
+// The origins must be validated.
+func generateCORSOriginMapValues(origins []string) []string {
+	var values []string
+	for _, origin := range origins {
+		value, _ := ParseCORSOrigin(origin)
+		values = append(values, value)
+	}
+	return values
+}
+
 func upstreamMapToSlice(upstreams map[string]version1.Upstream) []version1.Upstream {
 	keys := make([]string, 0, len(upstreams))
 	for k := range upstreams {
@@ -362,22 +586,23 @@ func upstreamMapToSlice(upstreams map[string]version1.Upstream) []version1.Upstr
 	return result
 }
 
-func generateNginxCfgForMergeableIngresses(mergeableIngs *MergeableIngresses, masterPems map[string]string, masterJwtKeyFileName string,
-	minionJwtKeyFileNames map[string]string, baseCfgParams *ConfigParams, isPlus bool, isResolverConfigured bool) version1.IngressNginxConfig {
+func generateNginxCfgForMergeableIngresses(mergeableIngs *MergeableIngresses, masterPems map[string]string, pemFileNameForMissingTLSSecret string, tlsPassthroughSocket string, masterJwtKeyFileName string,
+	minionJwtKeyFileNames map[string]string, masterBasicAuthFileName string, minionBasicAuthFileNames map[string]string, baseCfgParams *ConfigParams, isPlus bool, isResolverConfigured bool) version1.IngressNginxConfig {
 	var masterServer version1.Server
 	var locations []version1.Location
 	var upstreams []version1.Upstream
+	var maps []version1.Map
 	healthChecks := make(map[string]version1.HealthCheck)
 	var keepalive string
 
 	removedAnnotations := filterMasterAnnotations(mergeableIngs.Master.Ingress.Annotations)
 	if len(removedAnnotations) != 0 {
-		glog.Errorf("Ingress Resource %v/%v with the annotation 'nginx.org/mergeable-ingress-type' set to 'master' cannot contain the '%v' annotation(s). They will be ignored",
+		logging.Errorf("Ingress Resource %v/%v with the annotation 'nginx.org/mergeable-ingress-type' set to 'master' cannot contain the '%v' annotation(s). They will be ignored",
 			mergeableIngs.Master.Ingress.Namespace, mergeableIngs.Master.Ingress.Name, strings.Join(removedAnnotations, ","))
 	}
 
 	isMinion := false
-	masterNginxCfg := generateNginxCfg(mergeableIngs.Master, masterPems, isMinion, baseCfgParams, isPlus, isResolverConfigured, masterJwtKeyFileName)
+	masterNginxCfg := generateNginxCfg(mergeableIngs.Master, masterPems, pemFileNameForMissingTLSSecret, tlsPassthroughSocket, isMinion, baseCfgParams, isPlus, isResolverConfigured, masterJwtKeyFileName, masterBasicAuthFileName)
 
 	masterServer = masterNginxCfg.Servers[0]
 	masterServer.Locations = []version1.Location{}
@@ -398,14 +623,16 @@ func generateNginxCfgForMergeableIngresses(mergeableIngs *MergeableIngresses, ma
 
 		removedAnnotations = filterMinionAnnotations(minion.Ingress.Annotations)
 		if len(removedAnnotations) != 0 {
-			glog.Errorf("Ingress Resource %v/%v with the annotation 'nginx.org/mergeable-ingress-type' set to 'minion' cannot contain the %v annotation(s). They will be ignored",
+			logging.Errorf("Ingress Resource %v/%v with the annotation 'nginx.org/mergeable-ingress-type' set to 'minion' cannot contain the %v annotation(s). They will be ignored",
 				minion.Ingress.Namespace, minion.Ingress.Name, strings.Join(removedAnnotations, ","))
 		}
 
 		pems := make(map[string]string)
 		jwtKeyFileName := minionJwtKeyFileNames[objectMetaToFileName(&minion.Ingress.ObjectMeta)]
+		basicAuthFileName := minionBasicAuthFileNames[objectMetaToFileName(&minion.Ingress.ObjectMeta)]
 		isMinion := true
-		nginxCfg := generateNginxCfg(minion, pems, isMinion, baseCfgParams, isPlus, isResolverConfigured, jwtKeyFileName)
+		nginxCfg := generateNginxCfg(minion, pems, pemFileNameForMissingTLSSecret, tlsPassthroughSocket, isMinion, baseCfgParams, isPlus, isResolverConfigured, jwtKeyFileName,
+			basicAuthFileName)
 
 		for _, server := range nginxCfg.Servers {
 			for _, loc := range server.Locations {
@@ -416,9 +643,11 @@ func generateNginxCfgForMergeableIngresses(mergeableIngs *MergeableIngresses, ma
 				healthChecks[hcName] = healthCheck
 			}
 			masterServer.JWTRedirectLocations = append(masterServer.JWTRedirectLocations, server.JWTRedirectLocations...)
+			masterServer.ExternalAuthLocations = append(masterServer.ExternalAuthLocations, server.ExternalAuthLocations...)
 		}
 
 		upstreams = append(upstreams, nginxCfg.Upstreams...)
+		maps = append(maps, nginxCfg.Maps...)
 	}
 
 	masterServer.HealthChecks = healthChecks
@@ -427,6 +656,7 @@ func generateNginxCfgForMergeableIngresses(mergeableIngs *MergeableIngresses, ma
 	return version1.IngressNginxConfig{
 		Servers:   []version1.Server{masterServer},
 		Upstreams: upstreams,
+		Maps:      maps,
 		Keepalive: keepalive,
 		Ingress:   masterNginxCfg.Ingress,
 	}
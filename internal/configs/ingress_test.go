@@ -21,7 +21,7 @@ func TestGenerateNginxCfg(t *testing.T) {
 		"cafe.example.com": "/etc/nginx/secrets/default-cafe-secret",
 	}
 
-	result := generateNginxCfg(&cafeIngressEx, pems, false, configParams, false, false, "")
+	result := generateNginxCfg(&cafeIngressEx, pems, "/etc/nginx/secrets/default", "", false, configParams, false, false, "", "")
 
 	if !reflect.DeepEqual(result, expected) {
 		t.Errorf("generateNginxCfg returned \n%v,  but expected \n%v", result, expected)
@@ -62,7 +62,7 @@ func TestGenerateNginxCfgForJWT(t *testing.T) {
 		"cafe.example.com": "/etc/nginx/secrets/default-cafe-secret",
 	}
 
-	result := generateNginxCfg(&cafeIngressEx, pems, false, configParams, true, false, "/etc/nginx/secrets/default-cafe-jwk")
+	result := generateNginxCfg(&cafeIngressEx, pems, "/etc/nginx/secrets/default", "", false, configParams, true, false, "/etc/nginx/secrets/default-cafe-jwk", "")
 
 	if !reflect.DeepEqual(result.Servers[0].JWTAuth, expected.Servers[0].JWTAuth) {
 		t.Errorf("generateNginxCfg returned \n%v,  but expected \n%v", result.Servers[0].JWTAuth, expected.Servers[0].JWTAuth)
@@ -72,14 +72,348 @@ func TestGenerateNginxCfgForJWT(t *testing.T) {
 	}
 }
 
+func TestGenerateNginxCfgForExternalAuth(t *testing.T) {
+	cafeIngressEx := createCafeIngressEx()
+	cafeIngressEx.Ingress.Annotations["nginx.org/auth-url"] = "http://oauth2-proxy.default.svc.cluster.local/oauth2/auth"
+	cafeIngressEx.Ingress.Annotations["nginx.org/auth-response-headers"] = "X-Auth-Request-User,X-Auth-Request-Email"
+	cafeIngressEx.Ingress.Annotations["nginx.org/auth-signin"] = "https://cafe.example.com/oauth2/start?rd=$escaped_request_uri"
+
+	configParams := NewDefaultConfigParams()
+
+	expectedAuth := &version1.ExternalAuth{
+		LocationName:       "/_external_auth_default-cafe-ingress",
+		SigninLocationName: "@external_auth_signin_default-cafe-ingress",
+		ResponseHeaders: []version1.ExternalAuthResponseHeader{
+			{
+				Name:             "X-Auth-Request-User",
+				Variable:         "$external_auth_x_auth_request_user",
+				UpstreamVariable: "$upstream_http_x_auth_request_user",
+			},
+			{
+				Name:             "X-Auth-Request-Email",
+				Variable:         "$external_auth_x_auth_request_email",
+				UpstreamVariable: "$upstream_http_x_auth_request_email",
+			},
+		},
+	}
+	expectedLocations := []version1.ExternalAuthLocation{
+		{
+			Name:               "/_external_auth_default-cafe-ingress",
+			URL:                "http://oauth2-proxy.default.svc.cluster.local/oauth2/auth",
+			SigninLocationName: "@external_auth_signin_default-cafe-ingress",
+			SigninURL:          "https://cafe.example.com/oauth2/start?rd=$escaped_request_uri",
+		},
+	}
+
+	pems := map[string]string{
+		"cafe.example.com": "/etc/nginx/secrets/default-cafe-secret",
+	}
+
+	result := generateNginxCfg(&cafeIngressEx, pems, "/etc/nginx/secrets/default", "", false, configParams, false, false, "", "")
+
+	for _, loc := range result.Servers[0].Locations {
+		if !reflect.DeepEqual(loc.ExternalAuth, expectedAuth) {
+			t.Errorf("generateNginxCfg returned \n%v for location %v,  but expected \n%v", loc.ExternalAuth, loc.Path, expectedAuth)
+		}
+	}
+	if !reflect.DeepEqual(result.Servers[0].ExternalAuthLocations, expectedLocations) {
+		t.Errorf("generateNginxCfg returned \n%v,  but expected \n%v", result.Servers[0].ExternalAuthLocations, expectedLocations)
+	}
+}
+
+func TestGenerateNginxCfgForBasicAuth(t *testing.T) {
+	cafeIngressEx := createCafeIngressEx()
+	cafeIngressEx.Ingress.Annotations["nginx.org/basic-auth-secret"] = "cafe-htpasswd"
+	cafeIngressEx.Ingress.Annotations["nginx.org/basic-auth-realm"] = "Cafe App"
+
+	configParams := NewDefaultConfigParams()
+
+	expected := &version1.BasicAuth{
+		Realm:    "Cafe App",
+		UserFile: "/etc/nginx/secrets/default-cafe-htpasswd",
+	}
+
+	pems := map[string]string{
+		"cafe.example.com": "/etc/nginx/secrets/default-cafe-secret",
+	}
+
+	result := generateNginxCfg(&cafeIngressEx, pems, "/etc/nginx/secrets/default", "", false, configParams, false, false, "", "/etc/nginx/secrets/default-cafe-htpasswd")
+
+	if !reflect.DeepEqual(result.Servers[0].BasicAuth, expected) {
+		t.Errorf("generateNginxCfg returned \n%v,  but expected \n%v", result.Servers[0].BasicAuth, expected)
+	}
+	for _, loc := range result.Servers[0].Locations {
+		if loc.BasicAuth != nil {
+			t.Errorf("generateNginxCfg returned basic auth %v for location %v but expected it only at the server level", loc.BasicAuth, loc.Path)
+		}
+	}
+}
+
+func TestGenerateNginxCfgForCORS(t *testing.T) {
+	cafeIngressEx := createCafeIngressEx()
+	cafeIngressEx.Ingress.Annotations["nginx.org/cors-allow-origin"] = "https://example.com, https://*.example.com"
+	cafeIngressEx.Ingress.Annotations["nginx.org/cors-allow-methods"] = "GET, POST"
+	cafeIngressEx.Ingress.Annotations["nginx.org/cors-allow-credentials"] = "true"
+	cafeIngressEx.Ingress.Annotations["nginx.org/cors-expose-headers"] = "X-Request-ID"
+
+	configParams := NewDefaultConfigParams()
+
+	expectedCORS := &version1.CORS{
+		AllowOrigin:       "$ingress_default_cafe_ingress_cors_origin",
+		AllowMethods:      "GET, POST",
+		AllowHeaders:      "DNT, Keep-Alive, User-Agent, X-Requested-With, If-Modified-Since, Cache-Control, Content-Type, Range, Authorization",
+		AllowCredentials:  true,
+		MaxAge:            86400,
+		ExposeHeaders:     "X-Request-ID",
+		PreflightVariable: "$ingress_default_cafe_ingress_cors_preflight",
+	}
+	expectedMaps := []version1.Map{
+		{
+			Source:   `"$request_method:$http_access_control_request_method"`,
+			Variable: "$ingress_default_cafe_ingress_cors_preflight",
+			Parameters: []version1.Parameter{
+				{
+					Value:  `"~^OPTIONS:.+"`,
+					Result: "1",
+				},
+				{
+					Value:  "default",
+					Result: "0",
+				},
+			},
+		},
+		{
+			Source:   "$http_origin",
+			Variable: "$ingress_default_cafe_ingress_cors_origin",
+			Parameters: []version1.Parameter{
+				{
+					Value:  `"https://example.com"`,
+					Result: "$http_origin",
+				},
+				{
+					Value:  `"~^https://[a-z0-9-]+(\.[a-z0-9-]+)*\.example\.com$"`,
+					Result: "$http_origin",
+				},
+				{
+					Value:  "default",
+					Result: `""`,
+				},
+			},
+		},
+	}
+
+	pems := map[string]string{
+		"cafe.example.com": "/etc/nginx/secrets/default-cafe-secret",
+	}
+
+	result := generateNginxCfg(&cafeIngressEx, pems, "/etc/nginx/secrets/default", "", false, configParams, false, false, "", "")
+
+	if !reflect.DeepEqual(result.Maps, expectedMaps) {
+		t.Errorf("generateNginxCfg returned maps \n%+v,  but expected \n%+v", result.Maps, expectedMaps)
+	}
+	for _, loc := range result.Servers[0].Locations {
+		if !reflect.DeepEqual(loc.CORS, expectedCORS) {
+			t.Errorf("generateNginxCfg returned CORS %+v for location %v but expected %+v", loc.CORS, loc.Path, expectedCORS)
+		}
+	}
+}
+
+func TestGenerateNginxCfgForCORSWithAnyOrigin(t *testing.T) {
+	cafeIngressEx := createCafeIngressEx()
+	cafeIngressEx.Ingress.Annotations["nginx.org/cors-allow-origin"] = "*"
+
+	configParams := NewDefaultConfigParams()
+
+	pems := map[string]string{
+		"cafe.example.com": "/etc/nginx/secrets/default-cafe-secret",
+	}
+
+	result := generateNginxCfg(&cafeIngressEx, pems, "/etc/nginx/secrets/default", "", false, configParams, false, false, "", "")
+
+	if len(result.Maps) != 1 {
+		t.Errorf("generateNginxCfg returned %d maps but expected only the map for preflight requests", len(result.Maps))
+	}
+	for _, loc := range result.Servers[0].Locations {
+		if loc.CORS == nil || loc.CORS.AllowOrigin != "*" {
+			t.Errorf("generateNginxCfg returned CORS %+v for location %v but expected the `*` origin", loc.CORS, loc.Path)
+		}
+	}
+}
+
+func TestGenerateNginxCfgForProxyCache(t *testing.T) {
+	cafeIngressEx := createCafeIngressEx()
+	cafeIngressEx.Ingress.Annotations["nginx.org/proxy-cache-zone"] = "cafe"
+	cafeIngressEx.Ingress.Annotations["nginx.org/proxy-cache-valid"] = "200 302 10m, 404 1m"
+	cafeIngressEx.Ingress.Annotations["nginx.org/proxy-cache-methods"] = "GET, HEAD"
+	cafeIngressEx.Ingress.Annotations["nginx.org/proxy-cache-key"] = "$scheme$host$request_uri"
+	cafeIngressEx.Ingress.Annotations["nginx.org/proxy-cache-bypass"] = "$http_pragma, $cookie_nocache"
+	cafeIngressEx.Ingress.Annotations["nginx.org/proxy-cache-use-stale"] = "error, timeout, updating"
+	cafeIngressEx.Ingress.Annotations["nginx.org/proxy-cache-status-header"] = "true"
+
+	configParams := NewDefaultConfigParams()
+	configParams.MainProxyCacheZones = []ProxyCacheZone{
+		{
+			Name: "cafe",
+			Size: "10m",
+		},
+	}
+
+	expected := &version1.ProxyCache{
+		Zone:         "cafe",
+		Valid:        []string{"200 302 10m", "404 1m"},
+		Methods:      "GET HEAD",
+		Key:          "$scheme$host$request_uri",
+		Bypass:       "$http_pragma $cookie_nocache",
+		UseStale:     "error timeout updating",
+		StatusHeader: true,
+	}
+
+	pems := map[string]string{
+		"cafe.example.com": "/etc/nginx/secrets/default-cafe-secret",
+	}
+
+	result := generateNginxCfg(&cafeIngressEx, pems, "/etc/nginx/secrets/default", "", false, configParams, false, false, "", "")
+
+	for _, loc := range result.Servers[0].Locations {
+		if !reflect.DeepEqual(loc.ProxyCache, expected) {
+			t.Errorf("generateNginxCfg returned proxy cache %+v for location %v but expected %+v", loc.ProxyCache, loc.Path, expected)
+		}
+	}
+}
+
+func TestGenerateNginxCfgForProxyCacheWithUndeclaredZone(t *testing.T) {
+	cafeIngressEx := createCafeIngressEx()
+	cafeIngressEx.Ingress.Annotations["nginx.org/proxy-cache-zone"] = "cafe"
+
+	configParams := NewDefaultConfigParams()
+
+	pems := map[string]string{
+		"cafe.example.com": "/etc/nginx/secrets/default-cafe-secret",
+	}
+
+	result := generateNginxCfg(&cafeIngressEx, pems, "/etc/nginx/secrets/default", "", false, configParams, false, false, "", "")
+
+	for _, loc := range result.Servers[0].Locations {
+		if loc.ProxyCache != nil {
+			t.Errorf("generateNginxCfg returned proxy cache %+v for location %v but expected no caching for an undeclared zone", loc.ProxyCache, loc.Path)
+		}
+	}
+}
+
+func TestGenerateNginxCfgForRequestBuffering(t *testing.T) {
+	cafeIngressEx := createCafeIngressEx()
+	cafeIngressEx.Ingress.Annotations["nginx.org/proxy-request-buffering"] = "False"
+	cafeIngressEx.Ingress.Annotations["nginx.org/proxy-http-version"] = "1.0"
+	cafeIngressEx.Ingress.Annotations["nginx.org/proxy-ignore-client-abort"] = "True"
+	cafeIngressEx.Ingress.Annotations["nginx.org/client-body-buffer-size"] = "16k"
+	cafeIngressEx.Ingress.Annotations["nginx.org/client-body-timeout"] = "120s"
+
+	configParams := NewDefaultConfigParams()
+
+	pems := map[string]string{
+		"cafe.example.com": "/etc/nginx/secrets/default-cafe-secret",
+	}
+
+	result := generateNginxCfg(&cafeIngressEx, pems, "/etc/nginx/secrets/default", "", false, configParams, false, false, "", "")
+
+	for _, loc := range result.Servers[0].Locations {
+		if loc.ProxyRequestBuffering {
+			t.Errorf("generateNginxCfg returned ProxyRequestBuffering true for location %s but expected false", loc.Path)
+		}
+		if loc.ProxyHTTPVersion != "1.0" {
+			t.Errorf("generateNginxCfg returned ProxyHTTPVersion %q for location %s but expected %q", loc.ProxyHTTPVersion, loc.Path, "1.0")
+		}
+		if !loc.ProxyIgnoreClientAbort {
+			t.Errorf("generateNginxCfg returned ProxyIgnoreClientAbort false for location %s but expected true", loc.Path)
+		}
+		if loc.ClientBodyBufferSize != "16k" {
+			t.Errorf("generateNginxCfg returned ClientBodyBufferSize %q for location %s but expected %q", loc.ClientBodyBufferSize, loc.Path, "16k")
+		}
+		if loc.ClientBodyTimeout != "120s" {
+			t.Errorf("generateNginxCfg returned ClientBodyTimeout %q for location %s but expected %q", loc.ClientBodyTimeout, loc.Path, "120s")
+		}
+	}
+}
+
+func TestGenerateNginxCfgWithInvalidProxyHTTPVersion(t *testing.T) {
+	cafeIngressEx := createCafeIngressEx()
+	cafeIngressEx.Ingress.Annotations["nginx.org/proxy-http-version"] = "2.0"
+	cafeIngressEx.Ingress.Annotations["nginx.org/client-body-timeout"] = "two minutes"
+
+	configParams := NewDefaultConfigParams()
+
+	pems := map[string]string{
+		"cafe.example.com": "/etc/nginx/secrets/default-cafe-secret",
+	}
+
+	result := generateNginxCfg(&cafeIngressEx, pems, "/etc/nginx/secrets/default", "", false, configParams, false, false, "", "")
+
+	for _, loc := range result.Servers[0].Locations {
+		if loc.ProxyHTTPVersion != "1.1" {
+			t.Errorf("generateNginxCfg returned ProxyHTTPVersion %q for location %s but expected the default %q", loc.ProxyHTTPVersion, loc.Path, "1.1")
+		}
+		if loc.ClientBodyTimeout != "" {
+			t.Errorf("generateNginxCfg returned ClientBodyTimeout %q for location %s but expected it to be empty", loc.ClientBodyTimeout, loc.Path)
+		}
+	}
+}
+
+func TestGenerateNginxCfgForGzip(t *testing.T) {
+	cafeIngressEx := createCafeIngressEx()
+	cafeIngressEx.Ingress.Annotations["nginx.org/gzip"] = "True"
+	cafeIngressEx.Ingress.Annotations["nginx.org/gzip-types"] = "text/css, application/json"
+	cafeIngressEx.Ingress.Annotations["nginx.org/gzip-min-length"] = "1000"
+	cafeIngressEx.Ingress.Annotations["nginx.org/gzip-comp-level"] = "5"
+	cafeIngressEx.Ingress.Annotations["nginx.org/gzip-proxied"] = "expired, no-cache"
+
+	configParams := NewDefaultConfigParams()
+
+	pems := map[string]string{
+		"cafe.example.com": "/etc/nginx/secrets/default-cafe-secret",
+	}
+
+	expected := &version1.Gzip{
+		Types:     "text/css application/json",
+		MinLength: 1000,
+		CompLevel: 5,
+		Proxied:   "expired no-cache",
+	}
+
+	result := generateNginxCfg(&cafeIngressEx, pems, "/etc/nginx/secrets/default", "", false, configParams, false, false, "", "")
+	if !reflect.DeepEqual(result.Servers[0].Gzip, expected) {
+		t.Errorf("generateNginxCfg returned Gzip %+v but expected %+v", result.Servers[0].Gzip, expected)
+	}
+}
+
+func TestGenerateNginxCfgWithInvalidGzip(t *testing.T) {
+	cafeIngressEx := createCafeIngressEx()
+	cafeIngressEx.Ingress.Annotations["nginx.org/gzip"] = "True"
+	cafeIngressEx.Ingress.Annotations["nginx.org/gzip-types"] = "text/css, unknown/type"
+	cafeIngressEx.Ingress.Annotations["nginx.org/gzip-comp-level"] = "10"
+
+	configParams := NewDefaultConfigParams()
+
+	pems := map[string]string{
+		"cafe.example.com": "/etc/nginx/secrets/default-cafe-secret",
+	}
+
+	expected := &version1.Gzip{}
+
+	result := generateNginxCfg(&cafeIngressEx, pems, "/etc/nginx/secrets/default", "", false, configParams, false, false, "", "")
+	if !reflect.DeepEqual(result.Servers[0].Gzip, expected) {
+		t.Errorf("generateNginxCfg returned Gzip %+v but expected %+v", result.Servers[0].Gzip, expected)
+	}
+}
+
 func TestGenerateNginxCfgWithMissingTLSSecret(t *testing.T) {
 	cafeIngressEx := createCafeIngressEx()
 	configParams := NewDefaultConfigParams()
+	pemFileNameForMissingTLSSecret := "/etc/nginx/secrets/default"
 	pems := map[string]string{
 		"cafe.example.com": pemFileNameForMissingTLSSecret,
 	}
 
-	result := generateNginxCfg(&cafeIngressEx, pems, false, configParams, false, false, "")
+	result := generateNginxCfg(&cafeIngressEx, pems, pemFileNameForMissingTLSSecret, "", false, configParams, false, false, "", "")
 
 	expectedCiphers := "NULL"
 	resultCiphers := result.Servers[0].SSLCiphers
@@ -91,11 +425,12 @@ func TestGenerateNginxCfgWithMissingTLSSecret(t *testing.T) {
 func TestGenerateNginxCfgWithWildcardTLSSecret(t *testing.T) {
 	cafeIngressEx := createCafeIngressEx()
 	configParams := NewDefaultConfigParams()
+	pemFileNameForWildcardTLSSecret := "/etc/nginx/secrets/wildcard"
 	pems := map[string]string{
 		"cafe.example.com": pemFileNameForWildcardTLSSecret,
 	}
 
-	result := generateNginxCfg(&cafeIngressEx, pems, false, configParams, false, false, "")
+	result := generateNginxCfg(&cafeIngressEx, pems, "/etc/nginx/secrets/default", "", false, configParams, false, false, "", "")
 
 	resultServer := result.Servers[0]
 	if !reflect.DeepEqual(resultServer.SSLCertificate, pemFileNameForWildcardTLSSecret) {
@@ -161,22 +496,26 @@ func createExpectedConfigForCafeIngressEx() version1.IngressNginxConfig {
 				ServerTokens: "on",
 				Locations: []version1.Location{
 					{
-						Path:                "/coffee",
-						Upstream:            coffeeUpstream,
-						ProxyConnectTimeout: "60s",
-						ProxyReadTimeout:    "60s",
-						ProxySendTimeout:    "60s",
-						ClientMaxBodySize:   "1m",
-						ProxyBuffering:      true,
+						Path:                  "/coffee",
+						Upstream:              coffeeUpstream,
+						ProxyConnectTimeout:   "60s",
+						ProxyReadTimeout:      "60s",
+						ProxySendTimeout:      "60s",
+						ClientMaxBodySize:     "1m",
+						ProxyBuffering:        true,
+						ProxyRequestBuffering: true,
+						ProxyHTTPVersion:      "1.1",
 					},
 					{
-						Path:                "/tea",
-						Upstream:            teaUpstream,
-						ProxyConnectTimeout: "60s",
-						ProxyReadTimeout:    "60s",
-						ProxySendTimeout:    "60s",
-						ClientMaxBodySize:   "1m",
-						ProxyBuffering:      true,
+						Path:                  "/tea",
+						Upstream:              teaUpstream,
+						ProxyConnectTimeout:   "60s",
+						ProxyReadTimeout:      "60s",
+						ProxySendTimeout:      "60s",
+						ClientMaxBodySize:     "1m",
+						ProxyBuffering:        true,
+						ProxyRequestBuffering: true,
+						ProxyHTTPVersion:      "1.1",
 					},
 				},
 				SSL:               true,
@@ -268,7 +607,7 @@ func TestGenerateNginxCfgForMergeableIngresses(t *testing.T) {
 	minionJwtKeyFileNames := make(map[string]string)
 	configParams := NewDefaultConfigParams()
 
-	result := generateNginxCfgForMergeableIngresses(mergeableIngresses, masterPems, "", minionJwtKeyFileNames, configParams, false, false)
+	result := generateNginxCfgForMergeableIngresses(mergeableIngresses, masterPems, "/etc/nginx/secrets/default", "", "", minionJwtKeyFileNames, "", nil, configParams, false, false)
 
 	if !reflect.DeepEqual(result, expected) {
 		t.Errorf("generateNginxCfgForMergeableIngresses returned \n%v,  but expected \n%v", result, expected)
@@ -337,7 +676,7 @@ func TestGenerateNginxCfgForMergeableIngressesForJWT(t *testing.T) {
 	configParams := NewDefaultConfigParams()
 	isPlus := true
 
-	result := generateNginxCfgForMergeableIngresses(mergeableIngresses, masterPems, "/etc/nginx/secrets/default-cafe-jwk", minionJwtKeyFileNames, configParams, isPlus, false)
+	result := generateNginxCfgForMergeableIngresses(mergeableIngresses, masterPems, "/etc/nginx/secrets/default", "", "/etc/nginx/secrets/default-cafe-jwk", minionJwtKeyFileNames, "", nil, configParams, isPlus, false)
 
 	if !reflect.DeepEqual(result.Servers[0].JWTAuth, expected.Servers[0].JWTAuth) {
 		t.Errorf("generateNginxCfgForMergeableIngresses returned \n%v,  but expected \n%v", result.Servers[0].JWTAuth, expected.Servers[0].JWTAuth)
@@ -350,6 +689,44 @@ func TestGenerateNginxCfgForMergeableIngressesForJWT(t *testing.T) {
 	}
 }
 
+func TestGenerateNginxCfgForMergeableIngressesForBasicAuth(t *testing.T) {
+	mergeableIngresses := createMergeableCafeIngress()
+	mergeableIngresses.Master.Ingress.Annotations["nginx.org/basic-auth-secret"] = "cafe-htpasswd"
+	mergeableIngresses.Master.Ingress.Annotations["nginx.org/basic-auth-realm"] = "Cafe"
+	mergeableIngresses.Minions[0].Ingress.Annotations["nginx.org/basic-auth-secret"] = "coffee-htpasswd"
+
+	expected := createExpectedConfigForMergeableCafeIngress()
+	expected.Servers[0].BasicAuth = &version1.BasicAuth{
+		Realm:    "Cafe",
+		UserFile: "/etc/nginx/secrets/default-cafe-htpasswd",
+	}
+	expected.Servers[0].Locations[0].BasicAuth = &version1.BasicAuth{
+		Realm:    "Restricted",
+		UserFile: "/etc/nginx/secrets/default-coffee-htpasswd",
+	}
+
+	masterPems := map[string]string{
+		"cafe.example.com": "/etc/nginx/secrets/default-cafe-secret",
+	}
+	minionBasicAuthFileNames := map[string]string{
+		objectMetaToFileName(&mergeableIngresses.Minions[0].Ingress.ObjectMeta): "/etc/nginx/secrets/default-coffee-htpasswd",
+	}
+	configParams := NewDefaultConfigParams()
+
+	result := generateNginxCfgForMergeableIngresses(mergeableIngresses, masterPems, "/etc/nginx/secrets/default", "", "", nil,
+		"/etc/nginx/secrets/default-cafe-htpasswd", minionBasicAuthFileNames, configParams, false, false)
+
+	if !reflect.DeepEqual(result.Servers[0].BasicAuth, expected.Servers[0].BasicAuth) {
+		t.Errorf("generateNginxCfgForMergeableIngresses returned \n%v,  but expected \n%v", result.Servers[0].BasicAuth, expected.Servers[0].BasicAuth)
+	}
+	if !reflect.DeepEqual(result.Servers[0].Locations[0].BasicAuth, expected.Servers[0].Locations[0].BasicAuth) {
+		t.Errorf("generateNginxCfgForMergeableIngresses returned \n%v,  but expected \n%v", result.Servers[0].Locations[0].BasicAuth, expected.Servers[0].Locations[0].BasicAuth)
+	}
+	if result.Servers[0].Locations[1].BasicAuth != nil {
+		t.Errorf("generateNginxCfgForMergeableIngresses returned \n%v,  but expected no basic auth for the minion without the secret", result.Servers[0].Locations[1].BasicAuth)
+	}
+}
+
 func createMergeableCafeIngress() *MergeableIngresses {
 	master := v1beta1.Ingress{
 		ObjectMeta: meta_v1.ObjectMeta{
@@ -516,13 +893,15 @@ func createExpectedConfigForMergeableCafeIngress() version1.IngressNginxConfig {
 				ServerTokens: "on",
 				Locations: []version1.Location{
 					{
-						Path:                "/coffee",
-						Upstream:            coffeeUpstream,
-						ProxyConnectTimeout: "60s",
-						ProxyReadTimeout:    "60s",
-						ProxySendTimeout:    "60s",
-						ClientMaxBodySize:   "1m",
-						ProxyBuffering:      true,
+						Path:                  "/coffee",
+						Upstream:              coffeeUpstream,
+						ProxyConnectTimeout:   "60s",
+						ProxyReadTimeout:      "60s",
+						ProxySendTimeout:      "60s",
+						ClientMaxBodySize:     "1m",
+						ProxyBuffering:        true,
+						ProxyRequestBuffering: true,
+						ProxyHTTPVersion:      "1.1",
 						MinionIngress: &version1.Ingress{
 							Name:      "cafe-ingress-coffee-minion",
 							Namespace: "default",
@@ -533,13 +912,15 @@ func createExpectedConfigForMergeableCafeIngress() version1.IngressNginxConfig {
 						},
 					},
 					{
-						Path:                "/tea",
-						Upstream:            teaUpstream,
-						ProxyConnectTimeout: "60s",
-						ProxyReadTimeout:    "60s",
-						ProxySendTimeout:    "60s",
-						ClientMaxBodySize:   "1m",
-						ProxyBuffering:      true,
+						Path:                  "/tea",
+						Upstream:              teaUpstream,
+						ProxyConnectTimeout:   "60s",
+						ProxyReadTimeout:      "60s",
+						ProxySendTimeout:      "60s",
+						ClientMaxBodySize:     "1m",
+						ProxyBuffering:        true,
+						ProxyRequestBuffering: true,
+						ProxyHTTPVersion:      "1.1",
 						MinionIngress: &version1.Ingress{
 							Name:      "cafe-ingress-tea-minion",
 							Namespace: "default",
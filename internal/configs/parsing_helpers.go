@@ -3,12 +3,15 @@ package configs
 import (
 	"errors"
 	"fmt"
+	"net"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 // There seems to be no composite interface in the kubernetes api package,
@@ -186,3 +189,532 @@ func ParseTime(s string) (string, error) {
 	}
 	return "", errors.New("Invalid time string")
 }
+
+var validNginxSize = regexp.MustCompile(`^\d+[kKmM]?$`)
+
+// ParseSize ensures that the string value is a valid size, such as 16, 32k or 64M.
+func ParseSize(s string) (string, error) {
+	s = strings.TrimSpace(s)
+
+	if validNginxSize.MatchString(s) {
+		return s, nil
+	}
+	return "", errors.New("Invalid size string")
+}
+
+// ParseProxyHTTPVersion ensures that the string is an HTTP version supported by the proxy_http_version directive.
+func ParseProxyHTTPVersion(s string) (string, error) {
+	s = strings.TrimSpace(s)
+
+	if s == "1.0" || s == "1.1" {
+		return s, nil
+	}
+	return "", errors.New("Invalid HTTP version, must be 1.0 or 1.1")
+}
+
+var gzipTypeRegexp = regexp.MustCompile(`^(application|audio|font|image|message|model|multipart|text|video)/[a-zA-Z0-9][a-zA-Z0-9!#$&^_.+-]*$`)
+
+// ValidateGzipType validates a MIME type for the gzip_types directive. The special value `*` matches any MIME type.
+func ValidateGzipType(mimeType string) error {
+	if mimeType == "*" || gzipTypeRegexp.MatchString(mimeType) {
+		return nil
+	}
+	return fmt.Errorf("Invalid MIME type %q", mimeType)
+}
+
+var validGzipProxiedValues = map[string]bool{
+	"off":              true,
+	"expired":          true,
+	"no-cache":         true,
+	"no-store":         true,
+	"private":          true,
+	"no_last_modified": true,
+	"no_etag":          true,
+	"auth":             true,
+	"any":              true,
+}
+
+// ValidateGzipProxied validates a parameter of the gzip_proxied directive.
+func ValidateGzipProxied(value string) error {
+	if !validGzipProxiedValues[value] {
+		return fmt.Errorf("Invalid gzip proxied value %q", value)
+	}
+	return nil
+}
+
+// ValidateGzipCompLevel validates a compression level for the gzip_comp_level directive.
+func ValidateGzipCompLevel(level int) error {
+	if level < 1 || level > 9 {
+		return fmt.Errorf("Invalid compression level %d, must be between 1 and 9", level)
+	}
+	return nil
+}
+
+var validLogFormatEscapings = map[string]bool{
+	"default": true,
+	"json":    true,
+	"none":    true,
+}
+
+// ValidateLogFormatEscaping validates the escape parameter of the log_format directive.
+func ValidateLogFormatEscaping(escaping string) error {
+	if !validLogFormatEscapings[escaping] {
+		return fmt.Errorf("Invalid escaping %q, must be default, json or none", escaping)
+	}
+	return nil
+}
+
+var syslogSocketPathRegexp = regexp.MustCompile(`^/[^\s{};,"'\\]*$`)
+
+// ValidateSyslogServer validates the address of a syslog server: either a unix socket path with the unix: prefix or
+// an IP address or a hostname with an optional UDP port.
+func ValidateSyslogServer(server string) error {
+	if strings.HasPrefix(server, "unix:") {
+		if !syslogSocketPathRegexp.MatchString(strings.TrimPrefix(server, "unix:")) {
+			return fmt.Errorf("Invalid unix socket path %q", strings.TrimPrefix(server, "unix:"))
+		}
+		return nil
+	}
+
+	host := server
+	if h, port, err := net.SplitHostPort(server); err == nil {
+		if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+			return fmt.Errorf("Invalid port %q", port)
+		}
+		host = h
+	} else if strings.HasPrefix(server, "[") && strings.HasSuffix(server, "]") {
+		host = strings.TrimSuffix(strings.TrimPrefix(server, "["), "]")
+		if net.ParseIP(host) == nil {
+			return fmt.Errorf("Invalid IPv6 address %q", host)
+		}
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		if ip.To4() == nil && host == server {
+			return fmt.Errorf("IPv6 address %q must be enclosed in brackets", server)
+		}
+		return nil
+	}
+
+	if errs := validation.IsDNS1123Subdomain(host); len(errs) > 0 {
+		return fmt.Errorf("Invalid host %q: %s", host, strings.Join(errs, ", "))
+	}
+
+	return nil
+}
+
+var validSyslogFacilities = map[string]bool{
+	"kern":     true,
+	"user":     true,
+	"mail":     true,
+	"daemon":   true,
+	"auth":     true,
+	"intern":   true,
+	"lpr":      true,
+	"news":     true,
+	"uucp":     true,
+	"clock":    true,
+	"authpriv": true,
+	"ftp":      true,
+	"ntp":      true,
+	"audit":    true,
+	"alert":    true,
+	"cron":     true,
+	"local0":   true,
+	"local1":   true,
+	"local2":   true,
+	"local3":   true,
+	"local4":   true,
+	"local5":   true,
+	"local6":   true,
+	"local7":   true,
+}
+
+// ValidateSyslogFacility validates the facility parameter of a syslog target.
+func ValidateSyslogFacility(facility string) error {
+	if !validSyslogFacilities[facility] {
+		return fmt.Errorf("Invalid syslog facility %q", facility)
+	}
+	return nil
+}
+
+var validSyslogSeverities = map[string]bool{
+	"emerg":  true,
+	"alert":  true,
+	"crit":   true,
+	"error":  true,
+	"warn":   true,
+	"notice": true,
+	"info":   true,
+	"debug":  true,
+}
+
+// ValidateSyslogSeverity validates the severity parameter of a syslog target.
+func ValidateSyslogSeverity(severity string) error {
+	if !validSyslogSeverities[severity] {
+		return fmt.Errorf("Invalid syslog severity %q", severity)
+	}
+	return nil
+}
+
+var syslogTagRegexp = regexp.MustCompile(`^[a-zA-Z0-9_]{1,32}$`)
+
+// ValidateSyslogTag validates the tag parameter of a syslog target.
+func ValidateSyslogTag(tag string) error {
+	if !syslogTagRegexp.MatchString(tag) {
+		return fmt.Errorf("Invalid syslog tag %q, must consist of up to 32 alphanumeric characters or '_'", tag)
+	}
+	return nil
+}
+
+var nginxTimeComponent = regexp.MustCompile(`([0-9]+)(ms|[smhdwMy]?)`)
+
+var nginxTimeUnits = map[string]time.Duration{
+	"ms": time.Millisecond,
+	"":   time.Second,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+	"d":  24 * time.Hour,
+	"w":  7 * 24 * time.Hour,
+	"M":  30 * 24 * time.Hour,
+	"y":  365 * 24 * time.Hour,
+}
+
+// ParseTimeToDuration converts a valid NGINX time string to a time.Duration.
+func ParseTimeToDuration(s string) (time.Duration, error) {
+	s, err := ParseTime(s)
+	if err != nil {
+		return 0, err
+	}
+
+	var duration time.Duration
+	for _, component := range nginxTimeComponent.FindAllStringSubmatch(s, -1) {
+		value, err := strconv.Atoi(component[1])
+		if err != nil {
+			return 0, fmt.Errorf("Invalid time string: %v", err)
+		}
+		duration += time.Duration(value) * nginxTimeUnits[component[2]]
+	}
+
+	return duration, nil
+}
+
+var externalAuthURLRegexp = regexp.MustCompile(`^https?://[^\s"'{};]+$`)
+
+// ParseExternalAuthURL ensures that the string value in the annotation is a valid http or https URL of an external
+// authentication service or a sign-in page.
+func ParseExternalAuthURL(s string) (string, error) {
+	if !externalAuthURLRegexp.MatchString(s) {
+		return "", errors.New("Invalid URL: must start with http:// or https:// and must not include any whitespace character, quotes, `{`, `}` or `;`")
+	}
+
+	return s, nil
+}
+
+var headerNameRegexp = regexp.MustCompile(`^[-A-Za-z0-9]+$`)
+
+// ParseExternalAuthResponseHeaders parses a comma-separated list of the headers of the response of an external
+// authentication service.
+func ParseExternalAuthResponseHeaders(s string) ([]string, error) {
+	var headers []string
+
+	for _, header := range strings.Split(s, ",") {
+		header = strings.TrimSpace(header)
+		if !headerNameRegexp.MatchString(header) {
+			return nil, fmt.Errorf("Invalid header name %q", header)
+		}
+		headers = append(headers, header)
+	}
+
+	return headers, nil
+}
+
+var corsOriginRegexp = regexp.MustCompile(`^https?://(\*\.)?[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*(:[0-9]{1,5})?$`)
+
+// ParseCORSOrigin ensures that the string is a valid origin allowed by a CORS configuration and returns the value for
+// matching the origin in an NGINX map. The origin can be an exact origin, for example, `https://example.com`,
+// an origin with a wildcard subdomain, for example, `https://*.example.com`, or a regular expression that starts with `~`.
+func ParseCORSOrigin(s string) (string, error) {
+	if strings.HasPrefix(s, "~") {
+		if strings.ContainsAny(s, `"`) || strings.HasSuffix(s, `\`) {
+			return "", fmt.Errorf("Invalid regular expression %q: must not include double quotes or end with a backslash", s)
+		}
+		if _, err := regexp.Compile(s[1:]); err != nil {
+			return "", fmt.Errorf("Invalid regular expression %q: %v", s, err)
+		}
+		return fmt.Sprintf(`"%s"`, s), nil
+	}
+
+	if !corsOriginRegexp.MatchString(s) {
+		return "", fmt.Errorf("Invalid origin %q: must be a lowercase origin like `https://example.com` or `https://*.example.com` or a regular expression that starts with `~`", s)
+	}
+
+	if strings.Contains(s, "*") {
+		regex := regexp.QuoteMeta(s)
+		regex = strings.Replace(regex, `\*`, `[a-z0-9-]+(\.[a-z0-9-]+)*`, 1)
+		return fmt.Sprintf(`"~^%s$"`, regex), nil
+	}
+
+	return fmt.Sprintf(`"%s"`, s), nil
+}
+
+// ValidateCORSOrigins validates the origins allowed by a CORS configuration. The `*` origin allows any origin and must
+// not be combined with other origins or with credentials.
+func ValidateCORSOrigins(origins []string, allowCredentials bool) error {
+	if len(origins) == 0 {
+		return errors.New("At least one origin is required")
+	}
+
+	for _, origin := range origins {
+		if origin == "*" {
+			if len(origins) > 1 {
+				return errors.New("The `*` origin must not be combined with other origins")
+			}
+			if allowCredentials {
+				return errors.New("The `*` origin must not be combined with credentials")
+			}
+			continue
+		}
+
+		if _, err := ParseCORSOrigin(origin); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ParseCORSOrigins parses a comma-separated list of the origins allowed by a CORS configuration.
+func ParseCORSOrigins(s string, allowCredentials bool) ([]string, error) {
+	var origins []string
+	for _, origin := range strings.Split(s, ",") {
+		origins = append(origins, strings.TrimSpace(origin))
+	}
+
+	if err := ValidateCORSOrigins(origins, allowCredentials); err != nil {
+		return nil, err
+	}
+
+	return origins, nil
+}
+
+var corsMethodRegexp = regexp.MustCompile(`^[A-Z]+$`)
+
+// ValidateCORSMethod validates a method allowed by a CORS configuration.
+func ValidateCORSMethod(method string) error {
+	if !corsMethodRegexp.MatchString(method) {
+		return fmt.Errorf("Invalid method %q: must include only uppercase letters", method)
+	}
+	return nil
+}
+
+// ValidateHeaderName validates the name of an HTTP header.
+func ValidateHeaderName(name string) error {
+	if !headerNameRegexp.MatchString(name) {
+		return fmt.Errorf("Invalid header name %q", name)
+	}
+	return nil
+}
+
+// ParseCommaSeparatedList parses a comma-separated list and validates every element using the validate function.
+func ParseCommaSeparatedList(s string, validate func(string) error) ([]string, error) {
+	var result []string
+
+	for _, elem := range strings.Split(s, ",") {
+		elem = strings.TrimSpace(elem)
+		if err := validate(elem); err != nil {
+			return nil, err
+		}
+		result = append(result, elem)
+	}
+
+	return result, nil
+}
+
+var proxyCacheZoneNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// ValidateProxyCacheZoneName validates the name of a cache zone.
+func ValidateProxyCacheZoneName(name string) error {
+	if !proxyCacheZoneNameRegexp.MatchString(name) || name == "off" {
+		return fmt.Errorf("Invalid cache zone name %q: must include only letters, digits, `_` or `-` and must not be `off`", name)
+	}
+	return nil
+}
+
+var proxyCacheSizeRegexp = regexp.MustCompile(`^\d+[kKmMgG]?$`)
+
+// ParseProxyCacheZones parses a comma-separated list of cache zones. A zone starts with its name and the size of
+// the shared memory zone for the keys, followed by optional space-separated `max_size`, `min_free` and `inactive`
+// parameters of the proxy_cache_path directive, for example, `cafe:10m max_size=1g inactive=60m`.
+func ParseProxyCacheZones(s string) ([]ProxyCacheZone, error) {
+	var zones []ProxyCacheZone
+	names := make(map[string]bool)
+
+	for _, definition := range strings.Split(s, ",") {
+		zone, err := parseProxyCacheZone(definition)
+		if err != nil {
+			return nil, err
+		}
+		if names[zone.Name] {
+			return nil, fmt.Errorf("Duplicate cache zone %q", zone.Name)
+		}
+		names[zone.Name] = true
+		zones = append(zones, zone)
+	}
+
+	return zones, nil
+}
+
+func hasProxyCacheZone(zones []ProxyCacheZone, name string) bool {
+	for _, zone := range zones {
+		if zone.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func parseProxyCacheZone(s string) (ProxyCacheZone, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return ProxyCacheZone{}, errors.New("Empty cache zone")
+	}
+
+	nameAndSize := strings.SplitN(fields[0], ":", 2)
+	if len(nameAndSize) != 2 || !proxyCacheSizeRegexp.MatchString(nameAndSize[1]) {
+		return ProxyCacheZone{}, fmt.Errorf("Invalid cache zone %q: must start with the name and the size, for example, `cafe:10m`", fields[0])
+	}
+	if err := ValidateProxyCacheZoneName(nameAndSize[0]); err != nil {
+		return ProxyCacheZone{}, err
+	}
+
+	zone := ProxyCacheZone{
+		Name: nameAndSize[0],
+		Size: nameAndSize[1],
+	}
+
+	for _, param := range fields[1:] {
+		keyAndValue := strings.SplitN(param, "=", 2)
+		if len(keyAndValue) != 2 {
+			return ProxyCacheZone{}, fmt.Errorf("Invalid parameter %q of the cache zone %q: must be `key=value`", param, zone.Name)
+		}
+
+		key, value := keyAndValue[0], keyAndValue[1]
+		switch key {
+		case "max_size", "min_free":
+			if !proxyCacheSizeRegexp.MatchString(value) {
+				return ProxyCacheZone{}, fmt.Errorf("Invalid parameter %q of the cache zone %q: must be a size, for example, `1g`", param, zone.Name)
+			}
+			if key == "max_size" {
+				zone.MaxSize = value
+			} else {
+				zone.MinFree = value
+			}
+		case "inactive":
+			if _, err := ParseTime(value); err != nil {
+				return ProxyCacheZone{}, fmt.Errorf("Invalid parameter %q of the cache zone %q: %v", param, zone.Name, err)
+			}
+			zone.Inactive = value
+		default:
+			return ProxyCacheZone{}, fmt.Errorf("Invalid parameter %q of the cache zone %q: must be one of `max_size`, `min_free` or `inactive`", param, zone.Name)
+		}
+	}
+
+	return zone, nil
+}
+
+var proxyCacheValidCodeRegexp = regexp.MustCompile(`^([1-5][0-9][0-9]|any)$`)
+
+// ValidateProxyCacheValid validates the caching time for the responses with the status codes, for example,
+// `200 302 10m`. If no status codes are specified, the time applies to the 200, 301 and 302 responses.
+func ValidateProxyCacheValid(s string) error {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return errors.New("Invalid cache validity: must include the caching time")
+	}
+
+	for _, code := range fields[:len(fields)-1] {
+		if !proxyCacheValidCodeRegexp.MatchString(code) {
+			return fmt.Errorf("Invalid status code %q in the cache validity %q: must be a status code or `any`", code, s)
+		}
+	}
+
+	if _, err := ParseTime(fields[len(fields)-1]); err != nil {
+		return fmt.Errorf("Invalid caching time in the cache validity %q: %v", s, err)
+	}
+
+	return nil
+}
+
+// ValidateProxyCacheMethod validates a method of the requests whose responses are cached.
+func ValidateProxyCacheMethod(method string) error {
+	if method != "GET" && method != "HEAD" && method != "POST" {
+		return fmt.Errorf("Invalid method %q: must be one of `GET`, `HEAD` or `POST`", method)
+	}
+	return nil
+}
+
+// ValidateProxyCacheKey validates the key for caching.
+func ValidateProxyCacheKey(key string) error {
+	if key == "" {
+		return errors.New("Invalid cache key: must not be empty")
+	}
+	if strings.Contains(key, `"`) || strings.HasSuffix(key, `\`) {
+		return fmt.Errorf("Invalid cache key %q: must not include double quotes or end with a backslash", key)
+	}
+	return nil
+}
+
+var proxyCacheBypassRegexp = regexp.MustCompile(`^\$[a-zA-Z0-9_]+$`)
+
+// ValidateProxyCacheBypass validates a condition under which the response is taken from the upstream instead of
+// the cache.
+func ValidateProxyCacheBypass(condition string) error {
+	if !proxyCacheBypassRegexp.MatchString(condition) {
+		return fmt.Errorf("Invalid bypass condition %q: must be a variable, for example, `$http_pragma`", condition)
+	}
+	return nil
+}
+
+var proxyCacheUseStaleValues = map[string]bool{
+	"error":          true,
+	"timeout":        true,
+	"invalid_header": true,
+	"updating":       true,
+	"http_500":       true,
+	"http_502":       true,
+	"http_503":       true,
+	"http_504":       true,
+	"http_403":       true,
+	"http_404":       true,
+	"http_429":       true,
+	"off":            true,
+}
+
+// ValidateProxyCacheUseStale validates the cases in which a stale cached response can be used.
+func ValidateProxyCacheUseStale(values []string) error {
+	for _, value := range values {
+		if !proxyCacheUseStaleValues[value] {
+			return fmt.Errorf("Invalid value %q: must be one of `error`, `timeout`, `invalid_header`, `updating`, `http_500`, `http_502`, `http_503`, `http_504`, `http_403`, `http_404`, `http_429` or `off`", value)
+		}
+		if value == "off" && len(values) > 1 {
+			return errors.New("The `off` value must not be combined with other values")
+		}
+	}
+	return nil
+}
+
+// ParseProxyCacheUseStale parses a comma-separated list of the cases in which a stale cached response can be used.
+func ParseProxyCacheUseStale(s string) ([]string, error) {
+	var values []string
+	for _, value := range strings.Split(s, ",") {
+		values = append(values, strings.TrimSpace(value))
+	}
+
+	if err := ValidateProxyCacheUseStale(values); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
@@ -3,6 +3,7 @@ package configs
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
@@ -382,3 +383,495 @@ func TestParseTime(t *testing.T) {
 		}
 	}
 }
+
+func TestParseSize(t *testing.T) {
+	var testsWithValidInput = []string{"16", "32k", "32K", "64m", "64M"}
+	var invalidInput = []string{"", "k", "1g", "1.5k", "16 k", "16kb"}
+	for _, test := range testsWithValidInput {
+		result, err := ParseSize(test)
+		if err != nil {
+			t.Errorf("ParseSize(%q) returned an error for valid input", test)
+		}
+		if test != result {
+			t.Errorf("ParseSize(%q) returned %q expected %q", test, result, test)
+		}
+	}
+	for _, test := range invalidInput {
+		result, err := ParseSize(test)
+		if err == nil {
+			t.Errorf("ParseSize(%q) didn't return error. Returned: %q", test, result)
+		}
+	}
+}
+
+func TestParseProxyHTTPVersion(t *testing.T) {
+	var testsWithValidInput = []string{"1.0", "1.1"}
+	var invalidInput = []string{"", "1", "2.0", "HTTP/1.1"}
+	for _, test := range testsWithValidInput {
+		result, err := ParseProxyHTTPVersion(test)
+		if err != nil {
+			t.Errorf("ParseProxyHTTPVersion(%q) returned an error for valid input", test)
+		}
+		if test != result {
+			t.Errorf("ParseProxyHTTPVersion(%q) returned %q expected %q", test, result, test)
+		}
+	}
+	for _, test := range invalidInput {
+		result, err := ParseProxyHTTPVersion(test)
+		if err == nil {
+			t.Errorf("ParseProxyHTTPVersion(%q) didn't return error. Returned: %q", test, result)
+		}
+	}
+}
+
+func TestParseTimeToDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{
+			input:    "10",
+			expected: 10 * time.Second,
+		},
+		{
+			input:    "10s",
+			expected: 10 * time.Second,
+		},
+		{
+			input:    "1m30s",
+			expected: 90 * time.Second,
+		},
+		{
+			input:    "1h 30m",
+			expected: 90 * time.Minute,
+		},
+		{
+			input:    "2d",
+			expected: 48 * time.Hour,
+		},
+	}
+	for _, test := range tests {
+		result, err := ParseTimeToDuration(test.input)
+		if err != nil {
+			t.Errorf("ParseTimeToDuration(%q) returned an error for valid input: %v", test.input, err)
+		}
+		if result != test.expected {
+			t.Errorf("ParseTimeToDuration(%q) returned %v expected %v", test.input, result, test.expected)
+		}
+	}
+
+	_, err := ParseTimeToDuration("1L")
+	if err == nil {
+		t.Errorf("ParseTimeToDuration(%q) didn't return error", "1L")
+	}
+}
+
+func TestParseExternalAuthURL(t *testing.T) {
+	var validInput = []string{
+		"http://oauth2-proxy.default.svc.cluster.local:4180/oauth2/auth",
+		"https://auth.example.com/auth",
+		"https://$host/oauth2/start?rd=$escaped_request_uri",
+	}
+	var invalidInput = []string{"", "auth.example.com", "ftp://auth.example.com", "http://auth.example.com/a b", "http://auth.example.com/;", `http://auth.example.com/"`}
+
+	for _, input := range validInput {
+		result, err := ParseExternalAuthURL(input)
+		if err != nil {
+			t.Errorf("ParseExternalAuthURL(%q) returned an error for valid input: %v", input, err)
+		}
+		if result != input {
+			t.Errorf("ParseExternalAuthURL(%q) returned %q expected %q", input, result, input)
+		}
+	}
+	for _, input := range invalidInput {
+		if _, err := ParseExternalAuthURL(input); err == nil {
+			t.Errorf("ParseExternalAuthURL(%q) didn't return an error for invalid input", input)
+		}
+	}
+}
+
+func TestParseExternalAuthResponseHeaders(t *testing.T) {
+	expected := []string{"X-Auth-Request-User", "X-Auth-Request-Email"}
+
+	result, err := ParseExternalAuthResponseHeaders("X-Auth-Request-User, X-Auth-Request-Email")
+	if err != nil {
+		t.Errorf("ParseExternalAuthResponseHeaders() returned an error for valid input: %v", err)
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("ParseExternalAuthResponseHeaders() returned %v expected %v", result, expected)
+	}
+
+	var invalidInput = []string{"", "X-User,", "X User", "X-User;"}
+	for _, input := range invalidInput {
+		if _, err := ParseExternalAuthResponseHeaders(input); err == nil {
+			t.Errorf("ParseExternalAuthResponseHeaders(%q) didn't return an error for invalid input", input)
+		}
+	}
+}
+
+func TestParseCORSOrigin(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{
+			input:    "https://example.com",
+			expected: `"https://example.com"`,
+		},
+		{
+			input:    "http://example.com:8080",
+			expected: `"http://example.com:8080"`,
+		},
+		{
+			input:    "https://*.example.com",
+			expected: `"~^https://[a-z0-9-]+(\.[a-z0-9-]+)*\.example\.com$"`,
+		},
+		{
+			input:    `~^https://(www|app)\.example\.com$`,
+			expected: `"~^https://(www|app)\.example\.com$"`,
+		},
+	}
+
+	for _, test := range tests {
+		result, err := ParseCORSOrigin(test.input)
+		if err != nil {
+			t.Errorf("ParseCORSOrigin(%q) returned an error for valid input: %v", test.input, err)
+		}
+		if result != test.expected {
+			t.Errorf("ParseCORSOrigin(%q) returned %q expected %q", test.input, result, test.expected)
+		}
+	}
+
+	var invalidInput = []string{"", "example.com", "https://Example.com", "https://example.com/", "https://a.*.example.com", "~(", `~"abc`, `~abc\`}
+	for _, input := range invalidInput {
+		if _, err := ParseCORSOrigin(input); err == nil {
+			t.Errorf("ParseCORSOrigin(%q) didn't return an error for invalid input", input)
+		}
+	}
+}
+
+func TestParseCORSOrigins(t *testing.T) {
+	var tests = []struct {
+		input            string
+		allowCredentials bool
+		expected         []string
+		msg              string
+	}{
+		{
+			input:            "*",
+			allowCredentials: false,
+			expected:         []string{"*"},
+			msg:              "any origin",
+		},
+		{
+			input:            "https://example.com, https://*.example.com",
+			allowCredentials: true,
+			expected:         []string{"https://example.com", "https://*.example.com"},
+			msg:              "multiple origins with credentials",
+		},
+	}
+
+	for _, test := range tests {
+		result, err := ParseCORSOrigins(test.input, test.allowCredentials)
+		if err != nil {
+			t.Errorf("ParseCORSOrigins() returned an error for the case of %s: %v", test.msg, err)
+		}
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("ParseCORSOrigins() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
+	}
+}
+
+func TestParseCORSOriginsFails(t *testing.T) {
+	var tests = []struct {
+		input            string
+		allowCredentials bool
+		msg              string
+	}{
+		{
+			input: "",
+			msg:   "empty origin",
+		},
+		{
+			input:            "*",
+			allowCredentials: true,
+			msg:              "any origin with credentials",
+		},
+		{
+			input: "*, https://example.com",
+			msg:   "any origin combined with other origins",
+		},
+		{
+			input: "https://example.com,",
+			msg:   "trailing comma",
+		},
+	}
+
+	for _, test := range tests {
+		if _, err := ParseCORSOrigins(test.input, test.allowCredentials); err == nil {
+			t.Errorf("ParseCORSOrigins() returned no error for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestParseCommaSeparatedList(t *testing.T) {
+	expected := []string{"GET", "POST"}
+
+	result, err := ParseCommaSeparatedList("GET, POST", ValidateCORSMethod)
+	if err != nil {
+		t.Errorf("ParseCommaSeparatedList() returned an error for valid input: %v", err)
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("ParseCommaSeparatedList() returned %v expected %v", result, expected)
+	}
+
+	var invalidInput = []string{"", "GET,", "get", "GET POST"}
+	for _, input := range invalidInput {
+		if _, err := ParseCommaSeparatedList(input, ValidateCORSMethod); err == nil {
+			t.Errorf("ParseCommaSeparatedList(%q) didn't return an error for invalid input", input)
+		}
+	}
+}
+
+func TestParseProxyCacheZones(t *testing.T) {
+	expected := []ProxyCacheZone{
+		{
+			Name:     "cafe",
+			Size:     "10m",
+			MaxSize:  "1g",
+			MinFree:  "100m",
+			Inactive: "60m",
+		},
+		{
+			Name: "static-files",
+			Size: "1m",
+		},
+	}
+
+	result, err := ParseProxyCacheZones("cafe:10m max_size=1g min_free=100m inactive=60m, static-files:1m")
+	if err != nil {
+		t.Errorf("ParseProxyCacheZones() returned an error for valid input: %v", err)
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("ParseProxyCacheZones() returned %+v expected %+v", result, expected)
+	}
+
+	var invalidInput = []string{
+		"",
+		"cafe",
+		"cafe:10x",
+		"off:10m",
+		"cafe/tea:10m",
+		"cafe:10m max_size",
+		"cafe:10m max_size=1t",
+		"cafe:10m inactive=1x",
+		"cafe:10m levels=1:2",
+		"cafe:10m, cafe:1m",
+		"cafe:10m,",
+	}
+	for _, input := range invalidInput {
+		if _, err := ParseProxyCacheZones(input); err == nil {
+			t.Errorf("ParseProxyCacheZones(%q) didn't return an error for invalid input", input)
+		}
+	}
+}
+
+func TestValidateProxyCacheValid(t *testing.T) {
+	var validInput = []string{"10m", "200 302 10m", "404 1m", "any 5s"}
+	for _, input := range validInput {
+		if err := ValidateProxyCacheValid(input); err != nil {
+			t.Errorf("ValidateProxyCacheValid(%q) returned an error for valid input: %v", input, err)
+		}
+	}
+
+	var invalidInput = []string{"", "200 abc", "600 10m", "ok 10m", "200 1x"}
+	for _, input := range invalidInput {
+		if err := ValidateProxyCacheValid(input); err == nil {
+			t.Errorf("ValidateProxyCacheValid(%q) didn't return an error for invalid input", input)
+		}
+	}
+}
+
+func TestValidateProxyCacheBypass(t *testing.T) {
+	var validInput = []string{"$http_pragma", "$cookie_nocache", "$arg_nocache"}
+	for _, input := range validInput {
+		if err := ValidateProxyCacheBypass(input); err != nil {
+			t.Errorf("ValidateProxyCacheBypass(%q) returned an error for valid input: %v", input, err)
+		}
+	}
+
+	var invalidInput = []string{"", "http_pragma", "$http-pragma", "$http_pragma;"}
+	for _, input := range invalidInput {
+		if err := ValidateProxyCacheBypass(input); err == nil {
+			t.Errorf("ValidateProxyCacheBypass(%q) didn't return an error for invalid input", input)
+		}
+	}
+}
+
+func TestValidateGzipType(t *testing.T) {
+	var validInput = []string{"*", "text/css", "application/json", "image/svg+xml", "application/vnd.ms-fontobject"}
+	for _, input := range validInput {
+		if err := ValidateGzipType(input); err != nil {
+			t.Errorf("ValidateGzipType(%q) returned an error for valid input: %v", input, err)
+		}
+	}
+
+	var invalidInput = []string{"", "text", "text/", "/css", "unknown/css", "text/css;", "text/*"}
+	for _, input := range invalidInput {
+		if err := ValidateGzipType(input); err == nil {
+			t.Errorf("ValidateGzipType(%q) didn't return an error for invalid input", input)
+		}
+	}
+}
+
+func TestValidateGzipProxied(t *testing.T) {
+	var validInput = []string{"off", "expired", "no-cache", "any"}
+	for _, input := range validInput {
+		if err := ValidateGzipProxied(input); err != nil {
+			t.Errorf("ValidateGzipProxied(%q) returned an error for valid input: %v", input, err)
+		}
+	}
+
+	var invalidInput = []string{"", "on", "always", "no_cache"}
+	for _, input := range invalidInput {
+		if err := ValidateGzipProxied(input); err == nil {
+			t.Errorf("ValidateGzipProxied(%q) didn't return an error for invalid input", input)
+		}
+	}
+}
+
+func TestValidateGzipCompLevel(t *testing.T) {
+	var validInput = []int{1, 5, 9}
+	for _, input := range validInput {
+		if err := ValidateGzipCompLevel(input); err != nil {
+			t.Errorf("ValidateGzipCompLevel(%d) returned an error for valid input: %v", input, err)
+		}
+	}
+
+	var invalidInput = []int{-1, 0, 10}
+	for _, input := range invalidInput {
+		if err := ValidateGzipCompLevel(input); err == nil {
+			t.Errorf("ValidateGzipCompLevel(%d) didn't return an error for invalid input", input)
+		}
+	}
+}
+
+func TestValidateLogFormatEscaping(t *testing.T) {
+	var validInput = []string{"default", "json", "none"}
+	for _, input := range validInput {
+		if err := ValidateLogFormatEscaping(input); err != nil {
+			t.Errorf("ValidateLogFormatEscaping(%q) returned an error for valid input: %v", input, err)
+		}
+	}
+
+	var invalidInput = []string{"", "JSON", "escape=json", "off"}
+	for _, input := range invalidInput {
+		if err := ValidateLogFormatEscaping(input); err == nil {
+			t.Errorf("ValidateLogFormatEscaping(%q) didn't return an error for invalid input", input)
+		}
+	}
+}
+
+func TestValidateSyslogServer(t *testing.T) {
+	var validInput = []string{
+		"unix:/dev/log",
+		"unix:/var/run/syslog.sock",
+		"10.0.0.1",
+		"10.0.0.1:514",
+		"[::1]",
+		"[::1]:514",
+		"syslog",
+		"syslog.logging.svc.cluster.local:514",
+	}
+	for _, input := range validInput {
+		if err := ValidateSyslogServer(input); err != nil {
+			t.Errorf("ValidateSyslogServer(%q) returned an error for valid input: %v", input, err)
+		}
+	}
+
+	var invalidInput = []string{
+		"",
+		"unix:",
+		"unix:dev/log",
+		"unix:/dev/log,tag=nginx",
+		"::1",
+		"[syslog]",
+		"10.0.0.1:0",
+		"10.0.0.1:65536",
+		"syslog:port",
+		"Syslog_Server",
+		"syslog;",
+	}
+	for _, input := range invalidInput {
+		if err := ValidateSyslogServer(input); err == nil {
+			t.Errorf("ValidateSyslogServer(%q) didn't return an error for invalid input", input)
+		}
+	}
+}
+
+func TestValidateSyslogFacility(t *testing.T) {
+	var validInput = []string{"kern", "daemon", "local0", "local7"}
+	for _, input := range validInput {
+		if err := ValidateSyslogFacility(input); err != nil {
+			t.Errorf("ValidateSyslogFacility(%q) returned an error for valid input: %v", input, err)
+		}
+	}
+
+	var invalidInput = []string{"", "local8", "LOCAL0", "syslog"}
+	for _, input := range invalidInput {
+		if err := ValidateSyslogFacility(input); err == nil {
+			t.Errorf("ValidateSyslogFacility(%q) didn't return an error for invalid input", input)
+		}
+	}
+}
+
+func TestValidateSyslogSeverity(t *testing.T) {
+	var validInput = []string{"emerg", "error", "info", "debug"}
+	for _, input := range validInput {
+		if err := ValidateSyslogSeverity(input); err != nil {
+			t.Errorf("ValidateSyslogSeverity(%q) returned an error for valid input: %v", input, err)
+		}
+	}
+
+	var invalidInput = []string{"", "err", "warning", "INFO"}
+	for _, input := range invalidInput {
+		if err := ValidateSyslogSeverity(input); err == nil {
+			t.Errorf("ValidateSyslogSeverity(%q) didn't return an error for invalid input", input)
+		}
+	}
+}
+
+func TestValidateSyslogTag(t *testing.T) {
+	var validInput = []string{"nginx", "nginx_ingress", "NGINX1"}
+	for _, input := range validInput {
+		if err := ValidateSyslogTag(input); err != nil {
+			t.Errorf("ValidateSyslogTag(%q) returned an error for valid input: %v", input, err)
+		}
+	}
+
+	var invalidInput = []string{"", "nginx-ingress", "nginx,severity=info", "a_tag_that_is_longer_than_32_chars"}
+	for _, input := range invalidInput {
+		if err := ValidateSyslogTag(input); err == nil {
+			t.Errorf("ValidateSyslogTag(%q) didn't return an error for invalid input", input)
+		}
+	}
+}
+
+func TestParseProxyCacheUseStale(t *testing.T) {
+	expected := []string{"error", "timeout", "updating", "http_500"}
+
+	result, err := ParseProxyCacheUseStale("error, timeout, updating, http_500")
+	if err != nil {
+		t.Errorf("ParseProxyCacheUseStale() returned an error for valid input: %v", err)
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("ParseProxyCacheUseStale() returned %v expected %v", result, expected)
+	}
+
+	var invalidInput = []string{"", "error,", "http_501", "off, error"}
+	for _, input := range invalidInput {
+		if _, err := ParseProxyCacheUseStale(input); err == nil {
+			t.Errorf("ParseProxyCacheUseStale(%q) didn't return an error for invalid input", input)
+		}
+	}
+}
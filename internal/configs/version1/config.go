@@ -4,10 +4,24 @@ package version1
 type IngressNginxConfig struct {
 	Upstreams []Upstream
 	Servers   []Server
+	Maps      []Map
 	Keepalive string
 	Ingress   Ingress
 }
 
+// Map describes an NGINX map.
+type Map struct {
+	Source     string
+	Variable   string
+	Parameters []Parameter
+}
+
+// Parameter describes a parameter of an NGINX map.
+type Parameter struct {
+	Value  string
+	Result string
+}
+
 // Ingress holds information about an Ingress resource.
 type Ingress struct {
 	Name        string
@@ -82,8 +96,15 @@ type Server struct {
 	JWTAuth              *JWTAuth
 	JWTRedirectLocations []JWTRedirectLocation
 
-	Ports    []int
-	SSLPorts []int
+	BasicAuth *BasicAuth
+
+	Gzip *Gzip
+
+	ExternalAuthLocations []ExternalAuthLocation
+
+	Ports                []int
+	SSLPorts             []int
+	TLSPassthroughSocket string
 }
 
 // JWTRedirectLocation describes a location for redirecting client requests to a login URL for JWT Authentication.
@@ -100,24 +121,94 @@ type JWTAuth struct {
 	RedirectLocationName string
 }
 
+// BasicAuth holds HTTP basic authentication configuration.
+type BasicAuth struct {
+	Realm    string
+	UserFile string
+}
+
+// Gzip holds the gzip compression configuration of a server.
+type Gzip struct {
+	Types     string
+	MinLength int
+	CompLevel int
+	Proxied   string
+}
+
+// CORS describes the CORS configuration of a location. AllowOrigin is either `*` or a variable with the origin of
+// the request if the origin is allowed.
+type CORS struct {
+	AllowOrigin       string
+	AllowMethods      string
+	AllowHeaders      string
+	AllowCredentials  bool
+	MaxAge            int
+	ExposeHeaders     string
+	PreflightVariable string
+}
+
+// ProxyCache describes the caching of the responses of a location.
+type ProxyCache struct {
+	Zone         string
+	Valid        []string
+	Methods      string
+	Key          string
+	Bypass       string
+	UseStale     string
+	StatusHeader bool
+}
+
+// ExternalAuthLocation describes the internal locations for sending authentication subrequests to an external
+// authentication service and for redirecting unauthenticated client requests to a sign-in URL.
+type ExternalAuthLocation struct {
+	Name               string
+	URL                string
+	SigninLocationName string
+	SigninURL          string
+}
+
+// ExternalAuth holds external authentication configuration of a location.
+type ExternalAuth struct {
+	LocationName       string
+	SigninLocationName string
+	ResponseHeaders    []ExternalAuthResponseHeader
+}
+
+// ExternalAuthResponseHeader describes a header of the response of an external authentication service that is passed
+// to the upstream.
+type ExternalAuthResponseHeader struct {
+	Name             string
+	Variable         string
+	UpstreamVariable string
+}
+
 // Location describes an NGINX location.
 type Location struct {
-	LocationSnippets     []string
-	Path                 string
-	Upstream             Upstream
-	ProxyConnectTimeout  string
-	ProxyReadTimeout     string
-	ProxySendTimeout     string
-	ClientMaxBodySize    string
-	Websocket            bool
-	Rewrite              string
-	SSL                  bool
-	GRPC                 bool
-	ProxyBuffering       bool
-	ProxyBuffers         string
-	ProxyBufferSize      string
-	ProxyMaxTempFileSize string
-	JWTAuth              *JWTAuth
+	LocationSnippets       []string
+	Path                   string
+	Upstream               Upstream
+	ProxyConnectTimeout    string
+	ProxyReadTimeout       string
+	ProxySendTimeout       string
+	ClientMaxBodySize      string
+	Websocket              bool
+	Rewrite                string
+	SSL                    bool
+	GRPC                   bool
+	ProxyBuffering         bool
+	ProxyBuffers           string
+	ProxyBufferSize        string
+	ProxyMaxTempFileSize   string
+	ProxyRequestBuffering  bool
+	ProxyHTTPVersion       string
+	ProxyIgnoreClientAbort bool
+	ClientBodyBufferSize   string
+	ClientBodyTimeout      string
+	JWTAuth                *JWTAuth
+	BasicAuth              *BasicAuth
+	ExternalAuth           *ExternalAuth
+	CORS                   *CORS
+	ProxyCache             *ProxyCache
 
 	MinionIngress *Ingress
 }
@@ -128,8 +219,14 @@ type MainConfig struct {
 	ServerNamesHashMaxSize         string
 	AccessLogOff                   bool
 	LogFormat                      string
+	LogFormatEscaping              string
+	LogGzipRatio                   bool
 	ErrorLogLevel                  string
 	StreamLogFormat                string
+	StreamLogFormatEscaping        string
+	AccessLogSyslog                string
+	ErrorLogSyslog                 string
+	DefaultServerAccessLogOff      bool
 	HealthStatus                   bool
 	NginxStatus                    bool
 	NginxStatusAllowCIDRs          []string
@@ -162,6 +259,23 @@ type MainConfig struct {
 	OpenTracingEnabled             bool
 	OpenTracingTracer              string
 	OpenTracingTracerConfig        string
+	ConfPath                       string
+	LibPath                        string
+	LogPath                        string
+	DefaultServerSecret            string
+	TLSPassthrough                 bool
+	SetRealIPFrom                  []string
+	ProxyCacheZones                []ProxyCacheZone
+}
+
+// ProxyCacheZone describes a cache zone declared with the proxy_cache_path directive.
+type ProxyCacheZone struct {
+	Name     string
+	Path     string
+	Size     string
+	MaxSize  string
+	MinFree  string
+	Inactive string
 }
 
 // NewUpstreamWithDefaultServer creates an upstream with the default server.
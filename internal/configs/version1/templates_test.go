@@ -2,6 +2,7 @@ package version1
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 	"text/template"
 )
@@ -36,37 +37,114 @@ var healthCheck = HealthCheck{
 }
 
 var ingCfg = IngressNginxConfig{
-
+	Maps: []Map{
+		{
+			Source:   `"$request_method:$http_access_control_request_method"`,
+			Variable: "$ingress_default_tea_minion_cors_preflight",
+			Parameters: []Parameter{
+				{
+					Value:  `"~^OPTIONS:.+"`,
+					Result: "1",
+				},
+				{
+					Value:  "default",
+					Result: "0",
+				},
+			},
+		},
+		{
+			Source:   "$http_origin",
+			Variable: "$ingress_default_tea_minion_cors_origin",
+			Parameters: []Parameter{
+				{
+					Value:  `"https://example.com"`,
+					Result: "$http_origin",
+				},
+				{
+					Value:  "default",
+					Result: `""`,
+				},
+			},
+		},
+	},
 	Servers: []Server{
 		{
 			Name:         "test.example.com",
 			ServerTokens: "off",
-			StatusZone:   "test.example.com",
+			Gzip: &Gzip{
+				Types:     "text/css application/json",
+				MinLength: 1000,
+				CompLevel: 5,
+				Proxied:   "any",
+			},
+			StatusZone: "test.example.com",
 			JWTAuth: &JWTAuth{
 				Key:                  "/etc/nginx/secrets/key.jwk",
 				Realm:                "closed site",
 				Token:                "$cookie_auth_token",
 				RedirectLocationName: "@login_url-default-cafe-ingres",
 			},
-			SSL:               true,
-			SSLCertificate:    "secret.pem",
-			SSLCertificateKey: "secret.pem",
-			SSLCiphers:        "NULL",
-			SSLPorts:          []int{443},
-			SSLRedirect:       true,
+			BasicAuth: &BasicAuth{
+				Realm:    "Cafe",
+				UserFile: "/etc/nginx/secrets/default-cafe-htpasswd",
+			},
+			SSL:                  true,
+			SSLCertificate:       "secret.pem",
+			SSLCertificateKey:    "secret.pem",
+			SSLCiphers:           "NULL",
+			SSLPorts:             []int{443},
+			SSLRedirect:          true,
+			TLSPassthroughSocket: "unix:/var/lib/nginx/passthrough-https.sock",
 			Locations: []Location{
 				{
-					Path:                "/tea",
-					Upstream:            testUps,
-					ProxyConnectTimeout: "10s",
-					ProxyReadTimeout:    "10s",
-					ProxySendTimeout:    "10s",
-					ClientMaxBodySize:   "2m",
+					Path:                  "/tea",
+					Upstream:              testUps,
+					ProxyConnectTimeout:   "10s",
+					ProxyReadTimeout:      "10s",
+					ProxySendTimeout:      "10s",
+					ClientMaxBodySize:     "2m",
+					ProxyRequestBuffering: true,
+					ProxyHTTPVersion:      "1.1",
+					ClientBodyBufferSize:  "16k",
+					ClientBodyTimeout:     "60s",
 					JWTAuth: &JWTAuth{
 						Key:   "/etc/nginx/secrets/location-key.jwk",
 						Realm: "closed site",
 						Token: "$cookie_auth_token",
 					},
+					BasicAuth: &BasicAuth{
+						Realm:    "Tea",
+						UserFile: "/etc/nginx/secrets/default-tea-htpasswd",
+					},
+					ExternalAuth: &ExternalAuth{
+						LocationName:       "/_external_auth_default-tea-minion",
+						SigninLocationName: "@external_auth_signin_default-tea-minion",
+						ResponseHeaders: []ExternalAuthResponseHeader{
+							{
+								Name:             "X-Auth-Request-User",
+								Variable:         "$external_auth_x_auth_request_user",
+								UpstreamVariable: "$upstream_http_x_auth_request_user",
+							},
+						},
+					},
+					CORS: &CORS{
+						AllowOrigin:       "$ingress_default_tea_minion_cors_origin",
+						AllowMethods:      "GET, POST",
+						AllowHeaders:      "Content-Type",
+						AllowCredentials:  true,
+						MaxAge:            86400,
+						ExposeHeaders:     "X-Request-ID",
+						PreflightVariable: "$ingress_default_tea_minion_cors_preflight",
+					},
+					ProxyCache: &ProxyCache{
+						Zone:         "cafe",
+						Valid:        []string{"200 302 10m", "404 1m"},
+						Methods:      "GET HEAD",
+						Key:          "$scheme$host$request_uri",
+						Bypass:       "$http_pragma",
+						UseStale:     "error timeout updating",
+						StatusHeader: true,
+					},
 					MinionIngress: &Ingress{
 						Name:      "tea-minion",
 						Namespace: "default",
@@ -80,6 +158,14 @@ var ingCfg = IngressNginxConfig{
 					LoginURL: "https://test.example.com/login",
 				},
 			},
+			ExternalAuthLocations: []ExternalAuthLocation{
+				{
+					Name:               "/_external_auth_default-tea-minion",
+					URL:                "http://oauth2-proxy.default.svc.cluster.local/oauth2/auth",
+					SigninLocationName: "@external_auth_signin_default-tea-minion",
+					SigninURL:          "https://test.example.com/oauth2/start?rd=$escaped_request_uri",
+				},
+			},
 		},
 	},
 	Upstreams: []Upstream{testUps},
@@ -91,8 +177,17 @@ var ingCfg = IngressNginxConfig{
 }
 
 var mainCfg = MainConfig{
+	ConfPath:                "/etc/nginx",
+	LibPath:                 "/var/lib/nginx",
+	LogPath:                 "/var/log/nginx",
+	DefaultServerSecret:     "/etc/nginx/secrets/default",
 	ServerNamesHashMaxSize:  "512",
 	ServerTokens:            "off",
+	LogFormatEscaping:       "json",
+	LogGzipRatio:            true,
+	StreamLogFormatEscaping: "json",
+	AccessLogSyslog:         "syslog:server=unix:/dev/log,facility=local7,tag=nginx,severity=info",
+	ErrorLogSyslog:          "syslog:server=unix:/dev/log,facility=local7,tag=nginx",
 	WorkerProcesses:         "auto",
 	WorkerCPUAffinity:       "auto",
 	WorkerShutdownTimeout:   "1m",
@@ -108,6 +203,16 @@ var mainCfg = MainConfig{
 	KeepaliveRequests:       100,
 	VariablesHashBucketSize: 256,
 	VariablesHashMaxSize:    1024,
+	TLSPassthrough:          true,
+	ProxyCacheZones: []ProxyCacheZone{
+		{
+			Name:     "cafe",
+			Path:     "/var/cache/nginx/cache_cafe",
+			Size:     "10m",
+			MaxSize:  "1g",
+			Inactive: "60m",
+		},
+	},
 }
 
 func TestIngressForNGINXPlus(t *testing.T) {
@@ -170,6 +275,141 @@ func TestMainForNGINX(t *testing.T) {
 	}
 }
 
+func TestMainWithTLSPassthrough(t *testing.T) {
+	expectedLines := []string{
+		"listen unix:/var/lib/nginx/passthrough-https.sock ssl default_server proxy_protocol;",
+		"map $ssl_preread_server_name $dest_internal_passthrough {",
+		"default unix:/var/lib/nginx/passthrough-https.sock;",
+		"include /etc/nginx/tls-passthrough-hosts.d/*.conf;",
+		"ssl_preread on;",
+		"proxy_pass $dest_internal_passthrough;",
+	}
+
+	for _, tmplName := range []string{nginxMainTmpl, nginxPlusMainTmpl} {
+		tmpl, err := template.New(tmplName).ParseFiles(tmplName)
+		if err != nil {
+			t.Fatalf("Failed to parse template file: %v", err)
+		}
+
+		var buf bytes.Buffer
+
+		err = tmpl.Execute(&buf, mainCfg)
+		if err != nil {
+			t.Fatalf("Failed to write template %v", err)
+		}
+
+		for _, line := range expectedLines {
+			if !strings.Contains(buf.String(), line) {
+				t.Errorf("Template %v returned a config without %q", tmplName, line)
+			}
+		}
+	}
+}
+
+func TestMainUsesLogPath(t *testing.T) {
+	cfg := mainCfg
+	cfg.LogPath = "/tmp/nginx/log"
+	cfg.AccessLogSyslog = ""
+	cfg.ErrorLogSyslog = ""
+
+	expectedLines := []string{
+		"error_log  /tmp/nginx/log/error.log",
+		"access_log  /tmp/nginx/log/access.log  main;",
+		"access_log  /tmp/nginx/log/stream-access.log  stream-main;",
+	}
+
+	for _, tmplName := range []string{nginxMainTmpl, nginxPlusMainTmpl} {
+		tmpl, err := template.New(tmplName).ParseFiles(tmplName)
+		if err != nil {
+			t.Fatalf("Failed to parse template file: %v", err)
+		}
+
+		var buf bytes.Buffer
+
+		err = tmpl.Execute(&buf, cfg)
+		if err != nil {
+			t.Fatalf("Failed to write template %v", err)
+		}
+
+		for _, line := range expectedLines {
+			if !strings.Contains(buf.String(), line) {
+				t.Errorf("Template %v returned a config without %q", tmplName, line)
+			}
+		}
+	}
+}
+
+func TestMainLogsGzipRatioOnlyWithGzip(t *testing.T) {
+	for _, tmplName := range []string{nginxMainTmpl, nginxPlusMainTmpl} {
+		tmpl, err := template.New(tmplName).ParseFiles(tmplName)
+		if err != nil {
+			t.Fatalf("Failed to parse template file: %v", err)
+		}
+
+		for _, logGzipRatio := range []bool{true, false} {
+			cfg := mainCfg
+			cfg.LogGzipRatio = logGzipRatio
+
+			var buf bytes.Buffer
+
+			err = tmpl.Execute(&buf, cfg)
+			if err != nil {
+				t.Fatalf("Failed to write template %v", err)
+			}
+
+			if result := strings.Contains(buf.String(), "$gzip_ratio"); result != logGzipRatio {
+				t.Errorf("Template %v returned a config with $gzip_ratio %v but expected %v", tmplName, result, logGzipRatio)
+			}
+		}
+	}
+}
+
+func TestIngressListensOnTLSPassthroughSocket(t *testing.T) {
+	tmpl, err := template.New(nginxIngressTmpl).Funcs(helperFunctions).ParseFiles(nginxIngressTmpl)
+	if err != nil {
+		t.Fatalf("Failed to parse template file: %v", err)
+	}
+
+	var buf bytes.Buffer
+
+	err = tmpl.Execute(&buf, ingCfg)
+	if err != nil {
+		t.Fatalf("Failed to write template %v", err)
+	}
+
+	expected := "listen unix:/var/lib/nginx/passthrough-https.sock ssl proxy_protocol;"
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("Template %v returned a config without %q", nginxIngressTmpl, expected)
+	}
+}
+
+func TestIngressSetsResourceVariablesForMinions(t *testing.T) {
+	expectedLines := []string{
+		`set $resource_name "tea-minion";`,
+		`set $resource_namespace "default";`,
+	}
+
+	for _, tmplName := range []string{nginxIngressTmpl, nginxPlusIngressTmpl} {
+		tmpl, err := template.New(tmplName).Funcs(helperFunctions).ParseFiles(tmplName)
+		if err != nil {
+			t.Fatalf("Failed to parse template file: %v", err)
+		}
+
+		var buf bytes.Buffer
+
+		err = tmpl.Execute(&buf, ingCfg)
+		if err != nil {
+			t.Fatalf("Failed to write template %v", err)
+		}
+
+		for _, line := range expectedLines {
+			if !strings.Contains(buf.String(), line) {
+				t.Errorf("Template %v returned a config without %q", tmplName, line)
+			}
+		}
+	}
+}
+
 func TestSplitHelperFunction(t *testing.T) {
 	const tpl = `{{range $n := split . ","}}{{$n}} {{end}}`
 
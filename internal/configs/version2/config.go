@@ -6,9 +6,28 @@ type VirtualServerConfig struct {
 	Upstreams     []Upstream
 	SplitClients  []SplitClient
 	Maps          []Map
+	Geos          []Geo
 	StatusMatches []StatusMatch
 }
 
+// TLSPassthroughConfig holds NGINX configuration for TLS Passthrough of a VirtualServer.
+type TLSPassthroughConfig struct {
+	Hosts    []string
+	Socket   string
+	Upstream StreamUpstream
+}
+
+// StreamUpstream defines an upstream in the stream context.
+type StreamUpstream struct {
+	Name    string
+	Servers []StreamUpstreamServer
+}
+
+// StreamUpstreamServer defines a server of an upstream in the stream context.
+type StreamUpstreamServer struct {
+	Address string
+}
+
 // Upstream defines an upstream.
 type Upstream struct {
 	Name             string
@@ -32,20 +51,36 @@ type UpstreamServer struct {
 // Server defines a server.
 type Server struct {
 	ServerName                            string
+	ServerAliases                         []string
+	VSName                                string
+	VSNamespace                           string
 	StatusZone                            string
 	ProxyProtocol                         bool
+	TLSPassthroughSocket                  string
 	SSL                                   *SSL
 	RedirectToHTTPSBasedOnXForwarderProto bool
 	ServerTokens                          string
+	Gzip                                  *Gzip
+	AccessLog                             string
 	RealIPHeader                          string
 	SetRealIPFrom                         []string
 	RealIPRecursive                       bool
 	Snippets                              []string
 	InternalRedirectLocations             []InternalRedirectLocation
+	ExternalAuthLocations                 []ExternalAuthLocation
+	MirrorLocations                       []MirrorLocation
 	Locations                             []Location
 	HealthChecks                          []HealthCheck
 }
 
+// Gzip defines the gzip compression configuration of a server.
+type Gzip struct {
+	Types     string
+	MinLength int
+	CompLevel int
+	Proxied   string
+}
+
 // SSL defines SSL configuration for a server.
 type SSL struct {
 	HTTP2           bool
@@ -67,11 +102,100 @@ type Location struct {
 	ProxyBuffering           bool
 	ProxyBuffers             string
 	ProxyBufferSize          string
+	ProxyRequestBuffering    bool
+	ProxyHTTPVersion         string
+	ProxyIgnoreClientAbort   bool
+	ClientBodyBufferSize     string
+	ClientBodyTimeout        string
 	ProxyPass                string
 	ProxyNextUpstream        string
 	ProxyNextUpstreamTimeout string
 	ProxyNextUpstreamTries   int
 	HasKeepalive             bool
+	BasicAuth                *BasicAuth
+	ExternalAuth             *ExternalAuth
+	CORS                     *CORS
+	ProxyCache               *ProxyCache
+	Mirror                   *Mirror
+	Variables                []Variable
+}
+
+// Variable defines a variable that is set in a location.
+type Variable struct {
+	Name  string
+	Value string
+}
+
+// CORS defines the CORS configuration of a location. AllowOrigin is either `*` or a variable with the origin of
+// the request if the origin is allowed.
+type CORS struct {
+	AllowOrigin       string
+	AllowMethods      string
+	AllowHeaders      string
+	AllowCredentials  bool
+	MaxAge            int
+	ExposeHeaders     string
+	PreflightVariable string
+}
+
+// ProxyCache defines the caching of the responses of a location.
+type ProxyCache struct {
+	Zone         string
+	Valid        []string
+	Methods      string
+	Key          string
+	Bypass       string
+	UseStale     string
+	StatusHeader bool
+}
+
+// BasicAuth defines HTTP basic authentication configuration of a location.
+type BasicAuth struct {
+	Realm    string
+	UserFile string
+}
+
+// ExternalAuth defines external authentication configuration of a location.
+type ExternalAuth struct {
+	LocationName       string
+	SigninLocationName string
+	ResponseHeaders    []ExternalAuthResponseHeader
+}
+
+// ExternalAuthResponseHeader defines a header of the response of an external authentication service that is passed
+// to the upstream.
+type ExternalAuthResponseHeader struct {
+	Name             string
+	Variable         string
+	UpstreamVariable string
+}
+
+// ExternalAuthLocation defines the internal locations for sending authentication subrequests to an external
+// authentication service and for redirecting unauthenticated client requests to a sign-in URL.
+type ExternalAuthLocation struct {
+	Name               string
+	ProxyPass          string
+	SigninLocationName string
+	SigninURL          string
+}
+
+// Mirror defines the mirroring of the requests of a location.
+type Mirror struct {
+	LocationName string
+	RequestBody  bool
+}
+
+// MirrorLocation defines an internal location for the mirror subrequests. If Variable is set, only the requests for
+// which the variable is not empty are passed to the upstream.
+type MirrorLocation struct {
+	Name                string
+	Variable            string
+	ProxyConnectTimeout string
+	ProxyReadTimeout    string
+	ProxySendTimeout    string
+	ProxyPass           string
+	HasKeepalive        bool
+	RequestBody         bool
 }
 
 // SplitClient defines a split_clients.
@@ -117,7 +241,14 @@ type Map struct {
 	Parameters []Parameter
 }
 
-// Parameter defines a Parameter in a Map.
+// Geo defines a geo block. The Value of each Parameter is an IP address or a CIDR range.
+type Geo struct {
+	Source     string
+	Variable   string
+	Parameters []Parameter
+}
+
+// Parameter defines a Parameter in a Map or a Geo.
 type Parameter struct {
 	Value  string
 	Result string
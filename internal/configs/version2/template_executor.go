@@ -8,19 +8,26 @@ import (
 
 // TemplateExecutor executes NGINX configuration templates.
 type TemplateExecutor struct {
-	virtualServerTemplate *template.Template
+	virtualServerTemplate  *template.Template
+	tlsPassthroughTemplate *template.Template
 }
 
 // NewTemplateExecutor creates a TemplateExecutor.
-func NewTemplateExecutor(virtualServerTemplatePath string) (*TemplateExecutor, error) {
+func NewTemplateExecutor(virtualServerTemplatePath string, tlsPassthroughTemplatePath string) (*TemplateExecutor, error) {
 	// template name must be the base name of the template file https://golang.org/pkg/text/template/#Template.ParseFiles
 	vsTemplate, err := template.New(path.Base(virtualServerTemplatePath)).ParseFiles(virtualServerTemplatePath)
 	if err != nil {
 		return nil, err
 	}
 
+	tlsPassthroughTemplate, err := template.New(path.Base(tlsPassthroughTemplatePath)).ParseFiles(tlsPassthroughTemplatePath)
+	if err != nil {
+		return nil, err
+	}
+
 	return &TemplateExecutor{
-		virtualServerTemplate: vsTemplate,
+		virtualServerTemplate:  vsTemplate,
+		tlsPassthroughTemplate: tlsPassthroughTemplate,
 	}, nil
 }
 
@@ -31,3 +38,11 @@ func (te *TemplateExecutor) ExecuteVirtualServerTemplate(cfg *VirtualServerConfi
 
 	return configBuffer.Bytes(), err
 }
+
+// ExecuteTLSPassthroughTemplate generates the content of an NGINX stream configuration file for TLS Passthrough of a VirtualServer resource.
+func (te *TemplateExecutor) ExecuteTLSPassthroughTemplate(cfg *TLSPassthroughConfig) ([]byte, error) {
+	var configBuffer bytes.Buffer
+	err := te.tlsPassthroughTemplate.Execute(&configBuffer, cfg)
+
+	return configBuffer.Bytes(), err
+}
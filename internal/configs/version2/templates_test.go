@@ -1,9 +1,13 @@
 package version2
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 const nginxPlusVirtualServerTmpl = "nginx-plus.virtualserver.tmpl"
 const nginxVirtualServerTmpl = "nginx.virtualserver.tmpl"
+const nginxTLSPassthroughTmpl = "nginx.tlspassthrough.tmpl"
 
 var virtualServerCfg = VirtualServerConfig{
 	Upstreams: []Upstream{
@@ -94,10 +98,30 @@ var virtualServerCfg = VirtualServerConfig{
 			},
 		},
 	},
+	Geos: []Geo{
+		{
+			Source:   "$remote_addr",
+			Variable: "$match_0_1_geo",
+			Parameters: []Parameter{
+				{
+					Value:  "10.0.0.0/8",
+					Result: "1",
+				},
+				{
+					Value:  "default",
+					Result: "0",
+				},
+			},
+		},
+	},
 	Server: Server{
-		ServerName:    "example.com",
-		StatusZone:    "example.com",
-		ProxyProtocol: true,
+		ServerName:           "example.com",
+		ServerAliases:        []string{"www.example.com", "*.example.org"},
+		VSName:               "cafe",
+		VSNamespace:          "default",
+		TLSPassthroughSocket: "unix:/var/lib/nginx/passthrough-https.sock",
+		StatusZone:           "example.com",
+		ProxyProtocol:        true,
 		SSL: &SSL{
 			HTTP2:           true,
 			Certificate:     "cafe-secret.pem",
@@ -107,10 +131,17 @@ var virtualServerCfg = VirtualServerConfig{
 		},
 		RedirectToHTTPSBasedOnXForwarderProto: true,
 		ServerTokens:                          "off",
-		SetRealIPFrom:                         []string{"0.0.0.0/0"},
-		RealIPHeader:                          "X-Real-IP",
-		RealIPRecursive:                       true,
-		Snippets:                              []string{"# server snippet"},
+		AccessLog:                             "/var/log/nginx/cafe.log main",
+		Gzip: &Gzip{
+			Types:     "text/css application/json",
+			MinLength: 1000,
+			CompLevel: 5,
+			Proxied:   "any",
+		},
+		SetRealIPFrom:   []string{"0.0.0.0/0"},
+		RealIPHeader:    "X-Real-IP",
+		RealIPRecursive: true,
+		Snippets:        []string{"# server snippet"},
 		InternalRedirectLocations: []InternalRedirectLocation{
 			{
 				Path:        "/split",
@@ -121,19 +152,78 @@ var virtualServerCfg = VirtualServerConfig{
 				Destination: "@match",
 			},
 		},
+		ExternalAuthLocations: []ExternalAuthLocation{
+			{
+				Name:               "/_external_auth_0",
+				ProxyPass:          "http://auth-upstream/auth",
+				SigninLocationName: "@external_auth_signin_0",
+				SigninURL:          "https://example.com/signin",
+			},
+		},
+		MirrorLocations: []MirrorLocation{
+			{
+				Name:                "/_mirror_0",
+				Variable:            "$vs_default_cafe_mirror_0",
+				ProxyConnectTimeout: "30s",
+				ProxyReadTimeout:    "31s",
+				ProxySendTimeout:    "32s",
+				ProxyPass:           "http://shadow-upstream",
+				HasKeepalive:        true,
+				RequestBody:         false,
+			},
+		},
 		Locations: []Location{
 			{
-				Path:                 "/",
-				Snippets:             []string{"# location snippet"},
-				ProxyConnectTimeout:  "30s",
-				ProxyReadTimeout:     "31s",
-				ProxySendTimeout:     "32s",
-				ClientMaxBodySize:    "1m",
-				ProxyBuffering:       true,
-				ProxyBuffers:         "8 4k",
-				ProxyBufferSize:      "4k",
-				ProxyMaxTempFileSize: "1024m",
-				ProxyPass:            "http://test-upstream",
+				Path:                  "/",
+				Snippets:              []string{"# location snippet"},
+				ProxyConnectTimeout:   "30s",
+				ProxyReadTimeout:      "31s",
+				ProxySendTimeout:      "32s",
+				ClientMaxBodySize:     "1m",
+				ProxyBuffering:        true,
+				ProxyBuffers:          "8 4k",
+				ProxyBufferSize:       "4k",
+				ProxyRequestBuffering: true,
+				ProxyHTTPVersion:      "1.1",
+				ClientBodyBufferSize:  "16k",
+				ClientBodyTimeout:     "60s",
+				ProxyMaxTempFileSize:  "1024m",
+				ProxyPass:             "http://test-upstream",
+				CORS: &CORS{
+					AllowOrigin:       "*",
+					AllowMethods:      "GET, POST",
+					AllowHeaders:      "Content-Type",
+					MaxAge:            86400,
+					PreflightVariable: "$vs_default_cafe_cors_0_preflight",
+				},
+				Mirror: &Mirror{
+					LocationName: "/_mirror_0",
+					RequestBody:  false,
+				},
+				ProxyCache: &ProxyCache{
+					Zone:         "cafe",
+					Valid:        []string{"10m"},
+					Methods:      "GET HEAD",
+					Key:          "$scheme$host$request_uri",
+					Bypass:       "$http_pragma",
+					UseStale:     "error updating",
+					StatusHeader: true,
+				},
+				BasicAuth: &BasicAuth{
+					Realm:    "Restricted",
+					UserFile: "/etc/nginx/secrets/default-htpasswd",
+				},
+				ExternalAuth: &ExternalAuth{
+					LocationName:       "/_external_auth_0",
+					SigninLocationName: "@external_auth_signin_0",
+					ResponseHeaders: []ExternalAuthResponseHeader{
+						{
+							Name:             "X-User",
+							Variable:         "$external_auth_x_user",
+							UpstreamVariable: "$upstream_http_x_user",
+						},
+					},
+				},
 			},
 			{
 				Path:                "@loc0",
@@ -142,6 +232,12 @@ var virtualServerCfg = VirtualServerConfig{
 				ProxySendTimeout:    "32s",
 				ClientMaxBodySize:   "1m",
 				ProxyPass:           "http://coffee-v1",
+				Variables: []Variable{
+					{
+						Name:  "$path_capture_1",
+						Value: "$vs_default_cafe_rules_0_match_0_path_capture_1",
+					},
+				},
 			},
 			{
 				Path:                "@loc1",
@@ -171,8 +267,21 @@ var virtualServerCfg = VirtualServerConfig{
 	},
 }
 
+var tlsPassthroughCfg = TLSPassthroughConfig{
+	Hosts:  []string{"app.example.com", "*.app.example.com"},
+	Socket: "unix:/var/lib/nginx/passthrough-vs_default_app.sock",
+	Upstream: StreamUpstream{
+		Name: "vs_default_app_secure-app",
+		Servers: []StreamUpstreamServer{
+			{
+				Address: "10.0.0.20:8443",
+			},
+		},
+	},
+}
+
 func TestVirtualServerForNginxPlus(t *testing.T) {
-	executor, err := NewTemplateExecutor(nginxPlusVirtualServerTmpl)
+	executor, err := NewTemplateExecutor(nginxPlusVirtualServerTmpl, nginxTLSPassthroughTmpl)
 	if err != nil {
 		t.Fatalf("Failed to create template executor: %v", err)
 	}
@@ -186,7 +295,7 @@ func TestVirtualServerForNginxPlus(t *testing.T) {
 }
 
 func TestVirtualServerForNginx(t *testing.T) {
-	executor, err := NewTemplateExecutor(nginxVirtualServerTmpl)
+	executor, err := NewTemplateExecutor(nginxVirtualServerTmpl, nginxTLSPassthroughTmpl)
 	if err != nil {
 		t.Fatalf("Failed to create template executor: %v", err)
 	}
@@ -198,3 +307,55 @@ func TestVirtualServerForNginx(t *testing.T) {
 
 	t.Log(string(data))
 }
+
+func TestTLSPassthrough(t *testing.T) {
+	executor, err := NewTemplateExecutor(nginxVirtualServerTmpl, nginxTLSPassthroughTmpl)
+	if err != nil {
+		t.Fatalf("Failed to create template executor: %v", err)
+	}
+
+	data, err := executor.ExecuteTLSPassthroughTemplate(&tlsPassthroughCfg)
+	if err != nil {
+		t.Fatalf("Failed to execute template: %v", err)
+	}
+
+	t.Log(string(data))
+
+	expectedLines := []string{
+		"upstream vs_default_app_secure-app {",
+		"server 10.0.0.20:8443;",
+		"listen unix:/var/lib/nginx/passthrough-vs_default_app.sock proxy_protocol;",
+		"set_real_ip_from unix:;",
+		"proxy_pass vs_default_app_secure-app;",
+	}
+	for _, line := range expectedLines {
+		if !strings.Contains(string(data), line) {
+			t.Errorf("ExecuteTLSPassthroughTemplate() returned a config without %q", line)
+		}
+	}
+}
+
+func TestVirtualServerListensOnTLSPassthroughSocket(t *testing.T) {
+	executor, err := NewTemplateExecutor(nginxVirtualServerTmpl, nginxTLSPassthroughTmpl)
+	if err != nil {
+		t.Fatalf("Failed to create template executor: %v", err)
+	}
+
+	data, err := executor.ExecuteVirtualServerTemplate(&virtualServerCfg)
+	if err != nil {
+		t.Fatalf("Failed to execute template: %v", err)
+	}
+
+	expectedLines := []string{
+		"listen unix:/var/lib/nginx/passthrough-https.sock ssl http2 proxy_protocol;",
+		"set_real_ip_from unix:;",
+	}
+	for _, line := range expectedLines {
+		if !strings.Contains(string(data), line) {
+			t.Errorf("ExecuteVirtualServerTemplate() returned a config without %q", line)
+		}
+	}
+	if strings.Contains(string(data), "listen 443 ssl") {
+		t.Errorf("ExecuteVirtualServerTemplate() returned a config that listens on port 443 with TLS Passthrough enabled")
+	}
+}
@@ -1,10 +1,12 @@
 package configs
 
 import (
+	"bytes"
 	"fmt"
+	"path"
 	"strings"
 
-	"github.com/golang/glog"
+	"github.com/nginxinc/kubernetes-ingress/internal/logging"
 	"github.com/nginxinc/kubernetes-ingress/internal/nginx"
 	api_v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -14,7 +16,13 @@ import (
 	conf_v1alpha1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
 )
 
-const nginx502Server = "unix:/var/lib/nginx-502-server.sock"
+// nginx502ServerSocket is the filename of the unix socket in the -nginx-lib-path directory of the server that
+// responds with 502 to the requests for the upstreams without endpoints. The server is defined in the main template.
+const nginx502ServerSocket = "nginx-502-server.sock"
+
+func getNginx502Server(libPath string) string {
+	return "unix:" + path.Join(libPath, nginx502ServerSocket)
+}
 
 var incompatibleLBMethodsForSlowStart = map[string]bool{
 	"random":                          true,
@@ -32,6 +40,8 @@ type VirtualServerEx struct {
 	TLSSecret           *api_v1.Secret
 	VirtualServerRoutes []*conf_v1alpha1.VirtualServerRoute
 	ExternalNameSvcs    map[string]bool
+	PodsByIP            map[string]string
+	HtpasswdSecrets     map[string]*api_v1.Secret
 }
 
 func (vsx *VirtualServerEx) String() string {
@@ -93,10 +103,30 @@ func (namer *variableNamer) GetNameForVariableForRulesRouteMap(rulesIndex int, m
 	return fmt.Sprintf("$vs_%s_rules_%d_match_%d_cond_%d", namer.safeNsName, rulesIndex, matchIndex, conditionIndex)
 }
 
+func (namer *variableNamer) GetNameForVariableForRulesRouteGeo(rulesIndex int, matchIndex int, conditionIndex int) string {
+	return fmt.Sprintf("$vs_%s_rules_%d_match_%d_cond_%d_geo", namer.safeNsName, rulesIndex, matchIndex, conditionIndex)
+}
+
+func (namer *variableNamer) GetNameForVariableForRulesRoutePathCapture(rulesIndex int, matchIndex int, captureIndex int) string {
+	return fmt.Sprintf("$vs_%s_rules_%d_match_%d_path_capture_%d", namer.safeNsName, rulesIndex, matchIndex, captureIndex)
+}
+
 func (namer *variableNamer) GetNameForVariableForRulesRouteMainMap(rulesIndex int) string {
 	return fmt.Sprintf("$vs_%s_rules_%d", namer.safeNsName, rulesIndex)
 }
 
+func (namer *variableNamer) GetNameForMirrorVariable(index int) string {
+	return fmt.Sprintf("$vs_%s_mirror_%d", namer.safeNsName, index)
+}
+
+func (namer *variableNamer) GetNameForCORSPreflightVariable(corsIndex int) string {
+	return fmt.Sprintf("$vs_%s_cors_%d_preflight", namer.safeNsName, corsIndex)
+}
+
+func (namer *variableNamer) GetNameForCORSOriginVariable(corsIndex int) string {
+	return fmt.Sprintf("$vs_%s_cors_%d_origin", namer.safeNsName, corsIndex)
+}
+
 func newHealthCheckWithDefaults(upstream conf_v1alpha1.Upstream, upstreamName string, cfgParams *ConfigParams) *version2.HealthCheck {
 	return &version2.HealthCheck{
 		Name:                upstreamName,
@@ -114,11 +144,23 @@ func newHealthCheckWithDefaults(upstream conf_v1alpha1.Upstream, upstreamName st
 	}
 }
 
+// specialTLSSecrets holds the filenames of the special TLS secrets, which are used for TLS termination of a VirtualServer
+// without a TLS secret or with a missing or invalid TLS secret.
+type specialTLSSecrets struct {
+	defaultServerPemFileName string
+	// wildcardPemFileName is empty if the wildcard TLS secret is not configured.
+	wildcardPemFileName string
+	// isDefaultFallbackEnabled enables using the default server secret instead of breaking TLS connections.
+	isDefaultFallbackEnabled bool
+}
+
 // VirtualServerConfigurator generates a VirtualServer configuration
 type virtualServerConfigurator struct {
 	cfgParams            *ConfigParams
 	isPlus               bool
 	isResolverConfigured bool
+	nginx502Server       string
+	logPath              string
 	warnings             Warnings
 }
 
@@ -131,11 +173,13 @@ func (vsc *virtualServerConfigurator) clearWarnings() {
 }
 
 // newVirtualServerConfigurator creates a new VirtualServerConfigurator
-func newVirtualServerConfigurator(cfgParams *ConfigParams, isPlus bool, isResolverConfigured bool) *virtualServerConfigurator {
+func newVirtualServerConfigurator(cfgParams *ConfigParams, isPlus bool, isResolverConfigured bool, staticParams *StaticConfigParams) *virtualServerConfigurator {
 	return &virtualServerConfigurator{
 		cfgParams:            cfgParams,
 		isPlus:               isPlus,
 		isResolverConfigured: isResolverConfigured,
+		nginx502Server:       getNginx502Server(staticParams.NginxLibPath),
+		logPath:              staticParams.NginxLogPath,
 		warnings:             make(map[runtime.Object][]string),
 	}
 }
@@ -145,7 +189,7 @@ func (vsc *virtualServerConfigurator) generateEndpointsForUpstream(owner runtime
 	externalNameSvcKey := GenerateExternalNameSvcKey(namespace, upstream.Service)
 	endpoints := virtualServerEx.Endpoints[endpointsKey]
 	if !vsc.isPlus && len(endpoints) == 0 {
-		return []string{nginx502Server}
+		return []string{vsc.nginx502Server}
 	}
 
 	_, isExternalNameSvc := virtualServerEx.ExternalNameSvcs[externalNameSvcKey]
@@ -159,9 +203,15 @@ func (vsc *virtualServerConfigurator) generateEndpointsForUpstream(owner runtime
 }
 
 // GenerateVirtualServerConfig generates a full configuration for a VirtualServer
-func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(virtualServerEx *VirtualServerEx, tlsPemFileName string) (version2.VirtualServerConfig, Warnings) {
+// tlsPassthroughSocket is the unix socket of the HTTPS servers when TLS Passthrough is enabled, otherwise it is empty.
+func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(virtualServerEx *VirtualServerEx, tlsPemFileName string, specialSecrets specialTLSSecrets,
+	tlsPassthroughSocket string, htpasswdFileNames map[string]string) (version2.VirtualServerConfig, Warnings) {
 	vsc.clearWarnings()
-	ssl := generateSSLConfig(virtualServerEx.VirtualServer.Spec.TLS, tlsPemFileName, vsc.cfgParams)
+	ssl := vsc.generateSSLConfig(virtualServerEx.VirtualServer, virtualServerEx.VirtualServer.Spec.TLS, tlsPemFileName, specialSecrets, vsc.cfgParams)
+
+	if virtualServerEx.VirtualServer.Spec.TLSPassthrough != nil && tlsPassthroughSocket == "" {
+		vsc.addWarningf(virtualServerEx.VirtualServer, "TLS Passthrough is not enabled, tlsPassthrough will be ignored. To use tlsPassthrough, the -enable-tls-passthrough command-line argument must be set")
+	}
 
 	// crUpstreams maps an UpstreamName to its conf_v1alpha1.Upstream as they are generated
 	// necessary for generateLocation to know what Upstream each Location references
@@ -217,10 +267,14 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(virtualServerE
 
 	var locations []version2.Location
 	var internalRedirectLocations []version2.InternalRedirectLocation
+	var externalAuthLocations []version2.ExternalAuthLocation
+	var mirrorLocations []version2.MirrorLocation
 	var splitClients []version2.SplitClient
 	var maps []version2.Map
+	var geos []version2.Geo
 
 	rulesRoutes := 0
+	corsRoutes := 0
 
 	variableNamer := newVariableNamer(virtualServerEx.VirtualServer)
 
@@ -231,6 +285,8 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(virtualServerE
 			continue
 		}
 
+		routeLocationsStart := len(locations)
+
 		if len(r.Splits) > 0 {
 			splitCfg := generateSplitRouteConfig(r, virtualServerUpstreamNamer, crUpstreams, variableNamer, len(splitClients), vsc.cfgParams)
 
@@ -238,9 +294,11 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(virtualServerE
 			locations = append(locations, splitCfg.Locations...)
 			internalRedirectLocations = append(internalRedirectLocations, splitCfg.InternalRedirectLocation)
 		} else if r.Rules != nil {
-			rulesRouteCfg := generateRulesRouteConfig(r, virtualServerUpstreamNamer, crUpstreams, variableNamer, rulesRoutes, vsc.cfgParams)
+			rulesRouteCfg := generateRulesRouteConfig(r, virtualServerUpstreamNamer, crUpstreams, variableNamer, rulesRoutes, len(splitClients), vsc.cfgParams)
 
 			maps = append(maps, rulesRouteCfg.Maps...)
+			geos = append(geos, rulesRouteCfg.Geos...)
+			splitClients = append(splitClients, rulesRouteCfg.SplitClients...)
 			locations = append(locations, rulesRouteCfg.Locations...)
 			internalRedirectLocations = append(internalRedirectLocations, rulesRouteCfg.InternalRedirectLocation)
 
@@ -248,16 +306,50 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(virtualServerE
 		} else {
 			upstreamName := virtualServerUpstreamNamer.GetNameForUpstream(r.Upstream)
 			upstream := crUpstreams[upstreamName]
-			loc := generateLocation(r.Path, upstreamName, upstream, vsc.cfgParams)
+			loc := generateLocation(r.Path, upstreamName, upstream, r.Proxy, vsc.cfgParams)
 			locations = append(locations, loc)
 		}
 
+		if r.ExternalAuth != nil {
+			auth, authLocation := generateExternalAuth(r.ExternalAuth, virtualServerUpstreamNamer, crUpstreams, len(externalAuthLocations))
+			addExternalAuthToLocations(locations[routeLocationsStart:], auth)
+			externalAuthLocations = append(externalAuthLocations, authLocation)
+		}
+
+		if r.BasicAuth != nil {
+			auth := vsc.generateBasicAuth(virtualServerEx.VirtualServer, r.BasicAuth, virtualServerEx.VirtualServer.Namespace, virtualServerEx.HtpasswdSecrets, htpasswdFileNames)
+			addBasicAuthToLocations(locations[routeLocationsStart:], auth)
+		}
+
+		if r.CORS != nil {
+			cors, corsMaps := generateCORS(r.CORS, variableNamer, corsRoutes, vsc.cfgParams)
+			addCORSToLocations(locations[routeLocationsStart:], cors)
+			maps = append(maps, corsMaps...)
+
+			corsRoutes++
+		}
+
+		if r.Cache != nil {
+			cache := vsc.generateProxyCache(virtualServerEx.VirtualServer, r.Cache)
+			addProxyCacheToLocations(locations[routeLocationsStart:], cache)
+		}
+
+		if r.Mirror != nil {
+			mirror, mirrorLocation, splitClient := generateMirror(r.Mirror, virtualServerUpstreamNamer, crUpstreams, variableNamer, len(mirrorLocations), vsc.cfgParams)
+			addMirrorToLocations(locations[routeLocationsStart:], mirror)
+			mirrorLocations = append(mirrorLocations, mirrorLocation)
+			if splitClient != nil {
+				splitClients = append(splitClients, *splitClient)
+			}
+		}
 	}
 
 	// generate config for subroutes of each VirtualServerRoute
 	for _, vsr := range virtualServerEx.VirtualServerRoutes {
 		upstreamNamer := newUpstreamNamerForVirtualServerRoute(virtualServerEx.VirtualServer, vsr)
 		for _, r := range vsr.Spec.Subroutes {
+			routeLocationsStart := len(locations)
+
 			if len(r.Splits) > 0 {
 				splitCfg := generateSplitRouteConfig(r, upstreamNamer, crUpstreams, variableNamer, len(splitClients), vsc.cfgParams)
 
@@ -265,9 +357,11 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(virtualServerE
 				locations = append(locations, splitCfg.Locations...)
 				internalRedirectLocations = append(internalRedirectLocations, splitCfg.InternalRedirectLocation)
 			} else if r.Rules != nil {
-				rulesRouteCfg := generateRulesRouteConfig(r, upstreamNamer, crUpstreams, variableNamer, rulesRoutes, vsc.cfgParams)
+				rulesRouteCfg := generateRulesRouteConfig(r, upstreamNamer, crUpstreams, variableNamer, rulesRoutes, len(splitClients), vsc.cfgParams)
 
 				maps = append(maps, rulesRouteCfg.Maps...)
+				geos = append(geos, rulesRouteCfg.Geos...)
+				splitClients = append(splitClients, rulesRouteCfg.SplitClients...)
 				locations = append(locations, rulesRouteCfg.Locations...)
 				internalRedirectLocations = append(internalRedirectLocations, rulesRouteCfg.InternalRedirectLocation)
 
@@ -275,9 +369,42 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(virtualServerE
 			} else {
 				upstreamName := upstreamNamer.GetNameForUpstream(r.Upstream)
 				upstream := crUpstreams[upstreamName]
-				loc := generateLocation(r.Path, upstreamName, upstream, vsc.cfgParams)
+				loc := generateLocation(r.Path, upstreamName, upstream, r.Proxy, vsc.cfgParams)
 				locations = append(locations, loc)
 			}
+
+			if r.ExternalAuth != nil {
+				auth, authLocation := generateExternalAuth(r.ExternalAuth, upstreamNamer, crUpstreams, len(externalAuthLocations))
+				addExternalAuthToLocations(locations[routeLocationsStart:], auth)
+				externalAuthLocations = append(externalAuthLocations, authLocation)
+			}
+
+			if r.BasicAuth != nil {
+				auth := vsc.generateBasicAuth(vsr, r.BasicAuth, vsr.Namespace, virtualServerEx.HtpasswdSecrets, htpasswdFileNames)
+				addBasicAuthToLocations(locations[routeLocationsStart:], auth)
+			}
+
+			if r.CORS != nil {
+				cors, corsMaps := generateCORS(r.CORS, variableNamer, corsRoutes, vsc.cfgParams)
+				addCORSToLocations(locations[routeLocationsStart:], cors)
+				maps = append(maps, corsMaps...)
+
+				corsRoutes++
+			}
+
+			if r.Cache != nil {
+				cache := vsc.generateProxyCache(vsr, r.Cache)
+				addProxyCacheToLocations(locations[routeLocationsStart:], cache)
+			}
+
+			if r.Mirror != nil {
+				mirror, mirrorLocation, splitClient := generateMirror(r.Mirror, upstreamNamer, crUpstreams, variableNamer, len(mirrorLocations), vsc.cfgParams)
+				addMirrorToLocations(locations[routeLocationsStart:], mirror)
+				mirrorLocations = append(mirrorLocations, mirrorLocation)
+				if splitClient != nil {
+					splitClients = append(splitClients, *splitClient)
+				}
+			}
 		}
 	}
 
@@ -285,19 +412,28 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(virtualServerE
 		Upstreams:     upstreams,
 		SplitClients:  splitClients,
 		Maps:          maps,
+		Geos:          geos,
 		StatusMatches: statusMatches,
 		Server: version2.Server{
 			ServerName:                            virtualServerEx.VirtualServer.Spec.Host,
+			ServerAliases:                         virtualServerEx.VirtualServer.Spec.ServerAliases,
+			VSName:                                virtualServerEx.VirtualServer.Name,
+			VSNamespace:                           virtualServerEx.VirtualServer.Namespace,
 			StatusZone:                            virtualServerEx.VirtualServer.Spec.Host,
 			ProxyProtocol:                         vsc.cfgParams.ProxyProtocol,
+			TLSPassthroughSocket:                  tlsPassthroughSocket,
 			SSL:                                   ssl,
 			RedirectToHTTPSBasedOnXForwarderProto: vsc.cfgParams.RedirectToHTTPS,
 			ServerTokens:                          vsc.cfgParams.ServerTokens,
+			Gzip:                                  generateGzip(virtualServerEx.VirtualServer.Spec.Gzip, vsc.cfgParams),
+			AccessLog:                             generateAccessLog(virtualServerEx.VirtualServer.Spec.AccessLog, vsc.logPath),
 			SetRealIPFrom:                         vsc.cfgParams.SetRealIPFrom,
 			RealIPHeader:                          vsc.cfgParams.RealIPHeader,
 			RealIPRecursive:                       vsc.cfgParams.RealIPRecursive,
 			Snippets:                              vsc.cfgParams.ServerSnippets,
 			InternalRedirectLocations:             internalRedirectLocations,
+			ExternalAuthLocations:                 externalAuthLocations,
+			MirrorLocations:                       mirrorLocations,
 			Locations:                             locations,
 			HealthChecks:                          healthChecks,
 		},
@@ -306,6 +442,46 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(virtualServerE
 	return vscfg, vsc.warnings
 }
 
+// generateTLSPassthroughConfig generates the configuration of the stream server that passes the TLS connections
+// for the hosts of the VirtualServer through to the endpoints of the upstream referenced in tlsPassthrough.
+func generateTLSPassthroughConfig(virtualServerEx *VirtualServerEx, socket string) *version2.TLSPassthroughConfig {
+	vs := virtualServerEx.VirtualServer
+	upstreamNamer := newUpstreamNamerForVirtualServer(vs)
+
+	var servers []version2.StreamUpstreamServer
+	for _, u := range vs.Spec.Upstreams {
+		if u.Name != vs.Spec.TLSPassthrough.Upstream {
+			continue
+		}
+
+		endpointsKey := GenerateEndpointsKey(vs.Namespace, u.Service, u.Subselector, u.Port)
+		for _, e := range virtualServerEx.Endpoints[endpointsKey] {
+			servers = append(servers, version2.StreamUpstreamServer{
+				Address: e,
+			})
+		}
+	}
+
+	return &version2.TLSPassthroughConfig{
+		Hosts:  append([]string{vs.Spec.Host}, vs.Spec.ServerAliases...),
+		Socket: socket,
+		Upstream: version2.StreamUpstream{
+			Name:    upstreamNamer.GetNameForUpstream(vs.Spec.TLSPassthrough.Upstream),
+			Servers: servers,
+		},
+	}
+}
+
+// generateTLSPassthroughHosts generates the entries of the map that routes the TLS connections by the server name
+// to the stream server of the VirtualServer.
+func generateTLSPassthroughHosts(cfg *version2.TLSPassthroughConfig) []byte {
+	var b bytes.Buffer
+	for _, host := range cfg.Hosts {
+		fmt.Fprintf(&b, "%s %s;\n", host, cfg.Socket)
+	}
+	return b.Bytes()
+}
+
 func (vsc *virtualServerConfigurator) generateUpstream(owner runtime.Object, upstreamName string, upstream conf_v1alpha1.Upstream, isExternalNameSvc bool, endpoints []string) version2.Upstream {
 	var upsServers []version2.UpstreamServer
 	for _, e := range endpoints {
@@ -330,6 +506,11 @@ func (vsc *virtualServerConfigurator) generateUpstream(owner runtime.Object, ups
 		UpstreamZoneSize: vsc.cfgParams.UpstreamZoneSize,
 	}
 
+	// keepalive connections to the upstream require HTTP/1.1
+	if ups.Keepalive > 0 && generateString(upstream.ProxyHTTPVersion, vsc.cfgParams.ProxyHTTPVersion) == "1.0" {
+		vsc.addWarningf(owner, "proxy-http-version 1.0 disables the keepalive connections to the upstream %v", upstream.Name)
+	}
+
 	if vsc.isPlus {
 		ups.SlowStart = vsc.generateSlowStartForPlus(owner, upstream, lbMethod)
 		ups.Queue = generateQueueForPlus(upstream.Queue, "60s")
@@ -437,6 +618,53 @@ func generateLBMethod(method string, defaultMethod string) string {
 	return method
 }
 
+// generateGzip generates the gzip compression configuration of a server. The fields of the gzip of the VirtualServer
+// take precedence over the values from the ConfigMap. It returns nil if gzip is not enabled.
+func generateGzip(gzip *conf_v1alpha1.Gzip, cfgParams *ConfigParams) *version2.Gzip {
+	if gzip == nil {
+		if !cfgParams.Gzip {
+			return nil
+		}
+		gzip = &conf_v1alpha1.Gzip{Enable: true}
+	}
+
+	if !gzip.Enable {
+		return nil
+	}
+
+	return &version2.Gzip{
+		Types:     strings.Join(generateStringSlice(gzip.Types, cfgParams.GzipTypes), " "),
+		MinLength: generateIntFromPointer(gzip.MinLength, cfgParams.GzipMinLength),
+		CompLevel: generateIntFromPointer(gzip.Level, cfgParams.GzipCompLevel),
+		Proxied:   strings.Join(generateStringSlice(gzip.Proxied, cfgParams.GzipProxied), " "),
+	}
+}
+
+// generateAccessLog generates the parameters of the access_log directive of a server. It returns an empty string if
+// the VirtualServer doesn't override the access log of the http context.
+func generateAccessLog(accessLog *conf_v1alpha1.AccessLog, logPath string) string {
+	if accessLog == nil {
+		return ""
+	}
+
+	if accessLog.Off {
+		return "off"
+	}
+
+	var destination string
+	if accessLog.File != "" {
+		destination = path.Join(logPath, accessLog.File)
+	} else if accessLog.Stdout {
+		destination = "/dev/stdout"
+	} else if accessLog.Syslog != "" {
+		destination = fmt.Sprintf("syslog:server=unix:%s", accessLog.Syslog)
+	} else {
+		return ""
+	}
+
+	return fmt.Sprintf("%s main", destination)
+}
+
 func generateIntFromPointer(n *int, defaultN int) int {
 	if n == nil {
 		return defaultN
@@ -465,6 +693,13 @@ func generateString(s string, defaultS string) string {
 	return s
 }
 
+func generateStringSlice(s []string, defaultS []string) []string {
+	if len(s) == 0 {
+		return defaultS
+	}
+	return s
+}
+
 func generateBuffers(s *conf_v1alpha1.UpstreamBuffers, defaultS string) string {
 	if s == nil {
 		return defaultS
@@ -479,7 +714,9 @@ func generateBool(s *bool, defaultS bool) bool {
 	return defaultS
 }
 
-func generateLocation(path string, upstreamName string, upstream conf_v1alpha1.Upstream, cfgParams *ConfigParams) version2.Location {
+func generateLocation(path string, upstreamName string, upstream conf_v1alpha1.Upstream, proxy *conf_v1alpha1.RouteProxy, cfgParams *ConfigParams) version2.Location {
+	upstream = mergeRouteProxy(upstream, proxy)
+
 	return version2.Location{
 		Path:                     path,
 		Snippets:                 cfgParams.LocationSnippets,
@@ -491,6 +728,11 @@ func generateLocation(path string, upstreamName string, upstream conf_v1alpha1.U
 		ProxyBuffering:           generateBool(upstream.ProxyBuffering, cfgParams.ProxyBuffering),
 		ProxyBuffers:             generateBuffers(upstream.ProxyBuffers, cfgParams.ProxyBuffers),
 		ProxyBufferSize:          generateString(upstream.ProxyBufferSize, cfgParams.ProxyBufferSize),
+		ProxyRequestBuffering:    generateBool(upstream.ProxyRequestBuffering, cfgParams.ProxyRequestBuffering),
+		ProxyHTTPVersion:         generateString(upstream.ProxyHTTPVersion, cfgParams.ProxyHTTPVersion),
+		ProxyIgnoreClientAbort:   generateBool(upstream.ProxyIgnoreClientAbort, cfgParams.ProxyIgnoreClientAbort),
+		ClientBodyBufferSize:     generateString(upstream.ClientBodyBufferSize, cfgParams.ClientBodyBufferSize),
+		ClientBodyTimeout:        generateString(upstream.ClientBodyTimeout, cfgParams.ClientBodyTimeout),
 		ProxyPass:                fmt.Sprintf("%v://%v", generateProxyPassProtocol(upstream.TLS.Enable), upstreamName),
 		ProxyNextUpstream:        generateString(upstream.ProxyNextUpstream, "error timeout"),
 		ProxyNextUpstreamTimeout: generateString(upstream.ProxyNextUpstreamTimeout, "0s"),
@@ -499,6 +741,246 @@ func generateLocation(path string, upstreamName string, upstream conf_v1alpha1.U
 	}
 }
 
+// mergeRouteProxy returns a copy of the upstream with the proxy settings overridden by the ones defined in a route.
+func mergeRouteProxy(upstream conf_v1alpha1.Upstream, proxy *conf_v1alpha1.RouteProxy) conf_v1alpha1.Upstream {
+	if proxy == nil {
+		return upstream
+	}
+
+	upstream.ProxyConnectTimeout = generateString(proxy.ProxyConnectTimeout, upstream.ProxyConnectTimeout)
+	upstream.ProxyReadTimeout = generateString(proxy.ProxyReadTimeout, upstream.ProxyReadTimeout)
+	upstream.ProxySendTimeout = generateString(proxy.ProxySendTimeout, upstream.ProxySendTimeout)
+	upstream.ProxyNextUpstream = generateString(proxy.ProxyNextUpstream, upstream.ProxyNextUpstream)
+	upstream.ProxyNextUpstreamTimeout = generateString(proxy.ProxyNextUpstreamTimeout, upstream.ProxyNextUpstreamTimeout)
+	upstream.ProxyNextUpstreamTries = generateIntFromPointer(proxy.ProxyNextUpstreamTries, upstream.ProxyNextUpstreamTries)
+	upstream.ProxyBufferSize = generateString(proxy.ProxyBufferSize, upstream.ProxyBufferSize)
+	upstream.ClientMaxBodySize = generateString(proxy.ClientMaxBodySize, upstream.ClientMaxBodySize)
+
+	if proxy.ProxyBuffering != nil {
+		upstream.ProxyBuffering = proxy.ProxyBuffering
+	}
+
+	if proxy.ProxyBuffers != nil {
+		upstream.ProxyBuffers = proxy.ProxyBuffers
+	}
+
+	if proxy.ProxyRequestBuffering != nil {
+		upstream.ProxyRequestBuffering = proxy.ProxyRequestBuffering
+	}
+
+	return upstream
+}
+
+// generateExternalAuth generates the external authentication configuration for the locations of a route along with
+// the internal location for the authentication subrequests.
+func generateExternalAuth(externalAuth *conf_v1alpha1.ExternalAuth, upstreamNamer *upstreamNamer, crUpstreams map[string]conf_v1alpha1.Upstream,
+	index int) (*version2.ExternalAuth, version2.ExternalAuthLocation) {
+	auth := &version2.ExternalAuth{
+		LocationName: fmt.Sprintf("/_external_auth_%d", index),
+	}
+	for _, name := range externalAuth.ResponseHeaders {
+		variable, upstreamVariable := getVariablesForExternalAuthResponseHeader(name)
+		auth.ResponseHeaders = append(auth.ResponseHeaders, version2.ExternalAuthResponseHeader{
+			Name:             name,
+			Variable:         variable,
+			UpstreamVariable: upstreamVariable,
+		})
+	}
+
+	proxyPass := externalAuth.URL
+	if externalAuth.Upstream != "" {
+		upstreamName := upstreamNamer.GetNameForUpstream(externalAuth.Upstream)
+		upstream := crUpstreams[upstreamName]
+		proxyPass = fmt.Sprintf("%v://%v%v", generateProxyPassProtocol(upstream.TLS.Enable), upstreamName, externalAuth.Path)
+	}
+
+	location := version2.ExternalAuthLocation{
+		Name:      auth.LocationName,
+		ProxyPass: proxyPass,
+	}
+
+	if externalAuth.Signin != "" {
+		auth.SigninLocationName = fmt.Sprintf("@external_auth_signin_%d", index)
+		location.SigninLocationName = auth.SigninLocationName
+		location.SigninURL = externalAuth.Signin
+	}
+
+	return auth, location
+}
+
+func addExternalAuthToLocations(locations []version2.Location, auth *version2.ExternalAuth) {
+	for i := range locations {
+		locations[i].ExternalAuth = auth
+	}
+}
+
+// generateBasicAuth generates the basic authentication configuration for the locations of a route. If the htpasswd
+// secret is missing or invalid, NGINX rejects the requests to the route with the 500 status code.
+func (vsc *virtualServerConfigurator) generateBasicAuth(owner runtime.Object, basicAuth *conf_v1alpha1.BasicAuth, namespace string,
+	htpasswdSecrets map[string]*api_v1.Secret, htpasswdFileNames map[string]string) *version2.BasicAuth {
+	secretKey := fmt.Sprintf("%s/%s", namespace, basicAuth.Secret)
+
+	if htpasswdSecrets[secretKey] == nil {
+		vsc.addWarningf(owner, "htpasswd secret %s is missing or invalid, the requests to the route will be rejected", basicAuth.Secret)
+	}
+
+	realm := basicAuth.Realm
+	if realm == "" {
+		realm = vsc.cfgParams.BasicAuthRealm
+	}
+
+	return &version2.BasicAuth{
+		Realm:    realm,
+		UserFile: htpasswdFileNames[secretKey],
+	}
+}
+
+func addBasicAuthToLocations(locations []version2.Location, auth *version2.BasicAuth) {
+	for i := range locations {
+		locations[i].BasicAuth = auth
+	}
+}
+
+// generateCORS generates the CORS configuration for the locations of a route along with the maps for the preflight
+// requests and the allowed origins.
+func generateCORS(cors *conf_v1alpha1.CORS, variableNamer *variableNamer, index int, cfgParams *ConfigParams) (*version2.CORS, []version2.Map) {
+	result := &version2.CORS{
+		AllowOrigin:       "*",
+		AllowMethods:      strings.Join(generateStringSlice(cors.AllowMethods, cfgParams.CORSAllowMethods), ", "),
+		AllowHeaders:      strings.Join(generateStringSlice(cors.AllowHeaders, cfgParams.CORSAllowHeaders), ", "),
+		AllowCredentials:  cors.AllowCredentials,
+		MaxAge:            generateIntFromPointer(cors.MaxAge, cfgParams.CORSMaxAge),
+		ExposeHeaders:     strings.Join(cors.ExposeHeaders, ", "),
+		PreflightVariable: variableNamer.GetNameForCORSPreflightVariable(index),
+	}
+
+	maps := []version2.Map{
+		{
+			Source:   corsPreflightMapSource,
+			Variable: result.PreflightVariable,
+			Parameters: []version2.Parameter{
+				{
+					Value:  corsPreflightMapValue,
+					Result: "1",
+				},
+				{
+					Value:  "default",
+					Result: "0",
+				},
+			},
+		},
+	}
+
+	if !isCORSAnyOrigin(cors.AllowOrigin) {
+		result.AllowOrigin = variableNamer.GetNameForCORSOriginVariable(index)
+
+		originMap := version2.Map{
+			Source:   "$http_origin",
+			Variable: result.AllowOrigin,
+		}
+		for _, value := range generateCORSOriginMapValues(cors.AllowOrigin) {
+			originMap.Parameters = append(originMap.Parameters, version2.Parameter{
+				Value:  value,
+				Result: "$http_origin",
+			})
+		}
+		originMap.Parameters = append(originMap.Parameters, version2.Parameter{
+			Value:  "default",
+			Result: `""`,
+		})
+
+		maps = append(maps, originMap)
+	}
+
+	return result, maps
+}
+
+func addCORSToLocations(locations []version2.Location, cors *version2.CORS) {
+	for i := range locations {
+		locations[i].CORS = cors
+	}
+}
+
+// generateMirror generates the mirror configuration for the locations of a route along with the internal location
+// for the mirror subrequests. If only a percentage of the requests is mirrored, it also generates a split_clients
+// that selects those requests.
+func generateMirror(mirror *conf_v1alpha1.Mirror, upstreamNamer *upstreamNamer, crUpstreams map[string]conf_v1alpha1.Upstream,
+	variableNamer *variableNamer, index int, cfgParams *ConfigParams) (*version2.Mirror, version2.MirrorLocation, *version2.SplitClient) {
+	upstreamName := upstreamNamer.GetNameForUpstream(mirror.Upstream)
+	upstream := crUpstreams[upstreamName]
+	requestBody := generateBool(mirror.RequestBody, true)
+
+	result := &version2.Mirror{
+		LocationName: fmt.Sprintf("/_mirror_%d", index),
+		RequestBody:  requestBody,
+	}
+
+	location := version2.MirrorLocation{
+		Name:                result.LocationName,
+		ProxyConnectTimeout: generateString(upstream.ProxyConnectTimeout, cfgParams.ProxyConnectTimeout),
+		ProxyReadTimeout:    generateString(upstream.ProxyReadTimeout, cfgParams.ProxyReadTimeout),
+		ProxySendTimeout:    generateString(upstream.ProxySendTimeout, cfgParams.ProxySendTimeout),
+		ProxyPass:           fmt.Sprintf("%v://%v", generateProxyPassProtocol(upstream.TLS.Enable), upstreamName),
+		HasKeepalive:        upstreamHasKeepalive(upstream, cfgParams),
+		RequestBody:         requestBody,
+	}
+
+	percentage := generateIntFromPointer(mirror.Percentage, 100)
+	if percentage == 100 {
+		return result, location, nil
+	}
+
+	location.Variable = variableNamer.GetNameForMirrorVariable(index)
+
+	splitClient := &version2.SplitClient{
+		Source:   "$request_id",
+		Variable: location.Variable,
+		Distributions: []version2.Distribution{
+			{
+				Weight: fmt.Sprintf("%d%%", percentage),
+				Value:  "1",
+			},
+			{
+				Weight: "*",
+				Value:  `""`,
+			},
+		},
+	}
+
+	return result, location, splitClient
+}
+
+func addMirrorToLocations(locations []version2.Location, mirror *version2.Mirror) {
+	for i := range locations {
+		locations[i].Mirror = mirror
+	}
+}
+
+// generateProxyCache generates the caching configuration for the locations of a route. If the cache zone is not
+// declared in the ConfigMap, caching is disabled for the route.
+func (vsc *virtualServerConfigurator) generateProxyCache(owner runtime.Object, cache *conf_v1alpha1.Cache) *version2.ProxyCache {
+	if !hasProxyCacheZone(vsc.cfgParams.MainProxyCacheZones, cache.Zone) {
+		vsc.addWarningf(owner, "cache zone %s is not declared in the proxy-cache-zones ConfigMap key, caching is disabled for the route", cache.Zone)
+		return nil
+	}
+
+	return &version2.ProxyCache{
+		Zone:         cache.Zone,
+		Valid:        cache.Valid,
+		Methods:      strings.Join(cache.Methods, " "),
+		Key:          cache.Key,
+		Bypass:       strings.Join(cache.Bypass, " "),
+		UseStale:     strings.Join(cache.UseStale, " "),
+		StatusHeader: cache.StatusHeader,
+	}
+}
+
+func addProxyCacheToLocations(locations []version2.Location, cache *version2.ProxyCache) {
+	for i := range locations {
+		locations[i].ProxyCache = cache
+	}
+}
+
 type splitRouteCfg struct {
 	SplitClient              version2.SplitClient
 	Locations                []version2.Location
@@ -506,12 +988,28 @@ type splitRouteCfg struct {
 }
 
 func generateSplitRouteConfig(route conf_v1alpha1.Route, upstreamNamer *upstreamNamer, crUpstreams map[string]conf_v1alpha1.Upstream, variableNamer *variableNamer, index int, cfgParams *ConfigParams) splitRouteCfg {
-	splitClientVarName := variableNamer.GetNameForSplitClientVariable(index)
+	splitClient, locations := generateSplits(route.Splits, route.Proxy, upstreamNamer, crUpstreams, variableNamer, index, cfgParams)
 
+	// Generate an InternalRedirectLocation
+	irl := version2.InternalRedirectLocation{
+		Path:        route.Path,
+		Destination: splitClient.Variable,
+	}
+
+	return splitRouteCfg{
+		SplitClient:              splitClient,
+		Locations:                locations,
+		InternalRedirectLocation: irl,
+	}
+}
+
+// generateSplits generates a SplitClient and a named location per split. The variable of the SplitClient
+// evaluates to the name of one of the locations, so that it can be used as the destination of an internal redirect.
+func generateSplits(splits []conf_v1alpha1.Split, proxy *conf_v1alpha1.RouteProxy, upstreamNamer *upstreamNamer, crUpstreams map[string]conf_v1alpha1.Upstream, variableNamer *variableNamer, index int, cfgParams *ConfigParams) (version2.SplitClient, []version2.Location) {
 	// Generate a SplitClient
 	var distributions []version2.Distribution
 
-	for i, s := range route.Splits {
+	for i, s := range splits {
 		d := version2.Distribution{
 			Weight: fmt.Sprintf("%d%%", s.Weight),
 			Value:  fmt.Sprintf("@splits_%d_split_%d", index, i),
@@ -521,44 +1019,59 @@ func generateSplitRouteConfig(route conf_v1alpha1.Route, upstreamNamer *upstream
 
 	splitClient := version2.SplitClient{
 		Source:        "$request_id",
-		Variable:      splitClientVarName,
+		Variable:      variableNamer.GetNameForSplitClientVariable(index),
 		Distributions: distributions,
 	}
 
 	// Generate locations
 	var locations []version2.Location
 
-	for i, s := range route.Splits {
+	for i, s := range splits {
 		path := fmt.Sprintf("@splits_%d_split_%d", index, i)
 		upstreamName := upstreamNamer.GetNameForUpstream(s.Upstream)
 		upstream := crUpstreams[upstreamName]
-		loc := generateLocation(path, upstreamName, upstream, cfgParams)
+		loc := generateLocation(path, upstreamName, upstream, proxy, cfgParams)
 		locations = append(locations, loc)
 	}
 
-	// Generate an InternalRedirectLocation
-	irl := version2.InternalRedirectLocation{
-		Path:        route.Path,
-		Destination: splitClientVarName,
-	}
-
-	return splitRouteCfg{
-		SplitClient:              splitClient,
-		Locations:                locations,
-		InternalRedirectLocation: irl,
-	}
+	return splitClient, locations
 }
 
 type rulesRouteCfg struct {
 	Maps                     []version2.Map
+	Geos                     []version2.Geo
+	SplitClients             []version2.SplitClient
 	Locations                []version2.Location
 	InternalRedirectLocation version2.InternalRedirectLocation
 }
 
+// generateRulesRouteConfig generates config for a route with rules. Matches and the default action that use splits
+// get their own SplitClients, numbered from splitClientsIndex, so that the result of the main map is a split_clients variable.
 func generateRulesRouteConfig(route conf_v1alpha1.Route, upstreamNamer *upstreamNamer, crUpstreams map[string]conf_v1alpha1.Upstream,
-	variableNamer *variableNamer, index int, cfgParams *ConfigParams) rulesRouteCfg {
+	variableNamer *variableNamer, index int, splitClientsIndex int, cfgParams *ConfigParams) rulesRouteCfg {
+	var splitClients []version2.SplitClient
+	var locations []version2.Location
+
+	// generateDestination returns the destination for a match or the default action: either a named location
+	// that passes requests to the upstream or a split_clients variable that evaluates to one of the split locations.
+	generateDestination := func(path string, upstream string, splits []conf_v1alpha1.Split) string {
+		if len(splits) > 0 {
+			splitClient, splitLocations := generateSplits(splits, route.Proxy, upstreamNamer, crUpstreams, variableNamer, splitClientsIndex+len(splitClients), cfgParams)
+			splitClients = append(splitClients, splitClient)
+			locations = append(locations, splitLocations...)
+			return splitClient.Variable
+		}
+
+		upstreamName := upstreamNamer.GetNameForUpstream(upstream)
+		loc := generateLocation(path, upstreamName, crUpstreams[upstreamName], route.Proxy, cfgParams)
+		locations = append(locations, loc)
+		return path
+	}
+
 	// Generate maps
 	var maps []version2.Map
+	var geos []version2.Geo
+	pathCaptures := make([][]version2.Variable, len(route.Rules.Matches))
 
 	for i, m := range route.Rules.Matches {
 		for j, c := range route.Rules.Conditions {
@@ -569,7 +1082,20 @@ func generateRulesRouteConfig(route conf_v1alpha1.Route, upstreamNamer *upstream
 				successfulResult = variableNamer.GetNameForVariableForRulesRouteMap(index, i, j+1)
 			}
 
-			params := generateParametersForRulesRouteMap(m.Values[j], successfulResult)
+			matchedValue := m.Values[j]
+
+			if c.SourceIP {
+				// maps can't match CIDR ranges, so the address is matched by a geo block and the map checks its result
+				geo := generateGeoForRulesRoute(matchedValue, variableNamer.GetNameForVariableForRulesRouteGeo(index, i, j))
+				geos = append(geos, geo)
+
+				source = geo.Variable
+				matchedValue = "1"
+			} else if c.ArgumentPresent != "" {
+				matchedValue = generateValueForArgumentPresentCondition(c.ArgumentPresent, matchedValue)
+			}
+
+			params := generateParametersForRulesRouteMap(matchedValue, successfulResult)
 
 			matchMap := version2.Map{
 				Source:     source,
@@ -578,24 +1104,35 @@ func generateRulesRouteConfig(route conf_v1alpha1.Route, upstreamNamer *upstream
 			}
 			maps = append(maps, matchMap)
 		}
+
+		captureMaps := generatePathCaptureMapsForRulesRoute(route.Rules.Conditions, m, index, i, variableNamer)
+		maps = append(maps, captureMaps...)
+		pathCaptures[i] = generatePathCaptureVariables(captureMaps)
 	}
 
 	// Generate the main map
 	source := ""
 	var params []version2.Parameter
-	for i := range route.Rules.Matches {
+	for i, m := range route.Rules.Matches {
 		source += variableNamer.GetNameForVariableForRulesRouteMap(index, i, 0)
 
+		firstLocation := len(locations)
+
 		p := version2.Parameter{
 			Value:  fmt.Sprintf("~^%s1", strings.Repeat("0", i)),
-			Result: fmt.Sprintf("@rules_%d_match_%d", index, i),
+			Result: generateDestination(fmt.Sprintf("@rules_%d_match_%d", index, i), m.Upstream, m.Splits),
 		}
 		params = append(params, p)
+
+		// export the captures of the path conditions to the locations of the match
+		for k := firstLocation; k < len(locations); k++ {
+			locations[k].Variables = pathCaptures[i]
+		}
 	}
 
 	defaultParam := version2.Parameter{
 		Value:  "default",
-		Result: fmt.Sprintf("@rules_%d_default", index),
+		Result: generateDestination(fmt.Sprintf("@rules_%d_default", index), route.Rules.DefaultUpstream, route.Rules.DefaultSplits),
 	}
 	params = append(params, defaultParam)
 
@@ -608,24 +1145,6 @@ func generateRulesRouteConfig(route conf_v1alpha1.Route, upstreamNamer *upstream
 	}
 	maps = append(maps, mainMap)
 
-	// Generate locations for each match
-	var locations []version2.Location
-
-	for i, m := range route.Rules.Matches {
-		path := fmt.Sprintf("@rules_%d_match_%d", index, i)
-		upstreamName := upstreamNamer.GetNameForUpstream(m.Upstream)
-		upstream := crUpstreams[upstreamName]
-		loc := generateLocation(path, upstreamName, upstream, cfgParams)
-		locations = append(locations, loc)
-	}
-
-	// Generate defaultUpstream location
-	path := fmt.Sprintf("@rules_%d_default", index)
-	upstreamName := upstreamNamer.GetNameForUpstream(route.Rules.DefaultUpstream)
-	upstream := crUpstreams[upstreamName]
-	loc := generateLocation(path, upstreamName, upstream, cfgParams)
-	locations = append(locations, loc)
-
 	// Generate an InternalRedirectLocation to the location defined by the main map variable
 	irl := version2.InternalRedirectLocation{
 		Path:        route.Path,
@@ -634,11 +1153,104 @@ func generateRulesRouteConfig(route conf_v1alpha1.Route, upstreamNamer *upstream
 
 	return rulesRouteCfg{
 		Maps:                     maps,
+		Geos:                     geos,
+		SplitClients:             splitClients,
 		Locations:                locations,
 		InternalRedirectLocation: irl,
 	}
 }
 
+// pathCaptureVariablePrefix is the prefix of the variables that hold the captures of the regular expressions of the
+// path conditions in the locations of a match, numbered from 1.
+const pathCaptureVariablePrefix = "$path_capture_"
+
+// generatePathCaptureMapsForRulesRoute generates a map for every capture of the regular expressions of the path
+// conditions of a match. A map evaluates to the capture if the path matches the regular expression.
+// Negated regular expressions have no captures.
+func generatePathCaptureMapsForRulesRoute(conditions []conf_v1alpha1.Condition, match conf_v1alpha1.Match, rulesIndex int,
+	matchIndex int, variableNamer *variableNamer) []version2.Map {
+	var maps []version2.Map
+
+	for j, c := range conditions {
+		if !c.Path || !strings.HasPrefix(match.Values[j], "~") {
+			continue
+		}
+
+		value, _ := generateValueForRulesRouteMap(match.Values[j])
+		regex := strings.TrimPrefix(strings.TrimPrefix(match.Values[j], "~"), "*")
+
+		for n := 1; n <= countRegexCaptures(regex); n++ {
+			captureMap := version2.Map{
+				Source:   "$uri",
+				Variable: variableNamer.GetNameForVariableForRulesRoutePathCapture(rulesIndex, matchIndex, len(maps)+1),
+				Parameters: []version2.Parameter{
+					{
+						Value:  value,
+						Result: fmt.Sprintf("$%d", n),
+					},
+					{
+						Value:  "default",
+						Result: `""`,
+					},
+				},
+			}
+			maps = append(maps, captureMap)
+		}
+	}
+
+	return maps
+}
+
+// generatePathCaptureVariables generates the variables that hold the captures of the maps generated by
+// generatePathCaptureMapsForRulesRoute.
+func generatePathCaptureVariables(captureMaps []version2.Map) []version2.Variable {
+	var variables []version2.Variable
+	for i, m := range captureMaps {
+		variables = append(variables, version2.Variable{
+			Name:  fmt.Sprintf("%s%d", pathCaptureVariablePrefix, i+1),
+			Value: m.Variable,
+		})
+	}
+	return variables
+}
+
+// countRegexCaptures counts the capturing groups of a PCRE regular expression, which are the groups that start with
+// an unescaped parenthesis outside a character class and are either unnamed groups or named groups.
+func countRegexCaptures(regex string) int {
+	count := 0
+	inClass := false
+
+	for i := 0; i < len(regex); i++ {
+		switch {
+		case regex[i] == '\\':
+			i++
+		case inClass:
+			if regex[i] == ']' {
+				inClass = false
+			}
+		case regex[i] == '[':
+			inClass = true
+			// a closing bracket right after the opening bracket or the negation is a literal
+			if i+1 < len(regex) && regex[i+1] == '^' {
+				i++
+			}
+			if i+1 < len(regex) && regex[i+1] == ']' {
+				i++
+			}
+		case regex[i] == '(':
+			rest := regex[i+1:]
+			if !strings.HasPrefix(rest, "?") {
+				count++
+			} else if strings.HasPrefix(rest, "?P<") || strings.HasPrefix(rest, "?'") ||
+				(strings.HasPrefix(rest, "?<") && !strings.HasPrefix(rest, "?<=") && !strings.HasPrefix(rest, "?<!")) {
+				count++
+			}
+		}
+	}
+
+	return count
+}
+
 var specialMapParameters = map[string]bool{
 	"default":   true,
 	"hostnames": true,
@@ -687,6 +1299,50 @@ func generateParametersForRulesRouteMap(matchedValue string, successfulResult st
 	return params
 }
 
+// generateGeoForRulesRoute generates a geo block that evaluates to 1 if the client address matches the IP address
+// or the CIDR range of the matched value. The matched value can be negated with the `!` prefix.
+func generateGeoForRulesRoute(matchedValue string, variable string) version2.Geo {
+	isNegative := strings.HasPrefix(matchedValue, "!")
+
+	valueResult := "1"
+	defaultResult := "0"
+	if isNegative {
+		valueResult = "0"
+		defaultResult = "1"
+	}
+
+	return version2.Geo{
+		Source:   "$remote_addr",
+		Variable: variable,
+		Parameters: []version2.Parameter{
+			{
+				Value:  strings.TrimPrefix(matchedValue, "!"),
+				Result: valueResult,
+			},
+			{
+				Value:  "default",
+				Result: defaultResult,
+			},
+		},
+	}
+}
+
+// generateValueForArgumentPresentCondition converts the matched value `true` or `false` (optionally negated with
+// the `!` prefix) of an argumentPresent condition into a regular expression for the $args variable.
+func generateValueForArgumentPresentCondition(argument string, matchedValue string) string {
+	isNegative := strings.HasPrefix(matchedValue, "!")
+	if strings.TrimPrefix(matchedValue, "!") == "false" {
+		isNegative = !isNegative
+	}
+
+	value := fmt.Sprintf("~(^|&)%s(=|&|$)", argument)
+	if isNegative {
+		return "!" + value
+	}
+
+	return value
+}
+
 func getNameForSourceForRulesRouteMapFromCondition(condition conf_v1alpha1.Condition) string {
 	if condition.Header != "" {
 		return fmt.Sprintf("$http_%s", strings.ReplaceAll(condition.Header, "-", "_"))
@@ -700,25 +1356,47 @@ func getNameForSourceForRulesRouteMapFromCondition(condition conf_v1alpha1.Condi
 		return fmt.Sprintf("$arg_%s", condition.Argument)
 	}
 
+	if condition.ArgumentPresent != "" {
+		return "$args"
+	}
+
+	if condition.Method {
+		return "$request_method"
+	}
+
+	if condition.Path {
+		return "$uri"
+	}
+
+	if condition.SourceIP {
+		return "$remote_addr"
+	}
+
 	return condition.Variable
 }
 
-func generateSSLConfig(tls *conf_v1alpha1.TLS, tlsPemFileName string, cfgParams *ConfigParams) *version2.SSL {
+func (vsc *virtualServerConfigurator) generateSSLConfig(owner runtime.Object, tls *conf_v1alpha1.TLS, tlsPemFileName string, specialSecrets specialTLSSecrets,
+	cfgParams *ConfigParams) *version2.SSL {
 	if tls == nil {
 		return nil
 	}
 
-	if tls.Secret == "" {
-		return nil
-	}
-
 	var name string
 	var ciphers string
 
-	if tlsPemFileName != "" {
+	if tls.Secret == "" && specialSecrets.wildcardPemFileName != "" {
+		name = specialSecrets.wildcardPemFileName
+	} else if tlsPemFileName != "" {
 		name = tlsPemFileName
+	} else if specialSecrets.isDefaultFallbackEnabled {
+		name = specialSecrets.defaultServerPemFileName
+		if tls.Secret == "" {
+			vsc.addWarningf(owner, "TLS secret is not specified, the certificate of the default server is used")
+		} else {
+			vsc.addWarningf(owner, "TLS secret %s is missing or invalid, the certificate of the default server is used", tls.Secret)
+		}
 	} else {
-		name = pemFileNameForMissingTLSSecret
+		name = specialSecrets.defaultServerPemFileName
 		ciphers = "NULL"
 	}
 
@@ -743,17 +1421,17 @@ func createEndpointsFromUpstream(upstream version2.Upstream) []string {
 	return endpoints
 }
 
-func createUpstreamsForPlus(virtualServerEx *VirtualServerEx, baseCfgParams *ConfigParams) []version2.Upstream {
+func createUpstreamsForPlus(virtualServerEx *VirtualServerEx, baseCfgParams *ConfigParams, staticParams *StaticConfigParams) []version2.Upstream {
 	var upstreams []version2.Upstream
 
 	isPlus := true
 	upstreamNamer := newUpstreamNamerForVirtualServer(virtualServerEx.VirtualServer)
-	vsc := newVirtualServerConfigurator(baseCfgParams, isPlus, false)
+	vsc := newVirtualServerConfigurator(baseCfgParams, isPlus, false, staticParams)
 
 	for _, u := range virtualServerEx.VirtualServer.Spec.Upstreams {
 		isExternalNameSvc := virtualServerEx.ExternalNameSvcs[GenerateExternalNameSvcKey(virtualServerEx.VirtualServer.Namespace, u.Service)]
 		if isExternalNameSvc {
-			glog.V(3).Infof("Service %s is Type ExternalName, skipping NGINX Plus endpoints update via API", u.Service)
+			logging.V(3).Infof("Service %s is Type ExternalName, skipping NGINX Plus endpoints update via API", u.Service)
 			continue
 		}
 
@@ -772,7 +1450,7 @@ func createUpstreamsForPlus(virtualServerEx *VirtualServerEx, baseCfgParams *Con
 		for _, u := range vsr.Spec.Upstreams {
 			isExternalNameSvc := virtualServerEx.ExternalNameSvcs[GenerateExternalNameSvcKey(vsr.Namespace, u.Service)]
 			if isExternalNameSvc {
-				glog.V(3).Infof("Service %s is Type ExternalName, skipping NGINX Plus endpoints update via API", u.Service)
+				logging.V(3).Infof("Service %s is Type ExternalName, skipping NGINX Plus endpoints update via API", u.Service)
 				continue
 			}
 
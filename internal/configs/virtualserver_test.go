@@ -8,6 +8,7 @@ import (
 	"github.com/nginxinc/kubernetes-ingress/internal/configs/version2"
 	"github.com/nginxinc/kubernetes-ingress/internal/nginx"
 	conf_v1alpha1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
+	api_v1 "k8s.io/api/core/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -182,7 +183,8 @@ func TestGenerateVirtualServerConfig(t *testing.T) {
 				Namespace: "default",
 			},
 			Spec: conf_v1alpha1.VirtualServerSpec{
-				Host: "cafe.example.com",
+				Host:          "cafe.example.com",
+				ServerAliases: []string{"www.cafe.example.com"},
 				Upstreams: []conf_v1alpha1.Upstream{
 					{
 						Name:    "tea",
@@ -331,6 +333,9 @@ func TestGenerateVirtualServerConfig(t *testing.T) {
 		},
 		Server: version2.Server{
 			ServerName:                            "cafe.example.com",
+			ServerAliases:                         []string{"www.cafe.example.com"},
+			VSName:                                "cafe",
+			VSNamespace:                           "default",
 			StatusZone:                            "cafe.example.com",
 			ProxyProtocol:                         true,
 			RedirectToHTTPSBasedOnXForwarderProto: true,
@@ -379,8 +384,8 @@ func TestGenerateVirtualServerConfig(t *testing.T) {
 	isPlus := false
 	isResolverConfigured := false
 	tlsPemFileName := ""
-	vsc := newVirtualServerConfigurator(&baseCfgParams, isPlus, isResolverConfigured)
-	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, tlsPemFileName)
+	vsc := newVirtualServerConfigurator(&baseCfgParams, isPlus, isResolverConfigured, &StaticConfigParams{})
+	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, tlsPemFileName, specialTLSSecrets{defaultServerPemFileName: "/etc/nginx/secrets/default"}, "", nil)
 	if !reflect.DeepEqual(result, expected) {
 		t.Errorf("GenerateVirtualServerConfig returned \n%v but expected \n%v", result, expected)
 	}
@@ -553,8 +558,10 @@ func TestGenerateVirtualServerConfigForVirtualServerWithSplits(t *testing.T) {
 			},
 		},
 		Server: version2.Server{
-			ServerName: "cafe.example.com",
-			StatusZone: "cafe.example.com",
+			ServerName:  "cafe.example.com",
+			VSName:      "cafe",
+			VSNamespace: "default",
+			StatusZone:  "cafe.example.com",
 			InternalRedirectLocations: []version2.InternalRedirectLocation{
 				{
 					Path:        "/tea",
@@ -601,8 +608,8 @@ func TestGenerateVirtualServerConfigForVirtualServerWithSplits(t *testing.T) {
 	isPlus := false
 	isResolverConfigured := false
 	tlsPemFileName := ""
-	vsc := newVirtualServerConfigurator(&baseCfgParams, isPlus, isResolverConfigured)
-	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, tlsPemFileName)
+	vsc := newVirtualServerConfigurator(&baseCfgParams, isPlus, isResolverConfigured, &StaticConfigParams{})
+	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, tlsPemFileName, specialTLSSecrets{defaultServerPemFileName: "/etc/nginx/secrets/default"}, "", nil)
 	if !reflect.DeepEqual(result, expected) {
 		t.Errorf("GenerateVirtualServerConfig returned \n%v but expected \n%v", result, expected)
 	}
@@ -816,8 +823,10 @@ func TestGenerateVirtualServerConfigForVirtualServerWithRules(t *testing.T) {
 			},
 		},
 		Server: version2.Server{
-			ServerName: "cafe.example.com",
-			StatusZone: "cafe.example.com",
+			ServerName:  "cafe.example.com",
+			VSName:      "cafe",
+			VSNamespace: "default",
+			StatusZone:  "cafe.example.com",
 			InternalRedirectLocations: []version2.InternalRedirectLocation{
 				{
 					Path:        "/tea",
@@ -864,8 +873,8 @@ func TestGenerateVirtualServerConfigForVirtualServerWithRules(t *testing.T) {
 	isPlus := false
 	isResolverConfigured := false
 	tlsPemFileName := ""
-	vsc := newVirtualServerConfigurator(&baseCfgParams, isPlus, isResolverConfigured)
-	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, tlsPemFileName)
+	vsc := newVirtualServerConfigurator(&baseCfgParams, isPlus, isResolverConfigured, &StaticConfigParams{})
+	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, tlsPemFileName, specialTLSSecrets{defaultServerPemFileName: "/etc/nginx/secrets/default"}, "", nil)
 	if !reflect.DeepEqual(result, expected) {
 		t.Errorf("GenerateVirtualServerConfig returned \n%v but expected \n%v", result, expected)
 	}
@@ -875,6 +884,281 @@ func TestGenerateVirtualServerConfigForVirtualServerWithRules(t *testing.T) {
 	}
 }
 
+func TestGenerateTLSPassthroughConfig(t *testing.T) {
+	virtualServerEx := VirtualServerEx{
+		VirtualServer: &conf_v1alpha1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "app",
+				Namespace: "default",
+			},
+			Spec: conf_v1alpha1.VirtualServerSpec{
+				Host:          "app.example.com",
+				ServerAliases: []string{"*.app.example.com"},
+				TLSPassthrough: &conf_v1alpha1.TLSPassthrough{
+					Upstream: "secure-app",
+				},
+				Upstreams: []conf_v1alpha1.Upstream{
+					{
+						Name:    "app",
+						Service: "app-svc",
+						Port:    80,
+					},
+					{
+						Name:    "secure-app",
+						Service: "app-svc",
+						Port:    443,
+					},
+				},
+			},
+		},
+		Endpoints: map[string][]string{
+			"default/app-svc:80": {
+				"10.0.0.20:80",
+			},
+			"default/app-svc:443": {
+				"10.0.0.20:8443",
+				"10.0.0.21:8443",
+			},
+		},
+	}
+	socket := "unix:/var/lib/nginx/passthrough-vs_default_app.sock"
+
+	expected := &version2.TLSPassthroughConfig{
+		Hosts:  []string{"app.example.com", "*.app.example.com"},
+		Socket: socket,
+		Upstream: version2.StreamUpstream{
+			Name: "vs_default_app_secure-app",
+			Servers: []version2.StreamUpstreamServer{
+				{
+					Address: "10.0.0.20:8443",
+				},
+				{
+					Address: "10.0.0.21:8443",
+				},
+			},
+		},
+	}
+
+	result := generateTLSPassthroughConfig(&virtualServerEx, socket)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("generateTLSPassthroughConfig() returned %+v but expected %+v", result, expected)
+	}
+
+	expectedHosts := "app.example.com unix:/var/lib/nginx/passthrough-vs_default_app.sock;\n" +
+		"*.app.example.com unix:/var/lib/nginx/passthrough-vs_default_app.sock;\n"
+
+	hosts := string(generateTLSPassthroughHosts(result))
+	if hosts != expectedHosts {
+		t.Errorf("generateTLSPassthroughHosts() returned %q but expected %q", hosts, expectedHosts)
+	}
+}
+
+func TestGenerateVirtualServerConfigWarnsIfTLSPassthroughIsDisabled(t *testing.T) {
+	virtualServerEx := VirtualServerEx{
+		VirtualServer: &conf_v1alpha1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "app",
+				Namespace: "default",
+			},
+			Spec: conf_v1alpha1.VirtualServerSpec{
+				Host: "app.example.com",
+				TLSPassthrough: &conf_v1alpha1.TLSPassthrough{
+					Upstream: "secure-app",
+				},
+				Upstreams: []conf_v1alpha1.Upstream{
+					{
+						Name:    "secure-app",
+						Service: "app-svc",
+						Port:    443,
+					},
+				},
+			},
+		},
+	}
+
+	vsc := newVirtualServerConfigurator(NewDefaultConfigParams(), false, false, &StaticConfigParams{})
+
+	tlsPassthroughSocket := ""
+	_, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, "", specialTLSSecrets{defaultServerPemFileName: "/etc/nginx/secrets/default"}, tlsPassthroughSocket, nil)
+	if len(warnings[virtualServerEx.VirtualServer]) != 1 {
+		t.Errorf("GenerateVirtualServerConfig() returned warnings %v but expected a warning about disabled TLS Passthrough", warnings)
+	}
+
+	tlsPassthroughSocket = "unix:/var/lib/nginx/passthrough-https.sock"
+	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, "", specialTLSSecrets{defaultServerPemFileName: "/etc/nginx/secrets/default"}, tlsPassthroughSocket, nil)
+	if len(warnings) != 0 {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected warnings %v", warnings)
+	}
+	if result.Server.TLSPassthroughSocket != tlsPassthroughSocket {
+		t.Errorf("GenerateVirtualServerConfig() returned TLSPassthroughSocket %q but expected %q", result.Server.TLSPassthroughSocket, tlsPassthroughSocket)
+	}
+}
+
+func TestGenerateVirtualServerConfigForExternalAuth(t *testing.T) {
+	virtualServerEx := VirtualServerEx{
+		VirtualServer: &conf_v1alpha1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+			Spec: conf_v1alpha1.VirtualServerSpec{
+				Host: "cafe.example.com",
+				Upstreams: []conf_v1alpha1.Upstream{
+					{
+						Name:    "tea",
+						Service: "tea-svc",
+						Port:    80,
+					},
+					{
+						Name:    "coffee-v1",
+						Service: "coffee-svc-v1",
+						Port:    80,
+					},
+					{
+						Name:    "coffee-v2",
+						Service: "coffee-svc-v2",
+						Port:    80,
+					},
+				},
+				Routes: []conf_v1alpha1.Route{
+					{
+						Path:     "/tea",
+						Upstream: "tea",
+					},
+					{
+						Path: "/coffee",
+						Splits: []conf_v1alpha1.Split{
+							{
+								Weight:   90,
+								Upstream: "coffee-v1",
+							},
+							{
+								Weight:   10,
+								Upstream: "coffee-v2",
+							},
+						},
+						ExternalAuth: &conf_v1alpha1.ExternalAuth{
+							URL: "http://oauth2-proxy.default.svc.cluster.local/oauth2/auth",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	vsc := newVirtualServerConfigurator(NewDefaultConfigParams(), false, false, &StaticConfigParams{})
+
+	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, "", specialTLSSecrets{defaultServerPemFileName: "/etc/nginx/secrets/default"}, "", nil)
+	if len(warnings) != 0 {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected warnings %v", warnings)
+	}
+
+	expectedAuth := &version2.ExternalAuth{
+		LocationName: "/_external_auth_0",
+	}
+	expectedAuthByPath := map[string]*version2.ExternalAuth{
+		"/tea":              nil,
+		"@splits_0_split_0": expectedAuth,
+		"@splits_0_split_1": expectedAuth,
+	}
+
+	for _, loc := range result.Server.Locations {
+		if !reflect.DeepEqual(loc.ExternalAuth, expectedAuthByPath[loc.Path]) {
+			t.Errorf("GenerateVirtualServerConfig() returned external auth %v for location %v but expected %v", loc.ExternalAuth, loc.Path, expectedAuthByPath[loc.Path])
+		}
+	}
+
+	expectedLocations := []version2.ExternalAuthLocation{
+		{
+			Name:      "/_external_auth_0",
+			ProxyPass: "http://oauth2-proxy.default.svc.cluster.local/oauth2/auth",
+		},
+	}
+	if !reflect.DeepEqual(result.Server.ExternalAuthLocations, expectedLocations) {
+		t.Errorf("GenerateVirtualServerConfig() returned external auth locations %v but expected %v", result.Server.ExternalAuthLocations, expectedLocations)
+	}
+}
+
+func TestGenerateVirtualServerConfigForBasicAuth(t *testing.T) {
+	virtualServerEx := VirtualServerEx{
+		VirtualServer: &conf_v1alpha1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+			Spec: conf_v1alpha1.VirtualServerSpec{
+				Host: "cafe.example.com",
+				Upstreams: []conf_v1alpha1.Upstream{
+					{
+						Name:    "tea",
+						Service: "tea-svc",
+						Port:    80,
+					},
+					{
+						Name:    "coffee",
+						Service: "coffee-svc",
+						Port:    80,
+					},
+				},
+				Routes: []conf_v1alpha1.Route{
+					{
+						Path:     "/tea",
+						Upstream: "tea",
+						BasicAuth: &conf_v1alpha1.BasicAuth{
+							Secret: "tea-htpasswd",
+							Realm:  "Tea",
+						},
+					},
+					{
+						Path:     "/coffee",
+						Upstream: "coffee",
+						BasicAuth: &conf_v1alpha1.BasicAuth{
+							Secret: "coffee-htpasswd",
+						},
+					},
+				},
+			},
+		},
+		HtpasswdSecrets: map[string]*api_v1.Secret{
+			"default/tea-htpasswd": {
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "tea-htpasswd",
+					Namespace: "default",
+				},
+			},
+			"default/coffee-htpasswd": nil,
+		},
+	}
+	htpasswdFileNames := map[string]string{
+		"default/tea-htpasswd":    "/etc/nginx/secrets/default-tea-htpasswd",
+		"default/coffee-htpasswd": "/etc/nginx/secrets/default-coffee-htpasswd",
+	}
+
+	vsc := newVirtualServerConfigurator(NewDefaultConfigParams(), false, false, &StaticConfigParams{})
+
+	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, "", specialTLSSecrets{defaultServerPemFileName: "/etc/nginx/secrets/default"}, "", htpasswdFileNames)
+	if len(warnings) != 1 {
+		t.Errorf("GenerateVirtualServerConfig() returned warnings %v but expected a warning about the missing htpasswd secret", warnings)
+	}
+
+	expectedAuthByPath := map[string]*version2.BasicAuth{
+		"/tea": {
+			Realm:    "Tea",
+			UserFile: "/etc/nginx/secrets/default-tea-htpasswd",
+		},
+		"/coffee": {
+			Realm:    "Restricted",
+			UserFile: "/etc/nginx/secrets/default-coffee-htpasswd",
+		},
+	}
+
+	for _, loc := range result.Server.Locations {
+		if !reflect.DeepEqual(loc.BasicAuth, expectedAuthByPath[loc.Path]) {
+			t.Errorf("GenerateVirtualServerConfig() returned basic auth %v for location %v but expected %v", loc.BasicAuth, loc.Path, expectedAuthByPath[loc.Path])
+		}
+	}
+}
+
 func TestGenerateUpstream(t *testing.T) {
 	name := "test-upstream"
 	upstream := conf_v1alpha1.Upstream{Service: name, Port: 80}
@@ -905,7 +1189,7 @@ func TestGenerateUpstream(t *testing.T) {
 		UpstreamZoneSize: "256k",
 	}
 
-	vsc := newVirtualServerConfigurator(&cfgParams, false, false)
+	vsc := newVirtualServerConfigurator(&cfgParams, false, false, &StaticConfigParams{})
 	result := vsc.generateUpstream(&conf_v1alpha1.VirtualServer{}, name, upstream, false, endpoints)
 	if !reflect.DeepEqual(result, expected) {
 		t.Errorf("generateUpstream() returned %v but expected %v", result, expected)
@@ -974,7 +1258,7 @@ func TestGenerateUpstreamWithKeepalive(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		vsc := newVirtualServerConfigurator(test.cfgParams, false, false)
+		vsc := newVirtualServerConfigurator(test.cfgParams, false, false, &StaticConfigParams{})
 		result := vsc.generateUpstream(&conf_v1alpha1.VirtualServer{}, name, test.upstream, false, endpoints)
 		if !reflect.DeepEqual(result, test.expected) {
 			t.Errorf("generateUpstream() returned %v but expected %v for the case of %v", result, test.expected, test.msg)
@@ -986,6 +1270,57 @@ func TestGenerateUpstreamWithKeepalive(t *testing.T) {
 	}
 }
 
+func TestGenerateUpstreamWarnsAboutKeepaliveWithHTTP10(t *testing.T) {
+	name := "test-upstream"
+	keepalive := 32
+	noKeepalive := 0
+	endpoints := []string{
+		"192.168.10.10:8080",
+	}
+
+	tests := []struct {
+		upstream         conf_v1alpha1.Upstream
+		cfgParams        *ConfigParams
+		expectedWarnings int
+		msg              string
+	}{
+		{
+			upstream:         conf_v1alpha1.Upstream{Keepalive: &keepalive, ProxyHTTPVersion: "1.0", Service: name, Port: 80},
+			cfgParams:        &ConfigParams{ProxyHTTPVersion: "1.1"},
+			expectedWarnings: 1,
+			msg:              "upstream keepalive and HTTP/1.0 set",
+		},
+		{
+			upstream:         conf_v1alpha1.Upstream{Service: name, Port: 80},
+			cfgParams:        &ConfigParams{Keepalive: 21, ProxyHTTPVersion: "1.0"},
+			expectedWarnings: 1,
+			msg:              "configparam keepalive and HTTP/1.0 set",
+		},
+		{
+			upstream:         conf_v1alpha1.Upstream{Keepalive: &noKeepalive, Service: name, Port: 80},
+			cfgParams:        &ConfigParams{Keepalive: 21, ProxyHTTPVersion: "1.0"},
+			expectedWarnings: 0,
+			msg:              "upstream keepalive disabled, configparam HTTP/1.0 set",
+		},
+		{
+			upstream:         conf_v1alpha1.Upstream{ProxyHTTPVersion: "1.1", Service: name, Port: 80},
+			cfgParams:        &ConfigParams{Keepalive: 21, ProxyHTTPVersion: "1.0"},
+			expectedWarnings: 0,
+			msg:              "configparam keepalive set, upstream HTTP/1.1 set",
+		},
+	}
+
+	for _, test := range tests {
+		vsc := newVirtualServerConfigurator(test.cfgParams, false, false, &StaticConfigParams{})
+		owner := &conf_v1alpha1.VirtualServer{}
+		vsc.generateUpstream(owner, name, test.upstream, false, endpoints)
+
+		if len(vsc.warnings[owner]) != test.expectedWarnings {
+			t.Errorf("generateUpstream() returned warnings %v but expected %d warning(s) for the case of %s", vsc.warnings[owner], test.expectedWarnings, test.msg)
+		}
+	}
+}
+
 func TestGenerateUpstreamForExternalNameService(t *testing.T) {
 	name := "test-upstream"
 	endpoints := []string{"example.com"}
@@ -1002,7 +1337,7 @@ func TestGenerateUpstreamForExternalNameService(t *testing.T) {
 		Resolve: true,
 	}
 
-	vsc := newVirtualServerConfigurator(&cfgParams, true, true)
+	vsc := newVirtualServerConfigurator(&cfgParams, true, true, &StaticConfigParams{})
 	result := vsc.generateUpstream(&conf_v1alpha1.VirtualServer{}, name, upstream, true, endpoints)
 	if !reflect.DeepEqual(result, expected) {
 		t.Errorf("generateUpstream() returned %v but expected %v", result, expected)
@@ -1086,6 +1421,133 @@ func TestGenerateBuffer(t *testing.T) {
 	}
 }
 
+func TestGenerateGzip(t *testing.T) {
+	minLength := 500
+	level := 6
+
+	cfgParams := &ConfigParams{
+		Gzip:          true,
+		GzipTypes:     []string{"text/css", "application/json"},
+		GzipMinLength: 1000,
+		GzipCompLevel: 5,
+		GzipProxied:   []string{"any"},
+	}
+
+	tests := []struct {
+		gzip      *conf_v1alpha1.Gzip
+		cfgParams *ConfigParams
+		expected  *version2.Gzip
+		msg       string
+	}{
+		{
+			gzip:      nil,
+			cfgParams: &ConfigParams{},
+			expected:  nil,
+			msg:       "gzip is not configured",
+		},
+		{
+			gzip:      nil,
+			cfgParams: cfgParams,
+			expected: &version2.Gzip{
+				Types:     "text/css application/json",
+				MinLength: 1000,
+				CompLevel: 5,
+				Proxied:   "any",
+			},
+			msg: "gzip is enabled in the ConfigMap",
+		},
+		{
+			gzip: &conf_v1alpha1.Gzip{
+				Enable: false,
+			},
+			cfgParams: cfgParams,
+			expected:  nil,
+			msg:       "gzip is disabled in the VirtualServer",
+		},
+		{
+			gzip: &conf_v1alpha1.Gzip{
+				Enable:    true,
+				Types:     []string{"text/plain"},
+				MinLength: &minLength,
+				Level:     &level,
+				Proxied:   []string{"expired", "no-cache"},
+			},
+			cfgParams: cfgParams,
+			expected: &version2.Gzip{
+				Types:     "text/plain",
+				MinLength: 500,
+				CompLevel: 6,
+				Proxied:   "expired no-cache",
+			},
+			msg: "gzip of the VirtualServer overrides the ConfigMap",
+		},
+		{
+			gzip: &conf_v1alpha1.Gzip{
+				Enable: true,
+			},
+			cfgParams: &ConfigParams{},
+			expected:  &version2.Gzip{},
+			msg:       "gzip is enabled in the VirtualServer only",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateGzip(test.gzip, test.cfgParams)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("generateGzip() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
+	}
+}
+
+func TestGenerateAccessLog(t *testing.T) {
+	tests := []struct {
+		accessLog *conf_v1alpha1.AccessLog
+		expected  string
+		msg       string
+	}{
+		{
+			accessLog: nil,
+			expected:  "",
+			msg:       "access log is not configured",
+		},
+		{
+			accessLog: &conf_v1alpha1.AccessLog{
+				Off: true,
+			},
+			expected: "off",
+			msg:      "access log is off",
+		},
+		{
+			accessLog: &conf_v1alpha1.AccessLog{
+				File: "cafe.log",
+			},
+			expected: "/var/log/nginx/cafe.log main",
+			msg:      "file",
+		},
+		{
+			accessLog: &conf_v1alpha1.AccessLog{
+				Stdout: true,
+			},
+			expected: "/dev/stdout main",
+			msg:      "stdout",
+		},
+		{
+			accessLog: &conf_v1alpha1.AccessLog{
+				Syslog: "/dev/log",
+			},
+			expected: "syslog:server=unix:/dev/log main",
+			msg:      "syslog",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateAccessLog(test.accessLog, "/var/log/nginx")
+		if result != test.expected {
+			t.Errorf("generateAccessLog() returned %q but expected %q for the case of %s", result, test.expected, test.msg)
+		}
+	}
+}
+
 func TestGenerateLocation(t *testing.T) {
 	cfgParams := ConfigParams{
 		ProxyConnectTimeout:  "30s",
@@ -1118,23 +1580,190 @@ func TestGenerateLocation(t *testing.T) {
 		ProxyNextUpstreamTries:   0,
 	}
 
-	result := generateLocation(path, upstreamName, conf_v1alpha1.Upstream{}, &cfgParams)
+	result := generateLocation(path, upstreamName, conf_v1alpha1.Upstream{}, nil, &cfgParams)
 	if !reflect.DeepEqual(result, expected) {
 		t.Errorf("generateLocation() returned %v but expected %v", result, expected)
 	}
 }
 
-func TestGenerateSSLConfig(t *testing.T) {
-	tests := []struct {
-		inputTLS            *conf_v1alpha1.TLS
-		inputTLSPemFileName string
-		inputCfgParams      *ConfigParams
-		expected            *version2.SSL
-		msg                 string
+func TestGenerateLocationWithUpstreamRequestSettings(t *testing.T) {
+	cfgParams := ConfigParams{
+		ProxyConnectTimeout:   "30s",
+		ProxyReadTimeout:      "31s",
+		ProxySendTimeout:      "32s",
+		ClientMaxBodySize:     "1m",
+		ProxyRequestBuffering: true,
+		ProxyHTTPVersion:      "1.1",
+		ClientBodyBufferSize:  "8k",
+		ClientBodyTimeout:     "60s",
+	}
+	requestBuffering := false
+	ignoreClientAbort := true
+	upstream := conf_v1alpha1.Upstream{
+		ProxyRequestBuffering:  &requestBuffering,
+		ProxyIgnoreClientAbort: &ignoreClientAbort,
+		ProxyHTTPVersion:       "1.0",
+		ClientBodyTimeout:      "300s",
+	}
+
+	expected := version2.Location{
+		Path:                     "/events",
+		ProxyConnectTimeout:      "30s",
+		ProxyReadTimeout:         "31s",
+		ProxySendTimeout:         "32s",
+		ClientMaxBodySize:        "1m",
+		ProxyRequestBuffering:    false,
+		ProxyHTTPVersion:         "1.0",
+		ProxyIgnoreClientAbort:   true,
+		ClientBodyBufferSize:     "8k",
+		ClientBodyTimeout:        "300s",
+		ProxyPass:                "http://test-upstream",
+		ProxyNextUpstream:        "error timeout",
+		ProxyNextUpstreamTimeout: "0s",
+		ProxyNextUpstreamTries:   0,
+	}
+
+	result := generateLocation("/events", "test-upstream", upstream, nil, &cfgParams)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("generateLocation() returned %v but expected %v", result, expected)
+	}
+}
+
+func TestGenerateLocationWithRouteProxy(t *testing.T) {
+	cfgParams := ConfigParams{
+		ProxyConnectTimeout:   "30s",
+		ProxyReadTimeout:      "31s",
+		ProxySendTimeout:      "32s",
+		ClientMaxBodySize:     "1m",
+		ProxyBuffering:        true,
+		ProxyBuffers:          "8 4k",
+		ProxyBufferSize:       "4k",
+		ProxyRequestBuffering: true,
+	}
+	upstream := conf_v1alpha1.Upstream{
+		ProxyReadTimeout:  "60s",
+		ProxySendTimeout:  "61s",
+		ClientMaxBodySize: "2m",
+	}
+	tries := 2
+	buffering := false
+	requestBuffering := false
+	proxy := &conf_v1alpha1.RouteProxy{
+		ProxyReadTimeout:       "300s",
+		ClientMaxBodySize:      "1g",
+		ProxyNextUpstreamTries: &tries,
+		ProxyBuffering:         &buffering,
+		ProxyRequestBuffering:  &requestBuffering,
+	}
+
+	expected := version2.Location{
+		Path:                     "/upload",
+		ProxyConnectTimeout:      "30s",
+		ProxyReadTimeout:         "300s",
+		ProxySendTimeout:         "61s",
+		ClientMaxBodySize:        "1g",
+		ProxyBuffering:           false,
+		ProxyBuffers:             "8 4k",
+		ProxyBufferSize:          "4k",
+		ProxyRequestBuffering:    false,
+		ProxyPass:                "http://test-upstream",
+		ProxyNextUpstream:        "error timeout",
+		ProxyNextUpstreamTimeout: "0s",
+		ProxyNextUpstreamTries:   2,
+	}
+
+	result := generateLocation("/upload", "test-upstream", upstream, proxy, &cfgParams)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("generateLocation() returned %v but expected %v", result, expected)
+	}
+}
+
+func TestMergeRouteProxy(t *testing.T) {
+	upstreamBuffering := true
+	routeBuffering := false
+	tries := 5
+
+	upstream := conf_v1alpha1.Upstream{
+		Name:                   "test",
+		ProxyConnectTimeout:    "10s",
+		ProxyReadTimeout:       "20s",
+		ProxyNextUpstreamTries: 1,
+		ProxyBuffering:         &upstreamBuffering,
+		ProxyBufferSize:        "4k",
+	}
+
+	tests := []struct {
+		proxy    *conf_v1alpha1.RouteProxy
+		expected conf_v1alpha1.Upstream
+		msg      string
+	}{
+		{
+			proxy:    nil,
+			expected: upstream,
+			msg:      "no proxy",
+		},
+		{
+			proxy: &conf_v1alpha1.RouteProxy{
+				ProxyReadTimeout:       "300s",
+				ProxyNextUpstream:      "error",
+				ProxyNextUpstreamTries: &tries,
+				ProxyBuffering:         &routeBuffering,
+				ProxyBuffers: &conf_v1alpha1.UpstreamBuffers{
+					Number: 16,
+					Size:   "8k",
+				},
+			},
+			expected: conf_v1alpha1.Upstream{
+				Name:                   "test",
+				ProxyConnectTimeout:    "10s",
+				ProxyReadTimeout:       "300s",
+				ProxyNextUpstream:      "error",
+				ProxyNextUpstreamTries: 5,
+				ProxyBuffering:         &routeBuffering,
+				ProxyBuffers: &conf_v1alpha1.UpstreamBuffers{
+					Number: 16,
+					Size:   "8k",
+				},
+				ProxyBufferSize: "4k",
+			},
+			msg: "proxy overrides some settings",
+		},
+	}
+
+	for _, test := range tests {
+		result := mergeRouteProxy(upstream, test.proxy)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("mergeRouteProxy() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
+	}
+}
+
+func TestGenerateSSLConfig(t *testing.T) {
+	defaultSecrets := specialTLSSecrets{
+		defaultServerPemFileName: "/etc/nginx/secrets/default",
+	}
+	wildcardSecrets := specialTLSSecrets{
+		defaultServerPemFileName: "/etc/nginx/secrets/default",
+		wildcardPemFileName:      "/etc/nginx/secrets/wildcard",
+	}
+	fallbackSecrets := specialTLSSecrets{
+		defaultServerPemFileName: "/etc/nginx/secrets/default",
+		isDefaultFallbackEnabled: true,
+	}
+
+	tests := []struct {
+		inputTLS            *conf_v1alpha1.TLS
+		inputTLSPemFileName string
+		inputSecrets        specialTLSSecrets
+		inputCfgParams      *ConfigParams
+		expected            *version2.SSL
+		expectedWarnings    int
+		msg                 string
 	}{
 		{
 			inputTLS:            nil,
 			inputTLSPemFileName: "",
+			inputSecrets:        defaultSecrets,
 			inputCfgParams:      &ConfigParams{},
 			expected:            nil,
 			msg:                 "no TLS field",
@@ -1144,30 +1773,89 @@ func TestGenerateSSLConfig(t *testing.T) {
 				Secret: "",
 			},
 			inputTLSPemFileName: "",
+			inputSecrets:        defaultSecrets,
 			inputCfgParams:      &ConfigParams{},
-			expected:            nil,
-			msg:                 "TLS field with empty secret",
+			expected: &version2.SSL{
+				HTTP2:           false,
+				Certificate:     "/etc/nginx/secrets/default",
+				CertificateKey:  "/etc/nginx/secrets/default",
+				Ciphers:         "NULL",
+				RedirectToHTTPS: false,
+			},
+			msg: "TLS field with empty secret without wildcard secret",
+		},
+		{
+			inputTLS: &conf_v1alpha1.TLS{
+				Secret: "",
+			},
+			inputTLSPemFileName: "",
+			inputSecrets:        wildcardSecrets,
+			inputCfgParams:      &ConfigParams{},
+			expected: &version2.SSL{
+				HTTP2:           false,
+				Certificate:     "/etc/nginx/secrets/wildcard",
+				CertificateKey:  "/etc/nginx/secrets/wildcard",
+				Ciphers:         "",
+				RedirectToHTTPS: false,
+			},
+			msg: "TLS field with empty secret with wildcard secret",
+		},
+		{
+			inputTLS: &conf_v1alpha1.TLS{
+				Secret: "",
+			},
+			inputTLSPemFileName: "",
+			inputSecrets:        fallbackSecrets,
+			inputCfgParams:      &ConfigParams{},
+			expected: &version2.SSL{
+				HTTP2:           false,
+				Certificate:     "/etc/nginx/secrets/default",
+				CertificateKey:  "/etc/nginx/secrets/default",
+				Ciphers:         "",
+				RedirectToHTTPS: false,
+			},
+			expectedWarnings: 1,
+			msg:              "TLS field with empty secret with default fallback",
 		},
 		{
 			inputTLS: &conf_v1alpha1.TLS{
 				Secret: "secret",
 			},
 			inputTLSPemFileName: "",
+			inputSecrets:        wildcardSecrets,
 			inputCfgParams:      &ConfigParams{},
 			expected: &version2.SSL{
 				HTTP2:           false,
-				Certificate:     pemFileNameForMissingTLSSecret,
-				CertificateKey:  pemFileNameForMissingTLSSecret,
+				Certificate:     "/etc/nginx/secrets/default",
+				CertificateKey:  "/etc/nginx/secrets/default",
 				Ciphers:         "NULL",
 				RedirectToHTTPS: false,
 			},
 			msg: "secret doesn't exist in the cluster with HTTP2 and SSLRedirect disabled",
 		},
+		{
+			inputTLS: &conf_v1alpha1.TLS{
+				Secret: "secret",
+			},
+			inputTLSPemFileName: "",
+			inputSecrets:        fallbackSecrets,
+			inputCfgParams:      &ConfigParams{},
+			expected: &version2.SSL{
+				HTTP2:           false,
+				Certificate:     "/etc/nginx/secrets/default",
+				CertificateKey:  "/etc/nginx/secrets/default",
+				Ciphers:         "",
+				RedirectToHTTPS: false,
+			},
+			expectedWarnings: 1,
+			msg:              "secret doesn't exist in the cluster with default fallback",
+		},
 		{
 			inputTLS: &conf_v1alpha1.TLS{
 				Secret: "secret",
 			},
 			inputTLSPemFileName: "secret.pem",
+			inputSecrets:        fallbackSecrets,
 			inputCfgParams:      &ConfigParams{},
 			expected: &version2.SSL{
 				HTTP2:           false,
@@ -1183,6 +1871,7 @@ func TestGenerateSSLConfig(t *testing.T) {
 				Secret: "secret",
 			},
 			inputTLSPemFileName: "secret.pem",
+			inputSecrets:        defaultSecrets,
 			inputCfgParams: &ConfigParams{
 				HTTP2:       true,
 				SSLRedirect: true,
@@ -1199,10 +1888,16 @@ func TestGenerateSSLConfig(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		result := generateSSLConfig(test.inputTLS, test.inputTLSPemFileName, test.inputCfgParams)
+		vsc := newVirtualServerConfigurator(test.inputCfgParams, false, false, &StaticConfigParams{})
+		owner := &conf_v1alpha1.VirtualServer{}
+
+		result := vsc.generateSSLConfig(owner, test.inputTLS, test.inputTLSPemFileName, test.inputSecrets, test.inputCfgParams)
 		if !reflect.DeepEqual(result, test.expected) {
 			t.Errorf("generateSSLConfig() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
 		}
+		if len(vsc.warnings[owner]) != test.expectedWarnings {
+			t.Errorf("generateSSLConfig() returned warnings %v but expected %d warning(s) for the case of %s", vsc.warnings[owner], test.expectedWarnings, test.msg)
+		}
 	}
 }
 
@@ -1350,7 +2045,7 @@ func TestCreateUpstreamsForPlus(t *testing.T) {
 		},
 	}
 
-	result := createUpstreamsForPlus(&virtualServerEx, &ConfigParams{})
+	result := createUpstreamsForPlus(&virtualServerEx, &ConfigParams{}, &StaticConfigParams{})
 	if !reflect.DeepEqual(result, expected) {
 		t.Errorf("createUpstreamsForPlus returned \n%v but expected \n%v", result, expected)
 	}
@@ -1676,88 +2371,83 @@ func TestGenerateRulesRouteConfig(t *testing.T) {
 
 	cfgParams := ConfigParams{}
 
-	result := generateRulesRouteConfig(route, upstreamNamer, map[string]conf_v1alpha1.Upstream{}, variableNamer, index, &cfgParams)
+	result := generateRulesRouteConfig(route, upstreamNamer, map[string]conf_v1alpha1.Upstream{}, variableNamer, index, 0, &cfgParams)
 	if !reflect.DeepEqual(result, expected) {
 		t.Errorf("generateRulesRouteConfig() returned \n%v but expected \n%v", result, expected)
 	}
 }
 
-func TestGenerateValueForRulesRouteMap(t *testing.T) {
-	tests := []struct {
-		input              string
-		expectedValue      string
-		expectedIsNegative bool
-	}{
-		{
-			input:              "default",
-			expectedValue:      `\default`,
-			expectedIsNegative: false,
-		},
-		{
-			input:              "!default",
-			expectedValue:      `\default`,
-			expectedIsNegative: true,
-		},
-		{
-			input:              "hostnames",
-			expectedValue:      `\hostnames`,
-			expectedIsNegative: false,
-		},
-		{
-			input:              "include",
-			expectedValue:      `\include`,
-			expectedIsNegative: false,
-		},
-		{
-			input:              "volatile",
-			expectedValue:      `\volatile`,
-			expectedIsNegative: false,
-		},
-		{
-			input:              "abc",
-			expectedValue:      `"abc"`,
-			expectedIsNegative: false,
+func TestGenerateRulesRouteConfigWithSourceIPAndArgumentPresentConditions(t *testing.T) {
+	route := conf_v1alpha1.Route{
+		Path: "/",
+		Rules: &conf_v1alpha1.Rules{
+			Conditions: []conf_v1alpha1.Condition{
+				{
+					SourceIP: true,
+				},
+				{
+					ArgumentPresent: "debug",
+				},
+			},
+			Matches: []conf_v1alpha1.Match{
+				{
+					Values: []string{
+						"10.0.0.0/8",
+						"true",
+					},
+					Upstream: "coffee-debug",
+				},
+			},
+			DefaultUpstream: "coffee",
 		},
-		{
-			input:              "!abc",
-			expectedValue:      `"abc"`,
-			expectedIsNegative: true,
+	}
+	virtualServer := conf_v1alpha1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
 		},
+	}
+	upstreamNamer := newUpstreamNamerForVirtualServer(&virtualServer)
+	variableNamer := newVariableNamer(&virtualServer)
+	index := 0
+
+	expectedGeos := []version2.Geo{
 		{
-			input:              "",
-			expectedValue:      `""`,
-			expectedIsNegative: false,
+			Source:   "$remote_addr",
+			Variable: "$vs_default_cafe_rules_0_match_0_cond_0_geo",
+			Parameters: []version2.Parameter{
+				{
+					Value:  "10.0.0.0/8",
+					Result: "1",
+				},
+				{
+					Value:  "default",
+					Result: "0",
+				},
+			},
 		},
+	}
+	expectedMaps := []version2.Map{
 		{
-			input:              "!",
-			expectedValue:      `""`,
-			expectedIsNegative: true,
+			Source:   "$vs_default_cafe_rules_0_match_0_cond_0_geo",
+			Variable: "$vs_default_cafe_rules_0_match_0_cond_0",
+			Parameters: []version2.Parameter{
+				{
+					Value:  `"1"`,
+					Result: "$vs_default_cafe_rules_0_match_0_cond_1",
+				},
+				{
+					Value:  "default",
+					Result: "0",
+				},
+			},
 		},
-	}
-
-	for _, test := range tests {
-		resultValue, resultIsNegative := generateValueForRulesRouteMap(test.input)
-		if resultValue != test.expectedValue {
-			t.Errorf("generateValueForRulesRouteMap(%q) returned %q but expected %q as the value", test.input, resultValue, test.expectedValue)
-		}
-		if resultIsNegative != test.expectedIsNegative {
-			t.Errorf("generateValueForRulesRouteMap(%q) returned %v but expected %v as the isNegative", test.input, resultIsNegative, test.expectedIsNegative)
-		}
-	}
-}
-
-func TestGenerateParametersForRulesRouteMap(t *testing.T) {
-	tests := []struct {
-		inputMatchedValue     string
-		inputSuccessfulResult string
-		expected              []version2.Parameter
-	}{
 		{
-			inputMatchedValue:     "abc",
-			inputSuccessfulResult: "1",
-			expected: []version2.Parameter{
+			Source:   "$args",
+			Variable: "$vs_default_cafe_rules_0_match_0_cond_1",
+			Parameters: []version2.Parameter{
 				{
-					Value:  `"abc"`,
+					Value:  `"~(^|&)debug(=|&|$)"`,
 					Result: "1",
 				},
 				{
@@ -1767,43 +2457,890 @@ func TestGenerateParametersForRulesRouteMap(t *testing.T) {
 			},
 		},
 		{
-			inputMatchedValue:     "!abc",
-			inputSuccessfulResult: "1",
-			expected: []version2.Parameter{
+			Source:   "$vs_default_cafe_rules_0_match_0_cond_0",
+			Variable: "$vs_default_cafe_rules_0",
+			Parameters: []version2.Parameter{
 				{
-					Value:  `"abc"`,
-					Result: "0",
+					Value:  "~^1",
+					Result: "@rules_0_match_0",
 				},
 				{
 					Value:  "default",
-					Result: "1",
+					Result: "@rules_0_default",
 				},
 			},
 		},
 	}
 
-	for _, test := range tests {
-		result := generateParametersForRulesRouteMap(test.inputMatchedValue, test.inputSuccessfulResult)
-		if !reflect.DeepEqual(result, test.expected) {
-			t.Errorf("generateParametersForRulesRouteMap(%q, %q) returned %v but expected %v", test.inputMatchedValue, test.inputSuccessfulResult, result, test.expected)
-		}
+	cfgParams := ConfigParams{}
+
+	result := generateRulesRouteConfig(route, upstreamNamer, map[string]conf_v1alpha1.Upstream{}, variableNamer, index, 0, &cfgParams)
+	if !reflect.DeepEqual(result.Geos, expectedGeos) {
+		t.Errorf("generateRulesRouteConfig() returned geos \n%v but expected \n%v", result.Geos, expectedGeos)
+	}
+	if !reflect.DeepEqual(result.Maps, expectedMaps) {
+		t.Errorf("generateRulesRouteConfig() returned maps \n%v but expected \n%v", result.Maps, expectedMaps)
 	}
 }
 
-func TestGetNameForSourceForRulesRouteMapFromCondition(t *testing.T) {
-	tests := []struct {
-		input    conf_v1alpha1.Condition
-		expected string
-	}{
-		{
-			input: conf_v1alpha1.Condition{
-				Header: "x-version",
-			},
-			expected: "$http_x_version",
-		},
-		{
-			input: conf_v1alpha1.Condition{
-				Cookie: "mycookie",
+func TestGenerateRulesRouteConfigWithPathConditionCaptures(t *testing.T) {
+	route := conf_v1alpha1.Route{
+		Path: "/",
+		Rules: &conf_v1alpha1.Rules{
+			Conditions: []conf_v1alpha1.Condition{
+				{
+					Path: true,
+				},
+			},
+			Matches: []conf_v1alpha1.Match{
+				{
+					Values: []string{
+						"~^/api/(v[0-9]+)/(?<resource>[a-z]+)",
+					},
+					Upstream: "api",
+				},
+				{
+					Values: []string{
+						"!~^/web/(.*)",
+					},
+					Upstream: "api",
+				},
+			},
+			DefaultUpstream: "web",
+		},
+	}
+	virtualServer := conf_v1alpha1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+	upstreamNamer := newUpstreamNamerForVirtualServer(&virtualServer)
+	variableNamer := newVariableNamer(&virtualServer)
+
+	expectedMaps := []version2.Map{
+		{
+			Source:   "$uri",
+			Variable: "$vs_default_cafe_rules_0_match_0_cond_0",
+			Parameters: []version2.Parameter{
+				{
+					Value:  `"~^/api/(v[0-9]+)/(?<resource>[a-z]+)"`,
+					Result: "1",
+				},
+				{
+					Value:  "default",
+					Result: "0",
+				},
+			},
+		},
+		{
+			Source:   "$uri",
+			Variable: "$vs_default_cafe_rules_0_match_0_path_capture_1",
+			Parameters: []version2.Parameter{
+				{
+					Value:  `"~^/api/(v[0-9]+)/(?<resource>[a-z]+)"`,
+					Result: "$1",
+				},
+				{
+					Value:  "default",
+					Result: `""`,
+				},
+			},
+		},
+		{
+			Source:   "$uri",
+			Variable: "$vs_default_cafe_rules_0_match_0_path_capture_2",
+			Parameters: []version2.Parameter{
+				{
+					Value:  `"~^/api/(v[0-9]+)/(?<resource>[a-z]+)"`,
+					Result: "$2",
+				},
+				{
+					Value:  "default",
+					Result: `""`,
+				},
+			},
+		},
+		{
+			Source:   "$uri",
+			Variable: "$vs_default_cafe_rules_0_match_1_cond_0",
+			Parameters: []version2.Parameter{
+				{
+					Value:  `"~^/web/(.*)"`,
+					Result: "0",
+				},
+				{
+					Value:  "default",
+					Result: "1",
+				},
+			},
+		},
+		{
+			Source:   "$vs_default_cafe_rules_0_match_0_cond_0$vs_default_cafe_rules_0_match_1_cond_0",
+			Variable: "$vs_default_cafe_rules_0",
+			Parameters: []version2.Parameter{
+				{
+					Value:  "~^1",
+					Result: "@rules_0_match_0",
+				},
+				{
+					Value:  "~^01",
+					Result: "@rules_0_match_1",
+				},
+				{
+					Value:  "default",
+					Result: "@rules_0_default",
+				},
+			},
+		},
+	}
+	expectedVariables := []version2.Variable{
+		{
+			Name:  "$path_capture_1",
+			Value: "$vs_default_cafe_rules_0_match_0_path_capture_1",
+		},
+		{
+			Name:  "$path_capture_2",
+			Value: "$vs_default_cafe_rules_0_match_0_path_capture_2",
+		},
+	}
+
+	cfgParams := ConfigParams{}
+
+	result := generateRulesRouteConfig(route, upstreamNamer, map[string]conf_v1alpha1.Upstream{}, variableNamer, 0, 0, &cfgParams)
+	if !reflect.DeepEqual(result.Maps, expectedMaps) {
+		t.Errorf("generateRulesRouteConfig() returned maps \n%v but expected \n%v", result.Maps, expectedMaps)
+	}
+	if len(result.Locations) != 3 {
+		t.Fatalf("generateRulesRouteConfig() returned %v locations but expected 3", len(result.Locations))
+	}
+	if !reflect.DeepEqual(result.Locations[0].Variables, expectedVariables) {
+		t.Errorf("generateRulesRouteConfig() returned the variables %v for the location of the first match but expected %v", result.Locations[0].Variables, expectedVariables)
+	}
+	for _, loc := range result.Locations[1:] {
+		if len(loc.Variables) != 0 {
+			t.Errorf("generateRulesRouteConfig() returned the variables %v for the location %v but expected none", loc.Variables, loc.Path)
+		}
+	}
+}
+
+func TestCountRegexCaptures(t *testing.T) {
+	tests := []struct {
+		regex    string
+		expected int
+	}{
+		{
+			regex:    "^/api/",
+			expected: 0,
+		},
+		{
+			regex:    "^/api/(v[0-9]+)/(.*)",
+			expected: 2,
+		},
+		{
+			regex:    "^/(?:api|web)/(?<version>v[0-9]+)/(?P<resource>[a-z]+)/(?'id'[0-9]+)",
+			expected: 3,
+		},
+		{
+			regex:    "^/(?i)api(?=/)(?<!x)(?<=/)",
+			expected: 0,
+		},
+		{
+			regex:    `^/api/\(v1\)/[(]x[^)(]/[]()]`,
+			expected: 0,
+		},
+	}
+
+	for _, test := range tests {
+		result := countRegexCaptures(test.regex)
+		if result != test.expected {
+			t.Errorf("countRegexCaptures(%q) returned %v but expected %v", test.regex, result, test.expected)
+		}
+	}
+}
+
+func TestGenerateRulesRouteConfigWithSplits(t *testing.T) {
+	route := conf_v1alpha1.Route{
+		Path: "/",
+		Rules: &conf_v1alpha1.Rules{
+			Conditions: []conf_v1alpha1.Condition{
+				{
+					Header: "x-version",
+				},
+			},
+			Matches: []conf_v1alpha1.Match{
+				{
+					Values: []string{
+						"beta",
+					},
+					Splits: []conf_v1alpha1.Split{
+						{
+							Weight:   90,
+							Upstream: "coffee-v1",
+						},
+						{
+							Weight:   10,
+							Upstream: "coffee-v2",
+						},
+					},
+				},
+			},
+			DefaultSplits: []conf_v1alpha1.Split{
+				{
+					Weight:   50,
+					Upstream: "coffee-v1",
+				},
+				{
+					Weight:   50,
+					Upstream: "tea",
+				},
+			},
+		},
+	}
+	virtualServer := conf_v1alpha1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+	upstreamNamer := newUpstreamNamerForVirtualServer(&virtualServer)
+	variableNamer := newVariableNamer(&virtualServer)
+	index := 1
+	splitClientsIndex := 2
+
+	expected := rulesRouteCfg{
+		Maps: []version2.Map{
+			{
+				Source:   "$http_x_version",
+				Variable: "$vs_default_cafe_rules_1_match_0_cond_0",
+				Parameters: []version2.Parameter{
+					{
+						Value:  `"beta"`,
+						Result: "1",
+					},
+					{
+						Value:  "default",
+						Result: "0",
+					},
+				},
+			},
+			{
+				Source:   "$vs_default_cafe_rules_1_match_0_cond_0",
+				Variable: "$vs_default_cafe_rules_1",
+				Parameters: []version2.Parameter{
+					{
+						Value:  "~^1",
+						Result: "$vs_default_cafe_splits_2",
+					},
+					{
+						Value:  "default",
+						Result: "$vs_default_cafe_splits_3",
+					},
+				},
+			},
+		},
+		SplitClients: []version2.SplitClient{
+			{
+				Source:   "$request_id",
+				Variable: "$vs_default_cafe_splits_2",
+				Distributions: []version2.Distribution{
+					{
+						Weight: "90%",
+						Value:  "@splits_2_split_0",
+					},
+					{
+						Weight: "10%",
+						Value:  "@splits_2_split_1",
+					},
+				},
+			},
+			{
+				Source:   "$request_id",
+				Variable: "$vs_default_cafe_splits_3",
+				Distributions: []version2.Distribution{
+					{
+						Weight: "50%",
+						Value:  "@splits_3_split_0",
+					},
+					{
+						Weight: "50%",
+						Value:  "@splits_3_split_1",
+					},
+				},
+			},
+		},
+		Locations: []version2.Location{
+			{
+				Path:                     "@splits_2_split_0",
+				ProxyPass:                "http://vs_default_cafe_coffee-v1",
+				ProxyNextUpstream:        "error timeout",
+				ProxyNextUpstreamTimeout: "0s",
+				ProxyNextUpstreamTries:   0,
+			},
+			{
+				Path:                     "@splits_2_split_1",
+				ProxyPass:                "http://vs_default_cafe_coffee-v2",
+				ProxyNextUpstream:        "error timeout",
+				ProxyNextUpstreamTimeout: "0s",
+				ProxyNextUpstreamTries:   0,
+			},
+			{
+				Path:                     "@splits_3_split_0",
+				ProxyPass:                "http://vs_default_cafe_coffee-v1",
+				ProxyNextUpstream:        "error timeout",
+				ProxyNextUpstreamTimeout: "0s",
+				ProxyNextUpstreamTries:   0,
+			},
+			{
+				Path:                     "@splits_3_split_1",
+				ProxyPass:                "http://vs_default_cafe_tea",
+				ProxyNextUpstream:        "error timeout",
+				ProxyNextUpstreamTimeout: "0s",
+				ProxyNextUpstreamTries:   0,
+			},
+		},
+		InternalRedirectLocation: version2.InternalRedirectLocation{
+			Path:        "/",
+			Destination: "$vs_default_cafe_rules_1",
+		},
+	}
+
+	cfgParams := ConfigParams{}
+
+	result := generateRulesRouteConfig(route, upstreamNamer, map[string]conf_v1alpha1.Upstream{}, variableNamer, index, splitClientsIndex, &cfgParams)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("generateRulesRouteConfig() returned \n%v but expected \n%v", result, expected)
+	}
+}
+
+func TestGenerateExternalAuth(t *testing.T) {
+	virtualServer := conf_v1alpha1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+	upstreamNamer := newUpstreamNamerForVirtualServer(&virtualServer)
+	crUpstreams := map[string]conf_v1alpha1.Upstream{
+		"vs_default_cafe_auth": {
+			TLS: conf_v1alpha1.UpstreamTLS{
+				Enable: true,
+			},
+		},
+	}
+
+	tests := []struct {
+		externalAuth     *conf_v1alpha1.ExternalAuth
+		expectedAuth     *version2.ExternalAuth
+		expectedLocation version2.ExternalAuthLocation
+		msg              string
+	}{
+		{
+			externalAuth: &conf_v1alpha1.ExternalAuth{
+				URL: "http://auth.example.com/auth",
+			},
+			expectedAuth: &version2.ExternalAuth{
+				LocationName: "/_external_auth_1",
+			},
+			expectedLocation: version2.ExternalAuthLocation{
+				Name:      "/_external_auth_1",
+				ProxyPass: "http://auth.example.com/auth",
+			},
+			msg: "url",
+		},
+		{
+			externalAuth: &conf_v1alpha1.ExternalAuth{
+				Upstream:        "auth",
+				Path:            "/oauth2/auth",
+				ResponseHeaders: []string{"X-Auth-Request-User"},
+				Signin:          "https://cafe.example.com/oauth2/start",
+			},
+			expectedAuth: &version2.ExternalAuth{
+				LocationName:       "/_external_auth_1",
+				SigninLocationName: "@external_auth_signin_1",
+				ResponseHeaders: []version2.ExternalAuthResponseHeader{
+					{
+						Name:             "X-Auth-Request-User",
+						Variable:         "$external_auth_x_auth_request_user",
+						UpstreamVariable: "$upstream_http_x_auth_request_user",
+					},
+				},
+			},
+			expectedLocation: version2.ExternalAuthLocation{
+				Name:               "/_external_auth_1",
+				ProxyPass:          "https://vs_default_cafe_auth/oauth2/auth",
+				SigninLocationName: "@external_auth_signin_1",
+				SigninURL:          "https://cafe.example.com/oauth2/start",
+			},
+			msg: "upstream with response headers and signin",
+		},
+	}
+
+	for _, test := range tests {
+		auth, location := generateExternalAuth(test.externalAuth, upstreamNamer, crUpstreams, 1)
+		if !reflect.DeepEqual(auth, test.expectedAuth) {
+			t.Errorf("generateExternalAuth() returned %v but expected %v for the case of %s", auth, test.expectedAuth, test.msg)
+		}
+		if !reflect.DeepEqual(location, test.expectedLocation) {
+			t.Errorf("generateExternalAuth() returned %v but expected %v for the case of %s", location, test.expectedLocation, test.msg)
+		}
+	}
+}
+
+func TestGenerateCORS(t *testing.T) {
+	virtualServer := conf_v1alpha1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+	variableNamer := newVariableNamer(&virtualServer)
+	maxAge := 600
+	cfgParams := NewDefaultConfigParams()
+
+	expectedPreflightMap := version2.Map{
+		Source:   `"$request_method:$http_access_control_request_method"`,
+		Variable: "$vs_default_cafe_cors_1_preflight",
+		Parameters: []version2.Parameter{
+			{
+				Value:  `"~^OPTIONS:.+"`,
+				Result: "1",
+			},
+			{
+				Value:  "default",
+				Result: "0",
+			},
+		},
+	}
+
+	tests := []struct {
+		cors         *conf_v1alpha1.CORS
+		expectedCORS *version2.CORS
+		expectedMaps []version2.Map
+		msg          string
+	}{
+		{
+			cors: &conf_v1alpha1.CORS{
+				AllowOrigin: []string{"*"},
+			},
+			expectedCORS: &version2.CORS{
+				AllowOrigin:       "*",
+				AllowMethods:      "GET, PUT, POST, DELETE, PATCH, OPTIONS",
+				AllowHeaders:      "DNT, Keep-Alive, User-Agent, X-Requested-With, If-Modified-Since, Cache-Control, Content-Type, Range, Authorization",
+				MaxAge:            86400,
+				PreflightVariable: "$vs_default_cafe_cors_1_preflight",
+			},
+			expectedMaps: []version2.Map{expectedPreflightMap},
+			msg:          "any origin with defaults",
+		},
+		{
+			cors: &conf_v1alpha1.CORS{
+				AllowOrigin:      []string{"https://example.com", "https://*.example.com"},
+				AllowMethods:     []string{"GET", "POST"},
+				AllowHeaders:     []string{"Content-Type"},
+				AllowCredentials: true,
+				MaxAge:           &maxAge,
+				ExposeHeaders:    []string{"X-Request-ID", "X-Total-Count"},
+			},
+			expectedCORS: &version2.CORS{
+				AllowOrigin:       "$vs_default_cafe_cors_1_origin",
+				AllowMethods:      "GET, POST",
+				AllowHeaders:      "Content-Type",
+				AllowCredentials:  true,
+				MaxAge:            600,
+				ExposeHeaders:     "X-Request-ID, X-Total-Count",
+				PreflightVariable: "$vs_default_cafe_cors_1_preflight",
+			},
+			expectedMaps: []version2.Map{
+				expectedPreflightMap,
+				{
+					Source:   "$http_origin",
+					Variable: "$vs_default_cafe_cors_1_origin",
+					Parameters: []version2.Parameter{
+						{
+							Value:  `"https://example.com"`,
+							Result: "$http_origin",
+						},
+						{
+							Value:  `"~^https://[a-z0-9-]+(\.[a-z0-9-]+)*\.example\.com$"`,
+							Result: "$http_origin",
+						},
+						{
+							Value:  "default",
+							Result: `""`,
+						},
+					},
+				},
+			},
+			msg: "origins with credentials",
+		},
+	}
+
+	for _, test := range tests {
+		cors, maps := generateCORS(test.cors, variableNamer, 1, cfgParams)
+		if !reflect.DeepEqual(cors, test.expectedCORS) {
+			t.Errorf("generateCORS() returned %+v but expected %+v for the case of %s", cors, test.expectedCORS, test.msg)
+		}
+		if !reflect.DeepEqual(maps, test.expectedMaps) {
+			t.Errorf("generateCORS() returned %+v but expected %+v for the case of %s", maps, test.expectedMaps, test.msg)
+		}
+	}
+}
+
+func TestGenerateProxyCache(t *testing.T) {
+	cfgParams := NewDefaultConfigParams()
+	cfgParams.MainProxyCacheZones = []ProxyCacheZone{
+		{
+			Name: "cafe",
+			Size: "10m",
+		},
+	}
+	vsc := newVirtualServerConfigurator(cfgParams, false, false, &StaticConfigParams{})
+	owner := &conf_v1alpha1.VirtualServer{}
+
+	cache := &conf_v1alpha1.Cache{
+		Zone:         "cafe",
+		Valid:        []string{"200 302 10m", "404 1m"},
+		Methods:      []string{"GET", "HEAD"},
+		Key:          "$scheme$host$request_uri",
+		Bypass:       []string{"$http_pragma", "$cookie_nocache"},
+		UseStale:     []string{"error", "updating"},
+		StatusHeader: true,
+	}
+	expected := &version2.ProxyCache{
+		Zone:         "cafe",
+		Valid:        []string{"200 302 10m", "404 1m"},
+		Methods:      "GET HEAD",
+		Key:          "$scheme$host$request_uri",
+		Bypass:       "$http_pragma $cookie_nocache",
+		UseStale:     "error updating",
+		StatusHeader: true,
+	}
+
+	result := vsc.generateProxyCache(owner, cache)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("generateProxyCache() returned %+v but expected %+v", result, expected)
+	}
+	if len(vsc.warnings) != 0 {
+		t.Errorf("generateProxyCache() returned unexpected warnings %v", vsc.warnings)
+	}
+
+	result = vsc.generateProxyCache(owner, &conf_v1alpha1.Cache{Zone: "tea"})
+	if result != nil {
+		t.Errorf("generateProxyCache() returned %+v but expected nil for an undeclared zone", result)
+	}
+	if len(vsc.warnings[owner]) != 1 {
+		t.Errorf("generateProxyCache() returned warnings %v but expected a warning about the undeclared zone", vsc.warnings[owner])
+	}
+}
+
+func TestGenerateMirror(t *testing.T) {
+	virtualServer := conf_v1alpha1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+	upstreamNamer := newUpstreamNamerForVirtualServer(&virtualServer)
+	variableNamer := newVariableNamer(&virtualServer)
+	keepalive := 16
+	crUpstreams := map[string]conf_v1alpha1.Upstream{
+		"vs_default_cafe_shadow": {
+			ProxyReadTimeout: "5s",
+			Keepalive:        &keepalive,
+			TLS: conf_v1alpha1.UpstreamTLS{
+				Enable: true,
+			},
+		},
+	}
+	cfgParams := NewDefaultConfigParams()
+	percentage := 10
+	requestBody := false
+
+	tests := []struct {
+		mirror              *conf_v1alpha1.Mirror
+		expectedMirror      *version2.Mirror
+		expectedLocation    version2.MirrorLocation
+		expectedSplitClient *version2.SplitClient
+		msg                 string
+	}{
+		{
+			mirror: &conf_v1alpha1.Mirror{
+				Upstream: "shadow",
+			},
+			expectedMirror: &version2.Mirror{
+				LocationName: "/_mirror_1",
+				RequestBody:  true,
+			},
+			expectedLocation: version2.MirrorLocation{
+				Name:                "/_mirror_1",
+				ProxyConnectTimeout: "60s",
+				ProxyReadTimeout:    "5s",
+				ProxySendTimeout:    "60s",
+				ProxyPass:           "https://vs_default_cafe_shadow",
+				HasKeepalive:        true,
+				RequestBody:         true,
+			},
+			expectedSplitClient: nil,
+			msg:                 "all requests",
+		},
+		{
+			mirror: &conf_v1alpha1.Mirror{
+				Upstream:    "shadow",
+				Percentage:  &percentage,
+				RequestBody: &requestBody,
+			},
+			expectedMirror: &version2.Mirror{
+				LocationName: "/_mirror_1",
+				RequestBody:  false,
+			},
+			expectedLocation: version2.MirrorLocation{
+				Name:                "/_mirror_1",
+				Variable:            "$vs_default_cafe_mirror_1",
+				ProxyConnectTimeout: "60s",
+				ProxyReadTimeout:    "5s",
+				ProxySendTimeout:    "60s",
+				ProxyPass:           "https://vs_default_cafe_shadow",
+				HasKeepalive:        true,
+				RequestBody:         false,
+			},
+			expectedSplitClient: &version2.SplitClient{
+				Source:   "$request_id",
+				Variable: "$vs_default_cafe_mirror_1",
+				Distributions: []version2.Distribution{
+					{
+						Weight: "10%",
+						Value:  "1",
+					},
+					{
+						Weight: "*",
+						Value:  `""`,
+					},
+				},
+			},
+			msg: "percentage of requests without the request body",
+		},
+	}
+
+	for _, test := range tests {
+		mirror, location, splitClient := generateMirror(test.mirror, upstreamNamer, crUpstreams, variableNamer, 1, cfgParams)
+		if !reflect.DeepEqual(mirror, test.expectedMirror) {
+			t.Errorf("generateMirror() returned %+v but expected %+v for the case of %s", mirror, test.expectedMirror, test.msg)
+		}
+		if !reflect.DeepEqual(location, test.expectedLocation) {
+			t.Errorf("generateMirror() returned %+v but expected %+v for the case of %s", location, test.expectedLocation, test.msg)
+		}
+		if !reflect.DeepEqual(splitClient, test.expectedSplitClient) {
+			t.Errorf("generateMirror() returned %+v but expected %+v for the case of %s", splitClient, test.expectedSplitClient, test.msg)
+		}
+	}
+}
+
+func TestGenerateValueForRulesRouteMap(t *testing.T) {
+	tests := []struct {
+		input              string
+		expectedValue      string
+		expectedIsNegative bool
+	}{
+		{
+			input:              "default",
+			expectedValue:      `\default`,
+			expectedIsNegative: false,
+		},
+		{
+			input:              "!default",
+			expectedValue:      `\default`,
+			expectedIsNegative: true,
+		},
+		{
+			input:              "hostnames",
+			expectedValue:      `\hostnames`,
+			expectedIsNegative: false,
+		},
+		{
+			input:              "include",
+			expectedValue:      `\include`,
+			expectedIsNegative: false,
+		},
+		{
+			input:              "volatile",
+			expectedValue:      `\volatile`,
+			expectedIsNegative: false,
+		},
+		{
+			input:              "abc",
+			expectedValue:      `"abc"`,
+			expectedIsNegative: false,
+		},
+		{
+			input:              "!abc",
+			expectedValue:      `"abc"`,
+			expectedIsNegative: true,
+		},
+		{
+			input:              "",
+			expectedValue:      `""`,
+			expectedIsNegative: false,
+		},
+		{
+			input:              "!",
+			expectedValue:      `""`,
+			expectedIsNegative: true,
+		},
+	}
+
+	for _, test := range tests {
+		resultValue, resultIsNegative := generateValueForRulesRouteMap(test.input)
+		if resultValue != test.expectedValue {
+			t.Errorf("generateValueForRulesRouteMap(%q) returned %q but expected %q as the value", test.input, resultValue, test.expectedValue)
+		}
+		if resultIsNegative != test.expectedIsNegative {
+			t.Errorf("generateValueForRulesRouteMap(%q) returned %v but expected %v as the isNegative", test.input, resultIsNegative, test.expectedIsNegative)
+		}
+	}
+}
+
+func TestGenerateParametersForRulesRouteMap(t *testing.T) {
+	tests := []struct {
+		inputMatchedValue     string
+		inputSuccessfulResult string
+		expected              []version2.Parameter
+	}{
+		{
+			inputMatchedValue:     "abc",
+			inputSuccessfulResult: "1",
+			expected: []version2.Parameter{
+				{
+					Value:  `"abc"`,
+					Result: "1",
+				},
+				{
+					Value:  "default",
+					Result: "0",
+				},
+			},
+		},
+		{
+			inputMatchedValue:     "!abc",
+			inputSuccessfulResult: "1",
+			expected: []version2.Parameter{
+				{
+					Value:  `"abc"`,
+					Result: "0",
+				},
+				{
+					Value:  "default",
+					Result: "1",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		result := generateParametersForRulesRouteMap(test.inputMatchedValue, test.inputSuccessfulResult)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("generateParametersForRulesRouteMap(%q, %q) returned %v but expected %v", test.inputMatchedValue, test.inputSuccessfulResult, result, test.expected)
+		}
+	}
+}
+
+func TestGenerateGeoForRulesRoute(t *testing.T) {
+	tests := []struct {
+		matchedValue string
+		expected     version2.Geo
+		msg          string
+	}{
+		{
+			matchedValue: "10.0.0.0/8",
+			expected: version2.Geo{
+				Source:   "$remote_addr",
+				Variable: "$geo",
+				Parameters: []version2.Parameter{
+					{
+						Value:  "10.0.0.0/8",
+						Result: "1",
+					},
+					{
+						Value:  "default",
+						Result: "0",
+					},
+				},
+			},
+			msg: "CIDR range",
+		},
+		{
+			matchedValue: "!192.168.1.1",
+			expected: version2.Geo{
+				Source:   "$remote_addr",
+				Variable: "$geo",
+				Parameters: []version2.Parameter{
+					{
+						Value:  "192.168.1.1",
+						Result: "0",
+					},
+					{
+						Value:  "default",
+						Result: "1",
+					},
+				},
+			},
+			msg: "negated IP address",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateGeoForRulesRoute(test.matchedValue, "$geo")
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("generateGeoForRulesRoute() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
+	}
+}
+
+func TestGenerateValueForArgumentPresentCondition(t *testing.T) {
+	tests := []struct {
+		matchedValue string
+		expected     string
+	}{
+		{
+			matchedValue: "true",
+			expected:     "~(^|&)debug(=|&|$)",
+		},
+		{
+			matchedValue: "false",
+			expected:     "!~(^|&)debug(=|&|$)",
+		},
+		{
+			matchedValue: "!true",
+			expected:     "!~(^|&)debug(=|&|$)",
+		},
+		{
+			matchedValue: "!false",
+			expected:     "~(^|&)debug(=|&|$)",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateValueForArgumentPresentCondition("debug", test.matchedValue)
+		if result != test.expected {
+			t.Errorf("generateValueForArgumentPresentCondition() returned %q but expected %q for input %q", result, test.expected, test.matchedValue)
+		}
+	}
+}
+
+func TestGetNameForSourceForRulesRouteMapFromCondition(t *testing.T) {
+	tests := []struct {
+		input    conf_v1alpha1.Condition
+		expected string
+	}{
+		{
+			input: conf_v1alpha1.Condition{
+				Header: "x-version",
+			},
+			expected: "$http_x_version",
+		},
+		{
+			input: conf_v1alpha1.Condition{
+				Cookie: "mycookie",
 			},
 			expected: "$cookie_mycookie",
 		},
@@ -1819,6 +3356,30 @@ func TestGetNameForSourceForRulesRouteMapFromCondition(t *testing.T) {
 			},
 			expected: "$request_method",
 		},
+		{
+			input: conf_v1alpha1.Condition{
+				ArgumentPresent: "debug",
+			},
+			expected: "$args",
+		},
+		{
+			input: conf_v1alpha1.Condition{
+				Method: true,
+			},
+			expected: "$request_method",
+		},
+		{
+			input: conf_v1alpha1.Condition{
+				Path: true,
+			},
+			expected: "$uri",
+		},
+		{
+			input: conf_v1alpha1.Condition{
+				SourceIP: true,
+			},
+			expected: "$remote_addr",
+		},
 	}
 
 	for _, test := range tests {
@@ -2147,7 +3708,7 @@ func TestGenerateEndpointsForUpstream(t *testing.T) {
 			},
 			isPlus:               false,
 			isResolverConfigured: false,
-			expected:             []string{nginx502Server},
+			expected:             []string{"unix:/tmp/nginx/lib/nginx-502-server.sock"},
 			msg:                  "Service with no endpoints",
 		},
 		{
@@ -2210,13 +3771,13 @@ func TestGenerateEndpointsForUpstream(t *testing.T) {
 			},
 			isPlus:               false,
 			isResolverConfigured: false,
-			expected:             []string{nginx502Server},
+			expected:             []string{"unix:/tmp/nginx/lib/nginx-502-server.sock"},
 			msg:                  "Upstream with subselector, without a matching endpoint",
 		},
 	}
 
 	for _, test := range tests {
-		vsc := newVirtualServerConfigurator(&ConfigParams{}, test.isPlus, test.isResolverConfigured)
+		vsc := newVirtualServerConfigurator(&ConfigParams{}, test.isPlus, test.isResolverConfigured, &StaticConfigParams{NginxLibPath: "/tmp/nginx/lib"})
 		result := vsc.generateEndpointsForUpstream(test.vsEx.VirtualServer, namespace, test.upstream, test.vsEx)
 		if !reflect.DeepEqual(result, test.expected) {
 			t.Errorf("generateEndpointsForUpstream(isPlus=%v, isResolverConfigured=%v) returned %v, but expected %v for case: %v",
@@ -2251,7 +3812,7 @@ func TestGenerateSlowStartForPlusWithInCompatibleLBMethods(t *testing.T) {
 	}
 
 	for _, lbMethod := range tests {
-		vsc := newVirtualServerConfigurator(&ConfigParams{}, true, false)
+		vsc := newVirtualServerConfigurator(&ConfigParams{}, true, false, &StaticConfigParams{})
 		result := vsc.generateSlowStartForPlus(&conf_v1alpha1.VirtualServer{}, upstream, lbMethod)
 
 		if !reflect.DeepEqual(result, expected) {
@@ -2286,7 +3847,7 @@ func TestGenerateSlowStartForPlus(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		vsc := newVirtualServerConfigurator(&ConfigParams{}, true, false)
+		vsc := newVirtualServerConfigurator(&ConfigParams{}, true, false, &StaticConfigParams{})
 		result := vsc.generateSlowStartForPlus(&conf_v1alpha1.VirtualServer{}, test.upstream, test.lbMethod)
 		if !reflect.DeepEqual(result, test.expected) {
 			t.Errorf("generateSlowStartForPlus returned %v, but expected %v", result, test.expected)
@@ -2372,7 +3933,7 @@ func TestGenerateUpstreamWithQueue(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		vsc := newVirtualServerConfigurator(&ConfigParams{}, test.isPlus, false)
+		vsc := newVirtualServerConfigurator(&ConfigParams{}, test.isPlus, false, &StaticConfigParams{})
 		result := vsc.generateUpstream(&conf_v1alpha1.VirtualServer{}, test.name, test.upstream, false, []string{})
 		if !reflect.DeepEqual(result, test.expected) {
 			t.Errorf("generateUpstream() returned %v but expected %v for the case of %v", result, test.expected, test.msg)
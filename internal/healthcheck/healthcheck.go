@@ -0,0 +1,71 @@
+package healthcheck
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/nginxinc/kubernetes-ingress/internal/logging"
+)
+
+// readinessEndpoint is the path where the readiness of the Ingress controller is exposed
+const readinessEndpoint = "/readyz"
+
+// livenessEndpoint is the path where the liveness of the Ingress controller is exposed
+const livenessEndpoint = "/healthz"
+
+// ReadinessChecker reports the readiness of the Ingress controller.
+type ReadinessChecker interface {
+	IsReady() bool
+}
+
+// LivenessChecker checks the liveness of a component of the Ingress controller.
+type LivenessChecker interface {
+	CheckLiveness() error
+}
+
+// RunHealthCheckListener runs an http server to expose the readiness and the liveness endpoints of the Ingress controller.
+// The readiness endpoint returns 200 if the controller is ready and 503 otherwise. The liveness endpoint returns 200
+// if all livenessCheckers succeed and 500 otherwise.
+func RunHealthCheckListener(port int, readinessChecker ReadinessChecker, livenessCheckers ...LivenessChecker) {
+	address := fmt.Sprintf(":%v", port)
+	logging.Infof("Starting health check listener on: %v%v and %v%v", address, readinessEndpoint, address, livenessEndpoint)
+	logging.Fatal("Error in health check listener server: ", http.ListenAndServe(address, newHealthCheckHandler(readinessChecker, livenessCheckers)))
+}
+
+func newHealthCheckHandler(readinessChecker ReadinessChecker, livenessCheckers []LivenessChecker) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc(readinessEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		if !readinessChecker.IsReady() {
+			writeResponse(w, http.StatusServiceUnavailable, "not ready")
+			return
+		}
+		writeResponse(w, http.StatusOK, "ready")
+	})
+
+	mux.HandleFunc(livenessEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		var errs []string
+		for _, checker := range livenessCheckers {
+			if err := checker.CheckLiveness(); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+		if len(errs) > 0 {
+			logging.Warningf("Liveness check failed: %v", strings.Join(errs, "; "))
+			writeResponse(w, http.StatusInternalServerError, strings.Join(errs, "\n"))
+			return
+		}
+		writeResponse(w, http.StatusOK, "ok")
+	})
+
+	return mux
+}
+
+func writeResponse(w http.ResponseWriter, code int, message string) {
+	w.WriteHeader(code)
+	_, err := w.Write([]byte(message))
+	if err != nil {
+		logging.Warningf("Error while sending a response for the health check: %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package healthcheck
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type fakeReadinessChecker struct {
+	isReady bool
+}
+
+func (c *fakeReadinessChecker) IsReady() bool {
+	return c.isReady
+}
+
+type fakeLivenessChecker struct {
+	err error
+}
+
+func (c *fakeLivenessChecker) CheckLiveness() error {
+	return c.err
+}
+
+func TestHealthCheckHandler(t *testing.T) {
+	tests := []struct {
+		readinessChecker ReadinessChecker
+		livenessCheckers []LivenessChecker
+		path             string
+		expectedCode     int
+	}{
+		{
+			readinessChecker: &fakeReadinessChecker{isReady: true},
+			path:             "/readyz",
+			expectedCode:     http.StatusOK,
+		},
+		{
+			readinessChecker: &fakeReadinessChecker{isReady: false},
+			path:             "/readyz",
+			expectedCode:     http.StatusServiceUnavailable,
+		},
+		{
+			readinessChecker: &fakeReadinessChecker{},
+			livenessCheckers: []LivenessChecker{&fakeLivenessChecker{}, &fakeLivenessChecker{}},
+			path:             "/healthz",
+			expectedCode:     http.StatusOK,
+		},
+		{
+			readinessChecker: &fakeReadinessChecker{},
+			livenessCheckers: []LivenessChecker{&fakeLivenessChecker{}, &fakeLivenessChecker{err: errors.New("NGINX master process is not running")}},
+			path:             "/healthz",
+			expectedCode:     http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		handler := newHealthCheckHandler(test.readinessChecker, test.livenessCheckers)
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", test.path, nil))
+
+		if recorder.Code != test.expectedCode {
+			t.Errorf("GET %v returned %v but expected %v", test.path, recorder.Code, test.expectedCode)
+		}
+	}
+}
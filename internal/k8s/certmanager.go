@@ -0,0 +1,456 @@
+package k8s
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/nginxinc/kubernetes-ingress/internal/logging"
+	api_v1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	conf_v1alpha1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
+)
+
+const (
+	certManagerIssuerAnnotation        = "cert-manager.io/issuer"
+	certManagerClusterIssuerAnnotation = "cert-manager.io/cluster-issuer"
+	certManagerDurationAnnotation      = "cert-manager.io/duration"
+	certManagerRenewBeforeAnnotation   = "cert-manager.io/renew-before"
+)
+
+const (
+	issuerKind        = "Issuer"
+	clusterIssuerKind = "ClusterIssuer"
+)
+
+var certificateGVR = schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+
+var certificateGVK = certificateGVR.GroupVersion().WithKind("Certificate")
+
+// certificateParams are the parameters of a cert-manager Certificate.
+type certificateParams struct {
+	secretName  string
+	hosts       []string
+	issuerName  string
+	issuerKind  string
+	duration    string
+	renewBefore string
+}
+
+// addCertificateHandler adds the handler for cert-manager Certificates to the controller
+func (lbc *LoadBalancerController) addCertificateHandler(handlers cache.ResourceEventHandlerFuncs) {
+	certificates := lbc.dynClient.Resource(certificateGVR)
+
+	lbc.certificateLister, lbc.certificateController = cache.NewInformer(
+		&cache.ListWatch{
+			ListFunc: func(options meta_v1.ListOptions) (runtime.Object, error) {
+				return certificates.Namespace(lbc.namespace).List(options)
+			},
+			WatchFunc: func(options meta_v1.ListOptions) (watch.Interface, error) {
+				return certificates.Namespace(lbc.namespace).Watch(options)
+			},
+		},
+		&unstructured.Unstructured{},
+		lbc.resync,
+		handlers,
+	)
+}
+
+// createCertificateHandlers builds the handler funcs for cert-manager Certificates
+func createCertificateHandlers(lbc *LoadBalancerController) cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) {
+			cert, isCert := obj.(*unstructured.Unstructured)
+			if !isCert {
+				deletedState, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					logging.V(3).Infof("Error received unexpected object: %v", obj)
+					return
+				}
+				cert, ok = deletedState.Obj.(*unstructured.Unstructured)
+				if !ok {
+					logging.V(3).Infof("Error DeletedFinalStateUnknown contained non-Certificate object: %v", deletedState.Obj)
+					return
+				}
+			}
+
+			// the owner recreates the Certificate if it still needs it
+			logging.V(3).Infof("Removing Certificate: %v", cert.GetName())
+			lbc.enqueueOwnerOfCertificate(cert)
+		},
+		UpdateFunc: func(old, cur interface{}) {
+			oldCert := old.(*unstructured.Unstructured)
+			curCert := cur.(*unstructured.Unstructured)
+
+			if !reflect.DeepEqual(getCertificateReadyCondition(oldCert), getCertificateReadyCondition(curCert)) {
+				logging.V(3).Infof("Certificate %v changed its readiness, syncing", curCert.GetName())
+				lbc.AddSyncQueue(curCert)
+			}
+		},
+	}
+}
+
+// enqueueOwnerOfCertificate enqueues the Ingress or the VirtualServer that owns the Certificate.
+func (lbc *LoadBalancerController) enqueueOwnerOfCertificate(cert *unstructured.Unstructured) {
+	owner := lbc.findOwnerOfCertificate(cert)
+	if owner != nil {
+		lbc.AddSyncQueue(owner)
+	}
+}
+
+// findOwnerOfCertificate returns the Ingress or the VirtualServer that owns the Certificate.
+func (lbc *LoadBalancerController) findOwnerOfCertificate(cert *unstructured.Unstructured) runtime.Object {
+	ref := meta_v1.GetControllerOf(cert)
+	if ref == nil {
+		return nil
+	}
+
+	key := cert.GetNamespace() + "/" + ref.Name
+
+	var obj interface{}
+	var exists bool
+	var err error
+
+	switch ref.Kind {
+	case ingressKind:
+		obj, exists, err = lbc.ingressLister.Store.GetByKey(key)
+	case virtualServerKind:
+		if !lbc.areCustomResourcesEnabled {
+			return nil
+		}
+		obj, exists, err = lbc.virtualServerLister.GetByKey(key)
+	default:
+		return nil
+	}
+
+	if err != nil || !exists {
+		return nil
+	}
+
+	var owner runtime.Object
+	var uid types.UID
+
+	switch o := obj.(type) {
+	case *v1beta1.Ingress:
+		owner, uid = o, o.UID
+	case *conf_v1alpha1.VirtualServer:
+		owner, uid = o, o.UID
+	}
+
+	if uid != ref.UID {
+		return nil
+	}
+
+	return owner
+}
+
+func (lbc *LoadBalancerController) syncCertificate(task task) {
+	key := task.Key
+	obj, certExists, err := lbc.certificateLister.GetByKey(key)
+	if err != nil {
+		lbc.syncQueue.Requeue(task, err)
+		return
+	}
+
+	if !certExists {
+		return
+	}
+
+	cert := obj.(*unstructured.Unstructured)
+
+	owner := lbc.findOwnerOfCertificate(cert)
+	if owner == nil {
+		return
+	}
+
+	condition := getCertificateReadyCondition(cert)
+	if condition == nil {
+		return
+	}
+
+	if condition["status"] != string(api_v1.ConditionTrue) {
+		lbc.recorder.Eventf(owner, api_v1.EventTypeWarning, "CertificateNotReady", "Certificate %v is not ready: %v", key, condition["message"])
+		return
+	}
+
+	lbc.recorder.Eventf(owner, api_v1.EventTypeNormal, "CertificateReady", "Certificate %v is ready: %v", key, condition["message"])
+
+	// the secret with the issued certificate is processed like any other secret
+	secretName, _, _ := unstructured.NestedString(cert.Object, "spec", "secretName")
+	secretKey := cert.GetNamespace() + "/" + secretName
+
+	secret, secretExists, err := lbc.secretLister.Store.GetByKey(secretKey)
+	if err != nil || !secretExists {
+		logging.V(3).Infof("Secret %v of Certificate %v is not found", secretKey, key)
+		return
+	}
+	lbc.AddSyncQueue(secret)
+}
+
+// getCertificateReadyCondition returns the Ready condition of a Certificate or nil if the condition is not found.
+func getCertificateReadyCondition(cert *unstructured.Unstructured) map[string]interface{} {
+	conditions, _, _ := unstructured.NestedSlice(cert.Object, "status", "conditions")
+
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if condition["type"] == "Ready" {
+			return condition
+		}
+	}
+
+	return nil
+}
+
+// syncCertificatesForIngress creates, updates or deletes the Certificates owned by the Ingress.
+func (lbc *LoadBalancerController) syncCertificatesForIngress(ing *v1beta1.Ingress) {
+	params, err := getCertificateParamsForIngress(ing)
+	if err != nil {
+		lbc.recorder.Eventf(ing, api_v1.EventTypeWarning, "CertificateRejected", "The cert-manager annotations are invalid: %v", err)
+		return
+	}
+
+	owner := newCertificateOwner(ing, &ing.ObjectMeta, "extensions/v1beta1", ingressKind)
+	lbc.syncCertificates(owner, params)
+}
+
+// syncCertificatesForVirtualServer creates, updates or deletes the Certificate owned by the VirtualServer.
+func (lbc *LoadBalancerController) syncCertificatesForVirtualServer(vs *conf_v1alpha1.VirtualServer) {
+	var params []certificateParams
+	if p := getCertificateParamsForVirtualServer(vs); p != nil {
+		params = append(params, *p)
+	}
+
+	owner := newCertificateOwner(vs, &vs.ObjectMeta, conf_v1alpha1.SchemeGroupVersion.String(), virtualServerKind)
+	lbc.syncCertificates(owner, params)
+}
+
+// certificateOwner is the resource that owns the Certificates: an Ingress or a VirtualServer.
+type certificateOwner struct {
+	obj        runtime.Object
+	meta       *meta_v1.ObjectMeta
+	apiVersion string
+	kind       string
+}
+
+func newCertificateOwner(obj runtime.Object, objectMeta *meta_v1.ObjectMeta, apiVersion string, kind string) certificateOwner {
+	return certificateOwner{
+		obj:        obj,
+		meta:       objectMeta,
+		apiVersion: apiVersion,
+		kind:       kind,
+	}
+}
+
+func (o certificateOwner) ownerReference() meta_v1.OwnerReference {
+	isController := true
+	return meta_v1.OwnerReference{
+		APIVersion: o.apiVersion,
+		Kind:       o.kind,
+		Name:       o.meta.Name,
+		UID:        o.meta.UID,
+		Controller: &isController,
+	}
+}
+
+// owns checks if the owner is the controller of the Certificate.
+func (o certificateOwner) owns(cert *unstructured.Unstructured) bool {
+	ref := meta_v1.GetControllerOf(cert)
+	return ref != nil && ref.UID == o.meta.UID
+}
+
+func (lbc *LoadBalancerController) syncCertificates(owner certificateOwner, params []certificateParams) {
+	certificates := lbc.dynClient.Resource(certificateGVR).Namespace(owner.meta.Namespace)
+	desired := make(map[string]bool)
+
+	for _, p := range params {
+		desired[p.secretName] = true
+		key := owner.meta.Namespace + "/" + p.secretName
+
+		cert := newCertificate(owner, p)
+
+		obj, exists, err := lbc.certificateLister.GetByKey(key)
+		if err != nil {
+			logging.Errorf("Error getting Certificate %v: %v", key, err)
+			continue
+		}
+
+		if !exists {
+			_, err = certificates.Create(cert, meta_v1.CreateOptions{})
+			if err != nil {
+				lbc.recorder.Eventf(owner.obj, api_v1.EventTypeWarning, "CertificateCreatedWithError", "Failed to create Certificate %v: %v", key, err)
+				continue
+			}
+			lbc.recorder.Eventf(owner.obj, api_v1.EventTypeNormal, "CertificateCreated", "Created Certificate %v", key)
+			continue
+		}
+
+		existing := obj.(*unstructured.Unstructured)
+		if !owner.owns(existing) {
+			lbc.recorder.Eventf(owner.obj, api_v1.EventTypeWarning, "CertificateRejected", "Certificate %v already exists and is not owned by %v %v/%v", key, owner.kind, owner.meta.Namespace, owner.meta.Name)
+			continue
+		}
+
+		if reflect.DeepEqual(existing.Object["spec"], cert.Object["spec"]) {
+			continue
+		}
+
+		updated := existing.DeepCopy()
+		updated.Object["spec"] = cert.Object["spec"]
+
+		_, err = certificates.Update(updated, meta_v1.UpdateOptions{})
+		if err != nil {
+			lbc.recorder.Eventf(owner.obj, api_v1.EventTypeWarning, "CertificateUpdatedWithError", "Failed to update Certificate %v: %v", key, err)
+			continue
+		}
+		lbc.recorder.Eventf(owner.obj, api_v1.EventTypeNormal, "CertificateUpdated", "Updated Certificate %v", key)
+	}
+
+	for _, obj := range lbc.certificateLister.List() {
+		cert := obj.(*unstructured.Unstructured)
+		if cert.GetNamespace() != owner.meta.Namespace || !owner.owns(cert) || desired[cert.GetName()] {
+			continue
+		}
+
+		key := cert.GetNamespace() + "/" + cert.GetName()
+
+		err := certificates.Delete(cert.GetName(), &meta_v1.DeleteOptions{})
+		if err != nil {
+			lbc.recorder.Eventf(owner.obj, api_v1.EventTypeWarning, "CertificateDeletedWithError", "Failed to delete Certificate %v: %v", key, err)
+			continue
+		}
+		lbc.recorder.Eventf(owner.obj, api_v1.EventTypeNormal, "CertificateDeleted", "Deleted Certificate %v", key)
+	}
+}
+
+// newCertificate creates a cert-manager Certificate owned by the owner. The Certificate is named after its secret.
+func newCertificate(owner certificateOwner, params certificateParams) *unstructured.Unstructured {
+	var dnsNames []interface{}
+	for _, host := range params.hosts {
+		dnsNames = append(dnsNames, host)
+	}
+
+	spec := map[string]interface{}{
+		"secretName": params.secretName,
+		"dnsNames":   dnsNames,
+		"issuerRef": map[string]interface{}{
+			"name":  params.issuerName,
+			"kind":  params.issuerKind,
+			"group": certificateGVR.Group,
+		},
+	}
+	if params.duration != "" {
+		spec["duration"] = params.duration
+	}
+	if params.renewBefore != "" {
+		spec["renewBefore"] = params.renewBefore
+	}
+
+	cert := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": spec,
+		},
+	}
+	cert.SetGroupVersionKind(certificateGVK)
+	cert.SetName(params.secretName)
+	cert.SetNamespace(owner.meta.Namespace)
+	cert.SetOwnerReferences([]meta_v1.OwnerReference{owner.ownerReference()})
+
+	return cert
+}
+
+// getCertificateParamsForIngress returns the parameters of the Certificates for the TLS secrets of an Ingress
+// with the cert-manager annotations.
+func getCertificateParamsForIngress(ing *v1beta1.Ingress) ([]certificateParams, error) {
+	issuer := ing.Annotations[certManagerIssuerAnnotation]
+	clusterIssuer := ing.Annotations[certManagerClusterIssuerAnnotation]
+
+	if issuer == "" && clusterIssuer == "" {
+		return nil, nil
+	}
+	if issuer != "" && clusterIssuer != "" {
+		return nil, fmt.Errorf("%v and %v cannot be used together", certManagerIssuerAnnotation, certManagerClusterIssuerAnnotation)
+	}
+
+	issuerName, kind := issuer, issuerKind
+	if clusterIssuer != "" {
+		issuerName, kind = clusterIssuer, clusterIssuerKind
+	}
+
+	duration := ing.Annotations[certManagerDurationAnnotation]
+	if err := validateCertificateDuration(duration); err != nil {
+		return nil, fmt.Errorf("invalid %v: %v", certManagerDurationAnnotation, err)
+	}
+
+	renewBefore := ing.Annotations[certManagerRenewBeforeAnnotation]
+	if err := validateCertificateDuration(renewBefore); err != nil {
+		return nil, fmt.Errorf("invalid %v: %v", certManagerRenewBeforeAnnotation, err)
+	}
+
+	var params []certificateParams
+	for _, tls := range ing.Spec.TLS {
+		if tls.SecretName == "" || len(tls.Hosts) == 0 {
+			continue
+		}
+		params = append(params, certificateParams{
+			secretName:  tls.SecretName,
+			hosts:       tls.Hosts,
+			issuerName:  issuerName,
+			issuerKind:  kind,
+			duration:    duration,
+			renewBefore: renewBefore,
+		})
+	}
+
+	return params, nil
+}
+
+func validateCertificateDuration(duration string) error {
+	if duration == "" {
+		return nil
+	}
+
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return err
+	}
+	if d <= 0 {
+		return fmt.Errorf("must be positive")
+	}
+
+	return nil
+}
+
+// getCertificateParamsForVirtualServer returns the parameters of the Certificate for the TLS secret of a VirtualServer
+// with certManager or nil if certManager is not configured.
+func getCertificateParamsForVirtualServer(vs *conf_v1alpha1.VirtualServer) *certificateParams {
+	if vs.Spec.TLS == nil || vs.Spec.TLS.CertManager == nil || vs.Spec.TLS.Secret == "" {
+		return nil
+	}
+
+	certManager := vs.Spec.TLS.CertManager
+
+	issuerName, kind := certManager.Issuer, issuerKind
+	if certManager.ClusterIssuer != "" {
+		issuerName, kind = certManager.ClusterIssuer, clusterIssuerKind
+	}
+
+	return &certificateParams{
+		secretName:  vs.Spec.TLS.Secret,
+		hosts:       getVirtualServerHosts(vs),
+		issuerName:  issuerName,
+		issuerKind:  kind,
+		duration:    certManager.Duration,
+		renewBefore: certManager.RenewBefore,
+	}
+}
@@ -0,0 +1,292 @@
+package k8s
+
+import (
+	"reflect"
+	"testing"
+
+	extensions "k8s.io/api/extensions/v1beta1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	conf_v1alpha1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
+)
+
+func createTestIngressForCertificates() *extensions.Ingress {
+	return &extensions.Ingress{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe-ingress",
+			Namespace: "default",
+			UID:       "cafe-ingress-uid",
+			Annotations: map[string]string{
+				certManagerClusterIssuerAnnotation: "letsencrypt",
+				certManagerDurationAnnotation:      "2160h",
+			},
+		},
+		Spec: extensions.IngressSpec{
+			TLS: []extensions.IngressTLS{
+				{
+					Hosts:      []string{"cafe.example.com", "www.cafe.example.com"},
+					SecretName: "cafe-secret",
+				},
+				{
+					Hosts:      []string{"tea.example.com"},
+					SecretName: "tea-secret",
+				},
+				{
+					SecretName: "default-secret",
+				},
+			},
+		},
+	}
+}
+
+func createTestLoadBalancerControllerForCertificates(objects ...runtime.Object) (*LoadBalancerController, *fake.FakeDynamicClient) {
+	dynClient := fake.NewSimpleDynamicClient(runtime.NewScheme(), objects...)
+
+	lbc := &LoadBalancerController{
+		dynClient:            dynClient,
+		certificateLister:    cache.NewStore(cache.MetaNamespaceKeyFunc),
+		recorder:             record.NewFakeRecorder(10),
+		isCertManagerEnabled: true,
+	}
+
+	for _, obj := range objects {
+		lbc.certificateLister.Add(obj)
+	}
+
+	return lbc, dynClient
+}
+
+func TestGetCertificateParamsForIngress(t *testing.T) {
+	ing := createTestIngressForCertificates()
+
+	expected := []certificateParams{
+		{
+			secretName: "cafe-secret",
+			hosts:      []string{"cafe.example.com", "www.cafe.example.com"},
+			issuerName: "letsencrypt",
+			issuerKind: clusterIssuerKind,
+			duration:   "2160h",
+		},
+		{
+			secretName: "tea-secret",
+			hosts:      []string{"tea.example.com"},
+			issuerName: "letsencrypt",
+			issuerKind: clusterIssuerKind,
+			duration:   "2160h",
+		},
+	}
+
+	result, err := getCertificateParamsForIngress(ing)
+	if err != nil {
+		t.Fatalf("getCertificateParamsForIngress() returned unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("getCertificateParamsForIngress() returned %v but expected %v", result, expected)
+	}
+
+	delete(ing.Annotations, certManagerClusterIssuerAnnotation)
+	result, err = getCertificateParamsForIngress(ing)
+	if err != nil || result != nil {
+		t.Errorf("getCertificateParamsForIngress() returned %v and error %v but expected no Certificates for an Ingress without an issuer", result, err)
+	}
+}
+
+func TestGetCertificateParamsForIngressFails(t *testing.T) {
+	tests := []struct {
+		annotations map[string]string
+		msg         string
+	}{
+		{
+			annotations: map[string]string{
+				certManagerIssuerAnnotation:        "ca-issuer",
+				certManagerClusterIssuerAnnotation: "letsencrypt",
+			},
+			msg: "issuer and cluster issuer",
+		},
+		{
+			annotations: map[string]string{
+				certManagerIssuerAnnotation:   "ca-issuer",
+				certManagerDurationAnnotation: "90d",
+			},
+			msg: "invalid duration",
+		},
+		{
+			annotations: map[string]string{
+				certManagerIssuerAnnotation:      "ca-issuer",
+				certManagerRenewBeforeAnnotation: "-1h",
+			},
+			msg: "negative renew before",
+		},
+	}
+
+	for _, test := range tests {
+		ing := createTestIngressForCertificates()
+		ing.Annotations = test.annotations
+
+		_, err := getCertificateParamsForIngress(ing)
+		if err == nil {
+			t.Errorf("getCertificateParamsForIngress() returned no error for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestGetCertificateParamsForVirtualServer(t *testing.T) {
+	vs := &conf_v1alpha1.VirtualServer{
+		Spec: conf_v1alpha1.VirtualServerSpec{
+			Host:          "cafe.example.com",
+			ServerAliases: []string{"www.cafe.example.com"},
+			TLS: &conf_v1alpha1.TLS{
+				Secret: "cafe-secret",
+				CertManager: &conf_v1alpha1.CertManager{
+					Issuer:      "ca-issuer",
+					RenewBefore: "360h",
+				},
+			},
+		},
+	}
+
+	expected := &certificateParams{
+		secretName:  "cafe-secret",
+		hosts:       []string{"cafe.example.com", "www.cafe.example.com"},
+		issuerName:  "ca-issuer",
+		issuerKind:  issuerKind,
+		renewBefore: "360h",
+	}
+
+	result := getCertificateParamsForVirtualServer(vs)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("getCertificateParamsForVirtualServer() returned %v but expected %v", result, expected)
+	}
+
+	vs.Spec.TLS.CertManager = nil
+	result = getCertificateParamsForVirtualServer(vs)
+	if result != nil {
+		t.Errorf("getCertificateParamsForVirtualServer() returned %v but expected nil for a VirtualServer without certManager", result)
+	}
+}
+
+func TestSyncCertificatesForIngress(t *testing.T) {
+	ing := createTestIngressForCertificates()
+	owner := newCertificateOwner(ing, &ing.ObjectMeta, "extensions/v1beta1", ingressKind)
+
+	outdated := newCertificate(owner, certificateParams{
+		secretName: "cafe-secret",
+		hosts:      []string{"cafe.example.com"},
+		issuerName: "letsencrypt",
+		issuerKind: clusterIssuerKind,
+	})
+	stale := newCertificate(owner, certificateParams{
+		secretName: "coffee-secret",
+		hosts:      []string{"coffee.example.com"},
+		issuerName: "letsencrypt",
+		issuerKind: clusterIssuerKind,
+	})
+
+	lbc, dynClient := createTestLoadBalancerControllerForCertificates(outdated, stale)
+
+	lbc.syncCertificatesForIngress(ing)
+
+	certificates := dynClient.Resource(certificateGVR).Namespace("default")
+
+	for _, name := range []string{"cafe-secret", "tea-secret"} {
+		cert, err := certificates.Get(name, meta_v1.GetOptions{})
+		if err != nil {
+			t.Errorf("Certificate %v was not created or updated: %v", name, err)
+			continue
+		}
+		if !owner.owns(cert) {
+			t.Errorf("Certificate %v is not owned by the Ingress", name)
+		}
+	}
+
+	cert, err := certificates.Get("cafe-secret", meta_v1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get Certificate cafe-secret: %v", err)
+	}
+	dnsNames, _, _ := unstructured.NestedStringSlice(cert.Object, "spec", "dnsNames")
+	expectedDNSNames := []string{"cafe.example.com", "www.cafe.example.com"}
+	if !reflect.DeepEqual(dnsNames, expectedDNSNames) {
+		t.Errorf("Certificate cafe-secret has dnsNames %v but expected %v", dnsNames, expectedDNSNames)
+	}
+	duration, _, _ := unstructured.NestedString(cert.Object, "spec", "duration")
+	if duration != "2160h" {
+		t.Errorf("Certificate cafe-secret has duration %q but expected %q", duration, "2160h")
+	}
+
+	_, err = certificates.Get("coffee-secret", meta_v1.GetOptions{})
+	if err == nil {
+		t.Errorf("The Certificate coffee-secret that is no longer needed by the Ingress was not deleted")
+	}
+
+	_, err = certificates.Get("default-secret", meta_v1.GetOptions{})
+	if err == nil {
+		t.Errorf("A Certificate was created for a TLS entry without hosts")
+	}
+}
+
+func TestSyncCertificatesIgnoresCertificatesOfOtherOwners(t *testing.T) {
+	ing := createTestIngressForCertificates()
+
+	otherIng := createTestIngressForCertificates()
+	otherIng.Name = "other-ingress"
+	otherIng.UID = "other-ingress-uid"
+	otherOwner := newCertificateOwner(otherIng, &otherIng.ObjectMeta, "extensions/v1beta1", ingressKind)
+
+	existing := newCertificate(otherOwner, certificateParams{
+		secretName: "cafe-secret",
+		hosts:      []string{"cafe.example.com"},
+		issuerName: "ca-issuer",
+		issuerKind: issuerKind,
+	})
+
+	lbc, dynClient := createTestLoadBalancerControllerForCertificates(existing)
+
+	lbc.syncCertificatesForIngress(ing)
+
+	cert, err := dynClient.Resource(certificateGVR).Namespace("default").Get("cafe-secret", meta_v1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get Certificate cafe-secret: %v", err)
+	}
+	if !reflect.DeepEqual(cert.Object["spec"], existing.Object["spec"]) {
+		t.Errorf("The Certificate cafe-secret of another owner was updated: %v", cert.Object["spec"])
+	}
+	if !otherOwner.owns(cert) {
+		t.Errorf("The owner of the Certificate cafe-secret was changed")
+	}
+}
+
+func TestGetCertificateReadyCondition(t *testing.T) {
+	cert := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"status": map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{
+						"type":    "Issuing",
+						"status":  "True",
+						"message": "Issuing certificate",
+					},
+					map[string]interface{}{
+						"type":    "Ready",
+						"status":  "False",
+						"message": "Certificate is not issued yet",
+					},
+				},
+			},
+		},
+	}
+
+	condition := getCertificateReadyCondition(cert)
+	if condition == nil || condition["status"] != "False" {
+		t.Errorf("getCertificateReadyCondition() returned %v but expected the Ready condition", condition)
+	}
+
+	condition = getCertificateReadyCondition(&unstructured.Unstructured{Object: map[string]interface{}{}})
+	if condition != nil {
+		t.Errorf("getCertificateReadyCondition() returned %v but expected nil for a Certificate without status", condition)
+	}
+}
@@ -20,17 +20,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/golang/glog"
-
 	"github.com/nginxinc/kubernetes-ingress/internal/configs"
+	"github.com/nginxinc/kubernetes-ingress/internal/logging"
 	"github.com/nginxinc/kubernetes-ingress/internal/metrics/collectors"
 	"k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	core_v1 "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -52,106 +57,139 @@ const (
 	ingressClassKey = "kubernetes.io/ingress.class"
 )
 
+// syncStallTimeout is the time after which the sync queue is considered stalled if the worker is still syncing
+// the same task.
+const syncStallTimeout = 5 * time.Minute
+
+// certificateExpiryCheckPeriod is the period of checking the expiry of the certificates of the TLS Secrets.
+const certificateExpiryCheckPeriod = time.Hour
+
 // LoadBalancerController watches Kubernetes API and
 // reconfigures NGINX via NginxController when needed
 type LoadBalancerController struct {
-	client                       kubernetes.Interface
-	confClient                   k8s_nginx.Interface
-	ingressController            cache.Controller
-	svcController                cache.Controller
-	endpointController           cache.Controller
-	configMapController          cache.Controller
-	secretController             cache.Controller
-	virtualServerController      cache.Controller
-	virtualServerRouteController cache.Controller
-	podController                cache.Controller
-	ingressLister                storeToIngressLister
-	svcLister                    cache.Store
-	endpointLister               storeToEndpointLister
-	configMapLister              storeToConfigMapLister
-	podLister                    indexerToPodLister
-	secretLister                 storeToSecretLister
-	virtualServerLister          cache.Store
-	virtualServerRouteLister     cache.Store
-	syncQueue                    *taskQueue
-	ctx                          context.Context
-	cancel                       context.CancelFunc
-	configurator                 *configs.Configurator
-	watchNginxConfigMaps         bool
-	isNginxPlus                  bool
-	recorder                     record.EventRecorder
-	defaultServerSecret          string
-	ingressClass                 string
-	useIngressClassOnly          bool
-	statusUpdater                *statusUpdater
-	leaderElector                *leaderelection.LeaderElector
-	reportIngressStatus          bool
-	isLeaderElectionEnabled      bool
-	leaderElectionLockName       string
-	resync                       time.Duration
-	namespace                    string
-	controllerNamespace          string
-	wildcardTLSSecret            string
-	areCustomResourcesEnabled    bool
-	metricsCollector             collectors.ControllerCollector
+	client                         kubernetes.Interface
+	confClient                     k8s_nginx.Interface
+	dynClient                      dynamic.Interface
+	ingressController              cache.Controller
+	svcController                  cache.Controller
+	endpointController             cache.Controller
+	configMapController            cache.Controller
+	secretController               cache.Controller
+	virtualServerController        cache.Controller
+	virtualServerRouteController   cache.Controller
+	podController                  cache.Controller
+	ingressLister                  storeToIngressLister
+	svcLister                      cache.Store
+	endpointLister                 storeToEndpointLister
+	configMapLister                storeToConfigMapLister
+	podLister                      indexerToPodLister
+	secretLister                   storeToSecretLister
+	virtualServerLister            cache.Store
+	certificateController          cache.Controller
+	certificateLister              cache.Store
+	virtualServerRouteLister       cache.Store
+	syncQueue                      *taskQueue
+	ctx                            context.Context
+	cancel                         context.CancelFunc
+	configurator                   *configs.Configurator
+	watchNginxConfigMaps           bool
+	isNginxPlus                    bool
+	recorder                       record.EventRecorder
+	defaultServerSecret            string
+	ingressClass                   string
+	useIngressClassOnly            bool
+	statusUpdater                  *statusUpdater
+	leaderElector                  *leaderelection.LeaderElector
+	reportIngressStatus            bool
+	isLeaderElectionEnabled        bool
+	leaderElectionLockName         string
+	leaderElectionLockType         string
+	leaderElectorDone              chan struct{}
+	resync                         time.Duration
+	namespace                      string
+	controllerNamespace            string
+	wildcardTLSSecret              string
+	areCustomResourcesEnabled      bool
+	metricsCollector               collectors.ControllerCollector
+	certificateExpiryWarningPeriod time.Duration
+	isCertManagerEnabled           bool
+	logger                         *logging.Logger
+	isShuttingDown                 bool
+	isInitialSyncDone              bool
+	isConfigApplied                bool
+	readinessMutex                 sync.RWMutex
+	// hostOwners caches the winners of the hosts during a sync. See getHostOwners.
+	hostOwners          map[string]hostResource
+	isHostOwnersCacheOn bool
+	hostOwnersMutex     sync.Mutex
 }
 
 var keyFunc = cache.DeletionHandlingMetaNamespaceKeyFunc
 
 // NewLoadBalancerControllerInput holds the input needed to call NewLoadBalancerController.
 type NewLoadBalancerControllerInput struct {
-	KubeClient                kubernetes.Interface
-	ConfClient                k8s_nginx.Interface
-	ResyncPeriod              time.Duration
-	Namespace                 string
-	NginxConfigurator         *configs.Configurator
-	DefaultServerSecret       string
-	IsNginxPlus               bool
-	IngressClass              string
-	UseIngressClassOnly       bool
-	ExternalServiceName       string
-	ControllerNamespace       string
-	ReportIngressStatus       bool
-	IsLeaderElectionEnabled   bool
-	LeaderElectionLockName    string
-	WildcardTLSSecret         string
-	ConfigMaps                string
-	AreCustomResourcesEnabled bool
-	MetricsCollector          collectors.ControllerCollector
+	KubeClient                     kubernetes.Interface
+	ConfClient                     k8s_nginx.Interface
+	DynClient                      dynamic.Interface
+	ResyncPeriod                   time.Duration
+	Namespace                      string
+	NginxConfigurator              *configs.Configurator
+	DefaultServerSecret            string
+	IsNginxPlus                    bool
+	IngressClass                   string
+	UseIngressClassOnly            bool
+	ExternalServiceName            string
+	ControllerNamespace            string
+	ReportIngressStatus            bool
+	IsLeaderElectionEnabled        bool
+	LeaderElectionLockName         string
+	LeaderElectionLockType         string
+	WildcardTLSSecret              string
+	ConfigMaps                     string
+	AreCustomResourcesEnabled      bool
+	MetricsCollector               collectors.ControllerCollector
+	CertificateExpiryWarningPeriod time.Duration
+	IsCertManagerEnabled           bool
+	Logger                         *logging.Logger
 }
 
 // NewLoadBalancerController creates a controller
 func NewLoadBalancerController(input NewLoadBalancerControllerInput) *LoadBalancerController {
 	lbc := &LoadBalancerController{
-		client:                    input.KubeClient,
-		confClient:                input.ConfClient,
-		configurator:              input.NginxConfigurator,
-		defaultServerSecret:       input.DefaultServerSecret,
-		isNginxPlus:               input.IsNginxPlus,
-		ingressClass:              input.IngressClass,
-		useIngressClassOnly:       input.UseIngressClassOnly,
-		reportIngressStatus:       input.ReportIngressStatus,
-		isLeaderElectionEnabled:   input.IsLeaderElectionEnabled,
-		leaderElectionLockName:    input.LeaderElectionLockName,
-		resync:                    input.ResyncPeriod,
-		namespace:                 input.Namespace,
-		controllerNamespace:       input.ControllerNamespace,
-		wildcardTLSSecret:         input.WildcardTLSSecret,
-		areCustomResourcesEnabled: input.AreCustomResourcesEnabled,
-		metricsCollector:          input.MetricsCollector,
+		client:                         input.KubeClient,
+		confClient:                     input.ConfClient,
+		dynClient:                      input.DynClient,
+		configurator:                   input.NginxConfigurator,
+		defaultServerSecret:            input.DefaultServerSecret,
+		isNginxPlus:                    input.IsNginxPlus,
+		ingressClass:                   input.IngressClass,
+		useIngressClassOnly:            input.UseIngressClassOnly,
+		reportIngressStatus:            input.ReportIngressStatus,
+		isLeaderElectionEnabled:        input.IsLeaderElectionEnabled,
+		leaderElectionLockName:         input.LeaderElectionLockName,
+		leaderElectionLockType:         input.LeaderElectionLockType,
+		resync:                         input.ResyncPeriod,
+		namespace:                      input.Namespace,
+		controllerNamespace:            input.ControllerNamespace,
+		wildcardTLSSecret:              input.WildcardTLSSecret,
+		areCustomResourcesEnabled:      input.AreCustomResourcesEnabled,
+		metricsCollector:               input.MetricsCollector,
+		certificateExpiryWarningPeriod: input.CertificateExpiryWarningPeriod,
+		isCertManagerEnabled:           input.IsCertManagerEnabled,
+		logger:                         input.Logger,
 	}
 
 	eventBroadcaster := record.NewBroadcaster()
-	eventBroadcaster.StartLogging(glog.Infof)
+	eventBroadcaster.StartLogging(logging.Infof)
 	eventBroadcaster.StartRecordingToSink(&core_v1.EventSinkImpl{
 		Interface: core_v1.New(input.KubeClient.CoreV1().RESTClient()).Events(""),
 	})
 	lbc.recorder = eventBroadcaster.NewRecorder(scheme.Scheme,
 		api_v1.EventSource{Component: "nginx-ingress-controller"})
 
-	lbc.syncQueue = newTaskQueue(lbc.sync)
+	lbc.syncQueue = newTaskQueue(lbc.sync, lbc.park)
 
-	glog.V(3).Infof("Nginx Ingress Controller has class: %v", input.IngressClass)
+	logging.V(3).Infof("Nginx Ingress Controller has class: %v", input.IngressClass)
 
 	lbc.statusUpdater = &statusUpdater{
 		client:              input.KubeClient,
@@ -173,10 +211,14 @@ func NewLoadBalancerController(input NewLoadBalancerControllerInput) *LoadBalanc
 		lbc.addVirtualServerRouteHandler(createVirtualServerRouteHandlers(lbc))
 	}
 
+	if lbc.isCertManagerEnabled {
+		lbc.addCertificateHandler(createCertificateHandlers(lbc))
+	}
+
 	if input.ConfigMaps != "" {
 		nginxConfigMapsNS, nginxConfigMapsName, err := ParseNamespaceName(input.ConfigMaps)
 		if err != nil {
-			glog.Warning(err)
+			logging.Warning(err)
 		} else {
 			lbc.watchNginxConfigMaps = true
 			lbc.addConfigMapHandler(createConfigMapHandlers(lbc, nginxConfigMapsName), nginxConfigMapsNS)
@@ -198,9 +240,36 @@ func (lbc *LoadBalancerController) UpdateManagedAndMergeableIngresses(ingresses
 // addLeaderHandler adds the handler for leader election to the controller
 func (lbc *LoadBalancerController) addLeaderHandler(leaderHandler leaderelection.LeaderCallbacks) {
 	var err error
-	lbc.leaderElector, err = newLeaderElector(lbc.client, leaderHandler, lbc.controllerNamespace, lbc.leaderElectionLockName)
+	lbc.leaderElector, err = newLeaderElector(lbc.client, leaderHandler, lbc.controllerNamespace, lbc.leaderElectionLockName, lbc.leaderElectionLockType)
 	if err != nil {
-		glog.V(3).Infof("Error starting LeaderElection: %v", err)
+		logging.V(3).Infof("Error starting LeaderElection: %v", err)
+	}
+}
+
+// runLeaderElector runs the leader election until the controller is stopped. If the controller loses the leadership,
+// it joins the election again.
+func (lbc *LoadBalancerController) runLeaderElector() {
+	defer close(lbc.leaderElectorDone)
+
+	for {
+		lbc.leaderElector.Run(lbc.ctx)
+
+		select {
+		case <-lbc.ctx.Done():
+			return
+		default:
+			logging.V(3).Info("lost the leadership, joining the leader election again")
+		}
+	}
+}
+
+// updateAllStatuses updates the status of all managed resources until ctx is done.
+// VirtualServer and VirtualServerRoute resources don't have the status yet, so only Ingress resources are updated.
+func (lbc *LoadBalancerController) updateAllStatuses(ctx context.Context) {
+	ingresses, mergeableIngresses := lbc.GetManagedIngresses()
+	err := lbc.statusUpdater.ReconcileManagedAndMergeableIngresses(ctx, ingresses, mergeableIngresses)
+	if err != nil {
+		logging.V(3).Infof("error updating the status of Ingress resources: %v", err)
 	}
 }
 
@@ -209,6 +278,11 @@ func (lbc *LoadBalancerController) AddSyncQueue(item interface{}) {
 	lbc.syncQueue.Enqueue(item)
 }
 
+// AddSyncQueueForDeleted enqueues the provided deleted item on the sync queue
+func (lbc *LoadBalancerController) AddSyncQueueForDeleted(item interface{}) {
+	lbc.syncQueue.EnqueueDeleted(item)
+}
+
 // addSecretHandler adds the handler for secrets to the controller
 func (lbc *LoadBalancerController) addSecretHandler(handlers cache.ResourceEventHandlerFuncs) {
 	lbc.secretLister.Store, lbc.secretController = cache.NewInformer(
@@ -324,7 +398,8 @@ func (lbc *LoadBalancerController) Run() {
 	lbc.ctx, lbc.cancel = context.WithCancel(context.Background())
 
 	if lbc.leaderElector != nil {
-		go lbc.leaderElector.Run(lbc.ctx)
+		lbc.leaderElectorDone = make(chan struct{})
+		go lbc.runLeaderElector()
 	}
 	go lbc.svcController.Run(lbc.ctx.Done())
 	go lbc.podController.Run(lbc.ctx.Done())
@@ -338,20 +413,107 @@ func (lbc *LoadBalancerController) Run() {
 		go lbc.virtualServerController.Run(lbc.ctx.Done())
 		go lbc.virtualServerRouteController.Run(lbc.ctx.Done())
 	}
+	if lbc.isCertManagerEnabled {
+		go lbc.certificateController.Run(lbc.ctx.Done())
+	}
 	go lbc.syncQueue.Run(time.Second, lbc.ctx.Done())
+	go lbc.waitForCacheSync()
 	<-lbc.ctx.Done()
 }
 
+// waitForCacheSync waits for the caches to be synced and enqueues the initialSync marker.
+func (lbc *LoadBalancerController) waitForCacheSync() {
+	cacheSyncs := []cache.InformerSynced{
+		lbc.svcController.HasSynced,
+		lbc.podController.HasSynced,
+		lbc.endpointController.HasSynced,
+		lbc.secretController.HasSynced,
+		lbc.ingressController.HasSynced,
+	}
+	if lbc.watchNginxConfigMaps {
+		cacheSyncs = append(cacheSyncs, lbc.configMapController.HasSynced)
+	}
+	if lbc.areCustomResourcesEnabled {
+		cacheSyncs = append(cacheSyncs, lbc.virtualServerController.HasSynced, lbc.virtualServerRouteController.HasSynced)
+	}
+	if lbc.isCertManagerEnabled {
+		cacheSyncs = append(cacheSyncs, lbc.certificateController.HasSynced)
+	}
+
+	if !cache.WaitForCacheSync(lbc.ctx.Done(), cacheSyncs...) {
+		return
+	}
+
+	logging.V(3).Info("Caches are synced")
+	lbc.syncQueue.enqueueTask(task{Kind: initialSync, Key: "initial-sync"})
+
+	if lbc.certificateExpiryWarningPeriod > 0 {
+		go wait.Until(func() {
+			lbc.syncQueue.enqueueTask(task{Kind: certificateExpiryCheck, Key: "certificate-expiry-check"})
+		}, certificateExpiryCheckPeriod, lbc.ctx.Done())
+	}
+}
+
+// IsReady returns true if the controller is ready. The controller is ready once the caches are synced, all resources
+// that existed at that time are processed and NGINX is successfully reloaded. The controller is not ready once it
+// starts shutting down.
+func (lbc *LoadBalancerController) IsReady() bool {
+	lbc.readinessMutex.RLock()
+	defer lbc.readinessMutex.RUnlock()
+
+	return lbc.isConfigApplied && !lbc.isShuttingDown
+}
+
+// updateReadiness marks the configuration as applied after the initial sync is done, if the last NGINX reload
+// succeeded. It must be called from the worker of the sync queue.
+func (lbc *LoadBalancerController) updateReadiness() {
+	if !lbc.isInitialSyncDone || lbc.isConfigApplied || !lbc.configurator.IsLastReloadSuccessful() {
+		return
+	}
+
+	logging.V(3).Info("The initial configuration is applied")
+
+	lbc.readinessMutex.Lock()
+	defer lbc.readinessMutex.Unlock()
+
+	lbc.isConfigApplied = true
+}
+
+// CheckLiveness returns an error if the sync queue is stalled.
+func (lbc *LoadBalancerController) CheckLiveness() error {
+	if duration := lbc.syncQueue.GetSyncDuration(); duration > syncStallTimeout {
+		return fmt.Errorf("The sync queue is stalled: syncing a task takes %v", duration)
+	}
+	return nil
+}
+
+// MarkShuttingDown marks the controller as shutting down, so that it is no longer reported as ready.
+func (lbc *LoadBalancerController) MarkShuttingDown() {
+	lbc.readinessMutex.Lock()
+	defer lbc.readinessMutex.Unlock()
+
+	lbc.isShuttingDown = true
+}
+
 // Stop shutdowns the load balancer controller
 func (lbc *LoadBalancerController) Stop() {
 	lbc.cancel()
 
 	lbc.syncQueue.Shutdown()
+
+	// wait for the leader elector to release the lock, so that another replica can become the leader immediately
+	if lbc.leaderElectorDone != nil {
+		select {
+		case <-lbc.leaderElectorDone:
+		case <-time.After(leaderLockReleaseTimeout):
+			logging.Warningf("Timed out waiting for the leader election lock to be released")
+		}
+	}
 }
 
 func (lbc *LoadBalancerController) syncEndpoint(task task) {
 	key := task.Key
-	glog.V(3).Infof("Syncing endpoints %v", key)
+	logging.V(3).Infof("Syncing endpoints %v", key)
 
 	obj, endpExists, err := lbc.endpointLister.GetByKey(key)
 	if err != nil {
@@ -372,7 +534,7 @@ func (lbc *LoadBalancerController) syncEndpoint(task task) {
 			if isMinion(&ings[i]) {
 				master, err := lbc.FindMasterForMinion(&ings[i])
 				if err != nil {
-					glog.Errorf("Ignoring Ingress %v(Minion): %v", ings[i].Name, err)
+					logging.Errorf("Ignoring Ingress %v(Minion): %v", ings[i].Name, err)
 					continue
 				}
 				if !lbc.configurator.HasMinion(master, &ings[i]) {
@@ -380,7 +542,7 @@ func (lbc *LoadBalancerController) syncEndpoint(task task) {
 				}
 				mergeableIngresses, err := lbc.createMergableIngresses(master)
 				if err != nil {
-					glog.Errorf("Ignoring Ingress %v(Minion): %v", ings[i].Name, err)
+					logging.Errorf("Ignoring Ingress %v(Minion): %v", ings[i].Name, err)
 					continue
 				}
 
@@ -392,25 +554,25 @@ func (lbc *LoadBalancerController) syncEndpoint(task task) {
 			}
 			ingEx, err := lbc.createIngress(&ings[i])
 			if err != nil {
-				glog.Errorf("Error updating endpoints for %v/%v: %v, skipping", &ings[i].Namespace, &ings[i].Name, err)
+				logging.Errorf("Error updating endpoints for %v/%v: %v, skipping", &ings[i].Namespace, &ings[i].Name, err)
 				continue
 			}
 			ingExes = append(ingExes, ingEx)
 		}
 
 		if len(ingExes) > 0 {
-			glog.V(3).Infof("Updating Endpoints for %v", ingExes)
+			logging.V(3).Infof("Updating Endpoints for %v", ingExes)
 			err = lbc.configurator.UpdateEndpoints(ingExes)
 			if err != nil {
-				glog.Errorf("Error updating endpoints for %v: %v", ingExes, err)
+				logging.Errorf("Error updating endpoints for %v: %v", ingExes, err)
 			}
 		}
 
 		if len(mergableIngressesSlice) > 0 {
-			glog.V(3).Infof("Updating Endpoints for %v", mergableIngressesSlice)
+			logging.V(3).Infof("Updating Endpoints for %v", mergableIngressesSlice)
 			err = lbc.configurator.UpdateEndpointsMergeableIngress(mergableIngressesSlice)
 			if err != nil {
-				glog.Errorf("Error updating endpoints for %v: %v", mergableIngressesSlice, err)
+				logging.Errorf("Error updating endpoints for %v: %v", mergableIngressesSlice, err)
 			}
 		}
 
@@ -419,10 +581,10 @@ func (lbc *LoadBalancerController) syncEndpoint(task task) {
 			virtualServersExes := lbc.virtualServersToVirtualServerExes(virtualServers)
 
 			if len(virtualServersExes) > 0 {
-				glog.V(3).Infof("Updating endpoints for %v", virtualServersExes)
+				logging.V(3).Infof("Updating endpoints for %v", virtualServersExes)
 				err := lbc.configurator.UpdateEndpointsForVirtualServers(virtualServersExes)
 				if err != nil {
-					glog.Errorf("Error updating endpoints for %v: %v", virtualServersExes, err)
+					logging.Errorf("Error updating endpoints for %v: %v", virtualServersExes, err)
 				}
 			}
 		}
@@ -431,7 +593,7 @@ func (lbc *LoadBalancerController) syncEndpoint(task task) {
 
 func (lbc *LoadBalancerController) syncConfig(task task) {
 	key := task.Key
-	glog.V(3).Infof("Syncing configmap %v", key)
+	logging.V(3).Infof("Syncing configmap %v", key)
 
 	obj, configExists, err := lbc.configMapLister.GetByKey(key)
 	if err != nil {
@@ -453,7 +615,7 @@ func (lbc *LoadBalancerController) syncConfig(task task) {
 	if lbc.reportStatusEnabled() {
 		err = lbc.statusUpdater.UpdateManagedAndMergeableIngresses(ingresses, mergeableIngresses)
 		if err != nil {
-			glog.V(3).Infof("error updating status on ConfigMap change: %v", err)
+			logging.V(3).Infof("error updating status on ConfigMap change: %v", err)
 		}
 	}
 
@@ -538,7 +700,7 @@ func (lbc *LoadBalancerController) GetManagedIngresses() ([]extensions.Ingress,
 		if isMinion(&ing) {
 			master, err := lbc.FindMasterForMinion(&ing)
 			if err != nil {
-				glog.Errorf("Ignoring Ingress %v(Minion): %v", ing, err)
+				logging.Errorf("Ignoring Ingress %v(Minion): %v", ing, err)
 				continue
 			}
 			if !lbc.configurator.HasIngress(master) {
@@ -547,7 +709,7 @@ func (lbc *LoadBalancerController) GetManagedIngresses() ([]extensions.Ingress,
 			if _, exists := mergeableIngresses[master.Name]; !exists {
 				mergeableIngress, err := lbc.createMergableIngresses(master)
 				if err != nil {
-					glog.Errorf("Ignoring Ingress %v(Master): %v", master, err)
+					logging.Errorf("Ignoring Ingress %v(Master): %v", master, err)
 					continue
 				}
 				mergeableIngresses[master.Name] = mergeableIngress
@@ -586,7 +748,13 @@ func (lbc *LoadBalancerController) virtualServersToVirtualServerExes(virtualServ
 }
 
 func (lbc *LoadBalancerController) sync(task task) {
-	glog.V(3).Infof("Syncing %v", task.Key)
+	logging.V(3).Infof("Syncing %v", task.Key)
+
+	lbc.setHostOwnersCache(true)
+	defer lbc.setHostOwnersCache(false)
+
+	startTime := time.Now()
+	resourceVersion := lbc.getResourceVersionForTask(task)
 
 	switch task.Kind {
 	case ingress:
@@ -607,7 +775,108 @@ func (lbc *LoadBalancerController) sync(task task) {
 		lbc.syncVirtualServer(task)
 	case virtualServerRoute:
 		lbc.syncVirtualServerRoute(task)
+	case certificate:
+		lbc.syncCertificate(task)
+	case initialSync:
+		lbc.isInitialSyncDone = true
+	case certificateExpiryCheck:
+		lbc.checkCertificatesExpiry()
 	}
+
+	lbc.updateReadiness()
+
+	lbc.logSync(task, resourceVersion, time.Since(startTime))
+}
+
+// getResourceVersionForTask returns the resourceVersion of the resource of a task before the sync.
+// It returns an empty string if the resource doesn't exist.
+func (lbc *LoadBalancerController) getResourceVersionForTask(task task) string {
+	obj, exists, err := lbc.getObjectForTask(task)
+	if err != nil || !exists {
+		return ""
+	}
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return ""
+	}
+
+	return accessor.GetResourceVersion()
+}
+
+// logSync logs the result of the sync of a task with the kind, the namespace, the name and the resourceVersion of
+// the resource, the duration and the outcome of the sync. A successful sync is logged with the verbosity level 1,
+// a failed sync is logged regardless of the verbosity.
+func (lbc *LoadBalancerController) logSync(task task, resourceVersion string, duration time.Duration) {
+	namespace, name, err := cache.SplitMetaNamespaceKey(task.Key)
+	if err != nil {
+		name = task.Key
+	}
+
+	outcome := lbc.syncQueue.syncOutcome(task)
+
+	logger := lbc.logger.With(
+		"kind", task.Kind.String(),
+		"namespace", namespace,
+		"name", name,
+		"resourceVersion", resourceVersion,
+		"duration", duration,
+		"outcome", outcome)
+
+	// the error of a failed sync is already logged by the queue
+	switch outcome {
+	case syncOutcomeSynced:
+		logger.V(1).Info("Synced resource")
+	case syncOutcomeParked:
+		logger.Error("Failed to sync resource")
+	default:
+		logger.Warning("Failed to sync resource")
+	}
+}
+
+// park emits an event for the resource of a task that failed after all retries and counts the failure.
+func (lbc *LoadBalancerController) park(task task, err error) {
+	lbc.metricsCollector.IncSyncFailures(task.Kind.String())
+
+	obj, exists, getErr := lbc.getObjectForTask(task)
+	if getErr != nil || !exists {
+		return
+	}
+
+	lbc.recorder.Eventf(obj, api_v1.EventTypeWarning, "SyncFailed",
+		"Gave up syncing %v %v after %v retries, it will be retried on its next change: %v", task.Kind, task.Key, queueMaxRetries, err)
+}
+
+// getObjectForTask gets the resource of a task from the cache.
+func (lbc *LoadBalancerController) getObjectForTask(task task) (runtime.Object, bool, error) {
+	var obj interface{}
+	var exists bool
+	var err error
+
+	switch task.Kind {
+	case ingress, ingressMinion:
+		obj, exists, err = lbc.ingressLister.Store.GetByKey(task.Key)
+	case endpoints:
+		obj, exists, err = lbc.endpointLister.GetByKey(task.Key)
+	case configMap:
+		obj, exists, err = lbc.configMapLister.GetByKey(task.Key)
+	case secret:
+		obj, exists, err = lbc.secretLister.Store.GetByKey(task.Key)
+	case service:
+		obj, exists, err = lbc.svcLister.GetByKey(task.Key)
+	case virtualserver:
+		obj, exists, err = lbc.virtualServerLister.GetByKey(task.Key)
+	case virtualServerRoute:
+		obj, exists, err = lbc.virtualServerRouteLister.GetByKey(task.Key)
+	case certificate:
+		obj, exists, err = lbc.certificateLister.GetByKey(task.Key)
+	}
+
+	if err != nil || !exists {
+		return nil, exists, err
+	}
+
+	return obj.(runtime.Object), true, nil
 }
 
 func (lbc *LoadBalancerController) syncVirtualServer(task task) {
@@ -619,17 +888,17 @@ func (lbc *LoadBalancerController) syncVirtualServer(task task) {
 	}
 
 	if !vsExists {
-		glog.V(2).Infof("Deleting VirtualServer: %v\n", key)
+		logging.V(2).Infof("Deleting VirtualServer: %v\n", key)
 
 		err := lbc.configurator.DeleteVirtualServer(key)
 		// TO-DO: emit events for referenced VirtualServerRoutes
 		if err != nil {
-			glog.Errorf("Error when deleting configuration for %v: %v", key, err)
+			logging.Errorf("Error when deleting configuration for %v: %v", key, err)
 		}
 		return
 	}
 
-	glog.V(2).Infof("Adding or Updating VirtualServer: %v\n", key)
+	logging.V(2).Infof("Adding or Updating VirtualServer: %v\n", key)
 
 	vs := obj.(*conf_v1alpha1.VirtualServer)
 
@@ -637,13 +906,27 @@ func (lbc *LoadBalancerController) syncVirtualServer(task task) {
 	if validationErr != nil {
 		err := lbc.configurator.DeleteVirtualServer(key)
 		if err != nil {
-			glog.Errorf("Error when deleting configuration for %v: %v", key, err)
+			logging.Errorf("Error when deleting configuration for %v: %v", key, err)
 		}
 		lbc.recorder.Eventf(vs, api_v1.EventTypeWarning, "Rejected", "VirtualServer %v is invalid and was rejected: %v", key, validationErr)
 		// TO-DO: emit events for referenced VirtualServerRoutes
 		return
 	}
 
+	takenHosts := lbc.getTakenHostsForVirtualServer(vs)
+	if _, taken := takenHosts[vs.Spec.Host]; taken {
+		err := lbc.configurator.DeleteVirtualServer(key)
+		if err != nil {
+			logging.Errorf("Error when deleting configuration for %v: %v", key, err)
+		}
+		lbc.recorder.Eventf(vs, api_v1.EventTypeWarning, "Rejected", "VirtualServer %v was rejected: %v", key, formatTakenHosts(takenHosts))
+		return
+	}
+
+	if lbc.isCertManagerEnabled {
+		lbc.syncCertificatesForVirtualServer(removeTakenServerAliases(vs, takenHosts))
+	}
+
 	vsEx, vsrErrors := lbc.createVirtualServer(vs)
 
 	for _, vsrError := range vsrErrors {
@@ -669,7 +952,12 @@ func (lbc *LoadBalancerController) syncVirtualServer(task task) {
 	vsEventTitle := eventTitle
 	vsEventWarningMessage := eventWarningMessage
 
-	if messages, ok := warnings[vsEx.VirtualServer]; ok && addErr == nil {
+	messages := warnings[vsEx.VirtualServer]
+	if len(takenHosts) > 0 {
+		messages = append(messages, formatTakenHosts(takenHosts))
+	}
+
+	if len(messages) > 0 && addErr == nil {
 		vsEventType = api_v1.EventTypeWarning
 		vsEventTitle = "AddedOrUpdatedWithWarning"
 		vsEventWarningMessage = fmt.Sprintf("with warning(s): %v", formatWarningMessages(messages))
@@ -690,6 +978,9 @@ func (lbc *LoadBalancerController) syncVirtualServer(task task) {
 		lbc.recorder.Eventf(vsr, vsrEventType, vsrEventTitle, "Configuration for %v/%v was added or updated %s", vsr.Namespace, vsr.Name, vsrEventWarningMessage)
 	}
 
+	if addErr != nil {
+		lbc.syncQueue.Requeue(task, addErr)
+	}
 }
 
 func (lbc *LoadBalancerController) syncVirtualServerRoute(task task) {
@@ -702,13 +993,13 @@ func (lbc *LoadBalancerController) syncVirtualServerRoute(task task) {
 	}
 
 	if !exists {
-		glog.V(2).Infof("Deleting VirtualServerRoute: %v\n", key)
+		logging.V(2).Infof("Deleting VirtualServerRoute: %v\n", key)
 
 		lbc.enqueueVirtualServersForVirtualServerRouteKey(key)
 		return
 	}
 
-	glog.V(2).Infof("Adding or Updating VirtualServerRoute: %v\n", key)
+	logging.V(2).Infof("Adding or Updating VirtualServerRoute: %v\n", key)
 
 	vsr := obj.(*conf_v1alpha1.VirtualServerRoute)
 
@@ -734,22 +1025,22 @@ func (lbc *LoadBalancerController) syncIngMinion(task task) {
 	}
 
 	if !ingExists {
-		glog.V(2).Infof("Minion was deleted: %v\n", key)
+		logging.V(2).Infof("Minion was deleted: %v\n", key)
 		return
 	}
-	glog.V(2).Infof("Adding or Updating Minion: %v\n", key)
+	logging.V(2).Infof("Adding or Updating Minion: %v\n", key)
 
 	minion := obj.(*extensions.Ingress)
 
 	master, err := lbc.FindMasterForMinion(minion)
 	if err != nil {
-		lbc.syncQueue.RequeueAfter(task, err, 5*time.Second)
+		lbc.syncQueue.Requeue(task, err)
 		return
 	}
 
 	_, err = lbc.createIngress(minion)
 	if err != nil {
-		lbc.syncQueue.RequeueAfter(task, err, 5*time.Second)
+		lbc.syncQueue.Requeue(task, err)
 		if !lbc.configurator.HasMinion(master, minion) {
 			return
 		}
@@ -767,14 +1058,24 @@ func (lbc *LoadBalancerController) syncIng(task task) {
 	}
 
 	if !ingExists {
-		glog.V(2).Infof("Deleting Ingress: %v\n", key)
+		logging.V(2).Infof("Deleting Ingress: %v\n", key)
 
 		err := lbc.configurator.DeleteIngress(key)
 		if err != nil {
-			glog.Errorf("Error when deleting configuration for %v: %v", key, err)
+			logging.Errorf("Error when deleting configuration for %v: %v", key, err)
 		}
 	} else {
-		glog.V(2).Infof("Adding or Updating Ingress: %v\n", key)
+		logging.V(2).Infof("Adding or Updating Ingress: %v\n", key)
+
+		takenHosts := lbc.getTakenHostsForIngress(ing)
+		if len(takenHosts) > 0 && len(takenHosts) == len(getIngressHosts(ing)) {
+			lbc.rejectIngressWithTakenHosts(key, ing, takenHosts)
+			return
+		}
+
+		if lbc.isCertManagerEnabled {
+			lbc.syncCertificatesForIngress(removeTakenHosts(ing, takenHosts))
+		}
 
 		if isMaster(ing) {
 			mergeableIngExs, err := lbc.createMergableIngresses(ing)
@@ -782,12 +1083,12 @@ func (lbc *LoadBalancerController) syncIng(task task) {
 				// we need to requeue because an error can occur even if the master is valid
 				// otherwise, we will not be able to generate the config until there is change
 				// in the master or minions.
-				lbc.syncQueue.RequeueAfter(task, err, 5*time.Second)
+				lbc.syncQueue.Requeue(task, err)
 				lbc.recorder.Eventf(ing, api_v1.EventTypeWarning, "Rejected", "%v was rejected: %v", key, err)
 				if lbc.reportStatusEnabled() {
 					err = lbc.statusUpdater.ClearIngressStatus(*ing)
 					if err != nil {
-						glog.V(3).Infof("error clearing ing status: %v", err)
+						logging.V(3).Infof("error clearing ing status: %v", err)
 					}
 				}
 				return
@@ -812,9 +1113,13 @@ func (lbc *LoadBalancerController) syncIng(task task) {
 			if lbc.reportStatusEnabled() {
 				err = lbc.statusUpdater.UpdateMergableIngresses(mergeableIngExs)
 				if err != nil {
-					glog.V(3).Infof("error updating ingress status: %v", err)
+					logging.V(3).Infof("error updating ingress status: %v", err)
 				}
 			}
+
+			if addErr != nil {
+				lbc.syncQueue.Requeue(task, addErr)
+			}
 			return
 		}
 		ingEx, err := lbc.createIngress(ing)
@@ -823,24 +1128,49 @@ func (lbc *LoadBalancerController) syncIng(task task) {
 			if lbc.reportStatusEnabled() {
 				err = lbc.statusUpdater.ClearIngressStatus(*ing)
 				if err != nil {
-					glog.V(3).Infof("error clearing ing status: %v", err)
+					logging.V(3).Infof("error clearing ing status: %v", err)
 				}
 			}
 			return
 		}
 
-		err = lbc.configurator.AddOrUpdateIngress(ingEx)
-		if err != nil {
-			lbc.recorder.Eventf(ing, api_v1.EventTypeWarning, "AddedOrUpdatedWithError", "Configuration for %v was added or updated, but not applied: %v", key, err)
+		addErr := lbc.configurator.AddOrUpdateIngress(ingEx)
+		if addErr != nil {
+			lbc.recorder.Eventf(ing, api_v1.EventTypeWarning, "AddedOrUpdatedWithError", "Configuration for %v was added or updated, but not applied: %v", key, addErr)
+		} else if len(takenHosts) > 0 {
+			lbc.recorder.Eventf(ing, api_v1.EventTypeWarning, "AddedOrUpdatedWithWarning", "Configuration for %v was added or updated with warning(s): %v", key, formatTakenHosts(takenHosts))
 		} else {
 			lbc.recorder.Eventf(ing, api_v1.EventTypeNormal, "AddedOrUpdated", "Configuration for %v was added or updated", key)
 		}
 		if lbc.reportStatusEnabled() {
 			err = lbc.statusUpdater.UpdateIngressStatus(*ing)
 			if err != nil {
-				glog.V(3).Infof("error updating ing status: %v", err)
+				logging.V(3).Infof("error updating ing status: %v", err)
 			}
 		}
+
+		if addErr != nil {
+			lbc.syncQueue.Requeue(task, addErr)
+		}
+	}
+}
+
+// rejectIngressWithTakenHosts removes the configuration of an Ingress whose hosts are all taken by other resources.
+func (lbc *LoadBalancerController) rejectIngressWithTakenHosts(key string, ing *extensions.Ingress, takenHosts map[string]hostResource) {
+	if lbc.configurator.HasIngress(ing) {
+		err := lbc.configurator.DeleteIngress(key)
+		if err != nil {
+			logging.Errorf("Error when deleting configuration for %v: %v", key, err)
+		}
+	}
+
+	lbc.recorder.Eventf(ing, api_v1.EventTypeWarning, "Rejected", "%v was rejected: %v", key, formatTakenHosts(takenHosts))
+
+	if lbc.reportStatusEnabled() {
+		err := lbc.statusUpdater.ClearIngressStatus(*ing)
+		if err != nil {
+			logging.V(3).Infof("error clearing ing status: %v", err)
+		}
 	}
 }
 
@@ -871,7 +1201,7 @@ func (lbc *LoadBalancerController) syncExternalService(task task) {
 	if lbc.reportStatusEnabled() {
 		err = lbc.statusUpdater.UpdateManagedAndMergeableIngresses(statusIngs, mergableIngs)
 		if err != nil {
-			glog.Errorf("error updating ingress status in syncExternalService: %v", err)
+			logging.Errorf("error updating ingress status in syncExternalService: %v", err)
 		}
 	}
 }
@@ -881,6 +1211,133 @@ func (lbc *LoadBalancerController) IsExternalServiceForStatus(svc *api_v1.Servic
 	return lbc.statusUpdater.namespace == svc.Namespace && lbc.statusUpdater.externalServiceName == svc.Name
 }
 
+// ClearIngressStatusIfLastReplica clears the status of the managed Ingress resources if the controller is the last
+// running replica. Otherwise, the status is kept, as it is still valid for the remaining replicas.
+func (lbc *LoadBalancerController) ClearIngressStatusIfLastReplica() error {
+	if !lbc.reportIngressStatus {
+		return nil
+	}
+
+	isLast, err := lbc.isLastReplica()
+	if err != nil {
+		return fmt.Errorf("Error checking the replicas of the Ingress controller: %v", err)
+	}
+	if !isLast {
+		logging.V(3).Info("Other replicas of the Ingress controller are running, keeping the Ingress status")
+		return nil
+	}
+
+	logging.V(3).Info("The last replica of the Ingress controller is shutting down, clearing the Ingress status")
+
+	var ings []extensions.Ingress
+	managedIngresses, mergeableIngresses := lbc.GetManagedIngresses()
+	ings = append(ings, managedIngresses...)
+	for _, mergeableIng := range mergeableIngresses {
+		ings = append(ings, *mergeableIng.Master.Ingress)
+		for _, minion := range mergeableIng.Minions {
+			ings = append(ings, *minion.Ingress)
+		}
+	}
+
+	var errs []string
+	for _, ing := range ings {
+		err := lbc.statusUpdater.ClearIngressStatus(ing)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%v/%v: %v", ing.Namespace, ing.Name, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("Error clearing the status of Ingress resources: %v", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// isLastReplica checks if there are no other running pods of the Ingress controller, which are the pods in the
+// controller namespace selected by the label selector of the Deployment, DaemonSet or StatefulSet of the pod of
+// the controller. Pods that are being deleted are not counted. A pod without a controller is the only replica.
+func (lbc *LoadBalancerController) isLastReplica() (bool, error) {
+	podName := os.Getenv("POD_NAME")
+	if podName == "" {
+		return false, errors.New("POD_NAME environment variable is not set")
+	}
+
+	pod, err := lbc.client.CoreV1().Pods(lbc.controllerNamespace).Get(podName, meta_v1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	selector, err := lbc.getReplicasSelector(pod)
+	if err != nil {
+		return false, err
+	}
+	if selector == nil {
+		return true, nil
+	}
+
+	pods, err := lbc.client.CoreV1().Pods(lbc.controllerNamespace).List(meta_v1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	for _, p := range pods.Items {
+		if p.Name == podName || p.DeletionTimestamp != nil || p.Status.Phase != api_v1.PodRunning {
+			continue
+		}
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// getReplicasSelector returns the label selector of the Deployment, DaemonSet or StatefulSet that controls the pod.
+// Unlike the labels of the pod, the selector doesn't include the pod-template-hash and controller-revision-hash
+// labels, so it also selects the replicas of the previous revision during a rolling update.
+// It returns nil if the pod has no controller.
+func (lbc *LoadBalancerController) getReplicasSelector(pod *api_v1.Pod) (labels.Selector, error) {
+	owner := meta_v1.GetControllerOf(pod)
+	if owner == nil {
+		return nil, nil
+	}
+
+	var labelSelector *meta_v1.LabelSelector
+
+	switch owner.Kind {
+	case "ReplicaSet":
+		rs, err := lbc.client.AppsV1().ReplicaSets(pod.Namespace).Get(owner.Name, meta_v1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		labelSelector = rs.Spec.Selector
+
+		if rsOwner := meta_v1.GetControllerOf(rs); rsOwner != nil && rsOwner.Kind == "Deployment" {
+			deployment, err := lbc.client.AppsV1().Deployments(pod.Namespace).Get(rsOwner.Name, meta_v1.GetOptions{})
+			if err != nil {
+				return nil, err
+			}
+			labelSelector = deployment.Spec.Selector
+		}
+	case "DaemonSet":
+		ds, err := lbc.client.AppsV1().DaemonSets(pod.Namespace).Get(owner.Name, meta_v1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		labelSelector = ds.Spec.Selector
+	case "StatefulSet":
+		ss, err := lbc.client.AppsV1().StatefulSets(pod.Namespace).Get(owner.Name, meta_v1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		labelSelector = ss.Spec.Selector
+	default:
+		return nil, fmt.Errorf("Unsupported controller %v %v of the pod %v", owner.Kind, owner.Name, pod.Name)
+	}
+
+	return meta_v1.LabelSelectorAsSelector(labelSelector)
+}
+
 // reportStatusEnabled determines if we should attempt to report status
 func (lbc *LoadBalancerController) reportStatusEnabled() bool {
 	if lbc.reportIngressStatus {
@@ -902,38 +1359,42 @@ func (lbc *LoadBalancerController) syncSecret(task task) {
 
 	namespace, name, err := ParseNamespaceName(key)
 	if err != nil {
-		glog.Warningf("Secret key %v is invalid: %v", key, err)
+		logging.Warningf("Secret key %v is invalid: %v", key, err)
 		return
 	}
 
 	ings, err := lbc.findIngressesForSecret(namespace, name)
 	if err != nil {
-		glog.Warningf("Failed to find Ingress resources for Secret %v: %v", key, err)
-		lbc.syncQueue.RequeueAfter(task, err, 5*time.Second)
+		logging.Warningf("Failed to find Ingress resources for Secret %v: %v", key, err)
+		lbc.syncQueue.Requeue(task, err)
 	}
 
 	var virtualServers []*conf_v1alpha1.VirtualServer
 	if lbc.areCustomResourcesEnabled {
 		virtualServers = lbc.getVirtualServersForSecret(namespace, name)
-		glog.V(2).Infof("Found %v VirtualServers with Secret %v", len(virtualServers), key)
+		logging.V(2).Infof("Found %v VirtualServers with Secret %v", len(virtualServers), key)
 	}
 
-	glog.V(2).Infof("Found %v Ingresses with Secret %v", len(ings), key)
+	logging.V(2).Infof("Found %v Ingresses with Secret %v", len(ings), key)
 
 	if !secrExists {
-		glog.V(2).Infof("Deleting Secret: %v\n", key)
+		logging.V(2).Infof("Deleting Secret: %v\n", key)
+
+		lbc.metricsCollector.DeleteCertificateExpiry(namespace, name)
 
 		lbc.handleRegularSecretDeletion(key, ings, virtualServers)
 		if lbc.isSpecialSecret(key) {
-			glog.Warningf("A special TLS Secret %v was removed. Retaining the Secret.", key)
+			logging.Warningf("A special TLS Secret %v was removed. Retaining the Secret.", key)
 		}
 		return
 	}
 
-	glog.V(2).Infof("Adding / Updating Secret: %v\n", key)
+	logging.V(2).Infof("Adding / Updating Secret: %v\n", key)
 
 	secret := obj.(*api_v1.Secret)
 
+	lbc.updateCertificateExpiry(secret)
+
 	if lbc.isSpecialSecret(key) {
 		lbc.handleSpecialSecretUpdate(secret)
 		// we don't return here in case the special secret is also used in Ingress or VirtualServer resources.
@@ -944,6 +1405,59 @@ func (lbc *LoadBalancerController) syncSecret(task task) {
 	}
 }
 
+// updateCertificateExpiry updates the certificate expiry metric for the secret.
+func (lbc *LoadBalancerController) updateCertificateExpiry(secret *api_v1.Secret) {
+	cert, err := ParseTLSSecretCertificate(secret)
+	if err != nil {
+		lbc.metricsCollector.DeleteCertificateExpiry(secret.Namespace, secret.Name)
+		return
+	}
+
+	lbc.metricsCollector.SetCertificateExpiry(secret.Namespace, secret.Name, cert.NotAfter)
+}
+
+// checkCertificatesExpiry emits a Warning event for the Ingress and VirtualServer resources that reference
+// a TLS Secret with a certificate that expires within the certificate expiry warning period.
+func (lbc *LoadBalancerController) checkCertificatesExpiry() {
+	for _, obj := range lbc.secretLister.Store.List() {
+		secret := obj.(*api_v1.Secret)
+
+		cert, err := ParseTLSSecretCertificate(secret)
+		if err != nil {
+			continue
+		}
+
+		timeLeft := time.Until(cert.NotAfter)
+		if timeLeft > lbc.certificateExpiryWarningPeriod {
+			continue
+		}
+
+		secretNsName := secret.Namespace + "/" + secret.Name
+
+		message := fmt.Sprintf("The certificate of Secret %v expires on %v", secretNsName, cert.NotAfter.Format(time.RFC3339))
+		if timeLeft <= 0 {
+			message = fmt.Sprintf("The certificate of Secret %v expired on %v", secretNsName, cert.NotAfter.Format(time.RFC3339))
+		}
+
+		ings, err := lbc.findIngressesForSecret(secret.Namespace, secret.Name)
+		if err != nil {
+			logging.Warningf("Failed to find Ingress resources for Secret %v: %v", secretNsName, err)
+		}
+
+		var virtualServers []*conf_v1alpha1.VirtualServer
+		if lbc.areCustomResourcesEnabled {
+			virtualServers = lbc.getVirtualServersForSecret(secret.Namespace, secret.Name)
+		}
+
+		if len(ings)+len(virtualServers) > 0 {
+			logging.Warning(message)
+		}
+
+		lbc.emitEventForIngresses(api_v1.EventTypeWarning, "CertificateExpiringSoon", message, ings)
+		lbc.emitEventForVirtualServers(api_v1.EventTypeWarning, "CertificateExpiringSoon", message, virtualServers)
+	}
+}
+
 func (lbc *LoadBalancerController) isSpecialSecret(secretName string) bool {
 	return secretName == lbc.defaultServerSecret || secretName == lbc.wildcardTLSSecret
 }
@@ -965,7 +1479,7 @@ func (lbc *LoadBalancerController) handleRegularSecretDeletion(key string, ings
 	message = fmt.Sprintf("Configuration was updated due to removed secret %v", key)
 
 	if err := lbc.configurator.DeleteSecret(key, regular, mergeable, virtualServerExes); err != nil {
-		glog.Errorf("Error when deleting Secret: %v: %v", key, err)
+		logging.Errorf("Error when deleting Secret: %v: %v", key, err)
 
 		eventType = api_v1.EventTypeWarning
 		title = "UpdatedWithError"
@@ -982,8 +1496,8 @@ func (lbc *LoadBalancerController) handleSecretUpdate(secret *api_v1.Secret, ing
 	err := lbc.ValidateSecret(secret)
 	if err != nil {
 		// Secret becomes Invalid
-		glog.Errorf("Couldn't validate secret %v: %v", secretNsName, err)
-		glog.Errorf("Removing invalid secret %v", secretNsName)
+		logging.Errorf("Couldn't validate secret %v: %v", secretNsName, err)
+		logging.Errorf("Removing invalid secret %v", secretNsName)
 
 		lbc.handleRegularSecretDeletion(secretNsName, ings, virtualServers)
 
@@ -1005,9 +1519,14 @@ func (lbc *LoadBalancerController) handleSecretUpdate(secret *api_v1.Secret, ing
 
 		virtualServerExes := lbc.virtualServersToVirtualServerExes(virtualServers)
 
-		err := lbc.configurator.AddOrUpdateTLSSecret(secret, regular, mergeable, virtualServerExes)
+		var err error
+		if kind == Htpasswd {
+			err = lbc.configurator.AddOrUpdateHtpasswdSecret(secret, regular, mergeable, virtualServerExes)
+		} else {
+			err = lbc.configurator.AddOrUpdateTLSSecret(secret, regular, mergeable, virtualServerExes)
+		}
 		if err != nil {
-			glog.Errorf("Error when updating Secret %v: %v", secretNsName, err)
+			logging.Errorf("Error when updating Secret %v: %v", secretNsName, err)
 			lbc.recorder.Eventf(secret, api_v1.EventTypeWarning, "UpdatedWithError", "%v was updated, but not applied: %v", secretNsName, err)
 
 			eventType = api_v1.EventTypeWarning
@@ -1025,7 +1544,7 @@ func (lbc *LoadBalancerController) handleSpecialSecretUpdate(secret *api_v1.Secr
 	secretNsName := secret.Namespace + "/" + secret.Name
 	err := ValidateTLSSecret(secret)
 	if err != nil {
-		glog.Errorf("Couldn't validate the special Secret %v: %v", secretNsName, err)
+		logging.Errorf("Couldn't validate the special Secret %v: %v", secretNsName, err)
 		lbc.recorder.Eventf(secret, api_v1.EventTypeWarning, "Rejected", "the special Secret %v was rejected, using the previous version: %v", secretNsName, err)
 		return
 	}
@@ -1039,7 +1558,7 @@ func (lbc *LoadBalancerController) handleSpecialSecretUpdate(secret *api_v1.Secr
 
 	err = lbc.configurator.AddOrUpdateSpecialTLSSecrets(secret, specialSecretsToUpdate)
 	if err != nil {
-		glog.Errorf("Error when updating the special Secret %v: %v", secretNsName, err)
+		logging.Errorf("Error when updating the special Secret %v: %v", secretNsName, err)
 		lbc.recorder.Eventf(secret, api_v1.EventTypeWarning, "UpdatedWithError", "the special Secret %v was updated, but not applied: %v", secretNsName, err)
 		return
 	}
@@ -1053,7 +1572,7 @@ func (lbc *LoadBalancerController) emitEventForIngresses(eventType string, title
 		if isMinion(&ing) {
 			master, err := lbc.FindMasterForMinion(&ing)
 			if err != nil {
-				glog.Errorf("Ignoring Ingress %v(Minion): %v", ing.Name, err)
+				logging.Errorf("Ignoring Ingress %v(Minion): %v", ing.Name, err)
 				continue
 			}
 			masterMsg := fmt.Sprintf("%v for Minion %v/%v", message, ing.Namespace, ing.Name)
@@ -1073,7 +1592,7 @@ func (lbc *LoadBalancerController) createIngresses(ings []extensions.Ingress) (r
 		if isMaster(&ings[i]) {
 			mergeableIng, err := lbc.createMergableIngresses(&ings[i])
 			if err != nil {
-				glog.Errorf("Ignoring Ingress %v(Master): %v", ings[i].Name, err)
+				logging.Errorf("Ignoring Ingress %v(Master): %v", ings[i].Name, err)
 				continue
 			}
 			mergeable = append(mergeable, *mergeableIng)
@@ -1083,12 +1602,12 @@ func (lbc *LoadBalancerController) createIngresses(ings []extensions.Ingress) (r
 		if isMinion(&ings[i]) {
 			master, err := lbc.FindMasterForMinion(&ings[i])
 			if err != nil {
-				glog.Errorf("Ignoring Ingress %v(Minion): %v", ings[i].Name, err)
+				logging.Errorf("Ignoring Ingress %v(Minion): %v", ings[i].Name, err)
 				continue
 			}
 			mergeableIng, err := lbc.createMergableIngresses(master)
 			if err != nil {
-				glog.Errorf("Ignoring Ingress %v(Master): %v", master, err)
+				logging.Errorf("Ignoring Ingress %v(Master): %v", master, err)
 				continue
 			}
 
@@ -1098,7 +1617,7 @@ func (lbc *LoadBalancerController) createIngresses(ings []extensions.Ingress) (r
 
 		ingEx, err := lbc.createIngress(&ings[i])
 		if err != nil {
-			glog.Errorf("Ignoring Ingress %v/%v: $%v", ings[i].Namespace, ings[i].Name, err)
+			logging.Errorf("Ignoring Ingress %v/%v: $%v", ings[i].Namespace, ings[i].Name, err)
 		}
 		regular = append(regular, *ingEx)
 	}
@@ -1136,30 +1655,43 @@ items:
 				if jwtKey, exists := ing.Annotations[configs.JWTKeyAnnotation]; exists {
 					if jwtKey == secretName {
 						ings = append(ings, ing)
+						continue
 					}
 				}
 			}
+			if basicAuthSecret, exists := ing.Annotations[configs.BasicAuthSecretAnnotation]; exists {
+				if basicAuthSecret == secretName {
+					ings = append(ings, ing)
+				}
+			}
 			continue
 		}
 
 		// we're dealing with a minion
-		// minions can only have JWT secrets
-		if lbc.isNginxPlus {
-			master, err := lbc.FindMasterForMinion(&ing)
-			if err != nil {
-				glog.Infof("Ignoring Ingress %v(Minion): %v", ing.Name, err)
-				continue
-			}
+		// minions can only have JWT and htpasswd secrets
+		_, hasJWTKey := ing.Annotations[configs.JWTKeyAnnotation]
+		_, hasBasicAuthSecret := ing.Annotations[configs.BasicAuthSecretAnnotation]
+		if !(lbc.isNginxPlus && hasJWTKey) && !hasBasicAuthSecret {
+			continue
+		}
 
-			if !lbc.configurator.HasMinion(master, &ing) {
-				continue
-			}
+		master, err := lbc.FindMasterForMinion(&ing)
+		if err != nil {
+			logging.Infof("Ignoring Ingress %v(Minion): %v", ing.Name, err)
+			continue
+		}
 
-			if jwtKey, exists := ing.Annotations[configs.JWTKeyAnnotation]; exists {
-				if jwtKey == secretName {
-					ings = append(ings, ing)
-				}
-			}
+		if !lbc.configurator.HasMinion(master, &ing) {
+			continue
+		}
+
+		if lbc.isNginxPlus && ing.Annotations[configs.JWTKeyAnnotation] == secretName {
+			ings = append(ings, ing)
+			continue
+		}
+
+		if ing.Annotations[configs.BasicAuthSecretAnnotation] == secretName {
+			ings = append(ings, ing)
 		}
 	}
 
@@ -1176,7 +1708,7 @@ func (lbc *LoadBalancerController) EnqueueIngressForService(svc *api_v1.Service)
 		if isMinion(&ing) {
 			master, err := lbc.FindMasterForMinion(&ing)
 			if err != nil {
-				glog.Errorf("Ignoring Ingress %v(Minion): %v", ing.Name, err)
+				logging.Errorf("Ignoring Ingress %v(Minion): %v", ing.Name, err)
 				continue
 			}
 			ing = *master
@@ -1200,7 +1732,7 @@ func (lbc *LoadBalancerController) EnqueueVirtualServersForService(service *api_
 func (lbc *LoadBalancerController) getIngressesForService(svc *api_v1.Service) []extensions.Ingress {
 	ings, err := lbc.ingressLister.GetServiceIngress(svc)
 	if err != nil {
-		glog.V(3).Infof("For service %v: %v", svc.Name, err)
+		logging.V(3).Infof("For service %v: %v", svc.Name, err)
 		return nil
 	}
 	return ings
@@ -1212,7 +1744,7 @@ func (lbc *LoadBalancerController) getIngressForEndpoints(obj interface{}) []ext
 	svcKey := endp.GetNamespace() + "/" + endp.GetName()
 	svcObj, svcExists, err := lbc.svcLister.GetByKey(svcKey)
 	if err != nil {
-		glog.V(3).Infof("error getting service %v from the cache: %v\n", svcKey, err)
+		logging.V(3).Infof("error getting service %v from the cache: %v\n", svcKey, err)
 	} else {
 		if svcExists {
 			ings = append(ings, lbc.getIngressesForService(svcObj.(*api_v1.Service))...)
@@ -1226,11 +1758,11 @@ func (lbc *LoadBalancerController) getVirtualServersForEndpoints(endpoints *api_
 
 	svc, exists, err := lbc.svcLister.GetByKey(svcKey)
 	if err != nil {
-		glog.V(3).Infof("Error getting service %v from the cache: %v", svcKey, err)
+		logging.V(3).Infof("Error getting service %v from the cache: %v", svcKey, err)
 		return nil
 	}
 	if !exists {
-		glog.V(3).Infof("Service %v doesn't exist", svcKey)
+		logging.V(3).Infof("Service %v doesn't exist", svcKey)
 		return nil
 	}
 
@@ -1307,22 +1839,47 @@ func findVirtualServerRoutesForService(virtualServerRoutes []*conf_v1alpha1.Virt
 }
 
 func (lbc *LoadBalancerController) getVirtualServersForSecret(secretNamespace string, secretName string) []*conf_v1alpha1.VirtualServer {
-	virtualServers := lbc.getVirtualServers()
-	return findVirtualServersForSecret(virtualServers, secretNamespace, secretName)
+	allVirtualServers := lbc.getVirtualServers()
+
+	// find VirtualServers that reference the secret
+	result := findVirtualServersForSecret(allVirtualServers, secretNamespace, secretName)
+
+	// find VirtualServers that reference VirtualServerRoutes that reference the secret
+	virtualServerRoutes := findVirtualServerRoutesForSecret(lbc.getVirtualServerRoutes(), secretNamespace, secretName)
+	for _, vsr := range virtualServerRoutes {
+		for _, vs := range findVirtualServersForVirtualServerRoute(allVirtualServers, vsr) {
+			if !containsVirtualServer(result, vs) {
+				result = append(result, vs)
+			}
+		}
+	}
+
+	return result
+}
+
+func containsVirtualServer(virtualServers []*conf_v1alpha1.VirtualServer, virtualServer *conf_v1alpha1.VirtualServer) bool {
+	for _, vs := range virtualServers {
+		if vs.Namespace == virtualServer.Namespace && vs.Name == virtualServer.Name {
+			return true
+		}
+	}
+	return false
 }
 
 func findVirtualServersForSecret(virtualServers []*conf_v1alpha1.VirtualServer, secretNamespace string, secretName string) []*conf_v1alpha1.VirtualServer {
 	var result []*conf_v1alpha1.VirtualServer
 
 	for _, vs := range virtualServers {
-		if vs.Spec.TLS == nil {
+		if vs.Namespace != secretNamespace {
 			continue
 		}
-		if vs.Spec.TLS.Secret == "" {
+
+		if vs.Spec.TLS != nil && vs.Spec.TLS.Secret == secretName {
+			result = append(result, vs)
 			continue
 		}
 
-		if vs.Namespace == secretNamespace && vs.Spec.TLS.Secret == secretName {
+		if isBasicAuthSecretReferenced(vs.Spec.Routes, secretName) {
 			result = append(result, vs)
 		}
 	}
@@ -1330,15 +1887,48 @@ func findVirtualServersForSecret(virtualServers []*conf_v1alpha1.VirtualServer,
 	return result
 }
 
+func findVirtualServerRoutesForSecret(virtualServerRoutes []*conf_v1alpha1.VirtualServerRoute, secretNamespace string, secretName string) []*conf_v1alpha1.VirtualServerRoute {
+	var result []*conf_v1alpha1.VirtualServerRoute
+
+	for _, vsr := range virtualServerRoutes {
+		if vsr.Namespace != secretNamespace {
+			continue
+		}
+
+		if isBasicAuthSecretReferenced(vsr.Spec.Subroutes, secretName) {
+			result = append(result, vsr)
+		}
+	}
+
+	return result
+}
+
+func isBasicAuthSecretReferenced(routes []conf_v1alpha1.Route, secretName string) bool {
+	for _, r := range routes {
+		if r.BasicAuth != nil && r.BasicAuth.Secret == secretName {
+			return true
+		}
+	}
+	return false
+}
+
 func (lbc *LoadBalancerController) getVirtualServers() []*conf_v1alpha1.VirtualServer {
 	var virtualServers []*conf_v1alpha1.VirtualServer
 
+	owners := lbc.getHostOwners()
+
 	for _, obj := range lbc.virtualServerLister.List() {
 		vs := obj.(*conf_v1alpha1.VirtualServer)
 
 		err := validation.ValidateVirtualServer(vs, lbc.isNginxPlus)
 		if err != nil {
-			glog.V(3).Infof("Skipping invalid VirtualServer %s/%s: %v", vs.Namespace, vs.Name, err)
+			logging.V(3).Infof("Skipping invalid VirtualServer %s/%s: %v", vs.Namespace, vs.Name, err)
+			continue
+		}
+
+		takenHosts := findTakenHosts(newVirtualServerHostResource(vs), owners)
+		if _, taken := takenHosts[vs.Spec.Host]; taken {
+			logging.V(3).Infof("Skipping VirtualServer %s/%s: %v", vs.Namespace, vs.Name, formatTakenHosts(takenHosts))
 			continue
 		}
 
@@ -1348,6 +1938,88 @@ func (lbc *LoadBalancerController) getVirtualServers() []*conf_v1alpha1.VirtualS
 	return virtualServers
 }
 
+// getHostResources returns the resources that compete for hosts: the Ingress resources handled by the Ingress controller,
+// except minions, which share the host of their master, and the valid VirtualServers.
+func (lbc *LoadBalancerController) getHostResources() []hostResource {
+	var resources []hostResource
+
+	for _, obj := range lbc.ingressLister.Store.List() {
+		ing := obj.(*extensions.Ingress)
+		if !lbc.IsNginxIngress(ing) || isMinion(ing) {
+			continue
+		}
+		resources = append(resources, newIngressHostResource(ing))
+	}
+
+	if lbc.areCustomResourcesEnabled {
+		for _, obj := range lbc.virtualServerLister.List() {
+			vs := obj.(*conf_v1alpha1.VirtualServer)
+			if validation.ValidateVirtualServer(vs, lbc.isNginxPlus) != nil {
+				continue
+			}
+			resources = append(resources, newVirtualServerHostResource(vs))
+		}
+	}
+
+	return resources
+}
+
+// getTakenHostsForIngress returns the hosts of the Ingress that are taken by other resources.
+func (lbc *LoadBalancerController) getTakenHostsForIngress(ing *extensions.Ingress) map[string]hostResource {
+	if isMinion(ing) {
+		return nil
+	}
+	return findTakenHosts(newIngressHostResource(ing), lbc.getHostOwners())
+}
+
+// getTakenHostsForVirtualServer returns the host and the server aliases of the VirtualServer that are taken by other resources.
+func (lbc *LoadBalancerController) getTakenHostsForVirtualServer(vs *conf_v1alpha1.VirtualServer) map[string]hostResource {
+	return findTakenHosts(newVirtualServerHostResource(vs), lbc.getHostOwners())
+}
+
+// getHostOwners returns the resource that wins each host. Finding the winners requires listing all Ingress and
+// VirtualServer resources, so during a sync the winners are found once and reused, so that the syncs that process
+// all resources, such as the sync of the ConfigMap, don't list all resources for each of them.
+func (lbc *LoadBalancerController) getHostOwners() map[string]hostResource {
+	lbc.hostOwnersMutex.Lock()
+	defer lbc.hostOwnersMutex.Unlock()
+
+	if lbc.hostOwners != nil {
+		return lbc.hostOwners
+	}
+
+	owners := findHostOwners(lbc.getHostResources())
+	if lbc.isHostOwnersCacheOn {
+		lbc.hostOwners = owners
+	}
+
+	return owners
+}
+
+// setHostOwnersCache turns the cache of the winners of the hosts on at the beginning of a sync and off at the end.
+// The cache is cleared in both cases, so that every sync sees the current winners.
+func (lbc *LoadBalancerController) setHostOwnersCache(on bool) {
+	lbc.hostOwnersMutex.Lock()
+	defer lbc.hostOwnersMutex.Unlock()
+
+	lbc.isHostOwnersCacheOn = on
+	lbc.hostOwners = nil
+}
+
+// enqueueResourcesForHosts enqueues the resources that declare any of the hosts, so that the winner of a host
+// is chosen again after a resource that declares the host is added, updated or deleted.
+func (lbc *LoadBalancerController) enqueueResourcesForHosts(hosts []string) {
+	if len(hosts) == 0 {
+		return
+	}
+
+	for _, r := range lbc.getHostResources() {
+		if anyHostsOverlap(getHostsForResource(r), hosts) {
+			lbc.syncQueue.Enqueue(r.obj)
+		}
+	}
+}
+
 func (lbc *LoadBalancerController) getVirtualServerRoutes() []*conf_v1alpha1.VirtualServerRoute {
 	var virtualServerRoutes []*conf_v1alpha1.VirtualServerRoute
 
@@ -1356,7 +2028,7 @@ func (lbc *LoadBalancerController) getVirtualServerRoutes() []*conf_v1alpha1.Vir
 
 		err := validation.ValidateVirtualServerRoute(vsr, lbc.isNginxPlus)
 		if err != nil {
-			glog.V(3).Infof("Skipping invalid VirtualServerRoute %s/%s: %v", vsr.Namespace, vsr.Name, err)
+			logging.V(3).Infof("Skipping invalid VirtualServerRoute %s/%s: %v", vsr.Namespace, vsr.Name, err)
 			continue
 		}
 
@@ -1408,12 +2080,31 @@ func (lbc *LoadBalancerController) getAndValidateSecret(secretKey string) (*api_
 
 	err = ValidateTLSSecret(secret)
 	if err != nil {
-		return nil, fmt.Errorf("error validating secret %v", secretKey)
+		return nil, fmt.Errorf("error validating secret %v: %v", secretKey, err)
+	}
+	return secret, nil
+}
+
+func (lbc *LoadBalancerController) getAndValidateHtpasswdSecret(secretKey string) (*api_v1.Secret, error) {
+	secretObject, secretExists, err := lbc.secretLister.GetByKey(secretKey)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving secret %v", secretKey)
+	}
+	if !secretExists {
+		return nil, fmt.Errorf("secret %v not found", secretKey)
+	}
+	secret := secretObject.(*api_v1.Secret)
+
+	err = ValidateHtpasswdSecret(secret)
+	if err != nil {
+		return nil, fmt.Errorf("error validating secret %v: %v", secretKey, err)
 	}
 	return secret, nil
 }
 
 func (lbc *LoadBalancerController) createIngress(ing *extensions.Ingress) (*configs.IngressEx, error) {
+	ing = removeTakenHosts(ing, lbc.getTakenHostsForIngress(ing))
+
 	ingEx := &configs.IngressEx{
 		Ingress: ing,
 	}
@@ -1424,7 +2115,7 @@ func (lbc *LoadBalancerController) createIngress(ing *extensions.Ingress) (*conf
 		secretKey := ing.Namespace + "/" + secretName
 		secret, err := lbc.getAndValidateSecret(secretKey)
 		if err != nil {
-			glog.Warningf("Error trying to get the secret %v for Ingress %v: %v", secretName, ing.Name, err)
+			logging.Warningf("Error trying to get the secret %v for Ingress %v: %v", secretName, ing.Name, err)
 			continue
 		}
 		ingEx.TLSSecrets[secretName] = secret
@@ -1436,12 +2127,12 @@ func (lbc *LoadBalancerController) createIngress(ing *extensions.Ingress) (*conf
 
 			secret, err := lbc.client.CoreV1().Secrets(ing.Namespace).Get(secretName, meta_v1.GetOptions{})
 			if err != nil {
-				glog.Warningf("Error retrieving secret %v for Ingress %v: %v", secretName, ing.Name, err)
+				logging.Warningf("Error retrieving secret %v for Ingress %v: %v", secretName, ing.Name, err)
 				secret = nil
 			} else {
 				err = ValidateJWKSecret(secret)
 				if err != nil {
-					glog.Warningf("Error validating secret %v for Ingress %v: %v", secretName, ing.Name, err)
+					logging.Warningf("Error validating secret %v for Ingress %v: %v", secretName, ing.Name, err)
 					secret = nil
 				}
 			}
@@ -1453,25 +2144,40 @@ func (lbc *LoadBalancerController) createIngress(ing *extensions.Ingress) (*conf
 		}
 	}
 
+	if basicAuthSecret, exists := ingEx.Ingress.Annotations[configs.BasicAuthSecretAnnotation]; exists {
+		secret, err := lbc.getAndValidateHtpasswdSecret(ing.Namespace + "/" + basicAuthSecret)
+		if err != nil {
+			logging.Warningf("Error trying to get the htpasswd secret %v for Ingress %v: %v", basicAuthSecret, ing.Name, err)
+		}
+
+		ingEx.BasicAuthSecret = configs.BasicAuthSecret{
+			Name:   basicAuthSecret,
+			Secret: secret,
+		}
+	}
+
 	ingEx.Endpoints = make(map[string][]string)
 	ingEx.HealthChecks = make(map[string]*api_v1.Probe)
 	ingEx.ExternalNameSvcs = make(map[string]bool)
+	ingEx.PodsByIP = make(map[string]string)
 
 	if ing.Spec.Backend != nil {
 		endps := []string{}
 		var external bool
 		svc, err := lbc.getServiceForIngressBackend(ing.Spec.Backend, ing.Namespace)
 		if err != nil {
-			glog.V(3).Infof("Error getting service %v: %v", ing.Spec.Backend.ServiceName, err)
+			logging.V(3).Infof("Error getting service %v: %v", ing.Spec.Backend.ServiceName, err)
 		} else {
 			endps, external, err = lbc.getEndpointsForIngressBackend(ing.Spec.Backend, svc)
 			if err == nil && external && lbc.isNginxPlus {
 				ingEx.ExternalNameSvcs[svc.Name] = true
+			} else if err == nil && lbc.isNginxPlus {
+				lbc.addPodsByIPForService(ingEx.PodsByIP, svc)
 			}
 		}
 
 		if err != nil {
-			glog.Warningf("Error retrieving endpoints for the service %v: %v", ing.Spec.Backend.ServiceName, err)
+			logging.Warningf("Error retrieving endpoints for the service %v: %v", ing.Spec.Backend.ServiceName, err)
 		}
 		// endps is empty if there was any error before this point
 		ingEx.Endpoints[ing.Spec.Backend.ServiceName+ing.Spec.Backend.ServicePort.String()] = endps
@@ -1499,16 +2205,18 @@ func (lbc *LoadBalancerController) createIngress(ing *extensions.Ingress) (*conf
 			var external bool
 			svc, err := lbc.getServiceForIngressBackend(&path.Backend, ing.Namespace)
 			if err != nil {
-				glog.V(3).Infof("Error getting service %v: %v", &path.Backend.ServiceName, err)
+				logging.V(3).Infof("Error getting service %v: %v", &path.Backend.ServiceName, err)
 			} else {
 				endps, external, err = lbc.getEndpointsForIngressBackend(&path.Backend, svc)
 				if err == nil && external && lbc.isNginxPlus {
 					ingEx.ExternalNameSvcs[svc.Name] = true
+				} else if err == nil && lbc.isNginxPlus {
+					lbc.addPodsByIPForService(ingEx.PodsByIP, svc)
 				}
 			}
 
 			if err != nil {
-				glog.Warningf("Error retrieving endpoints for the service %v: %v", path.Backend.ServiceName, err)
+				logging.Warningf("Error retrieving endpoints for the service %v: %v", path.Backend.ServiceName, err)
 			}
 			// endps is empty if there was any error before this point
 			ingEx.Endpoints[path.Backend.ServiceName+path.Backend.ServicePort.String()] = endps
@@ -1554,6 +2262,8 @@ func newVirtualServerRouteErrorFromVSR(virtualServerRoute *conf_v1alpha1.Virtual
 }
 
 func (lbc *LoadBalancerController) createVirtualServer(virtualServer *conf_v1alpha1.VirtualServer) (*configs.VirtualServerEx, []virtualServerRouteError) {
+	virtualServer = removeTakenServerAliases(virtualServer, lbc.getTakenHostsForVirtualServer(virtualServer))
+
 	virtualServerEx := configs.VirtualServerEx{
 		VirtualServer: virtualServer,
 	}
@@ -1562,7 +2272,7 @@ func (lbc *LoadBalancerController) createVirtualServer(virtualServer *conf_v1alp
 		secretKey := virtualServer.Namespace + "/" + virtualServer.Spec.TLS.Secret
 		secret, err := lbc.getAndValidateSecret(secretKey)
 		if err != nil {
-			glog.Warningf("Error trying to get the secret %v for VirtualServer %v: %v", secretKey, virtualServer.Name, err)
+			logging.Warningf("Error trying to get the secret %v for VirtualServer %v: %v", secretKey, virtualServer.Name, err)
 		} else {
 			virtualServerEx.TLSSecret = secret
 		}
@@ -1570,6 +2280,7 @@ func (lbc *LoadBalancerController) createVirtualServer(virtualServer *conf_v1alp
 
 	endpoints := make(map[string][]string)
 	externalNameSvcs := make(map[string]bool)
+	podsByIP := make(map[string]string)
 
 	for _, u := range virtualServer.Spec.Upstreams {
 		endpointsKey := configs.GenerateEndpointsKey(virtualServer.Namespace, u.Service, u.Subselector, u.Port)
@@ -1589,7 +2300,9 @@ func (lbc *LoadBalancerController) createVirtualServer(virtualServer *conf_v1alp
 		}
 
 		if err != nil {
-			glog.Warningf("Error getting Endpoints for Upstream %v: %v", u.Name, err)
+			logging.Warningf("Error getting Endpoints for Upstream %v: %v", u.Name, err)
+		} else if lbc.isNginxPlus {
+			lbc.addPodsByIPForUpstream(podsByIP, virtualServer.Namespace, u)
 		}
 
 		endpoints[endpointsKey] = endps
@@ -1613,22 +2326,22 @@ func (lbc *LoadBalancerController) createVirtualServer(virtualServer *conf_v1alp
 
 		obj, exists, err := lbc.virtualServerRouteLister.GetByKey(vsrKey)
 		if err != nil {
-			glog.Warningf("Failed to get VirtualServerRoute %s for VirtualServer %s/%s: %v", vsrKey, virtualServer.Namespace, virtualServer.Name, err)
+			logging.Warningf("Failed to get VirtualServerRoute %s for VirtualServer %s/%s: %v", vsrKey, virtualServer.Namespace, virtualServer.Name, err)
 			virtualServerRouteErrors = append(virtualServerRouteErrors, newVirtualServerRouteErrorFromNsName(vsrKey, err))
 			continue
 		}
 
 		if !exists {
-			glog.Warningf("VirtualServer %s/%s references VirtualServerRoute %s that doesn't exist", virtualServer.Name, virtualServer.Namespace, vsrKey)
+			logging.Warningf("VirtualServer %s/%s references VirtualServerRoute %s that doesn't exist", virtualServer.Name, virtualServer.Namespace, vsrKey)
 			virtualServerRouteErrors = append(virtualServerRouteErrors, newVirtualServerRouteErrorFromNsName(vsrKey, errors.New("VirtualServerRoute doesn't exist")))
 			continue
 		}
 
 		vsr := obj.(*conf_v1alpha1.VirtualServerRoute)
 
-		err = validation.ValidateVirtualServerRouteForVirtualServer(vsr, virtualServer.Spec.Host, r.Path, lbc.isNginxPlus)
+		err = validation.ValidateVirtualServerRouteForVirtualServer(vsr, getVirtualServerHosts(virtualServer), r.Path, lbc.isNginxPlus)
 		if err != nil {
-			glog.Warningf("VirtualServer %s/%s references invalid VirtualServerRoute %s: %v", virtualServer.Name, virtualServer.Namespace, vsrKey, err)
+			logging.Warningf("VirtualServer %s/%s references invalid VirtualServerRoute %s: %v", virtualServer.Name, virtualServer.Namespace, vsrKey, err)
 			virtualServerRouteErrors = append(virtualServerRouteErrors, newVirtualServerRouteErrorFromVSR(vsr, err))
 			continue
 		}
@@ -1651,19 +2364,91 @@ func (lbc *LoadBalancerController) createVirtualServer(virtualServer *conf_v1alp
 				}
 			}
 			if err != nil {
-				glog.Warningf("Error getting Endpoints for Upstream %v: %v", u.Name, err)
+				logging.Warningf("Error getting Endpoints for Upstream %v: %v", u.Name, err)
+			} else if lbc.isNginxPlus {
+				lbc.addPodsByIPForUpstream(podsByIP, vsr.Namespace, u)
 			}
 			endpoints[endpointsKey] = endps
 		}
 	}
 
+	htpasswdSecrets := make(map[string]*api_v1.Secret)
+	lbc.addHtpasswdSecretsForRoutes(htpasswdSecrets, virtualServer.Namespace, virtualServer.Spec.Routes)
+	for _, vsr := range virtualServerRoutes {
+		lbc.addHtpasswdSecretsForRoutes(htpasswdSecrets, vsr.Namespace, vsr.Spec.Subroutes)
+	}
+
 	virtualServerEx.Endpoints = endpoints
 	virtualServerEx.VirtualServerRoutes = virtualServerRoutes
 	virtualServerEx.ExternalNameSvcs = externalNameSvcs
+	virtualServerEx.PodsByIP = podsByIP
+	virtualServerEx.HtpasswdSecrets = htpasswdSecrets
 
 	return &virtualServerEx, virtualServerRouteErrors
 }
 
+// addHtpasswdSecretsForRoutes adds the htpasswd secrets referenced by the routes to htpasswdSecrets.
+// A missing or invalid secret is added with a nil value.
+func (lbc *LoadBalancerController) addHtpasswdSecretsForRoutes(htpasswdSecrets map[string]*api_v1.Secret, namespace string, routes []conf_v1alpha1.Route) {
+	for _, r := range routes {
+		if r.BasicAuth == nil {
+			continue
+		}
+
+		secretKey := namespace + "/" + r.BasicAuth.Secret
+		if _, exists := htpasswdSecrets[secretKey]; exists {
+			continue
+		}
+
+		secret, err := lbc.getAndValidateHtpasswdSecret(secretKey)
+		if err != nil {
+			logging.Warningf("Error trying to get the htpasswd secret %v: %v", secretKey, err)
+		}
+		htpasswdSecrets[secretKey] = secret
+	}
+}
+
+// addPodsByIPForUpstream adds the names of the pods behind the Endpoints of the Service of the upstream to podsByIP.
+func (lbc *LoadBalancerController) addPodsByIPForUpstream(podsByIP map[string]string, namespace string, upstream conf_v1alpha1.Upstream) {
+	svc, err := lbc.getServiceForUpstream(upstream, namespace)
+	if err != nil {
+		logging.V(3).Infof("Error getting service %v: %v", upstream.Service, err)
+		return
+	}
+	lbc.addPodsByIPForService(podsByIP, svc)
+}
+
+// addPodsByIPForService adds the names of the pods behind the Endpoints of the Service to podsByIP.
+// The keys of podsByIP are the addresses of the endpoints in the <ip>:<port> format.
+func (lbc *LoadBalancerController) addPodsByIPForService(podsByIP map[string]string, svc *api_v1.Service) {
+	endps, err := lbc.endpointLister.GetServiceEndpoints(svc)
+	if err != nil {
+		logging.V(3).Infof("Error getting endpoints for service %s from the cache: %v", svc.Name, err)
+		return
+	}
+
+	for address, pod := range getPodsByIPForEndpoints(endps) {
+		podsByIP[address] = pod
+	}
+}
+
+func getPodsByIPForEndpoints(endps api_v1.Endpoints) map[string]string {
+	podsByIP := make(map[string]string)
+
+	for _, subset := range endps.Subsets {
+		for _, port := range subset.Ports {
+			for _, address := range subset.Addresses {
+				if address.TargetRef == nil || address.TargetRef.Kind != "Pod" {
+					continue
+				}
+				podsByIP[fmt.Sprintf("%v:%v", address.IP, port.Port)] = address.TargetRef.Name
+			}
+		}
+	}
+
+	return podsByIP
+}
+
 func (lbc *LoadBalancerController) getEndpointsForUpstream(namespace string, upstream conf_v1alpha1.Upstream) (endps []string, isExternal bool, err error) {
 	svc, err := lbc.getServiceForUpstream(upstream, namespace)
 	if err != nil {
@@ -1721,7 +2506,7 @@ func (lbc *LoadBalancerController) getEndpointsForServiceWithSubselector(targetP
 
 	svcEps, err := lbc.endpointLister.GetServiceEndpoints(svc)
 	if err != nil {
-		glog.V(3).Infof("Error getting endpoints for service %s from the cache: %v", svc.Name, err)
+		logging.V(3).Infof("Error getting endpoints for service %s from the cache: %v", svc.Name, err)
 		return nil, err
 	}
 
@@ -1751,7 +2536,7 @@ func getEndpointsBySubselectedPods(targetPort int32, pods []*api_v1.Pod, svcEps
 func (lbc *LoadBalancerController) getHealthChecksForIngressBackend(backend *extensions.IngressBackend, namespace string) *api_v1.Probe {
 	svc, err := lbc.getServiceForIngressBackend(backend, namespace)
 	if err != nil {
-		glog.V(3).Infof("Error getting service %v: %v", backend.ServiceName, err)
+		logging.V(3).Infof("Error getting service %v: %v", backend.ServiceName, err)
 		return nil
 	}
 	svcPort := lbc.getServicePortForIngressPort(backend.ServicePort, svc)
@@ -1760,7 +2545,7 @@ func (lbc *LoadBalancerController) getHealthChecksForIngressBackend(backend *ext
 	}
 	pods, err := lbc.podLister.ListByNamespace(svc.Namespace, labels.Set(svc.Spec.Selector).AsSelector())
 	if err != nil {
-		glog.V(3).Infof("Error fetching pods for namespace %v: %v", svc.Namespace, err)
+		logging.V(3).Infof("Error fetching pods for namespace %v: %v", svc.Namespace, err)
 		return nil
 	}
 	return findProbeForPods(pods, svcPort)
@@ -1813,13 +2598,13 @@ func (lbc *LoadBalancerController) getEndpointsForIngressBackend(backend *extens
 			result = lbc.getExternalEndpointsForIngressBackend(backend, svc)
 			return result, true, nil
 		}
-		glog.V(3).Infof("Error getting endpoints for service %s from the cache: %v", svc.Name, err)
+		logging.V(3).Infof("Error getting endpoints for service %s from the cache: %v", svc.Name, err)
 		return nil, false, err
 	}
 
 	result, err = lbc.getEndpointsForPort(endps, backend.ServicePort, svc)
 	if err != nil {
-		glog.V(3).Infof("Error getting endpoints for service %s port %v: %v", svc.Name, backend.ServicePort, err)
+		logging.V(3).Infof("Error getting endpoints for service %s port %v: %v", svc.Name, backend.ServicePort, err)
 		return nil, false, err
 	}
 	return result, false, nil
@@ -1934,28 +2719,44 @@ func (lbc *LoadBalancerController) IsNginxIngress(ing *extensions.Ingress) bool
 func (lbc *LoadBalancerController) isHealthCheckEnabled(ing *extensions.Ingress) bool {
 	if healthCheckEnabled, exists, err := configs.GetMapKeyAsBool(ing.Annotations, "nginx.com/health-checks", ing); exists {
 		if err != nil {
-			glog.Error(err)
+			logging.Error(err)
 		}
 		return healthCheckEnabled
 	}
 	return false
 }
 
-// ValidateSecret validates that the secret follows the TLS Secret format.
+// ValidateSecret validates that the secret follows the TLS or the htpasswd Secret format.
 // For NGINX Plus, it also checks if the secret follows the JWK Secret format.
 func (lbc *LoadBalancerController) ValidateSecret(secret *api_v1.Secret) error {
-	err1 := ValidateTLSSecret(secret)
-	if !lbc.isNginxPlus {
-		return err1
+	if !lbc.isSupportedSecret(secret) {
+		if !lbc.isNginxPlus {
+			return fmt.Errorf("Secret is not a TLS or htpasswd secret")
+		}
+		return fmt.Errorf("Secret is not a TLS, JWK or htpasswd secret")
+	}
+
+	// we can safely ignore the error because the kind of the secret is supported
+	kind, _ := GetSecretKind(secret)
+	switch kind {
+	case JWK:
+		return ValidateJWKSecret(secret)
+	case Htpasswd:
+		return ValidateHtpasswdSecret(secret)
 	}
 
-	err2 := ValidateJWKSecret(secret)
+	return ValidateTLSSecret(secret)
+}
 
-	if err1 == nil || err2 == nil {
-		return nil
+// isSupportedSecret checks if the kind of the secret is supported: TLS and htpasswd, or also JWK for NGINX Plus.
+// The secret itself might be invalid.
+func (lbc *LoadBalancerController) isSupportedSecret(secret *api_v1.Secret) bool {
+	kind, err := GetSecretKind(secret)
+	if err != nil {
+		return false
 	}
 
-	return fmt.Errorf("Secret is not a TLS or JWK secret")
+	return kind == TLS || kind == Htpasswd || lbc.isNginxPlus
 }
 
 // getMinionsForHost returns a list of all minion ingress resources for a given master
@@ -1984,20 +2785,20 @@ func (lbc *LoadBalancerController) getMinionsForMaster(master *configs.IngressEx
 			continue
 		}
 		if len(ings.Items[i].Spec.Rules) != 1 {
-			glog.Errorf("Ingress Resource %v/%v with the 'nginx.org/mergeable-ingress-type' annotation must contain only one host", ings.Items[i].Namespace, ings.Items[i].Name)
+			logging.Errorf("Ingress Resource %v/%v with the 'nginx.org/mergeable-ingress-type' annotation must contain only one host", ings.Items[i].Namespace, ings.Items[i].Name)
 			continue
 		}
 		if ings.Items[i].Spec.Rules[0].HTTP == nil {
-			glog.Errorf("Ingress Resource %v/%v with the 'nginx.org/mergeable-ingress-type' annotation set to 'minion' must contain a Path", ings.Items[i].Namespace, ings.Items[i].Name)
+			logging.Errorf("Ingress Resource %v/%v with the 'nginx.org/mergeable-ingress-type' annotation set to 'minion' must contain a Path", ings.Items[i].Namespace, ings.Items[i].Name)
 			continue
 		}
 
 		uniquePaths := []extensions.HTTPIngressPath{}
 		for _, path := range ings.Items[i].Spec.Rules[0].HTTP.Paths {
 			if val, ok := minionPaths[path.Path]; ok {
-				glog.Errorf("Ingress Resource %v/%v with the 'nginx.org/mergeable-ingress-type' annotation set to 'minion' cannot contain the same path as another ingress resource, %v/%v.",
+				logging.Errorf("Ingress Resource %v/%v with the 'nginx.org/mergeable-ingress-type' annotation set to 'minion' cannot contain the same path as another ingress resource, %v/%v.",
 					ings.Items[i].Namespace, ings.Items[i].Name, val.Namespace, val.Name)
-				glog.Errorf("Path %s for Ingress Resource %v/%v will be ignored", path.Path, ings.Items[i].Namespace, ings.Items[i].Name)
+				logging.Errorf("Path %s for Ingress Resource %v/%v will be ignored", path.Path, ings.Items[i].Namespace, ings.Items[i].Name)
 			} else {
 				minionPaths[path.Path] = &ings.Items[i]
 				uniquePaths = append(uniquePaths, path)
@@ -2007,11 +2808,11 @@ func (lbc *LoadBalancerController) getMinionsForMaster(master *configs.IngressEx
 
 		ingEx, err := lbc.createIngress(&ings.Items[i])
 		if err != nil {
-			glog.Errorf("Error creating ingress resource %v/%v: %v", ings.Items[i].Namespace, ings.Items[i].Name, err)
+			logging.Errorf("Error creating ingress resource %v/%v: %v", ings.Items[i].Namespace, ings.Items[i].Name, err)
 			continue
 		}
 		if len(ingEx.TLSSecrets) > 0 {
-			glog.Errorf("Ingress Resource %v/%v with the 'nginx.org/mergeable-ingress-type' annotation set to 'minion' cannot contain TLS Secrets", ingEx.Ingress.Namespace, ingEx.Ingress.Name)
+			logging.Errorf("Ingress Resource %v/%v with the 'nginx.org/mergeable-ingress-type' annotation set to 'minion' cannot contain TLS Secrets", ingEx.Ingress.Namespace, ingEx.Ingress.Name)
 			continue
 		}
 		minions = append(minions, ingEx)
@@ -2065,6 +2866,13 @@ func (lbc *LoadBalancerController) createMergableIngresses(master *extensions.In
 		}
 	}
 
+	// a master with a taken host would lose its only rule in lbc.createIngress()
+	if takenHosts := lbc.getTakenHostsForIngress(master); len(takenHosts) > 0 {
+		lbc.recorder.Eventf(master, api_v1.EventTypeWarning, "Rejected", "%v/%v was rejected: %v", master.Namespace, master.Name, formatTakenHosts(takenHosts))
+		err := fmt.Errorf("Ingress Resource %v/%v with the 'nginx.org/mergeable-ingress-type' annotation set to 'master' was rejected: %v", master.Namespace, master.Name, formatTakenHosts(takenHosts))
+		return &mergeableIngresses, err
+	}
+
 	// Makes sure there is an empty path assigned to a master, to allow for lbc.createIngress() to pass
 	master.Spec.Rules[0].HTTP = &extensions.HTTPIngressRuleValue{
 		Paths: []extensions.HTTPIngressPath{},
@@ -2075,6 +2883,10 @@ func (lbc *LoadBalancerController) createMergableIngresses(master *extensions.In
 		err := fmt.Errorf("Error creating Ingress Resource %v/%v: %v", master.Namespace, master.Name, err)
 		return &mergeableIngresses, err
 	}
+	if len(masterIngEx.Ingress.Spec.Rules) == 0 {
+		err := fmt.Errorf("Ingress Resource %v/%v with the 'nginx.org/mergeable-ingress-type' annotation set to 'master' has no host", master.Namespace, master.Name)
+		return &mergeableIngresses, err
+	}
 	mergeableIngresses.Master = masterIngEx
 
 	minions, err := lbc.getMinionsForMaster(masterIngEx)
@@ -1,8 +1,12 @@
 package k8s
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 	"unsafe"
@@ -10,9 +14,11 @@ import (
 	"github.com/nginxinc/kubernetes-ingress/internal/configs"
 	"github.com/nginxinc/kubernetes-ingress/internal/configs/version1"
 	"github.com/nginxinc/kubernetes-ingress/internal/configs/version2"
+	"github.com/nginxinc/kubernetes-ingress/internal/logging"
 	"github.com/nginxinc/kubernetes-ingress/internal/metrics/collectors"
 	"github.com/nginxinc/kubernetes-ingress/internal/nginx"
 	conf_v1alpha1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
+	apps_v1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	extensions "k8s.io/api/extensions/v1beta1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -629,10 +635,8 @@ func getMergableDefaults() (cafeMaster, coffeeMinion, teaMinion extensions.Ingre
 	}
 
 	ingExMap := make(map[string]*configs.IngressEx)
-	cafeMasterIngEx, _ := lbc.createIngress(&cafeMaster)
-	ingExMap["default-cafe-master"] = cafeMasterIngEx
 
-	cnf := configs.NewConfigurator(&nginx.LocalManager{}, &configs.StaticConfigParams{}, &configs.ConfigParams{}, &version1.TemplateExecutor{}, &version2.TemplateExecutor{}, false, false)
+	cnf := configs.NewConfigurator(&nginx.LocalManager{}, &configs.StaticConfigParams{}, &configs.ConfigParams{}, &version1.TemplateExecutor{}, &version2.TemplateExecutor{}, false, false, false, collectors.NewFakeLabelUpdater(), nil)
 
 	// edit private field ingresses to use in testing
 	pointerVal := reflect.ValueOf(cnf)
@@ -657,6 +661,9 @@ func getMergableDefaults() (cafeMaster, coffeeMinion, teaMinion extensions.Ingre
 		cache.NewListWatchFromClient(lbc.client.ExtensionsV1beta1().RESTClient(), "ingresses", "default", fields.Everything()),
 		&extensions.Ingress{}, time.Duration(1), nil)
 
+	cafeMasterIngEx, _ := lbc.createIngress(&cafeMaster)
+	ingExMap["default-cafe-master"] = cafeMasterIngEx
+
 	return
 }
 
@@ -846,7 +853,7 @@ func TestFindProbeForPods(t *testing.T) {
 
 func TestGetServicePortForIngressPort(t *testing.T) {
 	fakeClient := fake.NewSimpleClientset()
-	cnf := configs.NewConfigurator(&nginx.LocalManager{}, &configs.StaticConfigParams{}, &configs.ConfigParams{}, &version1.TemplateExecutor{}, &version2.TemplateExecutor{}, false, false)
+	cnf := configs.NewConfigurator(&nginx.LocalManager{}, &configs.StaticConfigParams{}, &configs.ConfigParams{}, &version1.TemplateExecutor{}, &version2.TemplateExecutor{}, false, false, false, collectors.NewFakeLabelUpdater(), nil)
 	lbc := LoadBalancerController{
 		client:           fakeClient,
 		ingressClass:     "nginx",
@@ -996,14 +1003,14 @@ func TestFindIngressesForSecret(t *testing.T) {
 				t.Fatalf("templateExecutor could not start: %v", err)
 			}
 
-			templateExecutorV2, err := version2.NewTemplateExecutor("../configs/version2/nginx-plus.virtualserver.tmpl")
+			templateExecutorV2, err := version2.NewTemplateExecutor("../configs/version2/nginx-plus.virtualserver.tmpl", "../configs/version2/nginx.tlspassthrough.tmpl")
 			if err != nil {
 				t.Fatalf("templateExecutorV2 could not start: %v", err)
 			}
 
-			manager := nginx.NewFakeManager("/etc/nginx")
+			manager := nginx.NewFakeManager("/etc/nginx", "/etc/nginx/secrets")
 
-			cnf := configs.NewConfigurator(manager, &configs.StaticConfigParams{}, &configs.ConfigParams{}, templateExecutor, templateExecutorV2, false, false)
+			cnf := configs.NewConfigurator(manager, &configs.StaticConfigParams{}, &configs.ConfigParams{}, templateExecutor, templateExecutorV2, false, false, false, collectors.NewFakeLabelUpdater(), nil)
 			lbc := LoadBalancerController{
 				client:           fakeClient,
 				ingressClass:     "nginx",
@@ -1187,14 +1194,14 @@ func TestFindIngressesForSecretWithMinions(t *testing.T) {
 				t.Fatalf("templateExecutor could not start: %v", err)
 			}
 
-			templateExecutorV2, err := version2.NewTemplateExecutor("../configs/version2/nginx-plus.virtualserver.tmpl")
+			templateExecutorV2, err := version2.NewTemplateExecutor("../configs/version2/nginx-plus.virtualserver.tmpl", "../configs/version2/nginx.tlspassthrough.tmpl")
 			if err != nil {
 				t.Fatalf("templateExecutorV2 could not start: %v", err)
 			}
 
-			manager := nginx.NewFakeManager("/etc/nginx")
+			manager := nginx.NewFakeManager("/etc/nginx", "/etc/nginx/secrets")
 
-			cnf := configs.NewConfigurator(manager, &configs.StaticConfigParams{}, &configs.ConfigParams{}, templateExecutor, templateExecutorV2, false, false)
+			cnf := configs.NewConfigurator(manager, &configs.StaticConfigParams{}, &configs.ConfigParams{}, templateExecutor, templateExecutorV2, false, false, false, collectors.NewFakeLabelUpdater(), nil)
 			lbc := LoadBalancerController{
 				client:           fakeClient,
 				ingressClass:     "nginx",
@@ -1436,9 +1443,27 @@ func TestFindVirtualServersForSecret(t *testing.T) {
 		},
 	}
 
-	virtualServers := []*conf_v1alpha1.VirtualServer{&vs1, &vs2, &vs3, &vs4, &vs5}
+	vs6 := conf_v1alpha1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "vs-6",
+			Namespace: "ns-1",
+		},
+		Spec: conf_v1alpha1.VirtualServerSpec{
+			Routes: []conf_v1alpha1.Route{
+				{
+					Path:     "/",
+					Upstream: "test",
+					BasicAuth: &conf_v1alpha1.BasicAuth{
+						Secret: "test-secret",
+					},
+				},
+			},
+		},
+	}
+
+	virtualServers := []*conf_v1alpha1.VirtualServer{&vs1, &vs2, &vs3, &vs4, &vs5, &vs6}
 
-	expected := []*conf_v1alpha1.VirtualServer{&vs4}
+	expected := []*conf_v1alpha1.VirtualServer{&vs4, &vs6}
 
 	result := findVirtualServersForSecret(virtualServers, "ns-1", "test-secret")
 	if !reflect.DeepEqual(result, expected) {
@@ -1446,6 +1471,66 @@ func TestFindVirtualServersForSecret(t *testing.T) {
 	}
 }
 
+func TestFindVirtualServerRoutesForSecret(t *testing.T) {
+	vsr1 := conf_v1alpha1.VirtualServerRoute{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "vsr-1",
+			Namespace: "ns-1",
+		},
+		Spec: conf_v1alpha1.VirtualServerRouteSpec{
+			Subroutes: []conf_v1alpha1.Route{
+				{
+					Path:     "/",
+					Upstream: "test",
+				},
+			},
+		},
+	}
+	vsr2 := conf_v1alpha1.VirtualServerRoute{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "vsr-2",
+			Namespace: "ns-1",
+		},
+		Spec: conf_v1alpha1.VirtualServerRouteSpec{
+			Subroutes: []conf_v1alpha1.Route{
+				{
+					Path:     "/",
+					Upstream: "test",
+					BasicAuth: &conf_v1alpha1.BasicAuth{
+						Secret: "test-secret",
+					},
+				},
+			},
+		},
+	}
+	vsr3 := conf_v1alpha1.VirtualServerRoute{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "vsr-3",
+			Namespace: "ns-2",
+		},
+		Spec: conf_v1alpha1.VirtualServerRouteSpec{
+			Subroutes: []conf_v1alpha1.Route{
+				{
+					Path:     "/",
+					Upstream: "test",
+					BasicAuth: &conf_v1alpha1.BasicAuth{
+						Secret: "test-secret",
+					},
+				},
+			},
+		},
+	}
+
+	virtualServerRoutes := []*conf_v1alpha1.VirtualServerRoute{&vsr1, &vsr2, &vsr3}
+
+	expected := []*conf_v1alpha1.VirtualServerRoute{&vsr2}
+
+	result := findVirtualServerRoutesForSecret(virtualServerRoutes, "ns-1", "test-secret")
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("findVirtualServerRoutesForSecret returned %v but expected %v", result, expected)
+	}
+}
+
 func TestFindVirtualServersForVirtualServerRoute(t *testing.T) {
 	vs1 := conf_v1alpha1.VirtualServer{
 		ObjectMeta: meta_v1.ObjectMeta{
@@ -1572,3 +1657,293 @@ func TestGetEndpointsBySubselectedPods(t *testing.T) {
 		})
 	}
 }
+
+func TestGetPodsByIPForEndpoints(t *testing.T) {
+	endps := v1.Endpoints{
+		Subsets: []v1.EndpointSubset{
+			{
+				Addresses: []v1.EndpointAddress{
+					{
+						IP: "10.0.0.1",
+						TargetRef: &v1.ObjectReference{
+							Kind: "Pod",
+							Name: "coffee-1",
+						},
+					},
+					{
+						IP: "10.0.0.2",
+						TargetRef: &v1.ObjectReference{
+							Kind: "Pod",
+							Name: "coffee-2",
+						},
+					},
+					{
+						IP: "10.0.0.3",
+					},
+				},
+				Ports: []v1.EndpointPort{
+					{
+						Port: 80,
+					},
+				},
+			},
+		},
+	}
+
+	expected := map[string]string{
+		"10.0.0.1:80": "coffee-1",
+		"10.0.0.2:80": "coffee-2",
+	}
+
+	result := getPodsByIPForEndpoints(endps)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("getPodsByIPForEndpoints() returned %v but expected %v", result, expected)
+	}
+}
+
+func createTestControllerPod(name string, ownerKind string, ownerName string, phase v1.PodPhase, isDeleted bool) *v1.Pod {
+	isController := true
+	pod := &v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      name,
+			Namespace: "nginx-ingress",
+			Labels: map[string]string{
+				"app":               "nginx-ingress",
+				"pod-template-hash": ownerName,
+			},
+			OwnerReferences: []meta_v1.OwnerReference{
+				{
+					Kind:       ownerKind,
+					Name:       ownerName,
+					Controller: &isController,
+				},
+			},
+		},
+		Status: v1.PodStatus{
+			Phase: phase,
+		},
+	}
+	if isDeleted {
+		now := meta_v1.Now()
+		pod.DeletionTimestamp = &now
+	}
+	return pod
+}
+
+func createTestControllerReplicaSet(name string) *apps_v1.ReplicaSet {
+	isController := true
+	return &apps_v1.ReplicaSet{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      name,
+			Namespace: "nginx-ingress",
+			OwnerReferences: []meta_v1.OwnerReference{
+				{
+					Kind:       "Deployment",
+					Name:       "nginx-ingress",
+					Controller: &isController,
+				},
+			},
+		},
+		Spec: apps_v1.ReplicaSetSpec{
+			Selector: &meta_v1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app":               "nginx-ingress",
+					"pod-template-hash": name,
+				},
+			},
+		},
+	}
+}
+
+func TestIsLastReplica(t *testing.T) {
+	podName := os.Getenv("POD_NAME")
+	defer os.Setenv("POD_NAME", podName)
+	os.Setenv("POD_NAME", "nginx-ingress-1")
+
+	selector := &meta_v1.LabelSelector{
+		MatchLabels: map[string]string{
+			"app": "nginx-ingress",
+		},
+	}
+	deployment := &apps_v1.Deployment{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "nginx-ingress",
+			Namespace: "nginx-ingress",
+		},
+		Spec: apps_v1.DeploymentSpec{
+			Selector: selector,
+		},
+	}
+	daemonSet := &apps_v1.DaemonSet{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "nginx-ingress-ds",
+			Namespace: "nginx-ingress",
+		},
+		Spec: apps_v1.DaemonSetSpec{
+			Selector: selector,
+		},
+	}
+
+	otherApp := createTestControllerPod("other-app", "ReplicaSet", "other-app", v1.PodRunning, false)
+	otherApp.Labels = map[string]string{"app": "other-app"}
+
+	podWithoutController := createTestControllerPod("nginx-ingress-1", "", "", v1.PodRunning, true)
+	podWithoutController.OwnerReferences = nil
+
+	tests := []struct {
+		pods     []*v1.Pod
+		expected bool
+		msg      string
+	}{
+		{
+			pods: []*v1.Pod{
+				createTestControllerPod("nginx-ingress-1", "ReplicaSet", "nginx-ingress-new", v1.PodRunning, true),
+			},
+			expected: true,
+			msg:      "single replica",
+		},
+		{
+			pods: []*v1.Pod{
+				createTestControllerPod("nginx-ingress-1", "ReplicaSet", "nginx-ingress-new", v1.PodRunning, true),
+				createTestControllerPod("nginx-ingress-2", "ReplicaSet", "nginx-ingress-new", v1.PodRunning, false),
+			},
+			expected: false,
+			msg:      "another running replica",
+		},
+		{
+			pods: []*v1.Pod{
+				createTestControllerPod("nginx-ingress-1", "ReplicaSet", "nginx-ingress-old", v1.PodRunning, true),
+				createTestControllerPod("nginx-ingress-2", "ReplicaSet", "nginx-ingress-new", v1.PodRunning, false),
+			},
+			expected: false,
+			msg:      "another running replica of a different ReplicaSet during a rolling update",
+		},
+		{
+			pods: []*v1.Pod{
+				createTestControllerPod("nginx-ingress-1", "ReplicaSet", "nginx-ingress-new", v1.PodRunning, true),
+				createTestControllerPod("nginx-ingress-2", "ReplicaSet", "nginx-ingress-new", v1.PodRunning, true),
+				createTestControllerPod("nginx-ingress-3", "ReplicaSet", "nginx-ingress-old", v1.PodPending, false),
+				otherApp,
+			},
+			expected: true,
+			msg:      "other replicas are being deleted or not running",
+		},
+		{
+			pods: []*v1.Pod{
+				createTestControllerPod("nginx-ingress-1", "DaemonSet", "nginx-ingress-ds", v1.PodRunning, true),
+				createTestControllerPod("nginx-ingress-2", "DaemonSet", "nginx-ingress-ds", v1.PodRunning, false),
+			},
+			expected: false,
+			msg:      "another running replica of a DaemonSet",
+		},
+		{
+			pods: []*v1.Pod{
+				podWithoutController,
+				createTestControllerPod("nginx-ingress-2", "ReplicaSet", "nginx-ingress-new", v1.PodRunning, false),
+			},
+			expected: true,
+			msg:      "pod without a controller",
+		},
+	}
+
+	for _, test := range tests {
+		client := fake.NewSimpleClientset(deployment, daemonSet,
+			createTestControllerReplicaSet("nginx-ingress-new"), createTestControllerReplicaSet("nginx-ingress-old"))
+		for _, pod := range test.pods {
+			_, err := client.CoreV1().Pods(pod.Namespace).Create(pod)
+			if err != nil {
+				t.Fatalf("Failed to create a pod: %v", err)
+			}
+		}
+
+		lbc := LoadBalancerController{
+			client:              client,
+			controllerNamespace: "nginx-ingress",
+		}
+
+		result, err := lbc.isLastReplica()
+		if err != nil {
+			t.Errorf("isLastReplica() returned unexpected error for the case of %s: %v", test.msg, err)
+		}
+		if result != test.expected {
+			t.Errorf("isLastReplica() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
+	}
+}
+
+func TestUpdateReadiness(t *testing.T) {
+	lbc := LoadBalancerController{
+		configurator: configs.NewConfigurator(nginx.NewFakeManager("/etc/nginx", "/etc/nginx/secrets"), &configs.StaticConfigParams{}, &configs.ConfigParams{}, &version1.TemplateExecutor{}, &version2.TemplateExecutor{}, false, false, false, collectors.NewFakeLabelUpdater(), nil),
+	}
+
+	lbc.updateReadiness()
+	if lbc.IsReady() {
+		t.Errorf("IsReady() returned true before the initial sync")
+	}
+
+	lbc.isInitialSyncDone = true
+	lbc.updateReadiness()
+	if !lbc.IsReady() {
+		t.Errorf("IsReady() returned false after the initial sync")
+	}
+
+	lbc.MarkShuttingDown()
+	if lbc.IsReady() {
+		t.Errorf("IsReady() returned true after the controller started shutting down")
+	}
+}
+
+func TestLogSync(t *testing.T) {
+	verbosity := logging.Verbosity()
+	defer logging.SetVerbosity(verbosity)
+	if err := logging.SetVerbosity(0); err != nil {
+		t.Fatalf("SetVerbosity() returned an unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	logger, err := logging.NewLogger(logging.FormatLogfmt, &buf)
+	if err != nil {
+		t.Fatalf("NewLogger() returned an unexpected error: %v", err)
+	}
+
+	lbc := LoadBalancerController{
+		logger:    logger,
+		syncQueue: newTaskQueue(func(task) {}, func(task, error) {}),
+	}
+	defer lbc.syncQueue.queue.ShutDown()
+
+	vsTask := task{Kind: virtualserver, Key: "default/cafe"}
+
+	lbc.logSync(vsTask, "1", time.Millisecond)
+	if buf.Len() != 0 {
+		t.Errorf("logSync() logged %q for a successful sync with the verbosity 0, expected nothing", buf.String())
+	}
+
+	if err := logging.SetVerbosity(1); err != nil {
+		t.Fatalf("SetVerbosity() returned an unexpected error: %v", err)
+	}
+	lbc.logSync(vsTask, "1", time.Millisecond)
+	if !strings.Contains(buf.String(), `level=info msg="Synced resource" kind=virtualserver namespace=default name=cafe resourceVersion=1 duration=1ms outcome=synced`) {
+		t.Errorf("logSync() logged %q for a successful sync with the verbosity 1", buf.String())
+	}
+
+	if err := logging.SetVerbosity(0); err != nil {
+		t.Fatalf("SetVerbosity() returned an unexpected error: %v", err)
+	}
+
+	buf.Reset()
+	lbc.syncQueue.Requeue(vsTask, errors.New("invalid config"))
+	lbc.logSync(vsTask, "1", time.Millisecond)
+	if !strings.Contains(buf.String(), `level=warning msg="Failed to sync resource"`) || !strings.Contains(buf.String(), "outcome=requeued") {
+		t.Errorf("logSync() logged %q for a requeued sync, expected a warning", buf.String())
+	}
+
+	buf.Reset()
+	for i := 0; i < queueMaxRetries; i++ {
+		lbc.syncQueue.Requeue(vsTask, errors.New("invalid config"))
+	}
+	lbc.logSync(vsTask, "1", time.Millisecond)
+	if !strings.Contains(buf.String(), `level=error msg="Failed to sync resource"`) || !strings.Contains(buf.String(), "outcome=parked") {
+		t.Errorf("logSync() logged %q for a parked sync, expected an error", buf.String())
+	}
+}
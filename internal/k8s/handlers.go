@@ -4,7 +4,7 @@ import (
 	"reflect"
 	"sort"
 
-	"github.com/golang/glog"
+	"github.com/nginxinc/kubernetes-ingress/internal/logging"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
 	"k8s.io/client-go/tools/cache"
@@ -18,7 +18,7 @@ func createConfigMapHandlers(lbc *LoadBalancerController, name string) cache.Res
 		AddFunc: func(obj interface{}) {
 			configMap := obj.(*v1.ConfigMap)
 			if configMap.Name == name {
-				glog.V(3).Infof("Adding ConfigMap: %v", configMap.Name)
+				logging.V(3).Infof("Adding ConfigMap: %v", configMap.Name)
 				lbc.AddSyncQueue(obj)
 			}
 		},
@@ -27,25 +27,25 @@ func createConfigMapHandlers(lbc *LoadBalancerController, name string) cache.Res
 			if !isConfigMap {
 				deletedState, ok := obj.(cache.DeletedFinalStateUnknown)
 				if !ok {
-					glog.V(3).Infof("Error received unexpected object: %v", obj)
+					logging.V(3).Infof("Error received unexpected object: %v", obj)
 					return
 				}
 				configMap, ok = deletedState.Obj.(*v1.ConfigMap)
 				if !ok {
-					glog.V(3).Infof("Error DeletedFinalStateUnknown contained non-ConfigMap object: %v", deletedState.Obj)
+					logging.V(3).Infof("Error DeletedFinalStateUnknown contained non-ConfigMap object: %v", deletedState.Obj)
 					return
 				}
 			}
 			if configMap.Name == name {
-				glog.V(3).Infof("Removing ConfigMap: %v", configMap.Name)
-				lbc.AddSyncQueue(obj)
+				logging.V(3).Infof("Removing ConfigMap: %v", configMap.Name)
+				lbc.AddSyncQueueForDeleted(obj)
 			}
 		},
 		UpdateFunc: func(old, cur interface{}) {
 			if !reflect.DeepEqual(old, cur) {
 				configMap := cur.(*v1.ConfigMap)
 				if configMap.Name == name {
-					glog.V(3).Infof("ConfigMap %v changed, syncing", cur.(*v1.ConfigMap).Name)
+					logging.V(3).Infof("ConfigMap %v changed, syncing", cur.(*v1.ConfigMap).Name)
 					lbc.AddSyncQueue(cur)
 				}
 			}
@@ -58,7 +58,7 @@ func createEndpointHandlers(lbc *LoadBalancerController) cache.ResourceEventHand
 	return cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
 			endpoint := obj.(*v1.Endpoints)
-			glog.V(3).Infof("Adding endpoints: %v", endpoint.Name)
+			logging.V(3).Infof("Adding endpoints: %v", endpoint.Name)
 			lbc.AddSyncQueue(obj)
 		},
 		DeleteFunc: func(obj interface{}) {
@@ -66,21 +66,21 @@ func createEndpointHandlers(lbc *LoadBalancerController) cache.ResourceEventHand
 			if !isEndpoint {
 				deletedState, ok := obj.(cache.DeletedFinalStateUnknown)
 				if !ok {
-					glog.V(3).Infof("Error received unexpected object: %v", obj)
+					logging.V(3).Infof("Error received unexpected object: %v", obj)
 					return
 				}
 				endpoint, ok = deletedState.Obj.(*v1.Endpoints)
 				if !ok {
-					glog.V(3).Infof("Error DeletedFinalStateUnknown contained non-Endpoints object: %v", deletedState.Obj)
+					logging.V(3).Infof("Error DeletedFinalStateUnknown contained non-Endpoints object: %v", deletedState.Obj)
 					return
 				}
 			}
-			glog.V(3).Infof("Removing endpoints: %v", endpoint.Name)
-			lbc.AddSyncQueue(obj)
+			logging.V(3).Infof("Removing endpoints: %v", endpoint.Name)
+			lbc.AddSyncQueueForDeleted(obj)
 		},
 		UpdateFunc: func(old, cur interface{}) {
 			if !reflect.DeepEqual(old, cur) {
-				glog.V(3).Infof("Endpoints %v changed, syncing", cur.(*v1.Endpoints).Name)
+				logging.V(3).Infof("Endpoints %v changed, syncing", cur.(*v1.Endpoints).Name)
 				lbc.AddSyncQueue(cur)
 			}
 		},
@@ -93,23 +93,26 @@ func createIngressHandlers(lbc *LoadBalancerController) cache.ResourceEventHandl
 		AddFunc: func(obj interface{}) {
 			ingress := obj.(*v1beta1.Ingress)
 			if !lbc.IsNginxIngress(ingress) {
-				glog.Infof("Ignoring Ingress %v based on Annotation %v", ingress.Name, ingressClassKey)
+				logging.Infof("Ignoring Ingress %v based on Annotation %v", ingress.Name, ingressClassKey)
 				return
 			}
-			glog.V(3).Infof("Adding Ingress: %v", ingress.Name)
+			logging.V(3).Infof("Adding Ingress: %v", ingress.Name)
 			lbc.AddSyncQueue(obj)
+			if !isMinion(ingress) {
+				lbc.enqueueResourcesForHosts(getIngressHosts(ingress))
+			}
 		},
 		DeleteFunc: func(obj interface{}) {
 			ingress, isIng := obj.(*v1beta1.Ingress)
 			if !isIng {
 				deletedState, ok := obj.(cache.DeletedFinalStateUnknown)
 				if !ok {
-					glog.V(3).Infof("Error received unexpected object: %v", obj)
+					logging.V(3).Infof("Error received unexpected object: %v", obj)
 					return
 				}
 				ingress, ok = deletedState.Obj.(*v1beta1.Ingress)
 				if !ok {
-					glog.V(3).Infof("Error DeletedFinalStateUnknown contained non-Ingress object: %v", deletedState.Obj)
+					logging.V(3).Infof("Error DeletedFinalStateUnknown contained non-Ingress object: %v", deletedState.Obj)
 					return
 				}
 			}
@@ -119,14 +122,15 @@ func createIngressHandlers(lbc *LoadBalancerController) cache.ResourceEventHandl
 			if isMinion(ingress) {
 				master, err := lbc.FindMasterForMinion(ingress)
 				if err != nil {
-					glog.Infof("Ignoring Ingress %v(Minion): %v", ingress.Name, err)
+					logging.Infof("Ignoring Ingress %v(Minion): %v", ingress.Name, err)
 					return
 				}
-				glog.V(3).Infof("Removing Ingress: %v(Minion) for %v(Master)", ingress.Name, master.Name)
+				logging.V(3).Infof("Removing Ingress: %v(Minion) for %v(Master)", ingress.Name, master.Name)
 				lbc.AddSyncQueue(master)
 			} else {
-				glog.V(3).Infof("Removing Ingress: %v", ingress.Name)
-				lbc.AddSyncQueue(obj)
+				logging.V(3).Infof("Removing Ingress: %v", ingress.Name)
+				lbc.AddSyncQueueForDeleted(obj)
+				lbc.enqueueResourcesForHosts(getIngressHosts(ingress))
 			}
 		},
 		UpdateFunc: func(old, current interface{}) {
@@ -135,10 +139,15 @@ func createIngressHandlers(lbc *LoadBalancerController) cache.ResourceEventHandl
 			if !lbc.IsNginxIngress(c) {
 				return
 			}
+			oldHosts := getIngressHosts(o)
+			curHosts := getIngressHosts(c)
 			if hasChanges(o, c) {
-				glog.V(3).Infof("Ingress %v changed, syncing", c.Name)
+				logging.V(3).Infof("Ingress %v changed, syncing", c.Name)
 				lbc.AddSyncQueue(c)
 			}
+			if !reflect.DeepEqual(oldHosts, curHosts) {
+				lbc.enqueueResourcesForHosts(append(oldHosts, curHosts...))
+			}
 		},
 	}
 }
@@ -148,10 +157,11 @@ func createSecretHandlers(lbc *LoadBalancerController) cache.ResourceEventHandle
 	return cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
 			secret := obj.(*v1.Secret)
-			if err := lbc.ValidateSecret(secret); err != nil {
+			// invalid secrets of a supported kind are synced too, so that they are rejected with an event
+			if !lbc.isSupportedSecret(secret) {
 				return
 			}
-			glog.V(3).Infof("Adding Secret: %v", secret.Name)
+			logging.V(3).Infof("Adding Secret: %v", secret.Name)
 			lbc.AddSyncQueue(obj)
 		},
 		DeleteFunc: func(obj interface{}) {
@@ -159,31 +169,29 @@ func createSecretHandlers(lbc *LoadBalancerController) cache.ResourceEventHandle
 			if !isSecr {
 				deletedState, ok := obj.(cache.DeletedFinalStateUnknown)
 				if !ok {
-					glog.V(3).Infof("Error received unexpected object: %v", obj)
+					logging.V(3).Infof("Error received unexpected object: %v", obj)
 					return
 				}
 				secret, ok = deletedState.Obj.(*v1.Secret)
 				if !ok {
-					glog.V(3).Infof("Error DeletedFinalStateUnknown contained non-Secret object: %v", deletedState.Obj)
+					logging.V(3).Infof("Error DeletedFinalStateUnknown contained non-Secret object: %v", deletedState.Obj)
 					return
 				}
 			}
-			if err := lbc.ValidateSecret(secret); err != nil {
+			if !lbc.isSupportedSecret(secret) {
 				return
 			}
 
-			glog.V(3).Infof("Removing Secret: %v", secret.Name)
-			lbc.AddSyncQueue(obj)
+			logging.V(3).Infof("Removing Secret: %v", secret.Name)
+			lbc.AddSyncQueueForDeleted(obj)
 		},
 		UpdateFunc: func(old, cur interface{}) {
-			errOld := lbc.ValidateSecret(old.(*v1.Secret))
-			errCur := lbc.ValidateSecret(cur.(*v1.Secret))
-			if errOld != nil && errCur != nil {
+			if !lbc.isSupportedSecret(old.(*v1.Secret)) && !lbc.isSupportedSecret(cur.(*v1.Secret)) {
 				return
 			}
 
 			if !reflect.DeepEqual(old, cur) {
-				glog.V(3).Infof("Secret %v changed, syncing", cur.(*v1.Secret).Name)
+				logging.V(3).Infof("Secret %v changed, syncing", cur.(*v1.Secret).Name)
 				lbc.AddSyncQueue(cur)
 			}
 		},
@@ -199,7 +207,7 @@ func createServiceHandlers(lbc *LoadBalancerController) cache.ResourceEventHandl
 				lbc.AddSyncQueue(svc)
 				return
 			}
-			glog.V(3).Infof("Adding service: %v", svc.Name)
+			logging.V(3).Infof("Adding service: %v", svc.Name)
 			lbc.EnqueueIngressForService(svc)
 
 			if lbc.areCustomResourcesEnabled {
@@ -211,21 +219,21 @@ func createServiceHandlers(lbc *LoadBalancerController) cache.ResourceEventHandl
 			if !isSvc {
 				deletedState, ok := obj.(cache.DeletedFinalStateUnknown)
 				if !ok {
-					glog.V(3).Infof("Error received unexpected object: %v", obj)
+					logging.V(3).Infof("Error received unexpected object: %v", obj)
 					return
 				}
 				svc, ok = deletedState.Obj.(*v1.Service)
 				if !ok {
-					glog.V(3).Infof("Error DeletedFinalStateUnknown contained non-Service object: %v", deletedState.Obj)
+					logging.V(3).Infof("Error DeletedFinalStateUnknown contained non-Service object: %v", deletedState.Obj)
 					return
 				}
 			}
 			if lbc.IsExternalServiceForStatus(svc) {
-				lbc.AddSyncQueue(svc)
+				lbc.AddSyncQueueForDeleted(svc)
 				return
 			}
 
-			glog.V(3).Infof("Removing service: %v", svc.Name)
+			logging.V(3).Infof("Removing service: %v", svc.Name)
 			lbc.EnqueueIngressForService(svc)
 
 			if lbc.areCustomResourcesEnabled {
@@ -242,7 +250,7 @@ func createServiceHandlers(lbc *LoadBalancerController) cache.ResourceEventHandl
 				}
 				oldSvc := old.(*v1.Service)
 				if hasServiceChanges(oldSvc, curSvc) {
-					glog.V(3).Infof("Service %v changed, syncing", curSvc.Name)
+					logging.V(3).Infof("Service %v changed, syncing", curSvc.Name)
 					lbc.EnqueueIngressForService(curSvc)
 
 					if lbc.areCustomResourcesEnabled {
@@ -309,32 +317,40 @@ func createVirtualServerHandlers(lbc *LoadBalancerController) cache.ResourceEven
 	return cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
 			vs := obj.(*conf_v1alpha1.VirtualServer)
-			glog.V(3).Infof("Adding VirtualServer: %v", vs.Name)
+			logging.V(3).Infof("Adding VirtualServer: %v", vs.Name)
 			lbc.AddSyncQueue(vs)
+			lbc.enqueueResourcesForHosts(getVirtualServerHosts(vs))
 		},
 		DeleteFunc: func(obj interface{}) {
 			vs, isVs := obj.(*conf_v1alpha1.VirtualServer)
 			if !isVs {
 				deletedState, ok := obj.(cache.DeletedFinalStateUnknown)
 				if !ok {
-					glog.V(3).Infof("Error received unexpected object: %v", obj)
+					logging.V(3).Infof("Error received unexpected object: %v", obj)
 					return
 				}
 				vs, ok = deletedState.Obj.(*conf_v1alpha1.VirtualServer)
 				if !ok {
-					glog.V(3).Infof("Error DeletedFinalStateUnknown contained non-VirtualServer object: %v", deletedState.Obj)
+					logging.V(3).Infof("Error DeletedFinalStateUnknown contained non-VirtualServer object: %v", deletedState.Obj)
 					return
 				}
 			}
-			glog.V(3).Infof("Removing VirtualServer: %v", vs.Name)
-			lbc.AddSyncQueue(vs)
+			logging.V(3).Infof("Removing VirtualServer: %v", vs.Name)
+			lbc.AddSyncQueueForDeleted(vs)
+			lbc.enqueueResourcesForHosts(getVirtualServerHosts(vs))
 		},
 		UpdateFunc: func(old, cur interface{}) {
 			curVs := cur.(*conf_v1alpha1.VirtualServer)
+			oldVs := old.(*conf_v1alpha1.VirtualServer)
 			if !reflect.DeepEqual(old, cur) {
-				glog.V(3).Infof("VirtualServer %v changed, syncing", curVs.Name)
+				logging.V(3).Infof("VirtualServer %v changed, syncing", curVs.Name)
 				lbc.AddSyncQueue(curVs)
 			}
+			oldHosts := getVirtualServerHosts(oldVs)
+			curHosts := getVirtualServerHosts(curVs)
+			if !reflect.DeepEqual(oldHosts, curHosts) {
+				lbc.enqueueResourcesForHosts(append(oldHosts, curHosts...))
+			}
 		},
 	}
 }
@@ -343,7 +359,7 @@ func createVirtualServerRouteHandlers(lbc *LoadBalancerController) cache.Resourc
 	return cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
 			vsr := obj.(*conf_v1alpha1.VirtualServerRoute)
-			glog.V(3).Infof("Adding VirtualServerRoute: %v", vsr.Name)
+			logging.V(3).Infof("Adding VirtualServerRoute: %v", vsr.Name)
 			lbc.AddSyncQueue(vsr)
 		},
 		DeleteFunc: func(obj interface{}) {
@@ -351,22 +367,22 @@ func createVirtualServerRouteHandlers(lbc *LoadBalancerController) cache.Resourc
 			if !isVsr {
 				deletedState, ok := obj.(cache.DeletedFinalStateUnknown)
 				if !ok {
-					glog.V(3).Infof("Error received unexpected object: %v", obj)
+					logging.V(3).Infof("Error received unexpected object: %v", obj)
 					return
 				}
 				vsr, ok = deletedState.Obj.(*conf_v1alpha1.VirtualServerRoute)
 				if !ok {
-					glog.V(3).Infof("Error DeletedFinalStateUnknown contained non-VirtualServerRoute object: %v", deletedState.Obj)
+					logging.V(3).Infof("Error DeletedFinalStateUnknown contained non-VirtualServerRoute object: %v", deletedState.Obj)
 					return
 				}
 			}
-			glog.V(3).Infof("Removing VirtualServerRoute: %v", vsr.Name)
-			lbc.AddSyncQueue(vsr)
+			logging.V(3).Infof("Removing VirtualServerRoute: %v", vsr.Name)
+			lbc.AddSyncQueueForDeleted(vsr)
 		},
 		UpdateFunc: func(old, cur interface{}) {
 			curVsr := cur.(*conf_v1alpha1.VirtualServerRoute)
 			if !reflect.DeepEqual(old, cur) {
-				glog.V(3).Infof("VirtualServerRoute %v changed, syncing", curVsr.Name)
+				logging.V(3).Infof("VirtualServerRoute %v changed, syncing", curVsr.Name)
 				lbc.AddSyncQueue(curVsr)
 			}
 		},
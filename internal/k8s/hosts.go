@@ -0,0 +1,247 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/api/extensions/v1beta1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	conf_v1alpha1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
+	"github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/validation"
+)
+
+const (
+	ingressKind       = "Ingress"
+	virtualServerKind = "VirtualServer"
+)
+
+// hostResource is a resource that declares hosts: an Ingress or a VirtualServer.
+type hostResource struct {
+	kind string
+	meta *meta_v1.ObjectMeta
+	obj  interface{}
+}
+
+func newIngressHostResource(ing *v1beta1.Ingress) hostResource {
+	return hostResource{
+		kind: ingressKind,
+		meta: &ing.ObjectMeta,
+		obj:  ing,
+	}
+}
+
+func newVirtualServerHostResource(vs *conf_v1alpha1.VirtualServer) hostResource {
+	return hostResource{
+		kind: virtualServerKind,
+		meta: &vs.ObjectMeta,
+		obj:  vs,
+	}
+}
+
+func (r hostResource) String() string {
+	return fmt.Sprintf("%v %v/%v", r.kind, r.meta.Namespace, r.meta.Name)
+}
+
+// isSame checks if the two hostResources refer to the same resource.
+func (r hostResource) isSame(other hostResource) bool {
+	return r.kind == other.kind && r.meta.Namespace == other.meta.Namespace && r.meta.Name == other.meta.Name
+}
+
+// takesPrecedenceOver checks if the resource wins a host declared by both resources.
+// The resource with the oldest creationTimestamp wins. Resources with the same creationTimestamp are compared by
+// kind, namespace and name, so that the winner is always the same.
+func (r hostResource) takesPrecedenceOver(other hostResource) bool {
+	if !r.meta.CreationTimestamp.Equal(&other.meta.CreationTimestamp) {
+		return r.meta.CreationTimestamp.Before(&other.meta.CreationTimestamp)
+	}
+	if r.kind != other.kind {
+		return r.kind < other.kind
+	}
+	if r.meta.Namespace != other.meta.Namespace {
+		return r.meta.Namespace < other.meta.Namespace
+	}
+	return r.meta.Name < other.meta.Name
+}
+
+// getIngressHosts returns the hosts of the rules of the Ingress.
+func getIngressHosts(ing *v1beta1.Ingress) []string {
+	var hosts []string
+	seen := make(map[string]bool)
+
+	for _, rule := range ing.Spec.Rules {
+		if rule.Host == "" || seen[rule.Host] {
+			continue
+		}
+		seen[rule.Host] = true
+		hosts = append(hosts, rule.Host)
+	}
+
+	return hosts
+}
+
+// findHostOwners returns the resource that wins each host declared by the resources.
+// A host is contested by the resources that declare it and by the resources that declare a wildcard host
+// that matches it. For example, if the resource with the wildcard host *.example.com takes precedence over
+// the resource with the host cafe.example.com, it wins cafe.example.com. Otherwise, each resource wins its host,
+// and NGINX routes the requests for cafe.example.com to the resource with that host, because NGINX prefers
+// an exact server name to a wildcard one.
+func findHostOwners(resources []hostResource) map[string]hostResource {
+	owners := make(map[string]hostResource)
+	var wildcards []string
+	wildcardOwners := make(map[string]hostResource)
+
+	for _, r := range resources {
+		for _, host := range getHostsForResource(r) {
+			owner, exists := owners[host]
+			if !exists || r.takesPrecedenceOver(owner) {
+				owners[host] = r
+			}
+			if isWildcardHost(host) {
+				if _, exists := wildcardOwners[host]; !exists {
+					wildcards = append(wildcards, host)
+				}
+				wildcardOwners[host] = owners[host]
+			}
+		}
+	}
+
+	for host, owner := range owners {
+		for _, wildcard := range wildcards {
+			if wildcard == host || !validation.HostMatches(wildcard, host) {
+				continue
+			}
+			if wildcardOwner := wildcardOwners[wildcard]; wildcardOwner.takesPrecedenceOver(owner) {
+				owner = wildcardOwner
+			}
+		}
+		owners[host] = owner
+	}
+
+	return owners
+}
+
+func isWildcardHost(host string) bool {
+	return strings.HasPrefix(host, "*")
+}
+
+// hostsOverlap checks if the two hosts are equal or one of them is a wildcard host that matches the other.
+func hostsOverlap(host string, other string) bool {
+	return validation.HostMatches(host, other) || validation.HostMatches(other, host)
+}
+
+func getHostsForResource(r hostResource) []string {
+	switch obj := r.obj.(type) {
+	case *v1beta1.Ingress:
+		return getIngressHosts(obj)
+	case *conf_v1alpha1.VirtualServer:
+		return getVirtualServerHosts(obj)
+	}
+	return nil
+}
+
+// getVirtualServerHosts returns the host and the server aliases of the VirtualServer.
+func getVirtualServerHosts(vs *conf_v1alpha1.VirtualServer) []string {
+	hosts := []string{vs.Spec.Host}
+	seen := map[string]bool{vs.Spec.Host: true}
+
+	for _, alias := range vs.Spec.ServerAliases {
+		if alias == "" || seen[alias] {
+			continue
+		}
+		seen[alias] = true
+		hosts = append(hosts, alias)
+	}
+
+	return hosts
+}
+
+// findTakenHosts returns the hosts of the resource that are won by other resources along with their winners.
+func findTakenHosts(r hostResource, owners map[string]hostResource) map[string]hostResource {
+	takenHosts := make(map[string]hostResource)
+
+	for _, host := range getHostsForResource(r) {
+		if owner, exists := owners[host]; exists && !owner.isSame(r) {
+			takenHosts[host] = owner
+		}
+	}
+
+	return takenHosts
+}
+
+// removeTakenHosts returns a copy of the Ingress without the rules and the TLS hosts for the taken hosts.
+func removeTakenHosts(ing *v1beta1.Ingress, takenHosts map[string]hostResource) *v1beta1.Ingress {
+	if len(takenHosts) == 0 {
+		return ing
+	}
+
+	result := ing.DeepCopy()
+
+	var rules []v1beta1.IngressRule
+	for _, rule := range result.Spec.Rules {
+		if _, taken := takenHosts[rule.Host]; !taken {
+			rules = append(rules, rule)
+		}
+	}
+	result.Spec.Rules = rules
+
+	var tlsList []v1beta1.IngressTLS
+	for _, tls := range result.Spec.TLS {
+		var hosts []string
+		for _, host := range tls.Hosts {
+			if _, taken := takenHosts[host]; !taken {
+				hosts = append(hosts, host)
+			}
+		}
+		// a TLS entry without hosts applies to the default backend, so we drop the entries that lost all their hosts
+		if len(tls.Hosts) > 0 && len(hosts) == 0 {
+			continue
+		}
+		tls.Hosts = hosts
+		tlsList = append(tlsList, tls)
+	}
+	result.Spec.TLS = tlsList
+
+	return result
+}
+
+// removeTakenServerAliases returns a copy of the VirtualServer without the taken server aliases.
+func removeTakenServerAliases(vs *conf_v1alpha1.VirtualServer, takenHosts map[string]hostResource) *conf_v1alpha1.VirtualServer {
+	if len(takenHosts) == 0 {
+		return vs
+	}
+
+	result := vs.DeepCopy()
+
+	var aliases []string
+	for _, alias := range result.Spec.ServerAliases {
+		if _, taken := takenHosts[alias]; !taken {
+			aliases = append(aliases, alias)
+		}
+	}
+	result.Spec.ServerAliases = aliases
+
+	return result
+}
+
+// formatTakenHosts formats the taken hosts for events and logs.
+func formatTakenHosts(takenHosts map[string]hostResource) string {
+	var messages []string
+	for host, owner := range takenHosts {
+		messages = append(messages, fmt.Sprintf("host %v is taken by %v", host, owner))
+	}
+	sort.Strings(messages)
+	return strings.Join(messages, "; ")
+}
+
+func anyHostsOverlap(hosts []string, others []string) bool {
+	for _, host := range hosts {
+		for _, other := range others {
+			if hostsOverlap(host, other) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,313 @@
+package k8s
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	extensions "k8s.io/api/extensions/v1beta1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	conf_v1alpha1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
+)
+
+func createTestIngressForHosts(name string, created time.Time, hosts ...string) *extensions.Ingress {
+	ing := &extensions.Ingress{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			CreationTimestamp: meta_v1.NewTime(created),
+		},
+	}
+	for _, host := range hosts {
+		ing.Spec.Rules = append(ing.Spec.Rules, extensions.IngressRule{Host: host})
+	}
+	return ing
+}
+
+func createTestVirtualServerForHost(name string, created time.Time, host string) *conf_v1alpha1.VirtualServer {
+	return &conf_v1alpha1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			CreationTimestamp: meta_v1.NewTime(created),
+		},
+		Spec: conf_v1alpha1.VirtualServerSpec{
+			Host: host,
+		},
+	}
+}
+
+func TestFindHostOwners(t *testing.T) {
+	now := time.Now()
+	oldIng := newIngressHostResource(createTestIngressForHosts("old-ing", now.Add(-time.Hour), "a.example.com", "b.example.com"))
+	newIng := newIngressHostResource(createTestIngressForHosts("new-ing", now, "b.example.com", "c.example.com"))
+	oldVs := newVirtualServerHostResource(createTestVirtualServerForHost("old-vs", now.Add(-2*time.Hour), "c.example.com"))
+	sameTimeVs := newVirtualServerHostResource(createTestVirtualServerForHost("same-time-vs", now.Add(-time.Hour), "a.example.com"))
+
+	owners := findHostOwners([]hostResource{newIng, sameTimeVs, oldVs, oldIng})
+
+	expected := map[string]string{
+		"a.example.com": "Ingress default/old-ing",
+		"b.example.com": "Ingress default/old-ing",
+		"c.example.com": "VirtualServer default/old-vs",
+	}
+
+	result := make(map[string]string)
+	for host, owner := range owners {
+		result[host] = owner.String()
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("findHostOwners() returned %v but expected %v", result, expected)
+	}
+
+	takenHosts := findTakenHosts(newIng, owners)
+	if len(takenHosts) != 2 || !takenHosts["b.example.com"].isSame(oldIng) || !takenHosts["c.example.com"].isSame(oldVs) {
+		t.Errorf("findTakenHosts() returned %v for %v", takenHosts, newIng)
+	}
+
+	takenHosts = findTakenHosts(oldIng, owners)
+	if len(takenHosts) != 0 {
+		t.Errorf("findTakenHosts() returned %v but expected no taken hosts for %v", takenHosts, oldIng)
+	}
+}
+
+func TestFindHostOwnersAfterWinnerIsDeleted(t *testing.T) {
+	now := time.Now()
+	winner := newVirtualServerHostResource(createTestVirtualServerForHost("winner", now.Add(-time.Hour), "cafe.example.com"))
+	next := newIngressHostResource(createTestIngressForHosts("next", now.Add(-time.Minute), "cafe.example.com"))
+	last := newVirtualServerHostResource(createTestVirtualServerForHost("last", now, "cafe.example.com"))
+
+	owners := findHostOwners([]hostResource{last, winner, next})
+	if owner := owners["cafe.example.com"]; !owner.isSame(winner) {
+		t.Errorf("findHostOwners() returned %v but expected %v", owner, winner)
+	}
+
+	owners = findHostOwners([]hostResource{last, next})
+	if owner := owners["cafe.example.com"]; !owner.isSame(next) {
+		t.Errorf("findHostOwners() returned %v but expected %v after the winner was deleted", owner, next)
+	}
+}
+
+func TestFindHostOwnersWithWildcardHosts(t *testing.T) {
+	now := time.Now()
+	oldWildcardVs := newVirtualServerHostResource(createTestVirtualServerForHost("old-wildcard-vs", now.Add(-time.Hour), "*.apps.example.com"))
+	newIng := newIngressHostResource(createTestIngressForHosts("new-ing", now, "foo.apps.example.com", "apps.example.com"))
+	oldIng := newIngressHostResource(createTestIngressForHosts("old-ing", now.Add(-2*time.Hour), "cafe.example.com"))
+	newWildcardVs := newVirtualServerHostResource(createTestVirtualServerForHost("new-wildcard-vs", now.Add(-time.Minute), "*.example.com"))
+
+	owners := findHostOwners([]hostResource{newIng, oldWildcardVs, newWildcardVs, oldIng})
+
+	expected := map[string]string{
+		"*.apps.example.com":   "VirtualServer default/old-wildcard-vs",
+		"foo.apps.example.com": "VirtualServer default/old-wildcard-vs",
+		"apps.example.com":     "VirtualServer default/new-wildcard-vs",
+		"cafe.example.com":     "Ingress default/old-ing",
+		"*.example.com":        "VirtualServer default/new-wildcard-vs",
+	}
+
+	result := make(map[string]string)
+	for host, owner := range owners {
+		result[host] = owner.String()
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("findHostOwners() returned %v but expected %v", result, expected)
+	}
+
+	takenHosts := findTakenHosts(newIng, owners)
+	if len(takenHosts) != 2 || !takenHosts["foo.apps.example.com"].isSame(oldWildcardVs) || !takenHosts["apps.example.com"].isSame(newWildcardVs) {
+		t.Errorf("findTakenHosts() returned %v for %v", takenHosts, newIng)
+	}
+
+	takenHosts = findTakenHosts(newWildcardVs, owners)
+	if len(takenHosts) != 0 {
+		t.Errorf("findTakenHosts() returned %v but expected no taken hosts for %v", takenHosts, newWildcardVs)
+	}
+}
+
+func TestAnyHostsOverlap(t *testing.T) {
+	tests := []struct {
+		hosts    []string
+		others   []string
+		expected bool
+	}{
+		{
+			hosts:    []string{"cafe.example.com"},
+			others:   []string{"cafe.example.com"},
+			expected: true,
+		},
+		{
+			hosts:    []string{"cafe.example.com"},
+			others:   []string{"*.example.com"},
+			expected: true,
+		},
+		{
+			hosts:    []string{"*.example.com"},
+			others:   []string{"tea.example.com", "cafe.example.com"},
+			expected: true,
+		},
+		{
+			hosts:    []string{"*.example.com"},
+			others:   []string{"example.com"},
+			expected: false,
+		},
+		{
+			hosts:    []string{"cafe.example.com"},
+			others:   []string{"tea.example.com"},
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		result := anyHostsOverlap(test.hosts, test.others)
+		if result != test.expected {
+			t.Errorf("anyHostsOverlap(%v, %v) returned %v but expected %v", test.hosts, test.others, result, test.expected)
+		}
+	}
+}
+
+func TestRemoveTakenHosts(t *testing.T) {
+	ing := createTestIngressForHosts("cafe", time.Now(), "a.example.com", "b.example.com")
+	ing.Spec.TLS = []extensions.IngressTLS{
+		{
+			Hosts:      []string{"a.example.com"},
+			SecretName: "a-secret",
+		},
+		{
+			Hosts:      []string{"a.example.com", "b.example.com"},
+			SecretName: "ab-secret",
+		},
+	}
+	takenHosts := map[string]hostResource{
+		"a.example.com": newVirtualServerHostResource(createTestVirtualServerForHost("vs", time.Now(), "a.example.com")),
+	}
+
+	result := removeTakenHosts(ing, takenHosts)
+
+	expectedRules := []extensions.IngressRule{{Host: "b.example.com"}}
+	if !reflect.DeepEqual(result.Spec.Rules, expectedRules) {
+		t.Errorf("removeTakenHosts() returned rules %v but expected %v", result.Spec.Rules, expectedRules)
+	}
+	expectedTLS := []extensions.IngressTLS{
+		{
+			Hosts:      []string{"b.example.com"},
+			SecretName: "ab-secret",
+		},
+	}
+	if !reflect.DeepEqual(result.Spec.TLS, expectedTLS) {
+		t.Errorf("removeTakenHosts() returned TLS %v but expected %v", result.Spec.TLS, expectedTLS)
+	}
+	if len(ing.Spec.Rules) != 2 || len(ing.Spec.TLS) != 2 {
+		t.Errorf("removeTakenHosts() modified the original Ingress")
+	}
+}
+
+func TestRemoveTakenServerAliases(t *testing.T) {
+	vs := createTestVirtualServerForHost("cafe", time.Now(), "cafe.example.com")
+	vs.Spec.ServerAliases = []string{"www.cafe.example.com", "*.cafe.example.com"}
+
+	owner := newIngressHostResource(createTestIngressForHosts("ing", time.Now().Add(-time.Hour), "www.cafe.example.com"))
+	owners := findHostOwners([]hostResource{newVirtualServerHostResource(vs), owner})
+
+	takenHosts := findTakenHosts(newVirtualServerHostResource(vs), owners)
+	if len(takenHosts) != 1 || !takenHosts["www.cafe.example.com"].isSame(owner) {
+		t.Errorf("findTakenHosts() returned %v but expected the alias www.cafe.example.com to be taken", takenHosts)
+	}
+
+	result := removeTakenServerAliases(vs, takenHosts)
+
+	expectedAliases := []string{"*.cafe.example.com"}
+	if !reflect.DeepEqual(result.Spec.ServerAliases, expectedAliases) {
+		t.Errorf("removeTakenServerAliases() returned aliases %v but expected %v", result.Spec.ServerAliases, expectedAliases)
+	}
+	if len(vs.Spec.ServerAliases) != 2 {
+		t.Errorf("removeTakenServerAliases() modified the original VirtualServer")
+	}
+}
+
+func createTestControllerForHosts(ings []*extensions.Ingress, virtualServers []*conf_v1alpha1.VirtualServer) *LoadBalancerController {
+	lbc := &LoadBalancerController{
+		ingressClass:              "nginx",
+		areCustomResourcesEnabled: true,
+		recorder:                  record.NewFakeRecorder(10),
+		virtualServerLister:       cache.NewStore(cache.MetaNamespaceKeyFunc),
+	}
+	lbc.ingressLister.Store = cache.NewStore(cache.MetaNamespaceKeyFunc)
+
+	for _, ing := range ings {
+		_ = lbc.ingressLister.Add(ing)
+	}
+	for _, vs := range virtualServers {
+		_ = lbc.virtualServerLister.Add(vs)
+	}
+
+	return lbc
+}
+
+func TestCreateMergableIngressesRejectsMasterWithTakenHost(t *testing.T) {
+	now := time.Now()
+
+	master := createTestIngressForHosts("cafe-master", now, "cafe.example.com")
+	master.Annotations = map[string]string{"nginx.org/mergeable-ingress-type": "master"}
+	minion := createTestIngressForHosts("cafe-minion", now, "cafe.example.com")
+	minion.Annotations = map[string]string{"nginx.org/mergeable-ingress-type": "minion"}
+	minion.Spec.Rules[0].HTTP = &extensions.HTTPIngressRuleValue{
+		Paths: []extensions.HTTPIngressPath{
+			{
+				Path: "/tea",
+				Backend: extensions.IngressBackend{
+					ServiceName: "tea-svc",
+				},
+			},
+		},
+	}
+	oldVs := createTestVirtualServerForHost("cafe", now.Add(-time.Hour), "cafe.example.com")
+
+	lbc := createTestControllerForHosts([]*extensions.Ingress{master, minion}, []*conf_v1alpha1.VirtualServer{oldVs})
+
+	_, err := lbc.createMergableIngresses(master)
+	if err == nil {
+		t.Fatalf("createMergableIngresses() returned no error for a master with a host taken by an older VirtualServer")
+	}
+
+	recorder := lbc.recorder.(*record.FakeRecorder)
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, "Rejected") || !strings.Contains(event, "VirtualServer default/cafe") {
+			t.Errorf("createMergableIngresses() emitted the event %q, expected a Rejected event that mentions VirtualServer default/cafe", event)
+		}
+	default:
+		t.Errorf("createMergableIngresses() didn't emit an event for the rejected master")
+	}
+}
+
+func TestGetHostOwnersCachesOwnersDuringSync(t *testing.T) {
+	now := time.Now()
+
+	ing := createTestIngressForHosts("cafe-ingress", now, "cafe.example.com")
+	lbc := createTestControllerForHosts([]*extensions.Ingress{ing}, nil)
+
+	lbc.setHostOwnersCache(true)
+	owners := lbc.getHostOwners()
+	if owner := owners["cafe.example.com"]; owner.meta == nil || owner.meta.Name != "cafe-ingress" {
+		t.Fatalf("getHostOwners() returned the owner %v, expected Ingress default/cafe-ingress", owner)
+	}
+
+	oldVs := createTestVirtualServerForHost("cafe", now.Add(-time.Hour), "cafe.example.com")
+	_ = lbc.virtualServerLister.Add(oldVs)
+
+	if owner := lbc.getHostOwners()["cafe.example.com"]; owner.kind != ingressKind {
+		t.Errorf("getHostOwners() returned the owner %v during the same sync, expected the cached Ingress default/cafe-ingress", owner)
+	}
+
+	lbc.setHostOwnersCache(false)
+
+	if owner := lbc.getHostOwners()["cafe.example.com"]; owner.kind != virtualServerKind {
+		t.Errorf("getHostOwners() returned the owner %v after the sync, expected VirtualServer default/cafe", owner)
+	}
+}
@@ -2,13 +2,15 @@ package k8s
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"sync"
 	"time"
 
-	"github.com/golang/glog"
+	"github.com/nginxinc/kubernetes-ingress/internal/logging"
 
 	v1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/leaderelection"
@@ -16,8 +18,25 @@ import (
 	"k8s.io/client-go/tools/record"
 )
 
+const (
+	// ConfigMapsLockType is the lock type that uses a ConfigMap as the lock for leader election.
+	ConfigMapsLockType = resourcelock.ConfigMapsResourceLock
+	// LeasesLockType is the lock type that uses a Lease as the lock for leader election.
+	LeasesLockType = resourcelock.LeasesResourceLock
+	// ConfigMapsLeasesLockType is the lock type that uses both a ConfigMap and a Lease as the lock for leader election.
+	// It allows migrating from the ConfigMap lock to the Lease lock without running two leaders at the same time.
+	ConfigMapsLeasesLockType = "configmapsleases"
+)
+
+// leaderLockReleaseTimeout is the time the controller waits for the lock to be released during the shutdown.
+const leaderLockReleaseTimeout = 5 * time.Second
+
+// unknownLeader is the identity of the leader reported by the multiLock when the ConfigMap and the Lease
+// are held by different leaders.
+const unknownLeader = "leaderelection.k8s.io/unknown"
+
 // newLeaderElector creates a new LeaderElection and returns the Elector.
-func newLeaderElector(client kubernetes.Interface, callbacks leaderelection.LeaderCallbacks, namespace string, lockName string) (*leaderelection.LeaderElector, error) {
+func newLeaderElector(client kubernetes.Interface, callbacks leaderelection.LeaderCallbacks, namespace string, lockName string, lockType string) (*leaderelection.LeaderElector, error) {
 	podName := os.Getenv("POD_NAME")
 
 	broadcaster := record.NewBroadcaster()
@@ -26,38 +45,144 @@ func newLeaderElector(client kubernetes.Interface, callbacks leaderelection.Lead
 	source := v1.EventSource{Component: "nginx-ingress-leader-elector", Host: hostname}
 	recorder := broadcaster.NewRecorder(scheme.Scheme, source)
 
-	lock := resourcelock.ConfigMapLock{
-		ConfigMapMeta: metav1.ObjectMeta{Namespace: namespace, Name: lockName},
-		Client:        client.CoreV1(),
-		LockConfig: resourcelock.ResourceLockConfig{
-			Identity:      podName,
-			EventRecorder: recorder,
-		},
+	lockConfig := resourcelock.ResourceLockConfig{
+		Identity:      podName,
+		EventRecorder: recorder,
+	}
+
+	lock, err := newResourceLock(client, lockType, namespace, lockName, lockConfig)
+	if err != nil {
+		return nil, err
 	}
 
 	ttl := 30 * time.Second
 	return leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
-		Lock:          &lock,
-		LeaseDuration: ttl,
-		RenewDeadline: ttl / 2,
-		RetryPeriod:   ttl / 4,
-		Callbacks:     callbacks,
+		Lock:            lock,
+		LeaseDuration:   ttl,
+		RenewDeadline:   ttl / 2,
+		RetryPeriod:     ttl / 4,
+		Callbacks:       callbacks,
+		ReleaseOnCancel: true,
 	})
 }
 
-// createLeaderHandler builds the handler funcs for leader handling
+// newResourceLock creates the lock for leader election of the given type.
+func newResourceLock(client kubernetes.Interface, lockType string, namespace string, lockName string, lockConfig resourcelock.ResourceLockConfig) (resourcelock.Interface, error) {
+	switch lockType {
+	case ConfigMapsLockType, LeasesLockType:
+		return resourcelock.New(lockType, namespace, lockName, client.CoreV1(), client.CoordinationV1(), lockConfig)
+	case ConfigMapsLeasesLockType:
+		primary, _ := resourcelock.New(ConfigMapsLockType, namespace, lockName, client.CoreV1(), client.CoordinationV1(), lockConfig)
+		secondary, _ := resourcelock.New(LeasesLockType, namespace, lockName, client.CoreV1(), client.CoordinationV1(), lockConfig)
+		return &multiLock{
+			primary:   primary,
+			secondary: secondary,
+		}, nil
+	default:
+		return nil, fmt.Errorf("Invalid lock type %v", lockType)
+	}
+}
+
+// multiLock is a lock for leader election that consists of two locks. The primary lock is the lock that is used by
+// the replicas that haven't been migrated yet, while the secondary lock is the lock to migrate to.
+// The leader holds both locks.
+type multiLock struct {
+	primary   resourcelock.Interface
+	secondary resourcelock.Interface
+}
+
+// Get returns the election record of the primary lock. If the locks are held by different leaders,
+// the holder identity of the record is unknownLeader.
+func (ml *multiLock) Get() (*resourcelock.LeaderElectionRecord, error) {
+	primary, err := ml.primary.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	secondary, err := ml.secondary.Get()
+	if err != nil {
+		// the secondary lock doesn't exist if the leader hasn't been migrated yet
+		if errors.IsNotFound(err) && primary.HolderIdentity != ml.Identity() {
+			return primary, nil
+		}
+		return nil, err
+	}
+
+	if primary.HolderIdentity != secondary.HolderIdentity {
+		primary.HolderIdentity = unknownLeader
+	}
+
+	return primary, nil
+}
+
+// Create creates both locks.
+func (ml *multiLock) Create(ler resourcelock.LeaderElectionRecord) error {
+	err := ml.primary.Create(ler)
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	return ml.secondary.Create(ler)
+}
+
+// Update updates both locks. The secondary lock is created if it doesn't exist.
+func (ml *multiLock) Update(ler resourcelock.LeaderElectionRecord) error {
+	err := ml.primary.Update(ler)
+	if err != nil {
+		return err
+	}
+
+	_, err = ml.secondary.Get()
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ml.secondary.Create(ler)
+		}
+		return err
+	}
+
+	return ml.secondary.Update(ler)
+}
+
+// RecordEvent records an event for both locks.
+func (ml *multiLock) RecordEvent(s string) {
+	ml.primary.RecordEvent(s)
+	ml.secondary.RecordEvent(s)
+}
+
+// Identity returns the identity of the lock holder.
+func (ml *multiLock) Identity() string {
+	return ml.primary.Identity()
+}
+
+// Describe describes both locks.
+func (ml *multiLock) Describe() string {
+	return fmt.Sprintf("%v,%v", ml.primary.Describe(), ml.secondary.Describe())
+}
+
+// createLeaderHandler builds the handler funcs for leader handling.
+// When the controller starts leading, it updates the status of all resources. When the controller stops leading,
+// the update is stopped, because the ctx of OnStartedLeading is done, and OnStoppedLeading waits for the update
+// to stop, so that the controller doesn't overwrite the status set by the new leader.
 func createLeaderHandler(lbc *LoadBalancerController) leaderelection.LeaderCallbacks {
+	var statusUpdateMutex sync.Mutex
+
 	return leaderelection.LeaderCallbacks{
 		OnStartedLeading: func(ctx context.Context) {
-			glog.V(3).Info("started leading, updating ingress status")
-			ingresses, mergeableIngresses := lbc.GetManagedIngresses()
-			err := lbc.UpdateManagedAndMergeableIngresses(ingresses, mergeableIngresses)
-			if err != nil {
-				glog.V(3).Infof("error updating status when starting leading: %v", err)
-			}
+			statusUpdateMutex.Lock()
+			defer statusUpdateMutex.Unlock()
+
+			logging.V(3).Info("started leading, updating the status of resources")
+			lbc.updateAllStatuses(ctx)
 		},
 		OnStoppedLeading: func() {
-			glog.V(3).Info("stopped leading")
+			logging.V(3).Info("stopped leading, stopping the status updates")
+
+			statusUpdateMutex.Lock()
+			defer statusUpdateMutex.Unlock()
+
+			logging.V(3).Info("stopped the status updates")
+		},
+		OnNewLeader: func(identity string) {
+			logging.V(3).Infof("new leader elected: %v", identity)
 		},
 	}
 }
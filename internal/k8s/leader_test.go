@@ -0,0 +1,232 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nginxinc/kubernetes-ingress/internal/configs"
+	"github.com/nginxinc/kubernetes-ingress/internal/configs/version1"
+	"github.com/nginxinc/kubernetes-ingress/internal/configs/version2"
+	"github.com/nginxinc/kubernetes-ingress/internal/metrics/collectors"
+	"github.com/nginxinc/kubernetes-ingress/internal/nginx"
+	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+func createTestMultiLock(identity string) (*fake.Clientset, *multiLock) {
+	client := fake.NewSimpleClientset()
+	lock, _ := newResourceLock(client, ConfigMapsLeasesLockType, "nginx-ingress", "leader", resourcelock.ResourceLockConfig{Identity: identity})
+	return client, lock.(*multiLock)
+}
+
+func TestNewResourceLock(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	config := resourcelock.ResourceLockConfig{Identity: "pod-1"}
+
+	tests := []struct {
+		lockType string
+		expected string
+	}{
+		{
+			lockType: ConfigMapsLockType,
+			expected: "nginx-ingress/leader",
+		},
+		{
+			lockType: LeasesLockType,
+			expected: "nginx-ingress/leader",
+		},
+		{
+			lockType: ConfigMapsLeasesLockType,
+			expected: "nginx-ingress/leader,nginx-ingress/leader",
+		},
+	}
+
+	for _, test := range tests {
+		lock, err := newResourceLock(client, test.lockType, "nginx-ingress", "leader", config)
+		if err != nil {
+			t.Errorf("newResourceLock() returned unexpected error for lock type %v: %v", test.lockType, err)
+			continue
+		}
+		if lock.Describe() != test.expected {
+			t.Errorf("newResourceLock() returned lock %v for lock type %v but expected %v", lock.Describe(), test.lockType, test.expected)
+		}
+	}
+
+	_, err := newResourceLock(client, "endpoints", "nginx-ingress", "leader", config)
+	if err == nil {
+		t.Errorf("newResourceLock() returned no error for an invalid lock type")
+	}
+}
+
+func TestMultiLockCreate(t *testing.T) {
+	_, lock := createTestMultiLock("pod-1")
+
+	_, err := lock.Get()
+	if !errors.IsNotFound(err) {
+		t.Fatalf("Get() returned %v but expected a NotFound error", err)
+	}
+
+	err = lock.Create(resourcelock.LeaderElectionRecord{HolderIdentity: "pod-1"})
+	if err != nil {
+		t.Fatalf("Create() returned unexpected error: %v", err)
+	}
+
+	for _, l := range []resourcelock.Interface{lock.primary, lock.secondary} {
+		record, err := l.Get()
+		if err != nil {
+			t.Fatalf("Get() for %v returned unexpected error: %v", l.Describe(), err)
+		}
+		if record.HolderIdentity != "pod-1" {
+			t.Errorf("Get() for %v returned the holder %v but expected pod-1", l.Describe(), record.HolderIdentity)
+		}
+	}
+}
+
+func TestMultiLockGetWithoutSecondaryLock(t *testing.T) {
+	client, lock := createTestMultiLock("pod-1")
+
+	// a replica that hasn't been migrated yet holds only the ConfigMap lock
+	oldLock, _ := newResourceLock(client, ConfigMapsLockType, "nginx-ingress", "leader", resourcelock.ResourceLockConfig{Identity: "pod-2"})
+	err := oldLock.Create(resourcelock.LeaderElectionRecord{HolderIdentity: "pod-2"})
+	if err != nil {
+		t.Fatalf("Create() returned unexpected error: %v", err)
+	}
+
+	record, err := lock.Get()
+	if err != nil {
+		t.Fatalf("Get() returned unexpected error: %v", err)
+	}
+	if record.HolderIdentity != "pod-2" {
+		t.Errorf("Get() returned the holder %v but expected pod-2", record.HolderIdentity)
+	}
+
+	err = lock.Update(resourcelock.LeaderElectionRecord{HolderIdentity: "pod-1"})
+	if err != nil {
+		t.Fatalf("Update() returned unexpected error: %v", err)
+	}
+
+	record, err = lock.secondary.Get()
+	if err != nil {
+		t.Fatalf("Get() for the secondary lock returned unexpected error: %v", err)
+	}
+	if record.HolderIdentity != "pod-1" {
+		t.Errorf("Update() didn't create the secondary lock with the holder pod-1, got %v", record.HolderIdentity)
+	}
+}
+
+func TestMultiLockGetWithDifferentHolders(t *testing.T) {
+	client, lock := createTestMultiLock("pod-1")
+
+	configMapLock, _ := newResourceLock(client, ConfigMapsLockType, "nginx-ingress", "leader", resourcelock.ResourceLockConfig{Identity: "pod-2"})
+	err := configMapLock.Create(resourcelock.LeaderElectionRecord{HolderIdentity: "pod-2"})
+	if err != nil {
+		t.Fatalf("Create() returned unexpected error: %v", err)
+	}
+
+	leaseLock, _ := newResourceLock(client, LeasesLockType, "nginx-ingress", "leader", resourcelock.ResourceLockConfig{Identity: "pod-3"})
+	err = leaseLock.Create(resourcelock.LeaderElectionRecord{HolderIdentity: "pod-3"})
+	if err != nil {
+		t.Fatalf("Create() returned unexpected error: %v", err)
+	}
+
+	record, err := lock.Get()
+	if err != nil {
+		t.Fatalf("Get() returned unexpected error: %v", err)
+	}
+	if record.HolderIdentity != unknownLeader {
+		t.Errorf("Get() returned the holder %v but expected %v", record.HolderIdentity, unknownLeader)
+	}
+}
+
+func TestLeaderHandlerUpdatesStatusOnlyWhileLeading(t *testing.T) {
+	ing := extensions.Ingress{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe-ingress",
+			Namespace: "default",
+		},
+		Spec: extensions.IngressSpec{
+			Rules: []extensions.IngressRule{
+				{
+					Host: "cafe.example.com",
+					IngressRuleValue: extensions.IngressRuleValue{
+						HTTP: &extensions.HTTPIngressRuleValue{
+							Paths: []extensions.HTTPIngressPath{
+								{
+									Path: "/",
+									Backend: extensions.IngressBackend{
+										ServiceName: "tea-svc",
+										ServicePort: intstr.FromInt(80),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	templateExecutor, err := version1.NewTemplateExecutor("../configs/version1/nginx.tmpl", "../configs/version1/nginx.ingress.tmpl")
+	if err != nil {
+		t.Fatalf("templateExecutor could not start: %v", err)
+	}
+	cnf := configs.NewConfigurator(nginx.NewFakeManager("/etc/nginx", "/etc/nginx/secrets"), &configs.StaticConfigParams{}, configs.NewDefaultConfigParams(), templateExecutor, &version2.TemplateExecutor{}, false, false, false, collectors.NewFakeLabelUpdater(), nil)
+	err = cnf.AddOrUpdateIngress(&configs.IngressEx{Ingress: &ing})
+	if err != nil {
+		t.Fatalf("Ingress was not added: %v", err)
+	}
+
+	fakeClient := fake.NewSimpleClientset(&extensions.IngressList{Items: []extensions.Ingress{ing}})
+
+	lbc := LoadBalancerController{
+		client:       fakeClient,
+		ingressClass: "nginx",
+		configurator: cnf,
+	}
+	lbc.ingressLister.Store, _ = cache.NewInformer(
+		cache.NewListWatchFromClient(fakeClient.ExtensionsV1beta1().RESTClient(), "ingresses", "default", fields.Everything()),
+		&extensions.Ingress{}, time.Duration(1), nil)
+	err = lbc.ingressLister.Add(&ing)
+	if err != nil {
+		t.Fatalf("Error adding Ingress to the ingress lister: %v", err)
+	}
+	lbc.statusUpdater = &statusUpdater{
+		client:    fakeClient,
+		ingLister: &lbc.ingressLister,
+		keyFunc:   cache.DeletionHandlingMetaNamespaceKeyFunc,
+	}
+	lbc.statusUpdater.SaveStatusFromExternalStatus("1.1.1.1")
+
+	getStatus := func() extensions.Ingress {
+		result, err := fakeClient.ExtensionsV1beta1().Ingresses("default").Get("cafe-ingress", meta_v1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Failed to get the Ingress: %v", err)
+		}
+		return *result
+	}
+
+	handler := createLeaderHandler(&lbc)
+
+	// client-go cancels the ctx of OnStartedLeading before it calls OnStoppedLeading
+	lostCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	handler.OnStartedLeading(lostCtx)
+	handler.OnStoppedLeading()
+
+	if result := getStatus(); !checkStatus("", result) {
+		t.Errorf("OnStartedLeading() updated the status to %v after the leadership was lost", result.Status.LoadBalancer.Ingress)
+	}
+
+	handler.OnStartedLeading(context.Background())
+
+	if result := getStatus(); !checkStatus("1.1.1.1", result) {
+		t.Errorf("OnStartedLeading() updated the status to %v but expected 1.1.1.1", result.Status.LoadBalancer.Ingress)
+	}
+}
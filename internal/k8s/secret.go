@@ -1,7 +1,14 @@
 package k8s
 
 import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
+	"strings"
 
 	v1 "k8s.io/api/core/v1"
 )
@@ -9,15 +16,55 @@ import (
 // JWTKeyKey is the key of the data field of a Secret where the JWK must be stored.
 const JWTKeyKey = "jwk"
 
+// HtpasswdKey is the key of the data field of a Secret where the htpasswd file must be stored.
+const HtpasswdKey = "htpasswd"
+
+// SecretTypeHtpasswd is the type of a Secret with an htpasswd file for HTTP basic authentication.
+const SecretTypeHtpasswd v1.SecretType = "nginx.org/htpasswd"
+
 const (
 	// TLS Secret
 	TLS = iota
 	// JWK Secret
 	JWK
+	// Htpasswd Secret
+	Htpasswd
 )
 
 // ValidateTLSSecret validates the secret. If it is valid, the function returns nil.
+// A TLS secret is valid if it contains a PEM chain of certificates and a private key of a supported type (RSA, ECDSA or Ed25519)
+// that matches the first certificate of the chain.
 func ValidateTLSSecret(secret *v1.Secret) error {
+	_, err := ParseTLSSecretCertificate(secret)
+	return err
+}
+
+// ParseTLSSecretCertificate validates the TLS secret and returns the first certificate of its chain.
+func ParseTLSSecretCertificate(secret *v1.Secret) (*x509.Certificate, error) {
+	if err := validateTLSSecretKeys(secret); err != nil {
+		return nil, err
+	}
+
+	certs, err := parseCertificateChain(secret.Data[v1.TLSCertKey])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid %v: %v", v1.TLSCertKey, err)
+	}
+
+	key, err := parsePrivateKey(secret.Data[v1.TLSPrivateKeyKey])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid %v: %v", v1.TLSPrivateKeyKey, err)
+	}
+
+	err = validateKeyMatchesCertificate(key, certs[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return certs[0], nil
+}
+
+// validateTLSSecretKeys checks that the secret has the certificate and the key.
+func validateTLSSecretKeys(secret *v1.Secret) error {
 	if _, exists := secret.Data[v1.TLSCertKey]; !exists {
 		return fmt.Errorf("Secret doesn't have %v", v1.TLSCertKey)
 	}
@@ -29,6 +76,110 @@ func ValidateTLSSecret(secret *v1.Secret) error {
 	return nil
 }
 
+func parseCertificateChain(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+
+	rest := data
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("unexpected PEM block of type %q", block.Type)
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("certificate #%d of the chain is invalid: %v", len(certs)+1, err)
+		}
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no PEM certificates found")
+	}
+	if len(bytes.TrimSpace(rest)) > 0 {
+		return nil, fmt.Errorf("unexpected data after the last PEM certificate")
+	}
+
+	return certs, nil
+}
+
+func parsePrivateKey(data []byte) (interface{}, error) {
+	rest := data
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, fmt.Errorf("no PEM private key found")
+		}
+		// the EC parameters might precede the EC private key
+		if block.Type == "EC PARAMETERS" {
+			continue
+		}
+
+		switch block.Type {
+		case "RSA PRIVATE KEY":
+			return x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			return x509.ParseECPrivateKey(block.Bytes)
+		case "PRIVATE KEY":
+			key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			switch key.(type) {
+			case *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey:
+				return key, nil
+			default:
+				return nil, fmt.Errorf("unsupported private key type %T", key)
+			}
+		}
+
+		if strings.HasSuffix(block.Type, "PRIVATE KEY") {
+			return nil, fmt.Errorf("unsupported private key type %q", block.Type)
+		}
+		return nil, fmt.Errorf("unexpected PEM block of type %q", block.Type)
+	}
+}
+
+func validateKeyMatchesCertificate(key interface{}, cert *x509.Certificate) error {
+	mismatchErr := fmt.Errorf("The private key doesn't match the certificate")
+
+	switch pub := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		priv, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return mismatchErr
+		}
+		if pub.N.Cmp(priv.N) != 0 || pub.E != priv.E {
+			return mismatchErr
+		}
+	case *ecdsa.PublicKey:
+		priv, ok := key.(*ecdsa.PrivateKey)
+		if !ok {
+			return mismatchErr
+		}
+		if pub.Curve != priv.Curve || pub.X.Cmp(priv.X) != 0 || pub.Y.Cmp(priv.Y) != 0 {
+			return mismatchErr
+		}
+	case ed25519.PublicKey:
+		priv, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return mismatchErr
+		}
+		if !bytes.Equal(pub, priv.Public().(ed25519.PublicKey)) {
+			return mismatchErr
+		}
+	default:
+		return fmt.Errorf("The certificate has an unsupported public key type %v", cert.PublicKeyAlgorithm)
+	}
+
+	return nil
+}
+
 // ValidateJWKSecret validates the secret. If it is valid, the function returns nil.
 func ValidateJWKSecret(secret *v1.Secret) error {
 	if _, exists := secret.Data[JWTKeyKey]; !exists {
@@ -38,9 +189,48 @@ func ValidateJWKSecret(secret *v1.Secret) error {
 	return nil
 }
 
+// ValidateHtpasswdSecret validates the secret. If it is valid, the function returns nil.
+// An htpasswd secret is valid if it has the htpasswd type and its htpasswd file consists of lines in the
+// user:password format. Empty lines and comments are allowed.
+func ValidateHtpasswdSecret(secret *v1.Secret) error {
+	if secret.Type != SecretTypeHtpasswd {
+		return fmt.Errorf("Secret is not of the %v type", SecretTypeHtpasswd)
+	}
+
+	data, exists := secret.Data[HtpasswdKey]
+	if !exists {
+		return fmt.Errorf("Secret doesn't have %v", HtpasswdKey)
+	}
+
+	users := 0
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("Invalid %v: line %d must be in the user:password format", HtpasswdKey, i+1)
+		}
+		users++
+	}
+
+	if users == 0 {
+		return fmt.Errorf("Invalid %v: no users found", HtpasswdKey)
+	}
+
+	return nil
+}
+
 // GetSecretKind returns the kind of the Secret.
+// The kind of the Secret is determined by its type for htpasswd Secrets and by its keys for other Secrets,
+// so that an invalid TLS Secret is still recognized as a TLS Secret.
 func GetSecretKind(secret *v1.Secret) (int, error) {
-	if err := ValidateTLSSecret(secret); err == nil {
+	if secret.Type == SecretTypeHtpasswd {
+		return Htpasswd, nil
+	}
+	if err := validateTLSSecretKeys(secret); err == nil {
 		return TLS, nil
 	}
 	if err := ValidateJWKSecret(secret); err == nil {
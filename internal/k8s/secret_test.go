@@ -0,0 +1,316 @@
+package k8s
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func createTestCertificate(t *testing.T, key crypto.Signer, notAfter time.Time) []byte {
+	t.Helper()
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "cafe.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatalf("Failed to create a test certificate: %v", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func encodeTestPKCS8PrivateKey(t *testing.T, key crypto.Signer) []byte {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal a test private key: %v", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func createTestTLSSecret(cert []byte, key []byte) *v1.Secret {
+	return &v1.Secret{
+		Type: v1.SecretTypeTLS,
+		Data: map[string][]byte{
+			v1.TLSCertKey:       cert,
+			v1.TLSPrivateKeyKey: key,
+		},
+	}
+}
+
+func TestValidateTLSSecret(t *testing.T) {
+	notAfter := time.Now().Add(24 * time.Hour)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate an ECDSA key: %v", err)
+	}
+	ecKeyDER, err := x509.MarshalECPrivateKey(ecKey)
+	if err != nil {
+		t.Fatalf("Failed to marshal an ECDSA key: %v", err)
+	}
+	ecCert := createTestCertificate(t, ecKey, notAfter)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate an RSA key: %v", err)
+	}
+	rsaCert := createTestCertificate(t, rsaKey, notAfter)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate an Ed25519 key: %v", err)
+	}
+	edCert := createTestCertificate(t, edKey, notAfter)
+
+	tests := []struct {
+		secret *v1.Secret
+		msg    string
+	}{
+		{
+			secret: createTestTLSSecret(ecCert, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecKeyDER})),
+			msg:    "ECDSA key",
+		},
+		{
+			secret: createTestTLSSecret(ecCert, encodeTestPKCS8PrivateKey(t, ecKey)),
+			msg:    "ECDSA key in PKCS #8",
+		},
+		{
+			secret: createTestTLSSecret(rsaCert, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})),
+			msg:    "RSA key",
+		},
+		{
+			secret: createTestTLSSecret(edCert, encodeTestPKCS8PrivateKey(t, edKey)),
+			msg:    "Ed25519 key",
+		},
+		{
+			secret: createTestTLSSecret(append(append([]byte{}, ecCert...), rsaCert...), encodeTestPKCS8PrivateKey(t, ecKey)),
+			msg:    "certificate chain",
+		},
+	}
+
+	for _, test := range tests {
+		err := ValidateTLSSecret(test.secret)
+		if err != nil {
+			t.Errorf("ValidateTLSSecret() returned error %v for the case of %s", err, test.msg)
+		}
+	}
+}
+
+func TestValidateTLSSecretFails(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate an ECDSA key: %v", err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate an ECDSA key: %v", err)
+	}
+	ecCert := createTestCertificate(t, ecKey, time.Now().Add(24*time.Hour))
+	ecKeyPEM := encodeTestPKCS8PrivateKey(t, ecKey)
+
+	tests := []struct {
+		secret *v1.Secret
+		msg    string
+	}{
+		{
+			secret: &v1.Secret{
+				Data: map[string][]byte{
+					v1.TLSPrivateKeyKey: ecKeyPEM,
+				},
+			},
+			msg: "missing certificate",
+		},
+		{
+			secret: &v1.Secret{
+				Data: map[string][]byte{
+					v1.TLSCertKey: ecCert,
+				},
+			},
+			msg: "missing key",
+		},
+		{
+			secret: createTestTLSSecret([]byte("garbage"), ecKeyPEM),
+			msg:    "certificate is not PEM",
+		},
+		{
+			secret: createTestTLSSecret(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("garbage")}), ecKeyPEM),
+			msg:    "invalid certificate",
+		},
+		{
+			secret: createTestTLSSecret(append(append([]byte{}, ecCert...), []byte("garbage")...), ecKeyPEM),
+			msg:    "garbage after the certificate chain",
+		},
+		{
+			secret: createTestTLSSecret(ecCert, []byte("garbage")),
+			msg:    "key is not PEM",
+		},
+		{
+			secret: createTestTLSSecret(ecCert, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("garbage")})),
+			msg:    "invalid key",
+		},
+		{
+			secret: createTestTLSSecret(ecCert, pem.EncodeToMemory(&pem.Block{Type: "DSA PRIVATE KEY", Bytes: []byte("dsa")})),
+			msg:    "unsupported key type",
+		},
+		{
+			secret: createTestTLSSecret(ecCert, pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: []byte("encrypted")})),
+			msg:    "encrypted key",
+		},
+		{
+			secret: createTestTLSSecret(ecCert, encodeTestPKCS8PrivateKey(t, otherKey)),
+			msg:    "key doesn't match the certificate",
+		},
+	}
+
+	for _, test := range tests {
+		err := ValidateTLSSecret(test.secret)
+		if err == nil {
+			t.Errorf("ValidateTLSSecret() returned no error for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestParseTLSSecretCertificate(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate an ECDSA key: %v", err)
+	}
+	notAfter := time.Now().Add(24 * time.Hour).Truncate(time.Second).UTC()
+	secret := createTestTLSSecret(createTestCertificate(t, key, notAfter), encodeTestPKCS8PrivateKey(t, key))
+
+	cert, err := ParseTLSSecretCertificate(secret)
+	if err != nil {
+		t.Fatalf("ParseTLSSecretCertificate() returned unexpected error %v", err)
+	}
+	if !cert.NotAfter.Equal(notAfter) {
+		t.Errorf("ParseTLSSecretCertificate() returned a certificate that expires on %v but expected %v", cert.NotAfter, notAfter)
+	}
+}
+
+func TestGetSecretKind(t *testing.T) {
+	tests := []struct {
+		secret   *v1.Secret
+		expected int
+		msg      string
+	}{
+		{
+			secret:   createTestTLSSecret([]byte("garbage"), []byte("garbage")),
+			expected: TLS,
+			msg:      "invalid TLS secret",
+		},
+		{
+			secret: &v1.Secret{
+				Data: map[string][]byte{
+					JWTKeyKey: []byte("jwk"),
+				},
+			},
+			expected: JWK,
+			msg:      "JWK secret",
+		},
+		{
+			secret: &v1.Secret{
+				Type: SecretTypeHtpasswd,
+			},
+			expected: Htpasswd,
+			msg:      "htpasswd secret without the htpasswd file",
+		},
+	}
+
+	for _, test := range tests {
+		result, err := GetSecretKind(test.secret)
+		if err != nil {
+			t.Errorf("GetSecretKind() returned unexpected error %v for the case of %s", err, test.msg)
+		}
+		if result != test.expected {
+			t.Errorf("GetSecretKind() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
+	}
+
+	_, err := GetSecretKind(&v1.Secret{})
+	if err == nil {
+		t.Errorf("GetSecretKind() returned no error for an empty secret")
+	}
+}
+
+func createTestHtpasswdSecret(htpasswd string) *v1.Secret {
+	return &v1.Secret{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "htpasswd-secret",
+			Namespace: "default",
+		},
+		Type: SecretTypeHtpasswd,
+		Data: map[string][]byte{
+			HtpasswdKey: []byte(htpasswd),
+		},
+	}
+}
+
+func TestValidateHtpasswdSecret(t *testing.T) {
+	htpasswd := "# users\nfoo:$apr1$Ou8QdXDx$8m4ZzPm4JHxZZ3yPe0kN4/\n\nbar:{SHA}Ys23Ag/5IOWqZCw9QGaVDdHwH00=\n"
+
+	err := ValidateHtpasswdSecret(createTestHtpasswdSecret(htpasswd))
+	if err != nil {
+		t.Errorf("ValidateHtpasswdSecret() returned unexpected error %v", err)
+	}
+}
+
+func TestValidateHtpasswdSecretFails(t *testing.T) {
+	tests := []struct {
+		secret *v1.Secret
+		msg    string
+	}{
+		{
+			secret: &v1.Secret{
+				Data: map[string][]byte{
+					HtpasswdKey: []byte("foo:bar"),
+				},
+			},
+			msg: "wrong type",
+		},
+		{
+			secret: &v1.Secret{
+				Type: SecretTypeHtpasswd,
+			},
+			msg: "missing htpasswd file",
+		},
+		{
+			secret: createTestHtpasswdSecret(""),
+			msg:    "no users",
+		},
+		{
+			secret: createTestHtpasswdSecret("foo:bar\nbaz"),
+			msg:    "line without password",
+		},
+		{
+			secret: createTestHtpasswdSecret(":bar"),
+			msg:    "line without user",
+		},
+	}
+
+	for _, test := range tests {
+		err := ValidateHtpasswdSecret(test.secret)
+		if err == nil {
+			t.Errorf("ValidateHtpasswdSecret() returned no error for the case of %s", test.msg)
+		}
+	}
+}
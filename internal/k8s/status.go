@@ -1,12 +1,13 @@
 package k8s
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"reflect"
 
-	"github.com/golang/glog"
 	"github.com/nginxinc/kubernetes-ingress/internal/configs"
+	"github.com/nginxinc/kubernetes-ingress/internal/logging"
 	api_v1 "k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -30,6 +31,16 @@ type statusUpdater struct {
 
 // UpdateManagedAndMergeableIngresses handles the full return format of LoadBalancerController.getManagedIngresses
 func (su *statusUpdater) UpdateManagedAndMergeableIngresses(managedIngresses []v1beta1.Ingress, mergableIngExes map[string]*configs.MergeableIngresses) error {
+	return su.BulkUpdateIngressStatus(getManagedAndMergeableIngresses(managedIngresses, mergableIngExes))
+}
+
+// ReconcileManagedAndMergeableIngresses is like UpdateManagedAndMergeableIngresses, but it stops updating
+// the Ingresses once ctx is done, for example, once the controller loses the leadership.
+func (su *statusUpdater) ReconcileManagedAndMergeableIngresses(ctx context.Context, managedIngresses []v1beta1.Ingress, mergableIngExes map[string]*configs.MergeableIngresses) error {
+	return su.bulkUpdateIngressStatus(ctx, getManagedAndMergeableIngresses(managedIngresses, mergableIngExes))
+}
+
+func getManagedAndMergeableIngresses(managedIngresses []v1beta1.Ingress, mergableIngExes map[string]*configs.MergeableIngresses) []v1beta1.Ingress {
 	ings := []v1beta1.Ingress{}
 	ings = append(ings, managedIngresses...)
 	for _, mergableIngEx := range mergableIngExes {
@@ -37,7 +48,7 @@ func (su *statusUpdater) UpdateManagedAndMergeableIngresses(managedIngresses []v
 			ings = append(ings, *minion.Ingress)
 		}
 	}
-	return su.BulkUpdateIngressStatus(ings)
+	return ings
 }
 
 // UpdateMergableIngresses is a convience passthru to update Ingresses with our configs.MergableIngresses type
@@ -74,16 +85,16 @@ func (su *statusUpdater) updateIngressWithStatus(ing v1beta1.Ingress, status []a
 	// for mergable Ingress objects and the update status API call will update annotations, not just status.
 	key, err := su.keyFunc(&ing)
 	if err != nil {
-		glog.V(3).Infof("error getting key for ing: %v", err)
+		logging.V(3).Infof("error getting key for ing: %v", err)
 		return err
 	}
 	ingCopy, exists, err := su.ingLister.GetByKeySafe(key)
 	if err != nil {
-		glog.V(3).Infof("error getting ing from Store by key: %v", err)
+		logging.V(3).Infof("error getting ing from Store by key: %v", err)
 		return err
 	}
 	if !exists {
-		glog.V(3).Infof("ing doesn't exist in Store")
+		logging.V(3).Infof("ing doesn't exist in Store")
 		return nil
 	}
 
@@ -91,26 +102,36 @@ func (su *statusUpdater) updateIngressWithStatus(ing v1beta1.Ingress, status []a
 	clientIngress := su.client.ExtensionsV1beta1().Ingresses(ingCopy.Namespace)
 	_, err = clientIngress.UpdateStatus(ingCopy)
 	if err != nil {
-		glog.V(3).Infof("error setting ingress status: %v", err)
+		logging.V(3).Infof("error setting ingress status: %v", err)
 		err = su.retryStatusUpdate(clientIngress, ingCopy)
 		if err != nil {
-			glog.V(3).Infof("error retrying status update: %v", err)
+			logging.V(3).Infof("error retrying status update: %v", err)
 			return err
 		}
 	}
-	glog.V(3).Infof("updated status for ing: %v %v", ing.Namespace, ing.Name)
+	logging.V(3).Infof("updated status for ing: %v %v", ing.Namespace, ing.Name)
 	return nil
 }
 
 // BulkUpdateIngressStatus sets the status field on the selected Ingresses, specifically
 // the External IP field.
 func (su *statusUpdater) BulkUpdateIngressStatus(ings []v1beta1.Ingress) error {
+	return su.bulkUpdateIngressStatus(context.Background(), ings)
+}
+
+func (su *statusUpdater) bulkUpdateIngressStatus(ctx context.Context, ings []v1beta1.Ingress) error {
 	if len(ings) < 1 {
-		glog.V(3).Info("no ingresses to update")
+		logging.V(3).Info("no ingresses to update")
 		return nil
 	}
 	failed := false
 	for _, ing := range ings {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("stopped updating the status of Ingresses: %v", ctx.Err())
+		default:
+		}
+
 		err := su.updateIngressWithStatus(ing, su.status)
 		if err != nil {
 			failed = true
@@ -128,15 +149,15 @@ func (su *statusUpdater) BulkUpdateIngressStatus(ings []v1beta1.Ingress) error {
 func (su *statusUpdater) retryStatusUpdate(clientIngress extensionsv1beta1.IngressInterface, ingCopy *v1beta1.Ingress) error {
 	apiIng, err := clientIngress.Get(ingCopy.Name, metav1.GetOptions{})
 	if err != nil {
-		glog.V(3).Infof("error getting ingress resource: %v", err)
+		logging.V(3).Infof("error getting ingress resource: %v", err)
 		return err
 	}
 	if !reflect.DeepEqual(ingCopy.Status.LoadBalancer, apiIng.Status.LoadBalancer) {
-		glog.V(3).Infof("retrying update status for ingress: %v, %v", ingCopy.Namespace, ingCopy.Name)
+		logging.V(3).Infof("retrying update status for ingress: %v, %v", ingCopy.Namespace, ingCopy.Name)
 		apiIng.Status.LoadBalancer = ingCopy.Status.LoadBalancer
 		_, err := clientIngress.UpdateStatus(apiIng)
 		if err != nil {
-			glog.V(3).Infof("update retry failed: %v", err)
+			logging.V(3).Infof("update retry failed: %v", err)
 		}
 		return err
 	}
@@ -208,7 +229,7 @@ func (su *statusUpdater) SaveStatusFromExternalService(svc *api_v1.Service) {
 	ips := getExternalServiceAddress(svc)
 	su.externalServiceAddresses = ips
 	if su.externalStatusAddress != "" {
-		glog.V(3).Info("skipping external service address - external-status-address is set and takes precedence")
+		logging.V(3).Info("skipping external service address - external-status-address is set and takes precedence")
 		return
 	}
 	su.saveStatus(ips)
@@ -2,34 +2,71 @@ package k8s
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
-	"github.com/golang/glog"
+	"github.com/nginxinc/kubernetes-ingress/internal/configs"
+	"github.com/nginxinc/kubernetes-ingress/internal/logging"
 	conf_v1alpha1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 )
 
+const (
+	// queueBaseRetryDelay is the delay before the first retry of a failed task
+	queueBaseRetryDelay = time.Second
+	// queueMaxRetryDelay is the maximum delay between the retries of a failed task
+	queueMaxRetryDelay = 5 * time.Minute
+	// queueMaxRetries is the number of retries of a failed task, after which the task is parked
+	queueMaxRetries = 10
+)
+
 // taskQueue manages a work queue through an independent worker that
 // invokes the given sync function for every work item inserted.
+// Failed tasks are retried with an exponential backoff. After queueMaxRetries retries, a task is parked:
+// it is no longer retried until its resource changes, which is detected by the resourceVersion of the resource,
+// and the task is enqueued again, or until its resource is deleted. The tasks that failed because NGINX failed
+// to reload are never parked.
 type taskQueue struct {
 	// queue is the work queue the worker polls
-	queue *workqueue.Type
+	queue workqueue.RateLimitingInterface
 	// sync is called for each item in the queue
 	sync func(task)
+	// park is called for each task that is parked
+	park func(task, error)
 	// workerDone is closed when the worker exits
 	workerDone chan struct{}
+	// syncStartTime is the time when the worker started to sync the current task. It is zero when the worker is idle
+	syncStartTime time.Time
+	// syncStartTimeMutex protects syncStartTime
+	syncStartTimeMutex sync.Mutex
+	// isRequeued is true if the task that the worker is syncing was requeued
+	isRequeued bool
+	// parked holds the resourceVersions of the resources of the parked tasks
+	parked map[task]string
+	// enqueuedVersions holds the resourceVersions of the resources of the tasks enqueued through Enqueue,
+	// which are recorded in parked once the tasks are parked
+	enqueuedVersions map[task]string
+	// parkedMutex protects parked and enqueuedVersions
+	parkedMutex sync.Mutex
 }
 
-// newTaskQueue creates a new task queue with the given sync function.
+// newTaskQueue creates a new task queue with the given sync and park functions.
 // The sync function is called for every element inserted into the queue.
-func newTaskQueue(syncFn func(task)) *taskQueue {
+// The park function is called for every task that is parked after it failed queueMaxRetries times.
+func newTaskQueue(syncFn func(task), parkFn func(task, error)) *taskQueue {
 	return &taskQueue{
-		queue:      workqueue.New(),
-		sync:       syncFn,
-		workerDone: make(chan struct{}),
+		queue:            workqueue.NewRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(queueBaseRetryDelay, queueMaxRetryDelay)),
+		sync:             syncFn,
+		park:             parkFn,
+		workerDone:       make(chan struct{}),
+		parked:           make(map[task]string),
+		enqueuedVersions: make(map[task]string),
 	}
 }
 
@@ -39,40 +76,164 @@ func (tq *taskQueue) Run(period time.Duration, stopCh <-chan struct{}) {
 }
 
 // Enqueue enqueues ns/name of the given api object in the task queue.
+// If the task of the object is parked, the task is unparked only if the resourceVersion of the object differs from
+// the resourceVersion of the resource when the task was parked. Otherwise, the task is not enqueued, so that
+// the resyncs and the changes of the related resources don't retry the parked task.
 func (tq *taskQueue) Enqueue(obj interface{}) {
 	key, err := keyFunc(obj)
 	if err != nil {
-		glog.V(3).Infof("Couldn't get key for object %v: %v", obj, err)
+		logging.V(3).Infof("Couldn't get key for object %v: %v", obj, err)
 		return
 	}
 
 	task, err := newTask(key, obj)
 	if err != nil {
-		glog.V(3).Infof("Couldn't create a task for object %v: %v", obj, err)
+		logging.V(3).Infof("Couldn't create a task for object %v: %v", obj, err)
 		return
 	}
 
-	glog.V(3).Infof("Adding an element with a key: %v", task.Key)
+	if !tq.unparkIfChanged(task, getResourceVersion(obj)) {
+		logging.V(3).Infof("Skipping the parked element with a key %v, because its resource hasn't changed", task.Key)
+		return
+	}
+
+	logging.V(3).Infof("Adding an element with a key: %v", task.Key)
 
 	tq.queue.Add(task)
 }
 
-// Requeue adds the task to the queue again and logs the given error
-func (tq *taskQueue) Requeue(task task, err error) {
-	glog.Errorf("Requeuing %v, err %v", task.Key, err)
+// EnqueueDeleted enqueues ns/name of the given deleted api object in the task queue. Unlike Enqueue, it always unparks
+// the task of the object, because the deleted object still has the resourceVersion of the resource when the task was
+// parked, and it forgets the recorded resourceVersions of the resource.
+func (tq *taskQueue) EnqueueDeleted(obj interface{}) {
+	key, err := keyFunc(obj)
+	if err != nil {
+		logging.V(3).Infof("Couldn't get key for object %v: %v", obj, err)
+		return
+	}
+
+	if deletedState, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = deletedState.Obj
+	}
+
+	task, err := newTask(key, obj)
+	if err != nil {
+		logging.V(3).Infof("Couldn't create a task for object %v: %v", obj, err)
+		return
+	}
+
+	tq.forget(task)
+
+	logging.V(3).Infof("Adding an element with a key: %v", task.Key)
+
 	tq.queue.Add(task)
 }
 
-// RequeueAfter adds the task to the queue after the given duration
-func (tq *taskQueue) RequeueAfter(t task, err error, after time.Duration) {
-	glog.Errorf("Requeuing %v after %s, err %v", t.Key, after.String(), err)
-	go func(t task, after time.Duration) {
-		time.Sleep(after)
-		tq.queue.Add(t)
-	}(t, after)
+// enqueueTask adds the task to the queue
+func (tq *taskQueue) enqueueTask(t task) {
+	logging.V(3).Infof("Adding an element with a key: %v", t.Key)
+	tq.queue.Add(t)
+}
+
+// Requeue adds the task to the queue again after a delay that grows exponentially with the number of retries
+// and logs the given error. If the task has been retried queueMaxRetries times, the task is parked instead,
+// unless the error is a failed reload of NGINX: such a failure might be caused by the main config or by another
+// resource, so the task is retried until NGINX reloads successfully.
+// Requeue must be called from the sync function.
+func (tq *taskQueue) Requeue(task task, err error) {
+	tq.isRequeued = true
+
+	retries := tq.queue.NumRequeues(task)
+	if retries >= queueMaxRetries && !configs.IsReloadError(err) {
+		logging.Errorf("Giving up on %v after %v retries, err %v", task.Key, retries, err)
+		tq.queue.Forget(task)
+
+		tq.parkedMutex.Lock()
+		tq.parked[task] = tq.enqueuedVersions[task]
+		tq.parkedMutex.Unlock()
+
+		tq.park(task, err)
+		return
+	}
+
+	logging.Errorf("Requeuing %v, retry %v, err %v", task.Key, retries+1, err)
+	tq.queue.AddRateLimited(task)
+}
+
+// unparkIfChanged records the resourceVersion of the resource of the task and removes the task from the parked tasks
+// if the resourceVersion differs from the resourceVersion of the resource when the task was parked.
+// It returns false if the task remains parked.
+func (tq *taskQueue) unparkIfChanged(t task, resourceVersion string) bool {
+	tq.parkedMutex.Lock()
+	defer tq.parkedMutex.Unlock()
+
+	if parkedVersion, parked := tq.parked[t]; parked {
+		if parkedVersion == resourceVersion {
+			return false
+		}
+		logging.V(3).Infof("Unparking %v, its resourceVersion changed from %q to %q", t.Key, parkedVersion, resourceVersion)
+		delete(tq.parked, t)
+	}
+
+	tq.enqueuedVersions[t] = resourceVersion
+	return true
+}
+
+// forget unparks the task and removes the recorded resourceVersion of its resource.
+func (tq *taskQueue) forget(t task) {
+	tq.parkedMutex.Lock()
+	defer tq.parkedMutex.Unlock()
+
+	if _, parked := tq.parked[t]; parked {
+		logging.V(3).Infof("Unparking %v, its resource was deleted", t.Key)
+		delete(tq.parked, t)
+	}
+	delete(tq.enqueuedVersions, t)
+}
+
+// forgetVersion removes the recorded resourceVersion of the resource of a task that is synced successfully.
+func (tq *taskQueue) forgetVersion(t task) {
+	tq.parkedMutex.Lock()
+	defer tq.parkedMutex.Unlock()
+
+	delete(tq.enqueuedVersions, t)
+}
+
+// getResourceVersion returns the resourceVersion of the object. It returns an empty string for the objects without
+// the resourceVersion, such as the DeletedFinalStateUnknown objects of the deleted resources.
+func getResourceVersion(obj interface{}) string {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return ""
+	}
+	return accessor.GetResourceVersion()
+}
+
+// The outcomes of a sync of a task.
+const (
+	syncOutcomeSynced   = "synced"
+	syncOutcomeRequeued = "requeued"
+	syncOutcomeParked   = "parked"
+)
+
+// syncOutcome returns the outcome of the sync of the task that the worker is syncing.
+// syncOutcome must be called from the sync function.
+func (tq *taskQueue) syncOutcome(t task) string {
+	if !tq.isRequeued {
+		return syncOutcomeSynced
+	}
+
+	tq.parkedMutex.Lock()
+	defer tq.parkedMutex.Unlock()
+
+	if _, parked := tq.parked[t]; parked {
+		return syncOutcomeParked
+	}
+	return syncOutcomeRequeued
 }
 
 // Worker processes work in the queue through sync.
+// If the task is not requeued by the sync function, its retries are reset.
 func (tq *taskQueue) worker() {
 	for {
 		t, quit := tq.queue.Get()
@@ -80,12 +241,36 @@ func (tq *taskQueue) worker() {
 			close(tq.workerDone)
 			return
 		}
-		glog.V(3).Infof("Syncing %v", t.(task).Key)
+		logging.V(3).Infof("Syncing %v", t.(task).Key)
+		tq.setSyncStartTime(time.Now())
+		tq.isRequeued = false
 		tq.sync(t.(task))
+		if !tq.isRequeued {
+			tq.queue.Forget(t)
+			tq.forgetVersion(t.(task))
+		}
+		tq.setSyncStartTime(time.Time{})
 		tq.queue.Done(t)
 	}
 }
 
+func (tq *taskQueue) setSyncStartTime(t time.Time) {
+	tq.syncStartTimeMutex.Lock()
+	defer tq.syncStartTimeMutex.Unlock()
+	tq.syncStartTime = t
+}
+
+// GetSyncDuration returns for how long the worker has been syncing the current task.
+// It returns 0 if the worker is idle.
+func (tq *taskQueue) GetSyncDuration() time.Duration {
+	tq.syncStartTimeMutex.Lock()
+	defer tq.syncStartTimeMutex.Unlock()
+	if tq.syncStartTime.IsZero() {
+		return 0
+	}
+	return time.Since(tq.syncStartTime)
+}
+
 // Shutdown shuts down the work queue and waits for the worker to ACK
 func (tq *taskQueue) Shutdown() {
 	tq.queue.ShutDown()
@@ -112,8 +297,35 @@ const (
 	virtualserver
 	// virtualServeRoute resource
 	virtualServerRoute
+	// certificate resource, which is a cert-manager Certificate
+	certificate
+	// initialSync is not a resource, but a marker enqueued after the caches are synced. Once the worker
+	// processes the marker, all resources that existed when the caches were synced have been processed.
+	initialSync
+	// certificateExpiryCheck is not a resource, but a marker enqueued periodically to check the expiry of
+	// the certificates of the TLS Secrets.
+	certificateExpiryCheck
 )
 
+// kindNames are the names of the kinds used in logs, events and metrics
+var kindNames = map[kind]string{
+	ingress:                "ingress",
+	ingressMinion:          "ingress",
+	endpoints:              "endpoints",
+	configMap:              "configmap",
+	secret:                 "secret",
+	service:                "service",
+	virtualserver:          "virtualserver",
+	virtualServerRoute:     "virtualserverroute",
+	certificate:            "certificate",
+	initialSync:            "initialsync",
+	certificateExpiryCheck: "certificateexpirycheck",
+}
+
+func (k kind) String() string {
+	return kindNames[k]
+}
+
 // task is an element of a taskQueue
 type task struct {
 	Kind kind
@@ -143,6 +355,8 @@ func newTask(key string, obj interface{}) (task, error) {
 		k = virtualserver
 	case *conf_v1alpha1.VirtualServerRoute:
 		k = virtualServerRoute
+	case *unstructured.Unstructured:
+		k = certificate
 	default:
 		return task{}, fmt.Errorf("Unknow type: %v", t)
 	}
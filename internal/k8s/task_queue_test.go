@@ -0,0 +1,180 @@
+package k8s
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nginxinc/kubernetes-ingress/internal/configs"
+	conf_v1alpha1 "github.com/nginxinc/kubernetes-ingress/pkg/apis/configuration/v1alpha1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestRequeueParksTaskAfterMaxRetries(t *testing.T) {
+	var parkedTasks []task
+	tq := newTaskQueue(func(task) {}, func(t task, err error) {
+		parkedTasks = append(parkedTasks, t)
+	})
+	defer tq.queue.ShutDown()
+
+	vs := &conf_v1alpha1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:            "cafe",
+			Namespace:       "default",
+			ResourceVersion: "1",
+		},
+	}
+	vsTask := task{Kind: virtualserver, Key: "default/cafe"}
+	syncErr := errors.New("invalid config")
+
+	tq.Enqueue(vs)
+
+	for i := 0; i < queueMaxRetries; i++ {
+		tq.Requeue(vsTask, syncErr)
+	}
+	if len(parkedTasks) != 0 {
+		t.Fatalf("Requeue() parked the task after %v retries, expected no parked tasks", queueMaxRetries)
+	}
+	if tq.queue.NumRequeues(vsTask) != queueMaxRetries {
+		t.Errorf("NumRequeues() returned %v, expected %v", tq.queue.NumRequeues(vsTask), queueMaxRetries)
+	}
+
+	tq.Requeue(vsTask, syncErr)
+	if len(parkedTasks) != 1 || parkedTasks[0] != vsTask {
+		t.Fatalf("Requeue() parked %v, expected %v", parkedTasks, []task{vsTask})
+	}
+	if tq.queue.NumRequeues(vsTask) != 0 {
+		t.Errorf("NumRequeues() returned %v for a parked task, expected 0", tq.queue.NumRequeues(vsTask))
+	}
+	if version, parked := tq.parked[vsTask]; !parked || version != "1" {
+		t.Errorf("Requeue() parked the task with the resourceVersion %q (parked %v), expected the resourceVersion \"1\"", version, parked)
+	}
+}
+
+func TestRequeueDoesNotParkTaskOnReloadError(t *testing.T) {
+	var parkedTasks []task
+	tq := newTaskQueue(func(task) {}, func(t task, err error) {
+		parkedTasks = append(parkedTasks, t)
+	})
+	defer tq.queue.ShutDown()
+
+	vsTask := task{Kind: virtualserver, Key: "default/cafe"}
+
+	for i := 0; i <= queueMaxRetries; i++ {
+		tq.Requeue(vsTask, &configs.ReloadError{})
+	}
+	if len(parkedTasks) != 0 {
+		t.Errorf("Requeue() parked %v after a failed reload of NGINX, expected no parked tasks", parkedTasks)
+	}
+	if _, parked := tq.parked[vsTask]; parked {
+		t.Errorf("Requeue() recorded the task as parked after a failed reload of NGINX")
+	}
+	if tq.queue.NumRequeues(vsTask) != queueMaxRetries+1 {
+		t.Errorf("NumRequeues() returned %v, expected %v", tq.queue.NumRequeues(vsTask), queueMaxRetries+1)
+	}
+
+	tq.Requeue(vsTask, errors.New("invalid config"))
+	if len(parkedTasks) != 1 || parkedTasks[0] != vsTask {
+		t.Errorf("Requeue() parked %v after an error of the resource, expected %v", parkedTasks, []task{vsTask})
+	}
+}
+
+func TestEnqueueUnparksTaskOnlyIfResourceChanged(t *testing.T) {
+	tq := newTaskQueue(func(task) {}, func(task, error) {})
+	defer tq.queue.ShutDown()
+
+	vs := &conf_v1alpha1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:            "cafe",
+			Namespace:       "default",
+			ResourceVersion: "1",
+		},
+	}
+	vsTask := task{Kind: virtualserver, Key: "default/cafe"}
+	tq.parked[vsTask] = "1"
+
+	tq.Enqueue(vs)
+	if _, parked := tq.parked[vsTask]; !parked {
+		t.Errorf("Enqueue() removed the task from the parked tasks, although the resourceVersion didn't change")
+	}
+	if tq.queue.Len() != 0 {
+		t.Errorf("Enqueue() added the parked task to the queue, although the resourceVersion didn't change")
+	}
+
+	updatedVS := vs.DeepCopy()
+	updatedVS.ResourceVersion = "2"
+
+	tq.Enqueue(updatedVS)
+	if _, parked := tq.parked[vsTask]; parked {
+		t.Errorf("Enqueue() didn't remove the task from the parked tasks after the resourceVersion changed")
+	}
+	if tq.queue.Len() != 1 {
+		t.Errorf("Enqueue() didn't add the task to the queue after the resourceVersion changed")
+	}
+	if tq.enqueuedVersions[vsTask] != "2" {
+		t.Errorf("Enqueue() recorded the resourceVersion %q, expected \"2\"", tq.enqueuedVersions[vsTask])
+	}
+}
+
+func TestEnqueueDeletedUnparksTask(t *testing.T) {
+	tq := newTaskQueue(func(task) {}, func(task, error) {})
+	defer tq.queue.ShutDown()
+
+	vs := &conf_v1alpha1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:            "cafe",
+			Namespace:       "default",
+			ResourceVersion: "1",
+		},
+	}
+	vsTask := task{Kind: virtualserver, Key: "default/cafe"}
+
+	tq.Enqueue(vs)
+	tq.queue.Get()
+	for i := 0; i <= queueMaxRetries; i++ {
+		tq.Requeue(vsTask, errors.New("invalid config"))
+	}
+	if _, parked := tq.parked[vsTask]; !parked {
+		t.Fatalf("Requeue() didn't park the task after %v retries", queueMaxRetries)
+	}
+	tq.queue.Done(vsTask)
+
+	tq.EnqueueDeleted(vs)
+	if _, parked := tq.parked[vsTask]; parked {
+		t.Errorf("EnqueueDeleted() didn't remove the task of the deleted resource from the parked tasks")
+	}
+	if _, recorded := tq.enqueuedVersions[vsTask]; recorded {
+		t.Errorf("EnqueueDeleted() didn't remove the recorded resourceVersion of the deleted resource")
+	}
+	if tq.queue.Len() != 1 {
+		t.Errorf("EnqueueDeleted() didn't add the task of the deleted resource to the queue")
+	}
+
+	tq.EnqueueDeleted(cache.DeletedFinalStateUnknown{Key: "default/cafe", Obj: vs})
+	if tq.queue.Len() != 1 {
+		t.Errorf("EnqueueDeleted() didn't add the task of a DeletedFinalStateUnknown object to the queue")
+	}
+}
+
+func TestSyncOutcome(t *testing.T) {
+	tq := newTaskQueue(func(task) {}, func(task, error) {})
+	defer tq.queue.ShutDown()
+
+	vsTask := task{Kind: virtualserver, Key: "default/cafe"}
+
+	if outcome := tq.syncOutcome(vsTask); outcome != syncOutcomeSynced {
+		t.Errorf("syncOutcome() returned %q for a task that wasn't requeued, expected %q", outcome, syncOutcomeSynced)
+	}
+
+	tq.Requeue(vsTask, errors.New("invalid config"))
+	if outcome := tq.syncOutcome(vsTask); outcome != syncOutcomeRequeued {
+		t.Errorf("syncOutcome() returned %q for a requeued task, expected %q", outcome, syncOutcomeRequeued)
+	}
+
+	for i := 0; i < queueMaxRetries; i++ {
+		tq.Requeue(vsTask, errors.New("invalid config"))
+	}
+	if outcome := tq.syncOutcome(vsTask); outcome != syncOutcomeParked {
+		t.Errorf("syncOutcome() returned %q for a parked task, expected %q", outcome, syncOutcomeParked)
+	}
+}
@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+)
+
+// defaultLogger is the logger of the package-level logging functions. It is nil until SetDefaultLogger is called,
+// so that the functions log with glog.
+var defaultLogger *Logger
+
+// SetDefaultLogger sets the logger of the package-level logging functions, which the Ingress controller uses
+// instead of the functions of glog, so that all its messages are written in the format of the logger.
+// SetDefaultLogger must be called before the other goroutines of the Ingress controller are started.
+func SetDefaultLogger(l *Logger) {
+	defaultLogger = l
+}
+
+// Info logs a message at the info level using the default logger. Arguments are handled in the manner of fmt.Print.
+func Info(args ...interface{}) {
+	defaultLogger.log(levelInfo, fmt.Sprint(args...), nil)
+}
+
+// Infof logs a message at the info level using the default logger. Arguments are handled in the manner of fmt.Printf.
+func Infof(format string, args ...interface{}) {
+	defaultLogger.log(levelInfo, fmt.Sprintf(format, args...), nil)
+}
+
+// Warning logs a message at the warning level using the default logger.
+// Arguments are handled in the manner of fmt.Print.
+func Warning(args ...interface{}) {
+	defaultLogger.log(levelWarning, fmt.Sprint(args...), nil)
+}
+
+// Warningf logs a message at the warning level using the default logger.
+// Arguments are handled in the manner of fmt.Printf.
+func Warningf(format string, args ...interface{}) {
+	defaultLogger.log(levelWarning, fmt.Sprintf(format, args...), nil)
+}
+
+// Error logs a message at the error level using the default logger. Arguments are handled in the manner of fmt.Print.
+func Error(args ...interface{}) {
+	defaultLogger.log(levelError, fmt.Sprint(args...), nil)
+}
+
+// Errorf logs a message at the error level using the default logger.
+// Arguments are handled in the manner of fmt.Printf.
+func Errorf(format string, args ...interface{}) {
+	defaultLogger.log(levelError, fmt.Sprintf(format, args...), nil)
+}
+
+// Fatal logs a message at the fatal level using the default logger and exits with the exit code 255, like glog.Fatal.
+// Arguments are handled in the manner of fmt.Print.
+func Fatal(args ...interface{}) {
+	defaultLogger.log(levelFatal, fmt.Sprint(args...), nil)
+	os.Exit(fatalExitCode)
+}
+
+// Fatalf logs a message at the fatal level using the default logger and exits with the exit code 255,
+// like glog.Fatalf. Arguments are handled in the manner of fmt.Printf.
+func Fatalf(format string, args ...interface{}) {
+	defaultLogger.log(levelFatal, fmt.Sprintf(format, args...), nil)
+	os.Exit(fatalExitCode)
+}
+
+// V returns a Verbose of the default logger that logs the info messages if the verbosity is at least the given level.
+func V(level int32) Verbose {
+	return defaultLogger.V(level)
+}
+
+// Infof logs a message at the info level if the verbosity is high enough.
+// Arguments are handled in the manner of fmt.Printf.
+func (v Verbose) Infof(format string, args ...interface{}) {
+	if v.enabled {
+		v.logger.log(levelInfo, fmt.Sprintf(format, args...), nil)
+	}
+}
@@ -0,0 +1,21 @@
+package logging
+
+import "testing"
+
+func TestDefaultLogger(t *testing.T) {
+	logger, buf := newTestLogger(t, FormatLogfmt)
+	SetDefaultLogger(logger)
+	defer SetDefaultLogger(nil)
+
+	Infof("Updated %v/%v", "default", "cafe")
+	Warning("Invalid value: ", 10)
+	Errorf("Error reloading NGINX: %v", "exit status 1")
+	V(Verbosity()+1).Infof("Reloaded %v", "nginx")
+
+	expected := "ts=2020-01-02T03:04:05Z level=info msg=\"Updated default/cafe\"\n" +
+		"ts=2020-01-02T03:04:05Z level=warning msg=\"Invalid value: 10\"\n" +
+		"ts=2020-01-02T03:04:05Z level=error msg=\"Error reloading NGINX: exit status 1\"\n"
+	if buf.String() != expected {
+		t.Errorf("The package-level functions wrote %q, expected %q", buf.String(), expected)
+	}
+}
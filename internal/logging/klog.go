@@ -0,0 +1,61 @@
+package logging
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+
+	"k8s.io/klog"
+)
+
+// RedirectKlog makes klog, which the Kubernetes client library uses, write its messages with the default logger.
+// RedirectKlog must be called after SetDefaultLogger.
+func RedirectKlog() error {
+	flagSet := flag.NewFlagSet("klog", flag.ContinueOnError)
+	klog.InitFlags(flagSet)
+
+	// klog writes the messages to the outputs set by SetOutputBySeverity only if it doesn't log to stderr
+	if err := flagSet.Set("logtostderr", "false"); err != nil {
+		return fmt.Errorf("error setting logtostderr of klog: %v", err)
+	}
+	if err := flagSet.Set("stderrthreshold", "FATAL"); err != nil {
+		return fmt.Errorf("error setting stderrthreshold of klog: %v", err)
+	}
+
+	// klog writes a message to the outputs of its severity and of all lower severities,
+	// so every message is written to the output of the info severity exactly once
+	klog.SetOutputBySeverity("INFO", klogWriter{})
+	for _, severity := range []string{"WARNING", "ERROR", "FATAL"} {
+		klog.SetOutputBySeverity(severity, ioutil.Discard)
+	}
+
+	return nil
+}
+
+// klogWriter logs the messages of klog with the default logger. klog writes every message with a header like
+// "I0102 03:04:05.123456   12345 reflector.go:125] ", which starts with the severity and ends with the caller.
+type klogWriter struct{}
+
+func (klogWriter) Write(p []byte) (int, error) {
+	level := levelInfo
+	msg := bytes.TrimRight(p, "\n")
+	var fields []interface{}
+
+	if end := bytes.Index(msg, []byte("] ")); end > 0 {
+		if header := bytes.Fields(msg[:end]); len(header) == 4 {
+			switch header[0][0] {
+			case 'W':
+				level = levelWarning
+			case 'E', 'F':
+				// klog exits after a fatal message itself
+				level = levelError
+			}
+			fields = []interface{}{"caller", string(header[3])}
+			msg = msg[end+2:]
+		}
+	}
+
+	defaultLogger.log(level, string(msg), fields)
+	return len(p), nil
+}
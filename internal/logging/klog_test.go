@@ -0,0 +1,47 @@
+package logging
+
+import "testing"
+
+func TestKlogWriter(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected string
+		msg      string
+	}{
+		{
+			line:     "I0102 03:04:05.123456   12345 reflector.go:125] Listing and watching *v1.Secret\n",
+			expected: "ts=2020-01-02T03:04:05Z level=info msg=\"Listing and watching *v1.Secret\" caller=reflector.go:125\n",
+			msg:      "info message",
+		},
+		{
+			line:     "W0102 03:04:05.123456   12345 reflector.go:302] watch of *v1.Secret ended\n",
+			expected: "ts=2020-01-02T03:04:05Z level=warning msg=\"watch of *v1.Secret ended\" caller=reflector.go:302\n",
+			msg:      "warning message",
+		},
+		{
+			line:     "E0102 03:04:05.123456   12345 leaderelection.go:306] error retrieving resource lock\n",
+			expected: "ts=2020-01-02T03:04:05Z level=error msg=\"error retrieving resource lock\" caller=leaderelection.go:306\n",
+			msg:      "error message",
+		},
+		{
+			line:     "message without a header\n",
+			expected: "ts=2020-01-02T03:04:05Z level=info msg=\"message without a header\"\n",
+			msg:      "message without a header",
+		},
+	}
+
+	for _, test := range tests {
+		logger, buf := newTestLogger(t, FormatLogfmt)
+		SetDefaultLogger(logger)
+
+		n, err := klogWriter{}.Write([]byte(test.line))
+		if err != nil || n != len(test.line) {
+			t.Errorf("Write() returned %v, %v for the case of %s, expected %v, nil", n, err, test.msg, len(test.line))
+		}
+		if buf.String() != test.expected {
+			t.Errorf("Write() logged %q for the case of %s, expected %q", buf.String(), test.msg, test.expected)
+		}
+	}
+
+	SetDefaultLogger(nil)
+}
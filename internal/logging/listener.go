@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// levelEndpoint is the path where the verbosity of the logs of the Ingress controller is exposed
+const levelEndpoint = "/log-level"
+
+// RunLevelListener runs an http server to expose the log level endpoint of the Ingress controller.
+// A GET request returns the current verbosity. A PUT request with the level query parameter, such as
+// /log-level?level=3, changes the verbosity. The endpoint is optional, so if the listener fails, the error is logged
+// and the Ingress controller keeps running.
+func RunLevelListener(ip string, port int) {
+	address := net.JoinHostPort(ip, fmt.Sprint(port))
+	Infof("Starting log level listener on: %v%v", address, levelEndpoint)
+	Errorf("Error in log level listener server: %v", http.ListenAndServe(address, newLevelHandler()))
+}
+
+func newLevelHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc(levelEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			writeResponse(w, http.StatusOK, fmt.Sprintf("%v\n", Verbosity()))
+		case http.MethodPut:
+			value := r.URL.Query().Get("level")
+			level, err := strconv.ParseInt(value, 10, 32)
+			if err != nil {
+				writeResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid level %q: must be a number\n", value))
+				return
+			}
+			if err := SetVerbosity(int32(level)); err != nil {
+				writeResponse(w, http.StatusBadRequest, fmt.Sprintf("%v\n", err))
+				return
+			}
+			Infof("Changed the log level to %v", level)
+			writeResponse(w, http.StatusOK, fmt.Sprintf("%v\n", level))
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			writeResponse(w, http.StatusMethodNotAllowed, "method not allowed\n")
+		}
+	})
+
+	return mux
+}
+
+func writeResponse(w http.ResponseWriter, code int, message string) {
+	w.WriteHeader(code)
+	_, err := w.Write([]byte(message))
+	if err != nil {
+		Warningf("Error while sending a response for the log level: %v", err)
+	}
+}
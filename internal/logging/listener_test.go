@@ -0,0 +1,85 @@
+package logging
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLevelHandler(t *testing.T) {
+	original := Verbosity()
+	defer func() {
+		if err := SetVerbosity(original); err != nil {
+			t.Fatalf("SetVerbosity() returned an unexpected error: %v", err)
+		}
+	}()
+
+	tests := []struct {
+		method       string
+		target       string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			method:       http.MethodPut,
+			target:       "/log-level?level=3",
+			expectedCode: http.StatusOK,
+			expectedBody: "3\n",
+		},
+		{
+			method:       http.MethodGet,
+			target:       "/log-level",
+			expectedCode: http.StatusOK,
+			expectedBody: "3\n",
+		},
+		{
+			method:       http.MethodPut,
+			target:       "/log-level?level=high",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			method:       http.MethodPut,
+			target:       "/log-level?level=-1",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			method:       http.MethodPost,
+			target:       "/log-level?level=1",
+			expectedCode: http.StatusMethodNotAllowed,
+		},
+	}
+
+	handler := newLevelHandler()
+
+	for _, test := range tests {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(test.method, test.target, nil)
+
+		handler.ServeHTTP(recorder, request)
+
+		if recorder.Code != test.expectedCode {
+			t.Errorf("%v %v returned code %v but expected %v", test.method, test.target, recorder.Code, test.expectedCode)
+		}
+		if test.expectedBody != "" && recorder.Body.String() != test.expectedBody {
+			t.Errorf("%v %v returned body %q but expected %q", test.method, test.target, recorder.Body.String(), test.expectedBody)
+		}
+	}
+
+	if Verbosity() != 3 {
+		t.Errorf("Verbosity() returned %v after the requests, expected 3", Verbosity())
+	}
+}
+
+func TestRunLevelListenerReturnsIfPortIsTaken(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to create a listener: %v", err)
+	}
+	defer listener.Close()
+
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	// RunLevelListener must return instead of exiting the process
+	RunLevelListener("127.0.0.1", port)
+}
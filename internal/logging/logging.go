@@ -0,0 +1,310 @@
+// Package logging implements the structured logger of the Ingress controller. The logger writes messages with
+// key-value fields in the glog, JSON or logfmt format. The package-level functions, which mirror the functions of
+// glog, write the other messages of the Ingress controller with the default logger in the same format.
+// The verbosity of the logger is the -v flag of glog, so that changing it at runtime also affects glog.V().
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// The formats of the logger.
+const (
+	// FormatGlog writes the messages using glog with the fields appended to the message in the logfmt format.
+	FormatGlog = "glog"
+	// FormatJSON writes every message as a JSON object.
+	FormatJSON = "json"
+	// FormatLogfmt writes every message as a line of logfmt key=value pairs.
+	FormatLogfmt = "logfmt"
+)
+
+// The levels of the messages.
+const (
+	levelInfo    = "info"
+	levelWarning = "warning"
+	levelError   = "error"
+	levelFatal   = "fatal"
+)
+
+// fatalExitCode is the exit code after a message at the fatal level, which is the exit code of glog.Fatal.
+const fatalExitCode = 255
+
+// Logger writes messages with key-value fields. A nil Logger writes the messages using glog.
+type Logger struct {
+	format string
+	out    *syncWriter
+	fields []interface{}
+	now    func() time.Time
+}
+
+// syncWriter serializes the writes of the loggers that share an output.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (sw *syncWriter) write(p []byte) {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	// nothing can be done about an error of writing a log message
+	_, _ = sw.w.Write(p)
+}
+
+// NewLogger creates a Logger that writes the messages in the given format to out.
+// For the glog format, out is ignored.
+func NewLogger(format string, out io.Writer) (*Logger, error) {
+	switch format {
+	case FormatGlog, FormatJSON, FormatLogfmt:
+	default:
+		return nil, fmt.Errorf("invalid log format %q, must be one of %v, %v or %v", format, FormatGlog, FormatJSON, FormatLogfmt)
+	}
+
+	return &Logger{
+		format: format,
+		out:    &syncWriter{w: out},
+		now:    time.Now,
+	}, nil
+}
+
+// With returns a Logger that adds the given key-value pairs to every message.
+func (l *Logger) With(keysAndValues ...interface{}) *Logger {
+	if l == nil {
+		l = &Logger{format: FormatGlog}
+	}
+
+	fields := make([]interface{}, 0, len(l.fields)+len(keysAndValues))
+	fields = append(fields, l.fields...)
+	fields = append(fields, keysAndValues...)
+
+	return &Logger{
+		format: l.format,
+		out:    l.out,
+		fields: fields,
+		now:    l.now,
+	}
+}
+
+// Info logs a message at the info level.
+func (l *Logger) Info(msg string, keysAndValues ...interface{}) {
+	l.log(levelInfo, msg, keysAndValues)
+}
+
+// Warning logs a message at the warning level.
+func (l *Logger) Warning(msg string, keysAndValues ...interface{}) {
+	l.log(levelWarning, msg, keysAndValues)
+}
+
+// Error logs a message at the error level.
+func (l *Logger) Error(msg string, keysAndValues ...interface{}) {
+	l.log(levelError, msg, keysAndValues)
+}
+
+// Verbose logs the info messages only if the verbosity of the logger is high enough. See Logger.V.
+type Verbose struct {
+	logger  *Logger
+	enabled bool
+}
+
+// V returns a Verbose that logs the info messages if the verbosity is at least the given level, similarly to glog.V.
+func (l *Logger) V(level int32) Verbose {
+	return Verbose{
+		logger:  l,
+		enabled: Verbosity() >= level,
+	}
+}
+
+// Info logs a message at the info level if the verbosity is high enough.
+func (v Verbose) Info(msg string, keysAndValues ...interface{}) {
+	if v.enabled {
+		v.logger.log(levelInfo, msg, keysAndValues)
+	}
+}
+
+// logDepth is the depth of the caller of a logging method of Logger or Verbose relative to glog.XDepth functions.
+const logDepth = 2
+
+func (l *Logger) log(level string, msg string, keysAndValues []interface{}) {
+	var fields []interface{}
+	format := FormatGlog
+	if l != nil {
+		fields = append(append(fields, l.fields...), keysAndValues...)
+		format = l.format
+	} else {
+		fields = keysAndValues
+	}
+
+	switch format {
+	case FormatJSON:
+		l.out.write(encodeJSON(l.now(), level, msg, fields))
+	case FormatLogfmt:
+		l.out.write(encodeLogfmt(l.now(), level, msg, fields))
+	default:
+		logWithGlog(level, msg, fields)
+	}
+}
+
+func logWithGlog(level string, msg string, fields []interface{}) {
+	var buf bytes.Buffer
+	buf.WriteString(msg)
+	forEachField(fields, func(key string, value interface{}) {
+		buf.WriteByte(' ')
+		writeLogfmtPair(&buf, key, value)
+	})
+
+	switch level {
+	case levelFatal:
+		glog.FatalDepth(logDepth+1, buf.String())
+	case levelError:
+		glog.ErrorDepth(logDepth+1, buf.String())
+	case levelWarning:
+		glog.WarningDepth(logDepth+1, buf.String())
+	default:
+		glog.InfoDepth(logDepth+1, buf.String())
+	}
+}
+
+func encodeJSON(ts time.Time, level string, msg string, fields []interface{}) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString(`{"ts":`)
+	writeJSONValue(&buf, ts.UTC().Format(time.RFC3339Nano))
+	buf.WriteString(`,"level":`)
+	writeJSONValue(&buf, level)
+	buf.WriteString(`,"msg":`)
+	writeJSONValue(&buf, msg)
+
+	forEachField(fields, func(key string, value interface{}) {
+		buf.WriteByte(',')
+		writeJSONValue(&buf, key)
+		buf.WriteByte(':')
+		writeJSONValue(&buf, normalizeValue(value))
+	})
+
+	buf.WriteString("}\n")
+	return buf.Bytes()
+}
+
+func writeJSONValue(buf *bytes.Buffer, value interface{}) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		b, _ = json.Marshal(fmt.Sprintf("%v", value))
+	}
+	buf.Write(b)
+}
+
+func encodeLogfmt(ts time.Time, level string, msg string, fields []interface{}) []byte {
+	var buf bytes.Buffer
+
+	writeLogfmtPair(&buf, "ts", ts.UTC().Format(time.RFC3339Nano))
+	buf.WriteByte(' ')
+	writeLogfmtPair(&buf, "level", level)
+	buf.WriteByte(' ')
+	writeLogfmtPair(&buf, "msg", msg)
+
+	forEachField(fields, func(key string, value interface{}) {
+		buf.WriteByte(' ')
+		writeLogfmtPair(&buf, key, value)
+	})
+
+	buf.WriteByte('\n')
+	return buf.Bytes()
+}
+
+func writeLogfmtPair(buf *bytes.Buffer, key string, value interface{}) {
+	buf.WriteString(key)
+	buf.WriteByte('=')
+
+	var s string
+	switch v := normalizeValue(value).(type) {
+	case nil:
+		s = "null"
+	case string:
+		s = v
+	default:
+		s = fmt.Sprintf("%v", v)
+	}
+
+	if s == "" || strings.ContainsAny(s, " =\"\\\t\r\n") {
+		s = strconv.Quote(s)
+	}
+	buf.WriteString(s)
+}
+
+// normalizeValue converts the values that don't have a natural representation in JSON or logfmt to strings.
+func normalizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v
+	case time.Duration:
+		return v.String()
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprintf("%+v", v)
+	}
+}
+
+// forEachField calls fn for every key-value pair of the fields. A key without a value gets a nil value.
+func forEachField(fields []interface{}, fn func(key string, value interface{})) {
+	for i := 0; i < len(fields); i += 2 {
+		key := fmt.Sprintf("%v", fields[i])
+		var value interface{}
+		if i+1 < len(fields) {
+			value = fields[i+1]
+		}
+		fn(key, value)
+	}
+}
+
+// verbosityMutex serializes the reads and the changes of the -v flag, because the value of the flag is not safe for
+// concurrent use.
+var verbosityMutex sync.Mutex
+
+// Verbosity returns the current verbosity, which is the value of the -v flag of glog.
+func Verbosity() int32 {
+	f := flag.Lookup("v")
+	if f == nil {
+		return 0
+	}
+
+	verbosityMutex.Lock()
+	defer verbosityMutex.Unlock()
+
+	if getter, ok := f.Value.(flag.Getter); ok {
+		if level, ok := getter.Get().(glog.Level); ok {
+			return int32(level)
+		}
+	}
+	return 0
+}
+
+// SetVerbosity sets the verbosity of the logger and of glog. It is safe for concurrent use. Unlike flag.Set, it
+// doesn't record the flag as set in the FlagSet, which is not safe for concurrent use.
+func SetVerbosity(level int32) error {
+	if level < 0 {
+		return fmt.Errorf("invalid verbosity %v, must not be negative", level)
+	}
+
+	f := flag.Lookup("v")
+	if f == nil {
+		return errors.New("the -v flag is not defined")
+	}
+
+	verbosityMutex.Lock()
+	defer verbosityMutex.Unlock()
+
+	return f.Value.Set(strconv.Itoa(int(level)))
+}
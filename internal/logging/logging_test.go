@@ -0,0 +1,144 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestLogger(t *testing.T, format string) (*Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(format, &buf)
+	if err != nil {
+		t.Fatalf("NewLogger() returned an unexpected error: %v", err)
+	}
+	logger.now = func() time.Time {
+		return time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	}
+	return logger, &buf
+}
+
+func TestLoggerFormats(t *testing.T) {
+	tests := []struct {
+		format   string
+		expected string
+		msg      string
+	}{
+		{
+			format:   FormatJSON,
+			expected: `{"ts":"2020-01-02T03:04:05Z","level":"info","msg":"Synced resource","kind":"VirtualServer","namespace":"default","name":"cafe","duration":"1.5s","outcome":"synced"}` + "\n",
+			msg:      "json format",
+		},
+		{
+			format:   FormatLogfmt,
+			expected: `ts=2020-01-02T03:04:05Z level=info msg="Synced resource" kind=VirtualServer namespace=default name=cafe duration=1.5s outcome=synced` + "\n",
+			msg:      "logfmt format",
+		},
+	}
+
+	for _, test := range tests {
+		logger, buf := newTestLogger(t, test.format)
+
+		logger.With("kind", "VirtualServer", "namespace", "default", "name", "cafe").
+			Info("Synced resource", "duration", 1500*time.Millisecond, "outcome", "synced")
+
+		if buf.String() != test.expected {
+			t.Errorf("Info() wrote %q but expected %q for the case of %s", buf.String(), test.expected, test.msg)
+		}
+	}
+}
+
+func TestLoggerEncodesValues(t *testing.T) {
+	tests := []struct {
+		format   string
+		expected string
+		msg      string
+	}{
+		{
+			format:   FormatJSON,
+			expected: `{"ts":"2020-01-02T03:04:05Z","level":"error","msg":"Failed to reload NGINX","configVersion":2,"error":"exit status 1","resourceVersion":"","missing":null}` + "\n",
+			msg:      "json format",
+		},
+		{
+			format:   FormatLogfmt,
+			expected: `ts=2020-01-02T03:04:05Z level=error msg="Failed to reload NGINX" configVersion=2 error="exit status 1" resourceVersion="" missing=null` + "\n",
+			msg:      "logfmt format",
+		},
+	}
+
+	for _, test := range tests {
+		logger, buf := newTestLogger(t, test.format)
+
+		logger.Error("Failed to reload NGINX", "configVersion", 2, "error", errors.New("exit status 1"), "resourceVersion", "", "missing")
+
+		if buf.String() != test.expected {
+			t.Errorf("Error() wrote %q but expected %q for the case of %s", buf.String(), test.expected, test.msg)
+		}
+	}
+}
+
+func TestWithDoesNotModifyParentLogger(t *testing.T) {
+	logger, buf := newTestLogger(t, FormatLogfmt)
+
+	logger.With("kind", "Ingress")
+	logger.Info("Reloaded NGINX")
+
+	expected := `ts=2020-01-02T03:04:05Z level=info msg="Reloaded NGINX"` + "\n"
+	if buf.String() != expected {
+		t.Errorf("Info() wrote %q but expected %q", buf.String(), expected)
+	}
+}
+
+func TestNewLoggerFails(t *testing.T) {
+	_, err := NewLogger("text", &bytes.Buffer{})
+	if err == nil {
+		t.Errorf("NewLogger() returned no error for an invalid format")
+	}
+}
+
+func TestVerbose(t *testing.T) {
+	logger, buf := newTestLogger(t, FormatLogfmt)
+
+	logger.V(Verbosity() + 1).Info("Updated config")
+	if buf.Len() != 0 {
+		t.Errorf("V().Info() wrote %q for a level above the verbosity, expected nothing", buf.String())
+	}
+
+	logger.V(Verbosity()).Info("Updated config")
+	if buf.Len() == 0 {
+		t.Errorf("V().Info() wrote nothing for the current verbosity")
+	}
+}
+
+func TestSetVerbosityConcurrently(t *testing.T) {
+	original := Verbosity()
+	defer func() {
+		if err := SetVerbosity(original); err != nil {
+			t.Fatalf("SetVerbosity() returned an unexpected error: %v", err)
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(level int32) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if err := SetVerbosity(level); err != nil {
+					t.Errorf("SetVerbosity(%v) returned an unexpected error: %v", level, err)
+				}
+				Verbosity()
+			}
+		}(int32(i))
+	}
+	wg.Wait()
+
+	if err := SetVerbosity(4); err != nil {
+		t.Fatalf("SetVerbosity() returned an unexpected error: %v", err)
+	}
+	if Verbosity() != 4 {
+		t.Errorf("Verbosity() returned %v, expected 4", Verbosity())
+	}
+}
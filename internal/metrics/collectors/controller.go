@@ -1,18 +1,39 @@
 package collectors
 
-import "github.com/prometheus/client_golang/prometheus"
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
 
 var labelNamesController = []string{"type"}
 
+var labelNamesSyncFailures = []string{"kind"}
+
+var labelNamesCertificateExpiry = []string{"secret_namespace", "secret_name"}
+
 // ControllerCollector is an interface for the metrics of the Controller
 type ControllerCollector interface {
 	SetIngressResources(ingressType string, count int)
+	IncSyncFailures(kind string)
+	SetCertificateExpiry(secretNamespace string, secretName string, expiry time.Time)
+	DeleteCertificateExpiry(secretNamespace string, secretName string)
 	Register(registry *prometheus.Registry) error
 }
 
 // ControllerMetricsCollector implements the ControllerCollector interface and prometheus.Collector interface
 type ControllerMetricsCollector struct {
 	ingressResourcesTotal *prometheus.GaugeVec
+	syncFailuresTotal     *prometheus.CounterVec
+	certificateExpiryDesc *prometheus.Desc
+	certificateExpiries   map[certificateExpiryKey]time.Time
+	certificateMutex      sync.Mutex
+}
+
+type certificateExpiryKey struct {
+	namespace string
+	name      string
 }
 
 // NewControllerMetricsCollector creates a new ControllerMetricsCollector
@@ -26,6 +47,21 @@ func NewControllerMetricsCollector() *ControllerMetricsCollector {
 			},
 			labelNamesController,
 		),
+		syncFailuresTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:      "sync_failures_total",
+				Namespace: metricsNamespace,
+				Help:      "Number of resources that failed to sync after all retries",
+			},
+			labelNamesSyncFailures,
+		),
+		certificateExpiryDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(metricsNamespace, "", "certificate_expiry_seconds"),
+			"Number of seconds until the certificate of a TLS Secret expires, negative if it has already expired",
+			labelNamesCertificateExpiry,
+			nil,
+		),
+		certificateExpiries: make(map[certificateExpiryKey]time.Time),
 	}
 
 	return cc
@@ -36,14 +72,46 @@ func (cc *ControllerMetricsCollector) SetIngressResources(ingressType string, co
 	cc.ingressResourcesTotal.WithLabelValues(ingressType).Set(float64(count))
 }
 
+// IncSyncFailures increments the counter of the resources of a given kind that failed to sync after all retries
+func (cc *ControllerMetricsCollector) IncSyncFailures(kind string) {
+	cc.syncFailuresTotal.WithLabelValues(kind).Inc()
+}
+
+// SetCertificateExpiry sets the expiry time of the certificate of a TLS Secret.
+// The number of seconds until the expiry is calculated when the metrics are collected.
+func (cc *ControllerMetricsCollector) SetCertificateExpiry(secretNamespace string, secretName string, expiry time.Time) {
+	cc.certificateMutex.Lock()
+	defer cc.certificateMutex.Unlock()
+
+	cc.certificateExpiries[certificateExpiryKey{namespace: secretNamespace, name: secretName}] = expiry
+}
+
+// DeleteCertificateExpiry deletes the expiry time of the certificate of a TLS Secret
+func (cc *ControllerMetricsCollector) DeleteCertificateExpiry(secretNamespace string, secretName string) {
+	cc.certificateMutex.Lock()
+	defer cc.certificateMutex.Unlock()
+
+	delete(cc.certificateExpiries, certificateExpiryKey{namespace: secretNamespace, name: secretName})
+}
+
 // Describe implements prometheus.Collector interface Describe method
 func (cc *ControllerMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
 	cc.ingressResourcesTotal.Describe(ch)
+	cc.syncFailuresTotal.Describe(ch)
+	ch <- cc.certificateExpiryDesc
 }
 
 // Collect implements the prometheus.Collector interface Collect method
 func (cc *ControllerMetricsCollector) Collect(ch chan<- prometheus.Metric) {
 	cc.ingressResourcesTotal.Collect(ch)
+	cc.syncFailuresTotal.Collect(ch)
+
+	cc.certificateMutex.Lock()
+	defer cc.certificateMutex.Unlock()
+
+	for key, expiry := range cc.certificateExpiries {
+		ch <- prometheus.MustNewConstMetric(cc.certificateExpiryDesc, prometheus.GaugeValue, time.Until(expiry).Seconds(), key.namespace, key.name)
+	}
 }
 
 // Register registers all the metrics of the collector
@@ -64,3 +132,14 @@ func (cc *ControllerFakeCollector) Register(registry *prometheus.Registry) error
 
 // SetIngressResources implements a fake SetIngressResources
 func (cc *ControllerFakeCollector) SetIngressResources(ingressType string, count int) {}
+
+// IncSyncFailures implements a fake IncSyncFailures
+func (cc *ControllerFakeCollector) IncSyncFailures(kind string) {}
+
+// SetCertificateExpiry implements a fake SetCertificateExpiry
+func (cc *ControllerFakeCollector) SetCertificateExpiry(secretNamespace string, secretName string, expiry time.Time) {
+}
+
+// DeleteCertificateExpiry implements a fake DeleteCertificateExpiry
+func (cc *ControllerFakeCollector) DeleteCertificateExpiry(secretNamespace string, secretName string) {
+}
@@ -0,0 +1,253 @@
+package collectors
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// UpstreamServerLabels are the names of the labels added to the NGINX Plus upstream and upstream server metrics.
+var UpstreamServerLabels = []string{"service", "resource_type", "resource_name", "resource_namespace"}
+
+// UpstreamServerPeerLabels are the names of the labels added to the NGINX Plus upstream server metrics.
+var UpstreamServerPeerLabels = []string{"pod_name"}
+
+// ServerZoneLabels are the names of the labels added to the NGINX Plus server zone metrics.
+var ServerZoneLabels = []string{"resource_type", "resource_name", "resource_namespace"}
+
+// LabelUpdater is an interface for updating the Kubernetes resource labels of the NGINX Plus metrics.
+type LabelUpdater interface {
+	UpdateUpstreamServerLabels(upstreamServerLabelValues map[string][]string)
+	DeleteUpstreamServerLabels(upstreamNames []string)
+	UpdateUpstreamServerPeerLabels(upstreamServerPeerLabelValues map[string][]string)
+	DeleteUpstreamServerPeerLabels(peers []string)
+	UpdateServerZoneLabels(resource string, serverZoneLabelValues map[string][]string)
+	DeleteServerZoneLabels(resource string, zoneNames []string)
+}
+
+// UpstreamServerPeerKey returns the key of an upstream server peer for the UpdateUpstreamServerPeerLabels
+// and DeleteUpstreamServerPeerLabels methods.
+func UpstreamServerPeerKey(upstream string, server string) string {
+	return upstream + "/" + server
+}
+
+// ResourceLabels implements the LabelUpdater interface. It keeps the label values of the upstreams, upstream server
+// peers and server zones, and adds them to the NGINX Plus metrics gathered through a prometheus.Gatherer.
+type ResourceLabels struct {
+	mu                            sync.RWMutex
+	upstreamServerLabelValues     map[string][]string
+	upstreamServerPeerLabelValues map[string][]string
+	// serverZoneLabelValues holds the label values of the server zones by the zone and then by the resource,
+	// because the resources that share a host share its server zone.
+	serverZoneLabelValues map[string]map[string][]string
+}
+
+// NewResourceLabels creates a new ResourceLabels.
+func NewResourceLabels() *ResourceLabels {
+	return &ResourceLabels{
+		upstreamServerLabelValues:     make(map[string][]string),
+		upstreamServerPeerLabelValues: make(map[string][]string),
+		serverZoneLabelValues:         make(map[string]map[string][]string),
+	}
+}
+
+// UpdateUpstreamServerLabels adds or updates the label values of the given upstreams.
+func (rl *ResourceLabels) UpdateUpstreamServerLabels(upstreamServerLabelValues map[string][]string) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	for name, values := range upstreamServerLabelValues {
+		rl.upstreamServerLabelValues[name] = values
+	}
+}
+
+// DeleteUpstreamServerLabels deletes the label values of the given upstreams.
+func (rl *ResourceLabels) DeleteUpstreamServerLabels(upstreamNames []string) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	for _, name := range upstreamNames {
+		delete(rl.upstreamServerLabelValues, name)
+	}
+}
+
+// UpdateUpstreamServerPeerLabels adds or updates the label values of the given upstream server peers.
+// The keys of the map must be generated by UpstreamServerPeerKey.
+func (rl *ResourceLabels) UpdateUpstreamServerPeerLabels(upstreamServerPeerLabelValues map[string][]string) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	for peer, values := range upstreamServerPeerLabelValues {
+		rl.upstreamServerPeerLabelValues[peer] = values
+	}
+}
+
+// DeleteUpstreamServerPeerLabels deletes the label values of the given upstream server peers.
+func (rl *ResourceLabels) DeleteUpstreamServerPeerLabels(peers []string) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	for _, peer := range peers {
+		delete(rl.upstreamServerPeerLabelValues, peer)
+	}
+}
+
+// UpdateServerZoneLabels adds or updates the label values of the given server zones for the resource.
+// The resource is a unique name of the resource, such as the name of its configuration file.
+func (rl *ResourceLabels) UpdateServerZoneLabels(resource string, serverZoneLabelValues map[string][]string) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	for zone, values := range serverZoneLabelValues {
+		if rl.serverZoneLabelValues[zone] == nil {
+			rl.serverZoneLabelValues[zone] = make(map[string][]string)
+		}
+		rl.serverZoneLabelValues[zone][resource] = values
+	}
+}
+
+// DeleteServerZoneLabels deletes the label values of the given server zones for the resource.
+// The label values of the server zones for other resources are kept.
+func (rl *ResourceLabels) DeleteServerZoneLabels(resource string, zoneNames []string) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	for _, zone := range zoneNames {
+		delete(rl.serverZoneLabelValues[zone], resource)
+		if len(rl.serverZoneLabelValues[zone]) == 0 {
+			delete(rl.serverZoneLabelValues, zone)
+		}
+	}
+}
+
+// WrapGatherer returns a prometheus.Gatherer that adds the resource labels to the NGINX Plus upstream,
+// upstream server and server zone metrics with the given namespace gathered by the gatherer.
+func (rl *ResourceLabels) WrapGatherer(gatherer prometheus.Gatherer, namespace string) prometheus.Gatherer {
+	return &labeledGatherer{
+		gatherer:       gatherer,
+		upstreamPrefix: namespace + "_upstream_",
+		zonePrefix:     namespace + "_server_zone_",
+		labels:         rl,
+	}
+}
+
+type labeledGatherer struct {
+	gatherer       prometheus.Gatherer
+	upstreamPrefix string
+	zonePrefix     string
+	labels         *ResourceLabels
+}
+
+// Gather implements the prometheus.Gatherer interface Gather method.
+func (lg *labeledGatherer) Gather() ([]*dto.MetricFamily, error) {
+	mfs, err := lg.gatherer.Gather()
+
+	lg.labels.mu.RLock()
+	defer lg.labels.mu.RUnlock()
+
+	for _, mf := range mfs {
+		if strings.HasPrefix(mf.GetName(), lg.upstreamPrefix) {
+			for _, m := range mf.Metric {
+				lg.labels.addUpstreamLabels(m)
+			}
+		} else if strings.HasPrefix(mf.GetName(), lg.zonePrefix) {
+			for _, m := range mf.Metric {
+				lg.labels.addServerZoneLabels(m)
+			}
+		}
+	}
+
+	return mfs, err
+}
+
+func (rl *ResourceLabels) addUpstreamLabels(m *dto.Metric) {
+	upstream, ok := getLabelValue(m, "upstream")
+	if !ok {
+		return
+	}
+	addLabels(m, UpstreamServerLabels, rl.upstreamServerLabelValues[upstream])
+
+	if server, ok := getLabelValue(m, "server"); ok {
+		addLabels(m, UpstreamServerPeerLabels, rl.upstreamServerPeerLabelValues[UpstreamServerPeerKey(upstream, server)])
+	}
+}
+
+func (rl *ResourceLabels) addServerZoneLabels(m *dto.Metric) {
+	zone, ok := getLabelValue(m, "server_zone")
+	if !ok {
+		return
+	}
+	addLabels(m, ServerZoneLabels, rl.getServerZoneLabelValues(zone))
+}
+
+// getServerZoneLabelValues returns the label values of the server zone. If several resources share the server zone,
+// the label values of the resource with the first name in the sorted order are returned, so that the labels don't
+// change between the scrapes.
+func (rl *ResourceLabels) getServerZoneLabelValues(zone string) []string {
+	var values []string
+	first := ""
+	found := false
+	for resource, v := range rl.serverZoneLabelValues[zone] {
+		if !found || resource < first {
+			first = resource
+			values = v
+			found = true
+		}
+	}
+	return values
+}
+
+func getLabelValue(m *dto.Metric, name string) (string, bool) {
+	for _, l := range m.Label {
+		if l.GetName() == name {
+			return l.GetValue(), true
+		}
+	}
+	return "", false
+}
+
+// addLabels adds the labels to the metric. Missing values are added as empty strings, so that all metrics of
+// a family have the same set of labels.
+func addLabels(m *dto.Metric, names []string, values []string) {
+	for i, name := range names {
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+		m.Label = append(m.Label, &dto.LabelPair{
+			Name:  proto.String(name),
+			Value: proto.String(value),
+		})
+	}
+
+	sort.Slice(m.Label, func(i, j int) bool {
+		return m.Label[i].GetName() < m.Label[j].GetName()
+	})
+}
+
+// FakeLabelUpdater is a fake label updater that implements the LabelUpdater interface.
+type FakeLabelUpdater struct{}
+
+// NewFakeLabelUpdater creates a fake label updater that implements the LabelUpdater interface.
+func NewFakeLabelUpdater() *FakeLabelUpdater {
+	return &FakeLabelUpdater{}
+}
+
+// UpdateUpstreamServerLabels implements a fake UpdateUpstreamServerLabels
+func (lu *FakeLabelUpdater) UpdateUpstreamServerLabels(upstreamServerLabelValues map[string][]string) {
+}
+
+// DeleteUpstreamServerLabels implements a fake DeleteUpstreamServerLabels
+func (lu *FakeLabelUpdater) DeleteUpstreamServerLabels(upstreamNames []string) {}
+
+// UpdateUpstreamServerPeerLabels implements a fake UpdateUpstreamServerPeerLabels
+func (lu *FakeLabelUpdater) UpdateUpstreamServerPeerLabels(upstreamServerPeerLabelValues map[string][]string) {
+}
+
+// DeleteUpstreamServerPeerLabels implements a fake DeleteUpstreamServerPeerLabels
+func (lu *FakeLabelUpdater) DeleteUpstreamServerPeerLabels(peers []string) {}
+
+// UpdateServerZoneLabels implements a fake UpdateServerZoneLabels
+func (lu *FakeLabelUpdater) UpdateServerZoneLabels(resource string, serverZoneLabelValues map[string][]string) {
+}
+
+// DeleteServerZoneLabels implements a fake DeleteServerZoneLabels
+func (lu *FakeLabelUpdater) DeleteServerZoneLabels(resource string, zoneNames []string) {}
@@ -0,0 +1,179 @@
+package collectors
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	dto "github.com/prometheus/client_model/go"
+)
+
+type fakeGatherer struct {
+	mfs []*dto.MetricFamily
+	err error
+}
+
+func (g *fakeGatherer) Gather() ([]*dto.MetricFamily, error) {
+	return g.mfs, g.err
+}
+
+func newMetricFamily(name string, labels ...string) *dto.MetricFamily {
+	m := &dto.Metric{}
+	for i := 0; i+1 < len(labels); i += 2 {
+		m.Label = append(m.Label, &dto.LabelPair{
+			Name:  proto.String(labels[i]),
+			Value: proto.String(labels[i+1]),
+		})
+	}
+	return &dto.MetricFamily{
+		Name:   proto.String(name),
+		Metric: []*dto.Metric{m},
+	}
+}
+
+func getLabels(m *dto.Metric) map[string]string {
+	labels := make(map[string]string)
+	for _, l := range m.Label {
+		labels[l.GetName()] = l.GetValue()
+	}
+	return labels
+}
+
+func gatherLabels(t *testing.T, rl *ResourceLabels, mfs ...*dto.MetricFamily) []map[string]string {
+	gathered, err := rl.WrapGatherer(&fakeGatherer{mfs: mfs}, "nginx_ingress_nginxplus").Gather()
+	if err != nil {
+		t.Fatalf("Gather() returned an unexpected error: %v", err)
+	}
+
+	var result []map[string]string
+	for _, mf := range gathered {
+		for _, m := range mf.Metric {
+			result = append(result, getLabels(m))
+		}
+	}
+	return result
+}
+
+func TestLabeledGathererAddsLabels(t *testing.T) {
+	rl := NewResourceLabels()
+	rl.UpdateUpstreamServerLabels(map[string][]string{
+		"vs_default_cafe_tea": {"tea-svc", "virtualserver", "cafe", "default"},
+	})
+	rl.UpdateUpstreamServerPeerLabels(map[string][]string{
+		UpstreamServerPeerKey("vs_default_cafe_tea", "10.0.0.1:80"): {"tea-7d8f-abcde"},
+	})
+	rl.UpdateServerZoneLabels("vs_default_cafe", map[string][]string{
+		"cafe.example.com": {"virtualserver", "cafe", "default"},
+	})
+
+	result := gatherLabels(t, rl,
+		newMetricFamily("nginx_ingress_nginxplus_upstream_server_requests", "upstream", "vs_default_cafe_tea", "server", "10.0.0.1:80"),
+		newMetricFamily("nginx_ingress_nginxplus_server_zone_requests", "server_zone", "cafe.example.com"),
+		newMetricFamily("nginx_ingress_nginxplus_connections_accepted"),
+	)
+
+	expected := []map[string]string{
+		{
+			"upstream":           "vs_default_cafe_tea",
+			"server":             "10.0.0.1:80",
+			"service":            "tea-svc",
+			"resource_type":      "virtualserver",
+			"resource_name":      "cafe",
+			"resource_namespace": "default",
+			"pod_name":           "tea-7d8f-abcde",
+		},
+		{
+			"server_zone":        "cafe.example.com",
+			"resource_type":      "virtualserver",
+			"resource_name":      "cafe",
+			"resource_namespace": "default",
+		},
+		{},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Gather() returned metrics with labels %v but expected %v", result, expected)
+	}
+}
+
+func TestLabeledGathererAddsEmptyLabelsForUnknownNames(t *testing.T) {
+	rl := NewResourceLabels()
+
+	result := gatherLabels(t, rl,
+		newMetricFamily("nginx_ingress_nginxplus_upstream_server_requests", "upstream", "unknown", "server", "10.0.0.1:80"),
+		newMetricFamily("nginx_ingress_nginxplus_server_zone_requests", "server_zone", "unknown.example.com"),
+	)
+
+	expected := []map[string]string{
+		{
+			"upstream":           "unknown",
+			"server":             "10.0.0.1:80",
+			"service":            "",
+			"resource_type":      "",
+			"resource_name":      "",
+			"resource_namespace": "",
+			"pod_name":           "",
+		},
+		{
+			"server_zone":        "unknown.example.com",
+			"resource_type":      "",
+			"resource_name":      "",
+			"resource_namespace": "",
+		},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Gather() returned metrics with labels %v but expected %v", result, expected)
+	}
+}
+
+func TestLabeledGathererReturnsGathererError(t *testing.T) {
+	rl := NewResourceLabels()
+	gatherErr := errors.New("failed to get stats")
+
+	_, err := rl.WrapGatherer(&fakeGatherer{err: gatherErr}, "nginx_ingress_nginxplus").Gather()
+	if err != gatherErr {
+		t.Errorf("Gather() returned error %v but expected %v", err, gatherErr)
+	}
+}
+
+func TestDeleteServerZoneLabelsKeepsLabelsOfOtherResources(t *testing.T) {
+	rl := NewResourceLabels()
+	rl.UpdateServerZoneLabels("default-cafe-ingress", map[string][]string{
+		"cafe.example.com": {"ingress", "cafe-ingress", "default"},
+	})
+	rl.UpdateServerZoneLabels("vs_default_cafe", map[string][]string{
+		"cafe.example.com": {"virtualserver", "cafe", "default"},
+	})
+
+	rl.DeleteServerZoneLabels("default-cafe-ingress", []string{"cafe.example.com"})
+
+	expected := []string{"virtualserver", "cafe", "default"}
+	if values := rl.getServerZoneLabelValues("cafe.example.com"); !reflect.DeepEqual(values, expected) {
+		t.Errorf("getServerZoneLabelValues() returned %v after deleting the labels of another resource, expected %v", values, expected)
+	}
+
+	rl.DeleteServerZoneLabels("vs_default_cafe", []string{"cafe.example.com"})
+
+	if _, exists := rl.serverZoneLabelValues["cafe.example.com"]; exists {
+		t.Errorf("DeleteServerZoneLabels() didn't delete the server zone after deleting the labels of all resources")
+	}
+}
+
+func TestGetServerZoneLabelValuesIsDeterministic(t *testing.T) {
+	rl := NewResourceLabels()
+	rl.UpdateServerZoneLabels("vs_default_cafe", map[string][]string{
+		"cafe.example.com": {"virtualserver", "cafe", "default"},
+	})
+	rl.UpdateServerZoneLabels("default-cafe-ingress", map[string][]string{
+		"cafe.example.com": {"ingress", "cafe-ingress", "default"},
+	})
+
+	expected := []string{"ingress", "cafe-ingress", "default"}
+	for i := 0; i < 10; i++ {
+		if values := rl.getServerZoneLabelValues("cafe.example.com"); !reflect.DeepEqual(values, expected) {
+			t.Fatalf("getServerZoneLabelValues() returned %v, expected %v", values, expected)
+		}
+	}
+}
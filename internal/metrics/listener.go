@@ -5,7 +5,8 @@ import (
 	"net/http"
 	"strconv"
 
-	"github.com/golang/glog"
+	"github.com/nginxinc/kubernetes-ingress/internal/logging"
+	"github.com/nginxinc/kubernetes-ingress/internal/metrics/collectors"
 	plusClient "github.com/nginxinc/nginx-plus-go-client/client"
 	prometheusClient "github.com/nginxinc/nginx-prometheus-exporter/client"
 	nginxCollector "github.com/nginxinc/nginx-prometheus-exporter/collector"
@@ -16,6 +17,9 @@ import (
 // metricsEndpoint is the path where prometheus metrics will be exposed
 const metricsEndpoint = "/metrics"
 
+// nginxPlusMetricsNamespace is the namespace of the NGINX Plus metrics
+const nginxPlusMetricsNamespace = "nginx_ingress_nginxplus"
+
 // NewNginxMetricsClient creates an NginxClient to fetch stats from NGINX over an unix socket
 func NewNginxMetricsClient(httpClient *http.Client) (*prometheusClient.NginxClient, error) {
 	return prometheusClient.NewNginxClient(httpClient, "http://config-status/stub_status")
@@ -27,10 +31,11 @@ func RunPrometheusListenerForNginx(port int, client *prometheusClient.NginxClien
 	runServer(strconv.Itoa(port), registry)
 }
 
-// RunPrometheusListenerForNginxPlus runs an http server to expose Prometheus metrics for NGINX Plus
-func RunPrometheusListenerForNginxPlus(port int, plusClient *plusClient.NginxClient, registry *prometheus.Registry) {
-	registry.MustRegister(nginxCollector.NewNginxPlusCollector(plusClient, "nginx_ingress_nginxplus"))
-	runServer(strconv.Itoa(port), registry)
+// RunPrometheusListenerForNginxPlus runs an http server to expose Prometheus metrics for NGINX Plus.
+// The upstream and server zone metrics are labeled with the Kubernetes resources from resourceLabels.
+func RunPrometheusListenerForNginxPlus(port int, plusClient *plusClient.NginxClient, registry *prometheus.Registry, resourceLabels *collectors.ResourceLabels) {
+	registry.MustRegister(nginxCollector.NewNginxPlusCollector(plusClient, nginxPlusMetricsNamespace))
+	runServer(strconv.Itoa(port), resourceLabels.WrapGatherer(registry, nginxPlusMetricsNamespace))
 }
 
 func runServer(port string, registry prometheus.Gatherer) {
@@ -45,10 +50,10 @@ func runServer(port string, registry prometheus.Gatherer) {
 			</body>
 			</html>`))
 		if err != nil {
-			glog.Warningf("Error while sending a response for the '/' path: %v", err)
+			logging.Warningf("Error while sending a response for the '/' path: %v", err)
 		}
 	})
 	address := fmt.Sprintf(":%v", port)
-	glog.Infof("Starting Prometheus listener on: %v%v", address, metricsEndpoint)
-	glog.Fatal("Error in Prometheus listener server: ", http.ListenAndServe(address, nil))
+	logging.Infof("Starting Prometheus listener on: %v%v", address, metricsEndpoint)
+	logging.Fatal("Error in Prometheus listener server: ", http.ListenAndServe(address, nil))
 }
@@ -5,7 +5,7 @@ import (
 	"os"
 	"path"
 
-	"github.com/golang/glog"
+	"github.com/nginxinc/kubernetes-ingress/internal/logging"
 	"github.com/nginxinc/nginx-plus-go-client/client"
 )
 
@@ -17,40 +17,62 @@ type FakeManager struct {
 }
 
 // NewFakeManager creates a FakeMananger.
-func NewFakeManager(confPath string) *FakeManager {
+func NewFakeManager(confPath string, secretsPath string) *FakeManager {
 	return &FakeManager{
 		confdPath:       path.Join(confPath, "conf.d"),
-		secretsPath:     path.Join(confPath, "secrets"),
-		dhparamFilename: path.Join(confPath, "secrets", "dhparam.pem"),
+		secretsPath:     secretsPath,
+		dhparamFilename: path.Join(secretsPath, "dhparam.pem"),
 	}
 }
 
 // CreateMainConfig provides a fake implementation of CreateMainConfig.
 func (*FakeManager) CreateMainConfig(content []byte) {
-	glog.V(3).Info("Writing main config")
-	glog.V(3).Info(string(content))
+	logging.V(3).Info("Writing main config")
+	logging.V(3).Info(string(content))
 }
 
 // CreateConfig provides a fake implementation of CreateConfig.
 func (*FakeManager) CreateConfig(name string, content []byte) {
-	glog.V(3).Infof("Writing config %v", name)
-	glog.V(3).Info(string(content))
+	logging.V(3).Infof("Writing config %v", name)
+	logging.V(3).Info(string(content))
 }
 
 // DeleteConfig provides a fake implementation of DeleteConfig.
 func (*FakeManager) DeleteConfig(name string) {
-	glog.V(3).Infof("Deleting config %v", name)
+	logging.V(3).Infof("Deleting config %v", name)
+}
+
+// CreateStreamConfig provides a fake implementation of CreateStreamConfig.
+func (*FakeManager) CreateStreamConfig(name string, content []byte) {
+	logging.V(3).Infof("Writing stream config %v", name)
+	logging.V(3).Info(string(content))
+}
+
+// DeleteStreamConfig provides a fake implementation of DeleteStreamConfig.
+func (*FakeManager) DeleteStreamConfig(name string) {
+	logging.V(3).Infof("Deleting stream config %v", name)
+}
+
+// CreateTLSPassthroughHostsConfig provides a fake implementation of CreateTLSPassthroughHostsConfig.
+func (*FakeManager) CreateTLSPassthroughHostsConfig(name string, content []byte) {
+	logging.V(3).Infof("Writing TLS Passthrough hosts config %v", name)
+	logging.V(3).Info(string(content))
+}
+
+// DeleteTLSPassthroughHostsConfig provides a fake implementation of DeleteTLSPassthroughHostsConfig.
+func (*FakeManager) DeleteTLSPassthroughHostsConfig(name string) {
+	logging.V(3).Infof("Deleting TLS Passthrough hosts config %v", name)
 }
 
 // CreateSecret provides a fake implementation of CreateSecret.
 func (fm *FakeManager) CreateSecret(name string, content []byte, mode os.FileMode) string {
-	glog.V(3).Infof("Writing secret %v", name)
+	logging.V(3).Infof("Writing secret %v", name)
 	return fm.GetFilenameForSecret(name)
 }
 
 // DeleteSecret provides a fake implementation of DeleteSecret.
 func (*FakeManager) DeleteSecret(name string) {
-	glog.V(3).Infof("Deleting secret %v", name)
+	logging.V(3).Infof("Deleting secret %v", name)
 }
 
 // GetFilenameForSecret provides a fake implementation of GetFilenameForSecret.
@@ -60,29 +82,35 @@ func (fm *FakeManager) GetFilenameForSecret(name string) string {
 
 // CreateDHParam provides a fake implementation of CreateDHParam.
 func (fm *FakeManager) CreateDHParam(content string) (string, error) {
-	glog.V(3).Infof("Writing dhparam file")
+	logging.V(3).Infof("Writing dhparam file")
 	return fm.dhparamFilename, nil
 }
 
 // Start provides a fake implementation of Start.
 func (*FakeManager) Start(done chan error) {
-	glog.V(3).Info("Starting nginx")
+	logging.V(3).Info("Starting nginx")
 }
 
 // Reload provides a fake implementation of Reload.
 func (*FakeManager) Reload() error {
-	glog.V(3).Infof("Reloading nginx")
+	logging.V(3).Infof("Reloading nginx")
 	return nil
 }
 
 // Quit provides a fake implementation of Quit.
-func (*FakeManager) Quit() {
-	glog.V(3).Info("Quitting nginx")
+func (*FakeManager) Quit() error {
+	logging.V(3).Info("Quitting nginx")
+	return nil
+}
+
+// CheckLiveness provides a fake implementation of CheckLiveness.
+func (*FakeManager) CheckLiveness() error {
+	return nil
 }
 
 // UpdateConfigVersionFile provides a fake implementation of UpdateConfigVersionFile.
 func (*FakeManager) UpdateConfigVersionFile(openTracing bool) {
-	glog.V(3).Infof("Writing config version")
+	logging.V(3).Infof("Writing config version")
 }
 
 // SetPlusClients provides a fake implementation of SetPlusClients.
@@ -91,13 +119,13 @@ func (*FakeManager) SetPlusClients(plusClient *client.NginxClient, plusConfigVer
 
 // UpdateServersInPlus provides a fake implementation of UpdateServersInPlus.
 func (*FakeManager) UpdateServersInPlus(upstream string, servers []string, config ServerConfig) error {
-	glog.V(3).Infof("Updating servers of %v: %v", upstream, servers)
+	logging.V(3).Infof("Updating servers of %v: %v", upstream, servers)
 	return nil
 }
 
 // CreateOpenTracingTracerConfig creates a fake implementation of CreateOpenTracingTracerConfig.
 func (*FakeManager) CreateOpenTracingTracerConfig(content string) error {
-	glog.V(3).Infof("Writing OpenTracing tracer config file")
+	logging.V(3).Infof("Writing OpenTracing tracer config file")
 
 	return nil
 }
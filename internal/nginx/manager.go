@@ -1,6 +1,7 @@
 package nginx
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -8,9 +9,9 @@ import (
 	"path"
 	"time"
 
+	"github.com/nginxinc/kubernetes-ingress/internal/logging"
 	"github.com/nginxinc/kubernetes-ingress/internal/metrics/collectors"
 
-	"github.com/golang/glog"
 	"github.com/nginxinc/nginx-plus-go-client/client"
 )
 
@@ -20,8 +21,13 @@ const TLSSecretFileMode = 0600
 // JWKSecretFileMode defines the default filemode for files with JWK Secrets.
 const JWKSecretFileMode = 0644
 
+// HtpasswdSecretFileMode defines the default filemode for files with htpasswd Secrets.
+const HtpasswdSecretFileMode = 0644
+
 const configFileMode = 0644
-const jsonFileForOpenTracingTracer = "/var/lib/nginx/tracer-config.json"
+const configDirMode = 0755
+const jsonFileForOpenTracingTracer = "tracer-config.json"
+const configVersionSocket = "nginx-config-version.sock"
 
 // ServerConfig holds the config data for an upstream server in NGINX Plus.
 type ServerConfig struct {
@@ -37,6 +43,10 @@ type Manager interface {
 	CreateMainConfig(content []byte)
 	CreateConfig(name string, content []byte)
 	DeleteConfig(name string)
+	CreateStreamConfig(name string, content []byte)
+	DeleteStreamConfig(name string)
+	CreateTLSPassthroughHostsConfig(name string, content []byte)
+	DeleteTLSPassthroughHostsConfig(name string)
 	CreateSecret(name string, content []byte, mode os.FileMode) string
 	DeleteSecret(name string)
 	GetFilenameForSecret(name string) string
@@ -44,7 +54,8 @@ type Manager interface {
 	CreateOpenTracingTracerConfig(content string) error
 	Start(done chan error)
 	Reload() error
-	Quit()
+	Quit() error
+	CheckLiveness() error
 	UpdateConfigVersionFile(openTracing bool)
 	SetPlusClients(plusClient *client.NginxClient, plusConfigVersionCheckClient *http.Client)
 	UpdateServersInPlus(upstream string, servers []string, config ServerConfig) error
@@ -55,11 +66,14 @@ type Manager interface {
 // updates NGINX Plus upstream servers. It assumes that NGINX is running in the same container.
 type LocalManager struct {
 	confdPath                    string
+	streamConfdPath              string
+	tlsPassthroughHostsPath      string
 	secretsPath                  string
 	mainConfFilename             string
 	configVersionFilename        string
 	binaryFilename               string
 	dhparamFilename              string
+	openTracingTracerFilename    string
 	verifyConfigGenerator        *verifyConfigGenerator
 	verifyClient                 *verifyClient
 	configVersion                int
@@ -69,28 +83,40 @@ type LocalManager struct {
 	plusConfigVersionCheckClient *http.Client
 	metricsCollector             collectors.ManagerCollector
 	OpenTracing                  bool
+	nginxExited                  chan struct{}
+	logger                       *logging.Logger
 }
 
-// NewLocalManager creates a LocalManager.
-func NewLocalManager(confPath string, binaryFilename string, mc collectors.ManagerCollector) *LocalManager {
-	verifyConfigGenerator, err := newVerifyConfigGenerator()
+// NewLocalManager creates a LocalManager. The NGINX configuration files are stored in confPath, the secrets -- in secretsPath.
+// libPath is the directory for the files that NGINX creates at runtime, such as the pid file and the unix sockets.
+func NewLocalManager(confPath string, libPath string, secretsPath string, binaryFilename string, mc collectors.ManagerCollector,
+	logger *logging.Logger) *LocalManager {
+	configVersionSocketFilename := path.Join(libPath, configVersionSocket)
+
+	verifyConfigGenerator, err := newVerifyConfigGenerator(configVersionSocketFilename)
 	if err != nil {
-		glog.Fatalf("error instantiating a verifyConfigGenerator: %v", err)
+		logging.Fatalf("error instantiating a verifyConfigGenerator: %v", err)
 	}
 
+	mainConfFilename := path.Join(confPath, "nginx.conf")
+
 	manager := LocalManager{
-		confdPath:             path.Join(confPath, "conf.d"),
-		secretsPath:           path.Join(confPath, "secrets"),
-		dhparamFilename:       path.Join(confPath, "secrets", "dhparam.pem"),
-		mainConfFilename:      path.Join(confPath, "nginx.conf"),
-		configVersionFilename: path.Join(confPath, "config-version.conf"),
-		binaryFilename:        binaryFilename,
-		verifyConfigGenerator: verifyConfigGenerator,
-		configVersion:         0,
-		verifyClient:          newVerifyClient(),
-		reloadCmd:             fmt.Sprintf("%v -s %v", binaryFilename, "reload"),
-		quitCmd:               fmt.Sprintf("%v -s %v", binaryFilename, "quit"),
-		metricsCollector:      mc,
+		confdPath:                 path.Join(confPath, "conf.d"),
+		streamConfdPath:           path.Join(confPath, "stream-conf.d"),
+		tlsPassthroughHostsPath:   path.Join(confPath, "tls-passthrough-hosts.d"),
+		secretsPath:               secretsPath,
+		dhparamFilename:           path.Join(secretsPath, "dhparam.pem"),
+		mainConfFilename:          mainConfFilename,
+		configVersionFilename:     path.Join(confPath, "config-version.conf"),
+		openTracingTracerFilename: path.Join(libPath, jsonFileForOpenTracingTracer),
+		binaryFilename:            binaryFilename,
+		verifyConfigGenerator:     verifyConfigGenerator,
+		configVersion:             0,
+		verifyClient:              newVerifyClient(configVersionSocketFilename),
+		reloadCmd:                 fmt.Sprintf("%v -c %v -s %v", binaryFilename, mainConfFilename, "reload"),
+		quitCmd:                   fmt.Sprintf("%v -c %v -s %v", binaryFilename, mainConfFilename, "quit"),
+		metricsCollector:          mc,
+		logger:                    logger,
 	}
 
 	return &manager
@@ -98,41 +124,75 @@ func NewLocalManager(confPath string, binaryFilename string, mc collectors.Manag
 
 // CreateMainConfig creates the main NGINX configuration file. If the file already exists, it will be overridden.
 func (lm *LocalManager) CreateMainConfig(content []byte) {
-	glog.V(3).Infof("Writing main config to %v", lm.mainConfFilename)
-	glog.V(3).Infof(string(content))
+	logging.V(3).Infof("Writing main config to %v", lm.mainConfFilename)
+	logging.V(3).Infof(string(content))
 
 	err := createFileAndWrite(lm.mainConfFilename, content)
 	if err != nil {
-		glog.Fatalf("Failed to write main config: %v", err)
+		logging.Fatalf("Failed to write main config: %v", err)
 	}
 }
 
 // CreateConfig creates a configuration file. If the file already exists, it will be overridden.
 func (lm *LocalManager) CreateConfig(name string, content []byte) {
-	filename := lm.getFilenameForConfig(name)
+	createConfigInDir(lm.confdPath, name, content)
+}
+
+// DeleteConfig deletes the configuration file from the conf.d folder.
+func (lm *LocalManager) DeleteConfig(name string) {
+	deleteConfigFromDir(lm.confdPath, name)
+}
 
-	glog.V(3).Infof("Writing config to %v", filename)
-	glog.V(3).Info(string(content))
+// CreateStreamConfig creates a configuration file in the stream-conf.d folder, which is included in the stream context.
+// If the file already exists, it will be overridden.
+func (lm *LocalManager) CreateStreamConfig(name string, content []byte) {
+	createConfigInDir(lm.streamConfdPath, name, content)
+}
+
+// DeleteStreamConfig deletes the configuration file from the stream-conf.d folder.
+func (lm *LocalManager) DeleteStreamConfig(name string) {
+	deleteConfigFromDir(lm.streamConfdPath, name)
+}
+
+// CreateTLSPassthroughHostsConfig creates a file with the entries of the map of the TLS Passthrough hosts.
+// If the file already exists, it will be overridden.
+func (lm *LocalManager) CreateTLSPassthroughHostsConfig(name string, content []byte) {
+	createConfigInDir(lm.tlsPassthroughHostsPath, name, content)
+}
+
+// DeleteTLSPassthroughHostsConfig deletes the file with the entries of the map of the TLS Passthrough hosts.
+func (lm *LocalManager) DeleteTLSPassthroughHostsConfig(name string) {
+	deleteConfigFromDir(lm.tlsPassthroughHostsPath, name)
+}
+
+func createConfigInDir(dir string, name string, content []byte) {
+	filename := getFilenameForConfig(dir, name)
+
+	logging.V(3).Infof("Writing config to %v", filename)
+	logging.V(3).Info(string(content))
+
+	if err := os.MkdirAll(dir, configDirMode); err != nil {
+		logging.Fatalf("Failed to create the folder %v: %v", dir, err)
+	}
 
 	err := createFileAndWrite(filename, content)
 	if err != nil {
-		glog.Fatalf("Failed to write config to %v: %v", filename, err)
+		logging.Fatalf("Failed to write config to %v: %v", filename, err)
 	}
 }
 
-// DeleteConfig deletes the configuration file from the conf.d folder.
-func (lm *LocalManager) DeleteConfig(name string) {
-	filename := lm.getFilenameForConfig(name)
+func deleteConfigFromDir(dir string, name string) {
+	filename := getFilenameForConfig(dir, name)
 
-	glog.V(3).Infof("Deleting config from %v", filename)
+	logging.V(3).Infof("Deleting config from %v", filename)
 
 	if err := os.Remove(filename); err != nil {
-		glog.Warningf("Failed to delete config from %v: %v", filename, err)
+		logging.Warningf("Failed to delete config from %v: %v", filename, err)
 	}
 }
 
-func (lm *LocalManager) getFilenameForConfig(name string) string {
-	return path.Join(lm.confdPath, name+".conf")
+func getFilenameForConfig(dir string, name string) string {
+	return path.Join(dir, name+".conf")
 }
 
 // CreateSecret creates a secret file with the specified name, content and mode. If the file already exists,
@@ -140,7 +200,7 @@ func (lm *LocalManager) getFilenameForConfig(name string) string {
 func (lm *LocalManager) CreateSecret(name string, content []byte, mode os.FileMode) string {
 	filename := lm.GetFilenameForSecret(name)
 
-	glog.V(3).Infof("Writing secret to %v", filename)
+	logging.V(3).Infof("Writing secret to %v", filename)
 
 	createFileAndWriteAtomically(filename, lm.secretsPath, mode, content)
 
@@ -151,10 +211,10 @@ func (lm *LocalManager) CreateSecret(name string, content []byte, mode os.FileMo
 func (lm *LocalManager) DeleteSecret(name string) {
 	filename := lm.GetFilenameForSecret(name)
 
-	glog.V(3).Infof("Deleting secret from %v", filename)
+	logging.V(3).Infof("Deleting secret from %v", filename)
 
 	if err := os.Remove(filename); err != nil {
-		glog.Warningf("Failed to delete secret from %v: %v", filename, err)
+		logging.Warningf("Failed to delete secret from %v: %v", filename, err)
 	}
 }
 
@@ -165,7 +225,7 @@ func (lm *LocalManager) GetFilenameForSecret(name string) string {
 
 // CreateDHParam creates the servers dhparam.pem file. If the file already exists, it will be overridden.
 func (lm *LocalManager) CreateDHParam(content string) (string, error) {
-	glog.V(3).Infof("Writing dhparam file to %v", lm.dhparamFilename)
+	logging.V(3).Infof("Writing dhparam file to %v", lm.dhparamFilename)
 
 	err := createFileAndWrite(lm.dhparamFilename, []byte(content))
 	if err != nil {
@@ -177,22 +237,25 @@ func (lm *LocalManager) CreateDHParam(content string) (string, error) {
 
 // Start starts NGINX.
 func (lm *LocalManager) Start(done chan error) {
-	glog.V(3).Info("Starting nginx")
+	logging.V(3).Info("Starting nginx")
 
-	cmd := exec.Command(lm.binaryFilename)
+	cmd := exec.Command(lm.binaryFilename, "-c", lm.mainConfFilename)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	if err := cmd.Start(); err != nil {
-		glog.Fatalf("Failed to start nginx: %v", err)
+		logging.Fatalf("Failed to start nginx: %v", err)
 	}
 
+	lm.nginxExited = make(chan struct{})
 	go func() {
-		done <- cmd.Wait()
+		err := cmd.Wait()
+		close(lm.nginxExited)
+		done <- err
 	}()
 
 	err := lm.verifyClient.WaitForCorrectVersion(lm.configVersion)
 	if err != nil {
-		glog.Fatalf("Could not get newest config version: %v", err)
+		logging.Fatalf("Could not get newest config version: %v", err)
 	}
 }
 
@@ -202,17 +265,19 @@ func (lm *LocalManager) Reload() error {
 	lm.configVersion++
 	lm.UpdateConfigVersionFile(lm.OpenTracing)
 
-	glog.V(3).Infof("Reloading nginx with configVersion: %v", lm.configVersion)
+	logging.V(3).Infof("Reloading nginx with configVersion: %v", lm.configVersion)
 
 	t1 := time.Now()
 
 	if err := shellOut(lm.reloadCmd); err != nil {
 		lm.metricsCollector.IncNginxReloadErrors()
+		lm.logReload(time.Since(t1), err)
 		return fmt.Errorf("nginx reload failed: %v", err)
 	}
 	err := lm.verifyClient.WaitForCorrectVersion(lm.configVersion)
 	if err != nil {
 		lm.metricsCollector.IncNginxReloadErrors()
+		lm.logReload(time.Since(t1), err)
 		return fmt.Errorf("could not get newest config version: %v", err)
 	}
 
@@ -220,15 +285,37 @@ func (lm *LocalManager) Reload() error {
 
 	t2 := time.Now()
 	lm.metricsCollector.UpdateLastReloadTime(t2.Sub(t1))
+	lm.logReload(t2.Sub(t1), nil)
 	return nil
 }
 
+// logReload logs the result of a reload of NGINX with the config version and the duration of the reload.
+func (lm *LocalManager) logReload(duration time.Duration, err error) {
+	logger := lm.logger.With("configVersion", lm.configVersion, "duration", duration)
+	if err != nil {
+		logger.Error("Failed to reload NGINX", "outcome", "failed", "error", err)
+		return
+	}
+	logger.Info("Reloaded NGINX", "outcome", "reloaded")
+}
+
 // Quit shutdowns NGINX gracefully.
-func (lm *LocalManager) Quit() {
-	glog.V(3).Info("Quitting nginx")
+func (lm *LocalManager) Quit() error {
+	logging.V(3).Info("Quitting nginx")
 
 	if err := shellOut(lm.quitCmd); err != nil {
-		glog.Fatalf("Failed to quit nginx: %v", err)
+		return fmt.Errorf("Failed to quit nginx: %v", err)
+	}
+	return nil
+}
+
+// CheckLiveness checks that the NGINX master process started by Start is running.
+func (lm *LocalManager) CheckLiveness() error {
+	select {
+	case <-lm.nginxExited:
+		return errors.New("NGINX master process is not running")
+	default:
+		return nil
 	}
 }
 
@@ -236,11 +323,11 @@ func (lm *LocalManager) Quit() {
 func (lm *LocalManager) UpdateConfigVersionFile(openTracing bool) {
 	cfg, err := lm.verifyConfigGenerator.GenerateVersionConfig(lm.configVersion, openTracing)
 	if err != nil {
-		glog.Fatalf("Error generating config version content: %v", err)
+		logging.Fatalf("Error generating config version content: %v", err)
 	}
 
-	glog.V(3).Infof("Writing config version to %v", lm.configVersionFilename)
-	glog.V(3).Info(string(cfg))
+	logging.V(3).Infof("Writing config version to %v", lm.configVersionFilename)
+	logging.V(3).Info(string(cfg))
 
 	createFileAndWriteAtomically(lm.configVersionFilename, path.Dir(lm.configVersionFilename), configFileMode, cfg)
 }
@@ -259,7 +346,7 @@ func (lm *LocalManager) UpdateServersInPlus(upstream string, servers []string, c
 		return fmt.Errorf("error verifying config version: %v", err)
 	}
 
-	glog.V(3).Infof("API has the correct config version: %v.", lm.configVersion)
+	logging.V(3).Infof("API has the correct config version: %v.", lm.configVersion)
 
 	var upsServers []client.UpstreamServer
 	for _, s := range servers {
@@ -274,19 +361,19 @@ func (lm *LocalManager) UpdateServersInPlus(upstream string, servers []string, c
 
 	added, removed, err := lm.plusClient.UpdateHTTPServers(upstream, upsServers)
 	if err != nil {
-		glog.V(3).Infof("Couldn't update servers of %v upstream: %v", upstream, err)
+		logging.V(3).Infof("Couldn't update servers of %v upstream: %v", upstream, err)
 		return fmt.Errorf("error updating servers of %v upstream: %v", upstream, err)
 	}
 
-	glog.V(3).Infof("Updated servers of %v; Added: %v, Removed: %v", upstream, added, removed)
+	logging.V(3).Infof("Updated servers of %v; Added: %v, Removed: %v", upstream, added, removed)
 
 	return nil
 }
 
 // CreateOpenTracingTracerConfig creates a json configuration file for the OpenTracing tracer with the content of the string.
 func (lm *LocalManager) CreateOpenTracingTracerConfig(content string) error {
-	glog.V(3).Infof("Writing OpenTracing tracer config file to %v", jsonFileForOpenTracingTracer)
-	err := createFileAndWrite(jsonFileForOpenTracingTracer, []byte(content))
+	logging.V(3).Infof("Writing OpenTracing tracer config file to %v", lm.openTracingTracerFilename)
+	err := createFileAndWrite(lm.openTracingTracerFilename, []byte(content))
 	if err != nil {
 		return fmt.Errorf("Failed to write config file: %v", err)
 	}
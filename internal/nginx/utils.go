@@ -8,14 +8,14 @@ import (
 	"os/exec"
 	"path"
 
-	"github.com/golang/glog"
+	"github.com/nginxinc/kubernetes-ingress/internal/logging"
 )
 
 func shellOut(cmd string) (err error) {
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
 
-	glog.V(3).Infof("executing %s", cmd)
+	logging.V(3).Infof("executing %s", cmd)
 
 	command := exec.Command("sh", "-c", cmd)
 	command.Stdout = &stdout
@@ -53,26 +53,26 @@ func createFileAndWrite(name string, b []byte) error {
 func createFileAndWriteAtomically(filename string, tempPath string, mode os.FileMode, content []byte) {
 	file, err := ioutil.TempFile(tempPath, path.Base(filename))
 	if err != nil {
-		glog.Fatalf("Couldn't create a temp file for the file %v: %v", filename, err)
+		logging.Fatalf("Couldn't create a temp file for the file %v: %v", filename, err)
 	}
 
 	err = file.Chmod(mode)
 	if err != nil {
-		glog.Fatalf("Couldn't change the mode of the temp file %v: %v", file.Name(), err)
+		logging.Fatalf("Couldn't change the mode of the temp file %v: %v", file.Name(), err)
 	}
 
 	_, err = file.Write(content)
 	if err != nil {
-		glog.Fatalf("Couldn't write to the temp file %v: %v", file.Name(), err)
+		logging.Fatalf("Couldn't write to the temp file %v: %v", file.Name(), err)
 	}
 
 	err = file.Close()
 	if err != nil {
-		glog.Fatalf("Couldn't close the temp file %v: %v", file.Name(), err)
+		logging.Fatalf("Couldn't close the temp file %v: %v", file.Name(), err)
 	}
 
 	err = os.Rename(file.Name(), filename)
 	if err != nil {
-		glog.Fatalf("Couldn't rename the temp file %v to %v: %v", file.Name(), filename, err)
+		logging.Fatalf("Couldn't rename the temp file %v to %v: %v", file.Name(), filename, err)
 	}
 }
@@ -11,7 +11,7 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/golang/glog"
+	"github.com/nginxinc/kubernetes-ingress/internal/logging"
 )
 
 // verifyClient is a client for verifying the config version.
@@ -21,12 +21,12 @@ type verifyClient struct {
 }
 
 // newVerifyClient returns a new client pointed at the config version socket.
-func newVerifyClient() *verifyClient {
+func newVerifyClient(socketFilename string) *verifyClient {
 	return &verifyClient{
 		client: &http.Client{
 			Transport: &http.Transport{
 				DialContext: func(_ context.Context, _, _ string) (net.Conn, error) {
-					return net.Dial("unix", "/var/lib/nginx/nginx-config-version.sock")
+					return net.Dial("unix", socketFilename)
 				},
 			},
 		},
@@ -67,11 +67,11 @@ func (c *verifyClient) WaitForCorrectVersion(expectedVersion int) error {
 
 		version, err := c.GetConfigVersion()
 		if err != nil {
-			glog.V(3).Infof("Unable to fetch version: %v", err)
+			logging.V(3).Infof("Unable to fetch version: %v", err)
 			continue
 		}
 		if version == expectedVersion {
-			glog.V(3).Infof("success, version %v ensured. iterations: %v. took: %v", expectedVersion, i, time.Duration(i)*sleep)
+			logging.V(3).Infof("success, version %v ensured. iterations: %v. took: %v", expectedVersion, i, time.Duration(i)*sleep)
 			return nil
 		}
 	}
@@ -79,7 +79,7 @@ func (c *verifyClient) WaitForCorrectVersion(expectedVersion int) error {
 }
 
 const configVersionTemplateString = `server {
-    listen unix:/var/lib/nginx/nginx-config-version.sock;
+    listen unix:{{.SocketFilename}};
 	access_log off;
 	
 	{{if .OpenTracingLoadModule}}
@@ -98,16 +98,19 @@ map $http_x_expected_config_version $config_version_mismatch {
 // verifyConfigGenerator handles generating and writing the config version file.
 type verifyConfigGenerator struct {
 	configVersionTemplate *template.Template
+	socketFilename        string
 }
 
 // newVerifyConfigGenerator builds a new ConfigWriter - primarily parsing the config version template.
-func newVerifyConfigGenerator() (*verifyConfigGenerator, error) {
+// The generated config makes NGINX listen for the config version requests on the socketFilename socket.
+func newVerifyConfigGenerator(socketFilename string) (*verifyConfigGenerator, error) {
 	configVersionTemplate, err := template.New("configVersionTemplate").Parse(configVersionTemplateString)
 	if err != nil {
 		return nil, err
 	}
 	return &verifyConfigGenerator{
 		configVersionTemplate: configVersionTemplate,
+		socketFilename:        socketFilename,
 	}, nil
 }
 
@@ -117,9 +120,11 @@ func (c *verifyConfigGenerator) GenerateVersionConfig(configVersion int, openTra
 	templateValues := struct {
 		ConfigVersion         int
 		OpenTracingLoadModule bool
+		SocketFilename        string
 	}{
 		configVersion,
 		openTracing,
+		c.socketFilename,
 	}
 	err := c.configVersionTemplate.Execute(&configBuffer, templateValues)
 	if err != nil {
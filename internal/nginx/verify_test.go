@@ -52,7 +52,7 @@ func TestVerifyClient(t *testing.T) {
 }
 
 func TestConfigWriter(t *testing.T) {
-	cw, err := newVerifyConfigGenerator()
+	cw, err := newVerifyConfigGenerator("/var/lib/nginx/nginx-config-version.sock")
 	if err != nil {
 		t.Fatalf("error instantiating ConfigWriter: %v", err)
 	}
@@ -63,6 +63,9 @@ func TestConfigWriter(t *testing.T) {
 	if !strings.Contains(string(config), "configVersion") {
 		t.Errorf("configVersion endpoint not set. config contents: %v", string(config))
 	}
+	if !strings.Contains(string(config), "listen unix:/var/lib/nginx/nginx-config-version.sock;") {
+		t.Errorf("config version socket not set. config contents: %v", string(config))
+	}
 	if !strings.Contains(string(config), "opentracing off") {
 		t.Errorf("opentracing directive missing when is enabled. config contents: %v", string(config))
 	}
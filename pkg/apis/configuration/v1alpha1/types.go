@@ -17,10 +17,14 @@ type VirtualServer struct {
 
 // VirtualServerSpec is the spec of the VirtualServer resource.
 type VirtualServerSpec struct {
-	Host      string     `json:"host"`
-	TLS       *TLS       `json:"tls"`
-	Upstreams []Upstream `json:"upstreams"`
-	Routes    []Route    `json:"routes"`
+	Host           string          `json:"host"`
+	ServerAliases  []string        `json:"serverAliases"`
+	TLS            *TLS            `json:"tls"`
+	TLSPassthrough *TLSPassthrough `json:"tlsPassthrough"`
+	Upstreams      []Upstream      `json:"upstreams"`
+	Routes         []Route         `json:"routes"`
+	Gzip           *Gzip           `json:"gzip"`
+	AccessLog      *AccessLog      `json:"accessLog"`
 }
 
 // Upstream defines an upstream.
@@ -44,6 +48,11 @@ type Upstream struct {
 	ProxyBuffers             *UpstreamBuffers  `json:"buffers"`
 	ProxyBufferSize          string            `json:"buffer-size"`
 	ClientMaxBodySize        string            `json:"client-max-body-size"`
+	ClientBodyBufferSize     string            `json:"client-body-buffer-size"`
+	ClientBodyTimeout        string            `json:"client-body-timeout"`
+	ProxyRequestBuffering    *bool             `json:"request-buffering"`
+	ProxyHTTPVersion         string            `json:"http-version"`
+	ProxyIgnoreClientAbort   *bool             `json:"ignore-client-abort"`
 	TLS                      UpstreamTLS       `json:"tls"`
 	HealthCheck              *HealthCheck      `json:"healthCheck"`
 	SlowStart                string            `json:"slow-start"`
@@ -86,11 +95,75 @@ type Header struct {
 
 // Route defines a route.
 type Route struct {
-	Path     string  `json:"path"`
-	Upstream string  `json:"upstream"`
-	Splits   []Split `json:"splits"`
-	Rules    *Rules  `json:"rules"`
-	Route    string  `json:"route"`
+	Path         string        `json:"path"`
+	Upstream     string        `json:"upstream"`
+	Splits       []Split       `json:"splits"`
+	Rules        *Rules        `json:"rules"`
+	Route        string        `json:"route"`
+	ExternalAuth *ExternalAuth `json:"externalAuth"`
+	BasicAuth    *BasicAuth    `json:"basicAuth"`
+	CORS         *CORS         `json:"cors"`
+	Cache        *Cache        `json:"cache"`
+	Mirror       *Mirror       `json:"mirror"`
+	Proxy        *RouteProxy   `json:"proxy"`
+}
+
+// RouteProxy defines the proxy settings of a route that override the ones of its upstreams.
+type RouteProxy struct {
+	ProxyConnectTimeout      string           `json:"connect-timeout"`
+	ProxyReadTimeout         string           `json:"read-timeout"`
+	ProxySendTimeout         string           `json:"send-timeout"`
+	ProxyNextUpstream        string           `json:"next-upstream"`
+	ProxyNextUpstreamTimeout string           `json:"next-upstream-timeout"`
+	ProxyNextUpstreamTries   *int             `json:"next-upstream-tries"`
+	ProxyBuffering           *bool            `json:"buffering"`
+	ProxyBuffers             *UpstreamBuffers `json:"buffers"`
+	ProxyBufferSize          string           `json:"buffer-size"`
+	ProxyRequestBuffering    *bool            `json:"request-buffering"`
+	ClientMaxBodySize        string           `json:"client-max-body-size"`
+}
+
+// CORS defines the CORS configuration for a route.
+type CORS struct {
+	AllowOrigin      []string `json:"allowOrigin"`
+	AllowMethods     []string `json:"allowMethods"`
+	AllowHeaders     []string `json:"allowHeaders"`
+	AllowCredentials bool     `json:"allowCredentials"`
+	MaxAge           *int     `json:"maxAge"`
+	ExposeHeaders    []string `json:"exposeHeaders"`
+}
+
+// Cache defines the caching of the responses for a route.
+type Cache struct {
+	Zone         string   `json:"zone"`
+	Valid        []string `json:"valid"`
+	Methods      []string `json:"methods"`
+	Key          string   `json:"key"`
+	Bypass       []string `json:"bypass"`
+	UseStale     []string `json:"useStale"`
+	StatusHeader bool     `json:"statusHeader"`
+}
+
+// Mirror defines the mirroring of the requests of a route to an upstream. The responses of the upstream are ignored.
+type Mirror struct {
+	Upstream    string `json:"upstream"`
+	Percentage  *int   `json:"percentage"`
+	RequestBody *bool  `json:"requestBody"`
+}
+
+// BasicAuth defines HTTP basic authentication for a route.
+type BasicAuth struct {
+	Secret string `json:"secret"`
+	Realm  string `json:"realm"`
+}
+
+// ExternalAuth defines an external authentication service for a route.
+type ExternalAuth struct {
+	URL             string   `json:"url"`
+	Upstream        string   `json:"upstream"`
+	Path            string   `json:"path"`
+	ResponseHeaders []string `json:"responseHeaders"`
+	Signin          string   `json:"signin"`
 }
 
 // Split defines a split.
@@ -104,25 +177,62 @@ type Rules struct {
 	Conditions      []Condition `json:"conditions"`
 	Matches         []Match     `json:"matches"`
 	DefaultUpstream string      `json:"defaultUpstream"`
+	DefaultSplits   []Split     `json:"defaultSplits"`
 }
 
 // Condition defines a condition in a MatchRule.
 type Condition struct {
-	Header   string `json:"header"`
-	Cookie   string `json:"cookie"`
-	Argument string `json:"argument"`
-	Variable string `json:"variable"`
+	Header          string `json:"header"`
+	Cookie          string `json:"cookie"`
+	Argument        string `json:"argument"`
+	ArgumentPresent string `json:"argumentPresent"`
+	Variable        string `json:"variable"`
+	Method          bool   `json:"method"`
+	Path            bool   `json:"path"`
+	SourceIP        bool   `json:"sourceIP"`
 }
 
 // Match defines a match in a MatchRule.
 type Match struct {
 	Values   []string `json:"values"`
 	Upstream string   `json:"upstream"`
+	Splits   []Split  `json:"splits"`
 }
 
 // TLS defines TLS configuration for a VirtualServer.
 type TLS struct {
-	Secret string `json:"secret"`
+	Secret      string       `json:"secret"`
+	CertManager *CertManager `json:"certManager"`
+}
+
+// CertManager defines a cert-manager Certificate for the TLS secret of a VirtualServer.
+type CertManager struct {
+	Issuer        string `json:"issuer"`
+	ClusterIssuer string `json:"clusterIssuer"`
+	Duration      string `json:"duration"`
+	RenewBefore   string `json:"renewBefore"`
+}
+
+// TLSPassthrough defines TLS Passthrough for a VirtualServer.
+type TLSPassthrough struct {
+	Upstream string `json:"upstream"`
+}
+
+// Gzip defines the gzip compression of the responses of a VirtualServer.
+type Gzip struct {
+	Enable    bool     `json:"enable"`
+	Types     []string `json:"types"`
+	MinLength *int     `json:"minLength"`
+	Level     *int     `json:"level"`
+	Proxied   []string `json:"proxied"`
+}
+
+// AccessLog defines the destination of the access log of a VirtualServer.
+type AccessLog struct {
+	Off    bool   `json:"off"`
+	File   string `json:"file"`
+	Stdout bool   `json:"stdout"`
+	Syslog string `json:"syslog"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
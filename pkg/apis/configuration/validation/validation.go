@@ -2,9 +2,11 @@ package validation
 
 import (
 	"fmt"
+	"net"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/nginxinc/kubernetes-ingress/internal/configs"
 
@@ -25,12 +27,16 @@ func validateVirtualServerSpec(spec *v1alpha1.VirtualServerSpec, fieldPath *fiel
 	allErrs := field.ErrorList{}
 
 	allErrs = append(allErrs, validateHost(spec.Host, fieldPath.Child("host"))...)
+	allErrs = append(allErrs, validateServerAliases(spec.ServerAliases, spec.Host, fieldPath.Child("serverAliases"))...)
 	allErrs = append(allErrs, validateTLS(spec.TLS, fieldPath.Child("tls"))...)
 
 	upstreamErrs, upstreamNames := validateUpstreams(spec.Upstreams, fieldPath.Child("upstreams"), isPlus)
 	allErrs = append(allErrs, upstreamErrs...)
 
+	allErrs = append(allErrs, validateTLSPassthrough(spec.TLSPassthrough, spec.TLS, fieldPath.Child("tlsPassthrough"), upstreamNames)...)
 	allErrs = append(allErrs, validateVirtualServerRoutes(spec.Routes, fieldPath.Child("routes"), upstreamNames)...)
+	allErrs = append(allErrs, validateGzip(spec.Gzip, fieldPath.Child("gzip"))...)
+	allErrs = append(allErrs, validateAccessLog(spec.AccessLog, fieldPath.Child("accessLog"))...)
 
 	return allErrs
 }
@@ -42,20 +48,240 @@ func validateHost(host string, fieldPath *field.Path) field.ErrorList {
 		return append(allErrs, field.Required(fieldPath, ""))
 	}
 
-	for _, msg := range validation.IsDNS1123Subdomain(host) {
+	var msgs []string
+	if strings.HasPrefix(host, "*") {
+		msgs = validation.IsWildcardDNS1123Subdomain(host)
+	} else {
+		msgs = validation.IsDNS1123Subdomain(host)
+	}
+
+	for _, msg := range msgs {
 		allErrs = append(allErrs, field.Invalid(fieldPath, host, msg))
 	}
 
 	return allErrs
 }
 
+// HostMatches checks if the host matches the pattern. The pattern is either a host, which the host must be equal to,
+// or a host with a leading wildcard, such as *.example.com, which matches every host that ends with the suffix
+// after the wildcard, such as foo.example.com, foo.bar.example.com or *.bar.example.com, the same way NGINX matches
+// the server names with a leading wildcard.
+func HostMatches(pattern string, host string) bool {
+	if pattern == host {
+		return true
+	}
+
+	if !strings.HasPrefix(pattern, "*.") {
+		return false
+	}
+
+	suffix := strings.TrimPrefix(pattern, "*")
+	return len(host) > len(suffix) && strings.HasSuffix(host, suffix)
+}
+
+func validateServerAliases(aliases []string, host string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	allHosts := sets.NewString(host)
+
+	for i, alias := range aliases {
+		idxPath := fieldPath.Index(i)
+
+		allErrs = append(allErrs, validateHost(alias, idxPath)...)
+
+		if allHosts.Has(alias) {
+			allErrs = append(allErrs, field.Duplicate(idxPath, alias))
+		} else {
+			allHosts.Insert(alias)
+		}
+	}
+
+	return allErrs
+}
+
 func validateTLS(tls *v1alpha1.TLS, fieldPath *field.Path) field.ErrorList {
 	if tls == nil {
 		// valid case - tls is not defined
 		return field.ErrorList{}
 	}
 
-	return validateSecretName(tls.Secret, fieldPath.Child("secret"))
+	if tls.Secret == "" {
+		if tls.CertManager != nil {
+			return field.ErrorList{field.Required(fieldPath.Child("secret"), "must be specified when certManager is specified")}
+		}
+		// valid case - the wildcard TLS secret or the default server TLS secret is used
+		return field.ErrorList{}
+	}
+
+	allErrs := validateSecretName(tls.Secret, fieldPath.Child("secret"))
+	allErrs = append(allErrs, validateCertManager(tls.CertManager, fieldPath.Child("certManager"))...)
+
+	return allErrs
+}
+
+func validateCertManager(certManager *v1alpha1.CertManager, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if certManager == nil {
+		// valid case - certManager is not defined
+		return allErrs
+	}
+
+	if certManager.Issuer == "" && certManager.ClusterIssuer == "" {
+		allErrs = append(allErrs, field.Required(fieldPath, "issuer or clusterIssuer must be specified"))
+	} else if certManager.Issuer != "" && certManager.ClusterIssuer != "" {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("clusterIssuer"), "cannot be used together with issuer"))
+	}
+
+	if certManager.Issuer != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(certManager.Issuer) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("issuer"), certManager.Issuer, msg))
+		}
+	}
+	if certManager.ClusterIssuer != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(certManager.ClusterIssuer) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("clusterIssuer"), certManager.ClusterIssuer, msg))
+		}
+	}
+
+	allErrs = append(allErrs, validateCertManagerDuration(certManager.Duration, fieldPath.Child("duration"))...)
+	allErrs = append(allErrs, validateCertManagerDuration(certManager.RenewBefore, fieldPath.Child("renewBefore"))...)
+
+	return allErrs
+}
+
+// validateCertManagerDuration validates a duration in the format of cert-manager, which is the format of Go durations. For example, 2160h.
+func validateCertManagerDuration(duration string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if duration == "" {
+		return allErrs
+	}
+
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return append(allErrs, field.Invalid(fieldPath, duration, "must be a duration like 2160h or 90m"))
+	}
+	if d <= 0 {
+		return append(allErrs, field.Invalid(fieldPath, duration, "must be positive"))
+	}
+
+	return allErrs
+}
+
+func validateTLSPassthrough(tlsPassthrough *v1alpha1.TLSPassthrough, tls *v1alpha1.TLS, fieldPath *field.Path, upstreamNames sets.String) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if tlsPassthrough == nil {
+		// valid case - tlsPassthrough is not defined
+		return allErrs
+	}
+
+	if tls != nil {
+		// the TLS connections for the host are passed through, so NGINX cannot terminate them
+		return append(allErrs, field.Forbidden(fieldPath, "cannot be used together with tls"))
+	}
+
+	return validateReferencedUpstream(tlsPassthrough.Upstream, fieldPath.Child("upstream"), upstreamNames)
+}
+
+func validateGzip(gzip *v1alpha1.Gzip, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if gzip == nil {
+		return allErrs
+	}
+
+	for i, t := range gzip.Types {
+		if err := configs.ValidateGzipType(t); err != nil {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("types").Index(i), t, err.Error()))
+		}
+	}
+
+	allErrs = append(allErrs, validatePositiveIntOrZeroFromPointer(gzip.MinLength, fieldPath.Child("minLength"))...)
+
+	if gzip.Level != nil {
+		if err := configs.ValidateGzipCompLevel(*gzip.Level); err != nil {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("level"), *gzip.Level, err.Error()))
+		}
+	}
+
+	for i, p := range gzip.Proxied {
+		if err := configs.ValidateGzipProxied(p); err != nil {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("proxied").Index(i), p, err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+func validateAccessLog(accessLog *v1alpha1.AccessLog, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if accessLog == nil {
+		return allErrs
+	}
+
+	fieldCount := 0
+
+	if accessLog.Off {
+		fieldCount++
+	}
+
+	if accessLog.File != "" {
+		allErrs = append(allErrs, validateLogFileName(accessLog.File, fieldPath.Child("file"))...)
+		fieldCount++
+	}
+
+	if accessLog.Stdout {
+		fieldCount++
+	}
+
+	if accessLog.Syslog != "" {
+		allErrs = append(allErrs, validateLogFilePath(accessLog.Syslog, fieldPath.Child("syslog"))...)
+		fieldCount++
+	}
+
+	if fieldCount != 1 {
+		msg := "must specify exactly one of: `off`, `file`, `stdout` or `syslog`"
+		allErrs = append(allErrs, field.Invalid(fieldPath, "", msg))
+	}
+
+	return allErrs
+}
+
+// logFileNameFmt allows only a file name, so that the access log of a VirtualServer is always written to the log directory
+// of NGINX and can't overwrite the configuration, the secrets or the runtime files of NGINX.
+const logFileNameFmt = `[a-zA-Z0-9][a-zA-Z0-9_.-]*`
+const logFileNameErrMsg = "must be a file name that consists of alphanumeric characters, '_', '-' or '.', starts with an alphanumeric character and doesn't include '..'"
+
+var logFileNameRegexp = regexp.MustCompile("^" + logFileNameFmt + "$")
+
+func validateLogFileName(name string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if !logFileNameRegexp.MatchString(name) || strings.Contains(name, "..") {
+		msg := validation.RegexError(logFileNameErrMsg, logFileNameFmt, "cafe.log", "cafe-access.log")
+		return append(allErrs, field.Invalid(fieldPath, name, msg))
+	}
+
+	return allErrs
+}
+
+const logFilePathFmt = `/[^\s{};"'\\]*`
+const logFilePathErrMsg = "must start with / and must not include any whitespace character, `{`, `}`, `;`, quotes or `\\`"
+
+var logFilePathRegexp = regexp.MustCompile("^" + logFilePathFmt + "$")
+
+func validateLogFilePath(path string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if !logFilePathRegexp.MatchString(path) {
+		msg := validation.RegexError(logFilePathErrMsg, logFilePathFmt, "/var/log/nginx/cafe.log", "/dev/log")
+		return append(allErrs, field.Invalid(fieldPath, path, msg))
+	}
+
+	return allErrs
 }
 
 func validatePositiveIntOrZero(n int, fieldPath *field.Path) field.ErrorList {
@@ -373,6 +599,9 @@ func validateUpstreams(upstreams []v1alpha1.Upstream, fieldPath *field.Path, isP
 		allErrs = append(allErrs, validateTime(u.SlowStart, idxPath.Child("slow-start"))...)
 		allErrs = append(allErrs, validateBuffer(u.ProxyBuffers, idxPath.Child("buffers"))...)
 		allErrs = append(allErrs, validateSize(u.ProxyBufferSize, idxPath.Child("buffer-size"))...)
+		allErrs = append(allErrs, validateSize(u.ClientBodyBufferSize, idxPath.Child("client-body-buffer-size"))...)
+		allErrs = append(allErrs, validateTime(u.ClientBodyTimeout, idxPath.Child("client-body-timeout"))...)
+		allErrs = append(allErrs, validateProxyHTTPVersion(u.ProxyHTTPVersion, idxPath.Child("http-version"))...)
 		allErrs = append(allErrs, rejectPlusResourcesInOSS(u, idxPath, isPlus)...)
 		allErrs = append(allErrs, validateQueue(u.Queue, idxPath.Child("queue"), isPlus)...)
 
@@ -384,6 +613,20 @@ func validateUpstreams(upstreams []v1alpha1.Upstream, fieldPath *field.Path, isP
 	return allErrs, upstreamNames
 }
 
+func validateProxyHTTPVersion(version string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if version == "" {
+		return allErrs
+	}
+
+	if _, err := configs.ParseProxyHTTPVersion(version); err != nil {
+		allErrs = append(allErrs, field.Invalid(fieldPath, version, "must be 1.0 or 1.1"))
+	}
+
+	return allErrs
+}
+
 var validNextUpstreamParams = map[string]bool{
 	"error":          true,
 	"timeout":        true,
@@ -502,6 +745,54 @@ func validateRoute(route v1alpha1.Route, fieldPath *field.Path, upstreamNames se
 		}
 	}
 
+	if route.ExternalAuth != nil {
+		if route.Route != "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("externalAuth"), "is not allowed in a route that references a VirtualServerRoute"))
+		} else {
+			allErrs = append(allErrs, validateExternalAuth(route.ExternalAuth, fieldPath.Child("externalAuth"), upstreamNames)...)
+		}
+	}
+
+	if route.BasicAuth != nil {
+		if route.Route != "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("basicAuth"), "is not allowed in a route that references a VirtualServerRoute"))
+		} else {
+			allErrs = append(allErrs, validateBasicAuth(route.BasicAuth, fieldPath.Child("basicAuth"))...)
+		}
+	}
+
+	if route.CORS != nil {
+		if route.Route != "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("cors"), "is not allowed in a route that references a VirtualServerRoute"))
+		} else {
+			allErrs = append(allErrs, validateCORS(route.CORS, fieldPath.Child("cors"))...)
+		}
+	}
+
+	if route.Cache != nil {
+		if route.Route != "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("cache"), "is not allowed in a route that references a VirtualServerRoute"))
+		} else {
+			allErrs = append(allErrs, validateCache(route.Cache, fieldPath.Child("cache"))...)
+		}
+	}
+
+	if route.Mirror != nil {
+		if route.Route != "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("mirror"), "is not allowed in a route that references a VirtualServerRoute"))
+		} else {
+			allErrs = append(allErrs, validateMirror(route.Mirror, fieldPath.Child("mirror"), upstreamNames)...)
+		}
+	}
+
+	if route.Proxy != nil {
+		if route.Route != "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("proxy"), "is not allowed in a route that references a VirtualServerRoute"))
+		} else {
+			allErrs = append(allErrs, validateRouteProxy(route.Proxy, fieldPath.Child("proxy"))...)
+		}
+	}
+
 	if fieldCount != 1 {
 		msg := "must specify exactly one of: `upstream`, `splits`, `rules` or `route`"
 		if isRouteFieldForbidden {
@@ -514,6 +805,166 @@ func validateRoute(route v1alpha1.Route, fieldPath *field.Path, upstreamNames se
 	return allErrs
 }
 
+func validateExternalAuth(auth *v1alpha1.ExternalAuth, fieldPath *field.Path, upstreamNames sets.String) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if auth.URL != "" && auth.Upstream != "" {
+		allErrs = append(allErrs, field.Invalid(fieldPath, "", "must specify exactly one of: `url` or `upstream`"))
+	} else if auth.URL != "" {
+		allErrs = append(allErrs, validateExternalAuthURL(auth.URL, fieldPath.Child("url"))...)
+		if auth.Path != "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("path"), "is not allowed with `url`"))
+		}
+	} else if auth.Upstream != "" {
+		allErrs = append(allErrs, validateReferencedUpstream(auth.Upstream, fieldPath.Child("upstream"), upstreamNames)...)
+		if auth.Path != "" {
+			allErrs = append(allErrs, validatePath(auth.Path, fieldPath.Child("path"))...)
+		}
+	} else {
+		allErrs = append(allErrs, field.Required(fieldPath, "must specify exactly one of: `url` or `upstream`"))
+	}
+
+	for i, h := range auth.ResponseHeaders {
+		idxPath := fieldPath.Child("responseHeaders").Index(i)
+		if h == "" {
+			allErrs = append(allErrs, field.Required(idxPath, ""))
+			continue
+		}
+		for _, msg := range validation.IsHTTPHeaderName(h) {
+			allErrs = append(allErrs, field.Invalid(idxPath, h, msg))
+		}
+	}
+
+	if auth.Signin != "" {
+		allErrs = append(allErrs, validateExternalAuthURL(auth.Signin, fieldPath.Child("signin"))...)
+	}
+
+	return allErrs
+}
+
+func validateBasicAuth(auth *v1alpha1.BasicAuth, fieldPath *field.Path) field.ErrorList {
+	allErrs := validateSecretName(auth.Secret, fieldPath.Child("secret"))
+
+	if auth.Realm == "off" {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("realm"), auth.Realm, "must not be `off`"))
+	} else if strings.ContainsAny(auth.Realm, `"\`) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("realm"), auth.Realm, "must not include double quotes or backslashes"))
+	}
+
+	return allErrs
+}
+
+func validateCORS(cors *v1alpha1.CORS, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if err := configs.ValidateCORSOrigins(cors.AllowOrigin, cors.AllowCredentials); err != nil {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("allowOrigin"), strings.Join(cors.AllowOrigin, ","), err.Error()))
+	}
+
+	for i, method := range cors.AllowMethods {
+		if err := configs.ValidateCORSMethod(method); err != nil {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("allowMethods").Index(i), method, err.Error()))
+		}
+	}
+
+	allErrs = append(allErrs, validateCORSHeaders(cors.AllowHeaders, fieldPath.Child("allowHeaders"))...)
+	allErrs = append(allErrs, validateCORSHeaders(cors.ExposeHeaders, fieldPath.Child("exposeHeaders"))...)
+
+	allErrs = append(allErrs, validatePositiveIntOrZeroFromPointer(cors.MaxAge, fieldPath.Child("maxAge"))...)
+
+	return allErrs
+}
+
+func validateCORSHeaders(headers []string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, h := range headers {
+		for _, msg := range validation.IsHTTPHeaderName(h) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Index(i), h, msg))
+		}
+	}
+
+	return allErrs
+}
+
+func validateCache(cache *v1alpha1.Cache, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if cache.Zone == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("zone"), ""))
+	} else if err := configs.ValidateProxyCacheZoneName(cache.Zone); err != nil {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("zone"), cache.Zone, err.Error()))
+	}
+
+	for i, valid := range cache.Valid {
+		if err := configs.ValidateProxyCacheValid(valid); err != nil {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("valid").Index(i), valid, err.Error()))
+		}
+	}
+
+	for i, method := range cache.Methods {
+		if err := configs.ValidateProxyCacheMethod(method); err != nil {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("methods").Index(i), method, err.Error()))
+		}
+	}
+
+	if cache.Key != "" {
+		if err := configs.ValidateProxyCacheKey(cache.Key); err != nil {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("key"), cache.Key, err.Error()))
+		}
+	}
+
+	for i, condition := range cache.Bypass {
+		if err := configs.ValidateProxyCacheBypass(condition); err != nil {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("bypass").Index(i), condition, err.Error()))
+		}
+	}
+
+	if err := configs.ValidateProxyCacheUseStale(cache.UseStale); err != nil {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("useStale"), strings.Join(cache.UseStale, ","), err.Error()))
+	}
+
+	return allErrs
+}
+
+func validateMirror(mirror *v1alpha1.Mirror, fieldPath *field.Path, upstreamNames sets.String) field.ErrorList {
+	allErrs := validateReferencedUpstream(mirror.Upstream, fieldPath.Child("upstream"), upstreamNames)
+
+	if mirror.Percentage != nil {
+		for _, msg := range validation.IsInRange(*mirror.Percentage, 1, 100) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("percentage"), *mirror.Percentage, msg))
+		}
+	}
+
+	return allErrs
+}
+
+func validateRouteProxy(proxy *v1alpha1.RouteProxy, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	allErrs = append(allErrs, validateTime(proxy.ProxyConnectTimeout, fieldPath.Child("connect-timeout"))...)
+	allErrs = append(allErrs, validateTime(proxy.ProxyReadTimeout, fieldPath.Child("read-timeout"))...)
+	allErrs = append(allErrs, validateTime(proxy.ProxySendTimeout, fieldPath.Child("send-timeout"))...)
+	allErrs = append(allErrs, validateNextUpstream(proxy.ProxyNextUpstream, fieldPath.Child("next-upstream"))...)
+	allErrs = append(allErrs, validateTime(proxy.ProxyNextUpstreamTimeout, fieldPath.Child("next-upstream-timeout"))...)
+	allErrs = append(allErrs, validatePositiveIntOrZeroFromPointer(proxy.ProxyNextUpstreamTries, fieldPath.Child("next-upstream-tries"))...)
+	allErrs = append(allErrs, validateBuffer(proxy.ProxyBuffers, fieldPath.Child("buffers"))...)
+	allErrs = append(allErrs, validateSize(proxy.ProxyBufferSize, fieldPath.Child("buffer-size"))...)
+	allErrs = append(allErrs, validateOffset(proxy.ClientMaxBodySize, fieldPath.Child("client-max-body-size"))...)
+
+	return allErrs
+}
+
+func validateExternalAuthURL(url string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if _, err := configs.ParseExternalAuthURL(url); err != nil {
+		allErrs = append(allErrs, field.Invalid(fieldPath, url, err.Error()))
+	}
+
+	return allErrs
+}
+
 func validateRouteField(value string, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -601,11 +1052,18 @@ func validateRules(rules *v1alpha1.Rules, fieldPath *field.Path, upstreamNames s
 		allErrs = append(allErrs, field.Required(fieldPath.Child("matches"), "must specify at least one match"))
 	} else {
 		for i, m := range rules.Matches {
-			allErrs = append(allErrs, validateMatch(m, fieldPath.Child("matches").Index(i), len(rules.Conditions), upstreamNames)...)
+			allErrs = append(allErrs, validateMatch(m, fieldPath.Child("matches").Index(i), rules.Conditions, upstreamNames)...)
 		}
 	}
 
-	allErrs = append(allErrs, validateReferencedUpstream(rules.DefaultUpstream, fieldPath.Child("defaultUpstream"), upstreamNames)...)
+	if rules.DefaultUpstream != "" && len(rules.DefaultSplits) > 0 {
+		msg := "must specify exactly one of: `defaultUpstream` or `defaultSplits`"
+		allErrs = append(allErrs, field.Invalid(fieldPath, "", msg))
+	} else if len(rules.DefaultSplits) > 0 {
+		allErrs = append(allErrs, validateSplits(rules.DefaultSplits, fieldPath.Child("defaultSplits"), upstreamNames)...)
+	} else {
+		allErrs = append(allErrs, validateReferencedUpstream(rules.DefaultUpstream, fieldPath.Child("defaultUpstream"), upstreamNames)...)
+	}
 
 	return allErrs
 }
@@ -636,13 +1094,33 @@ func validateCondition(condition v1alpha1.Condition, fieldPath *field.Path) fiel
 		fieldCount++
 	}
 
+	if condition.ArgumentPresent != "" {
+		for _, msg := range isArgumentName(condition.ArgumentPresent) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("argumentPresent"), condition.ArgumentPresent, msg))
+		}
+		fieldCount++
+	}
+
 	if condition.Variable != "" {
 		allErrs = append(allErrs, validateVariableName(condition.Variable, fieldPath.Child("variable"))...)
 		fieldCount++
 	}
 
+	if condition.Method {
+		fieldCount++
+	}
+
+	if condition.Path {
+		fieldCount++
+	}
+
+	if condition.SourceIP {
+		fieldCount++
+	}
+
 	if fieldCount != 1 {
-		allErrs = append(allErrs, field.Invalid(fieldPath, "", "must specify exactly one of: `header`, `cookie`, `argument` or `variable`"))
+		msg := "must specify exactly one of: `header`, `cookie`, `argument`, `argumentPresent`, `variable`, `method`, `path` or `sourceIP`"
+		allErrs = append(allErrs, field.Invalid(fieldPath, "", msg))
 	}
 
 	return allErrs
@@ -702,11 +1180,11 @@ func validateVariableName(name string, fieldPath *field.Path) field.ErrorList {
 	return allErrs
 }
 
-func validateMatch(match v1alpha1.Match, fieldPath *field.Path, conditionsCount int, upstreamNames sets.String) field.ErrorList {
+func validateMatch(match v1alpha1.Match, fieldPath *field.Path, conditions []v1alpha1.Condition, upstreamNames sets.String) field.ErrorList {
 	allErrs := field.ErrorList{}
 
-	if len(match.Values) != conditionsCount {
-		msg := fmt.Sprintf("must specify %d values (same as the number of conditions)", conditionsCount)
+	if len(match.Values) != len(conditions) {
+		msg := fmt.Sprintf("must specify %d values (same as the number of conditions)", len(conditions))
 		allErrs = append(allErrs, field.Invalid(fieldPath.Child("values"), "", msg))
 	}
 
@@ -714,9 +1192,22 @@ func validateMatch(match v1alpha1.Match, fieldPath *field.Path, conditionsCount
 		for _, msg := range isValidMatchValue(v) {
 			allErrs = append(allErrs, field.Invalid(fieldPath.Child("values").Index(i), v, msg))
 		}
+
+		if i < len(conditions) {
+			for _, msg := range isValidMatchValueForCondition(v, conditions[i]) {
+				allErrs = append(allErrs, field.Invalid(fieldPath.Child("values").Index(i), v, msg))
+			}
+		}
 	}
 
-	allErrs = append(allErrs, validateReferencedUpstream(match.Upstream, fieldPath.Child("upstream"), upstreamNames)...)
+	if match.Upstream != "" && len(match.Splits) > 0 {
+		msg := "must specify exactly one of: `upstream` or `splits`"
+		allErrs = append(allErrs, field.Invalid(fieldPath, "", msg))
+	} else if len(match.Splits) > 0 {
+		allErrs = append(allErrs, validateSplits(match.Splits, fieldPath.Child("splits"), upstreamNames)...)
+	} else {
+		allErrs = append(allErrs, validateReferencedUpstream(match.Upstream, fieldPath.Child("upstream"), upstreamNames)...)
+	}
 
 	return allErrs
 }
@@ -733,22 +1224,52 @@ func isValidMatchValue(value string) []string {
 	return nil
 }
 
+const methodFmt string = "[A-Z]+"
+const methodErrMsg string = "a valid HTTP method must consist of uppercase letters"
+
+var methodRegexp = regexp.MustCompile("^" + methodFmt + "$")
+
+// isValidMatchValueForCondition validates a value against the kind of its condition.
+// The value can be negated with the `!` prefix for all kinds of conditions.
+func isValidMatchValueForCondition(value string, condition v1alpha1.Condition) []string {
+	value = strings.TrimPrefix(value, "!")
+
+	if condition.Method && !strings.HasPrefix(value, "~") {
+		if !methodRegexp.MatchString(value) {
+			return []string{validation.RegexError(methodErrMsg, methodFmt, "GET", "POST")}
+		}
+	}
+
+	if condition.SourceIP {
+		if _, _, err := net.ParseCIDR(value); err != nil && net.ParseIP(value) == nil {
+			return []string{"must be a valid IP address or CIDR range, e.g. 10.0.0.1 or 10.0.0.0/8"}
+		}
+	}
+
+	if condition.ArgumentPresent != "" && value != "true" && value != "false" {
+		return []string{"must be `true` or `false`"}
+	}
+
+	return nil
+}
+
 // ValidateVirtualServerRoute validates a VirtualServerRoute.
 func ValidateVirtualServerRoute(virtualServerRoute *v1alpha1.VirtualServerRoute, isPlus bool) error {
-	allErrs := validateVirtualServerRouteSpec(&virtualServerRoute.Spec, field.NewPath("spec"), "", "/", isPlus)
+	allErrs := validateVirtualServerRouteSpec(&virtualServerRoute.Spec, field.NewPath("spec"), nil, "/", isPlus)
 	return allErrs.ToAggregate()
 }
 
-// ValidateVirtualServerRouteForVirtualServer validates a VirtualServerRoute for a VirtualServer represented by its host and path prefix.
-func ValidateVirtualServerRouteForVirtualServer(virtualServerRoute *v1alpha1.VirtualServerRoute, virtualServerHost string, pathPrefix string, isPlus bool) error {
-	allErrs := validateVirtualServerRouteSpec(&virtualServerRoute.Spec, field.NewPath("spec"), virtualServerHost, pathPrefix, isPlus)
+// ValidateVirtualServerRouteForVirtualServer validates a VirtualServerRoute for a VirtualServer represented by its hosts and path prefix.
+// The hosts of the VirtualServer are its host and its server aliases.
+func ValidateVirtualServerRouteForVirtualServer(virtualServerRoute *v1alpha1.VirtualServerRoute, virtualServerHosts []string, pathPrefix string, isPlus bool) error {
+	allErrs := validateVirtualServerRouteSpec(&virtualServerRoute.Spec, field.NewPath("spec"), virtualServerHosts, pathPrefix, isPlus)
 	return allErrs.ToAggregate()
 }
 
-func validateVirtualServerRouteSpec(spec *v1alpha1.VirtualServerRouteSpec, fieldPath *field.Path, virtualServerHost string, pathPrefix string, isPlus bool) field.ErrorList {
+func validateVirtualServerRouteSpec(spec *v1alpha1.VirtualServerRouteSpec, fieldPath *field.Path, virtualServerHosts []string, pathPrefix string, isPlus bool) field.ErrorList {
 	allErrs := field.ErrorList{}
 
-	allErrs = append(allErrs, validateVirtualServerRouteHost(spec.Host, virtualServerHost, fieldPath.Child("host"))...)
+	allErrs = append(allErrs, validateVirtualServerRouteHost(spec.Host, virtualServerHosts, fieldPath.Child("host"))...)
 
 	upstreamErrs, upstreamNames := validateUpstreams(spec.Upstreams, fieldPath.Child("upstreams"), isPlus)
 	allErrs = append(allErrs, upstreamErrs...)
@@ -758,19 +1279,33 @@ func validateVirtualServerRouteSpec(spec *v1alpha1.VirtualServerRouteSpec, field
 	return allErrs
 }
 
-func validateVirtualServerRouteHost(host string, virtualServerHost string, fieldPath *field.Path) field.ErrorList {
+func validateVirtualServerRouteHost(host string, virtualServerHosts []string, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
 	allErrs = append(allErrs, validateHost(host, fieldPath)...)
 
-	if virtualServerHost != "" && host != virtualServerHost {
-		msg := fmt.Sprintf("must be equal to '%s'", virtualServerHost)
+	if len(virtualServerHosts) > 0 && !matchesAnyHost(virtualServerHosts, host) {
+		var msg string
+		if len(virtualServerHosts) == 1 {
+			msg = fmt.Sprintf("must match '%s'", virtualServerHosts[0])
+		} else {
+			msg = fmt.Sprintf("must match one of '%s'", strings.Join(virtualServerHosts, "', '"))
+		}
 		allErrs = append(allErrs, field.Invalid(fieldPath, host, msg))
 	}
 
 	return allErrs
 }
 
+func matchesAnyHost(patterns []string, host string) bool {
+	for _, pattern := range patterns {
+		if HostMatches(pattern, host) {
+			return true
+		}
+	}
+	return false
+}
+
 func validateVirtualServerRouteSubroutes(routes []v1alpha1.Route, fieldPath *field.Path, upstreamNames sets.String, pathPrefix string) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -60,6 +60,7 @@ func TestValidateHost(t *testing.T) {
 		"hello",
 		"example.com",
 		"hello-world-1",
+		"*.example.com",
 	}
 
 	for _, h := range validHosts {
@@ -75,6 +76,9 @@ func TestValidateHost(t *testing.T) {
 		"..",
 		".example.com",
 		"-hello-world-1",
+		"*example.com",
+		"www.*.example.com",
+		"*.*.example.com",
 	}
 
 	for _, h := range invalidHosts {
@@ -85,11 +89,59 @@ func TestValidateHost(t *testing.T) {
 	}
 }
 
+func TestValidateServerAliases(t *testing.T) {
+	host := "example.com"
+
+	validAliases := [][]string{
+		nil,
+		{"www.example.com"},
+		{"www.example.com", "*.example.org"},
+	}
+
+	for _, aliases := range validAliases {
+		allErrs := validateServerAliases(aliases, host, field.NewPath("serverAliases"))
+		if len(allErrs) > 0 {
+			t.Errorf("validateServerAliases(%v) returned errors %v for valid input", aliases, allErrs)
+		}
+	}
+
+	invalidAliases := [][]string{
+		{""},
+		{"www.*.example.com"},
+		{"example.com"},
+		{"www.example.com", "www.example.com"},
+	}
+
+	for _, aliases := range invalidAliases {
+		allErrs := validateServerAliases(aliases, host, field.NewPath("serverAliases"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateServerAliases(%v) returned no errors for invalid input", aliases)
+		}
+	}
+}
+
 func TestValidateTLS(t *testing.T) {
 	validTLSes := []*v1alpha1.TLS{
 		nil,
+		{
+			Secret: "",
+		},
+		{
+			Secret: "my-secret",
+		},
+		{
+			Secret: "my-secret",
+			CertManager: &v1alpha1.CertManager{
+				Issuer: "my-issuer",
+			},
+		},
 		{
 			Secret: "my-secret",
+			CertManager: &v1alpha1.CertManager{
+				ClusterIssuer: "letsencrypt",
+				Duration:      "2160h",
+				RenewBefore:   "360h",
+			},
 		},
 	}
 
@@ -101,14 +153,48 @@ func TestValidateTLS(t *testing.T) {
 	}
 
 	invalidTLSes := []*v1alpha1.TLS{
+		{
+			Secret: "-",
+		},
+		{
+			Secret: "a/b",
+		},
 		{
 			Secret: "",
+			CertManager: &v1alpha1.CertManager{
+				Issuer: "my-issuer",
+			},
 		},
 		{
-			Secret: "-",
+			Secret:      "my-secret",
+			CertManager: &v1alpha1.CertManager{},
 		},
 		{
-			Secret: "a/b",
+			Secret: "my-secret",
+			CertManager: &v1alpha1.CertManager{
+				Issuer:        "my-issuer",
+				ClusterIssuer: "letsencrypt",
+			},
+		},
+		{
+			Secret: "my-secret",
+			CertManager: &v1alpha1.CertManager{
+				Issuer: "my/issuer",
+			},
+		},
+		{
+			Secret: "my-secret",
+			CertManager: &v1alpha1.CertManager{
+				Issuer:   "my-issuer",
+				Duration: "90d",
+			},
+		},
+		{
+			Secret: "my-secret",
+			CertManager: &v1alpha1.CertManager{
+				Issuer:      "my-issuer",
+				RenewBefore: "-1h",
+			},
 		},
 	}
 
@@ -120,6 +206,271 @@ func TestValidateTLS(t *testing.T) {
 	}
 }
 
+func TestValidateTLSPassthrough(t *testing.T) {
+	upstreamNames := sets.NewString("secure-app")
+
+	tests := []struct {
+		tlsPassthrough *v1alpha1.TLSPassthrough
+		tls            *v1alpha1.TLS
+		msg            string
+	}{
+		{
+			tlsPassthrough: nil,
+			tls:            nil,
+			msg:            "tlsPassthrough is not defined",
+		},
+		{
+			tlsPassthrough: nil,
+			tls: &v1alpha1.TLS{
+				Secret: "my-secret",
+			},
+			msg: "only tls is defined",
+		},
+		{
+			tlsPassthrough: &v1alpha1.TLSPassthrough{
+				Upstream: "secure-app",
+			},
+			tls: nil,
+			msg: "tlsPassthrough references an existing upstream",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateTLSPassthrough(test.tlsPassthrough, test.tls, field.NewPath("tlsPassthrough"), upstreamNames)
+		if len(allErrs) > 0 {
+			t.Errorf("validateTLSPassthrough() returned errors %v for valid input for the case of %s", allErrs, test.msg)
+		}
+	}
+
+	invalidTests := []struct {
+		tlsPassthrough *v1alpha1.TLSPassthrough
+		tls            *v1alpha1.TLS
+		msg            string
+	}{
+		{
+			tlsPassthrough: &v1alpha1.TLSPassthrough{
+				Upstream: "",
+			},
+			tls: nil,
+			msg: "empty upstream",
+		},
+		{
+			tlsPassthrough: &v1alpha1.TLSPassthrough{
+				Upstream: "missing",
+			},
+			tls: nil,
+			msg: "non-existing upstream",
+		},
+		{
+			tlsPassthrough: &v1alpha1.TLSPassthrough{
+				Upstream: "secure-app",
+			},
+			tls: &v1alpha1.TLS{
+				Secret: "my-secret",
+			},
+			msg: "tlsPassthrough together with tls",
+		},
+	}
+
+	for _, test := range invalidTests {
+		allErrs := validateTLSPassthrough(test.tlsPassthrough, test.tls, field.NewPath("tlsPassthrough"), upstreamNames)
+		if len(allErrs) == 0 {
+			t.Errorf("validateTLSPassthrough() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateGzip(t *testing.T) {
+	tests := []struct {
+		gzip *v1alpha1.Gzip
+		msg  string
+	}{
+		{
+			gzip: nil,
+			msg:  "gzip is not defined",
+		},
+		{
+			gzip: &v1alpha1.Gzip{
+				Enable: false,
+			},
+			msg: "gzip is disabled",
+		},
+		{
+			gzip: &v1alpha1.Gzip{
+				Enable:    true,
+				Types:     []string{"text/css", "application/json", "image/svg+xml"},
+				MinLength: createPointerFromInt(1000),
+				Level:     createPointerFromInt(5),
+				Proxied:   []string{"expired", "no-cache"},
+			},
+			msg: "all fields are defined",
+		},
+		{
+			gzip: &v1alpha1.Gzip{
+				Enable: true,
+				Types:  []string{"*"},
+			},
+			msg: "all types",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateGzip(test.gzip, field.NewPath("gzip"))
+		if len(allErrs) > 0 {
+			t.Errorf("validateGzip() returned errors %v for valid input for the case of %s", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateGzipFails(t *testing.T) {
+	tests := []struct {
+		gzip *v1alpha1.Gzip
+		msg  string
+	}{
+		{
+			gzip: &v1alpha1.Gzip{
+				Enable: true,
+				Types:  []string{"text/css", "text"},
+			},
+			msg: "invalid type",
+		},
+		{
+			gzip: &v1alpha1.Gzip{
+				Enable: true,
+				Types:  []string{"unknown/css"},
+			},
+			msg: "unknown type",
+		},
+		{
+			gzip: &v1alpha1.Gzip{
+				Enable:    true,
+				MinLength: createPointerFromInt(-1),
+			},
+			msg: "negative minLength",
+		},
+		{
+			gzip: &v1alpha1.Gzip{
+				Enable: true,
+				Level:  createPointerFromInt(10),
+			},
+			msg: "level out of range",
+		},
+		{
+			gzip: &v1alpha1.Gzip{
+				Enable:  true,
+				Proxied: []string{"always"},
+			},
+			msg: "invalid proxied value",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateGzip(test.gzip, field.NewPath("gzip"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateGzip() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateAccessLog(t *testing.T) {
+	tests := []struct {
+		accessLog *v1alpha1.AccessLog
+		msg       string
+	}{
+		{
+			accessLog: nil,
+			msg:       "access log is not defined",
+		},
+		{
+			accessLog: &v1alpha1.AccessLog{
+				Off: true,
+			},
+			msg: "off",
+		},
+		{
+			accessLog: &v1alpha1.AccessLog{
+				File: "cafe.log",
+			},
+			msg: "file",
+		},
+		{
+			accessLog: &v1alpha1.AccessLog{
+				Stdout: true,
+			},
+			msg: "stdout",
+		},
+		{
+			accessLog: &v1alpha1.AccessLog{
+				Syslog: "/dev/log",
+			},
+			msg: "syslog",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateAccessLog(test.accessLog, field.NewPath("accessLog"))
+		if len(allErrs) > 0 {
+			t.Errorf("validateAccessLog() returned errors %v for valid input for the case of %s", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateAccessLogFails(t *testing.T) {
+	tests := []struct {
+		accessLog *v1alpha1.AccessLog
+		msg       string
+	}{
+		{
+			accessLog: &v1alpha1.AccessLog{},
+			msg:       "no destination",
+		},
+		{
+			accessLog: &v1alpha1.AccessLog{
+				Off:    true,
+				Stdout: true,
+			},
+			msg: "more than one destination",
+		},
+		{
+			accessLog: &v1alpha1.AccessLog{
+				File: "/var/log/nginx/cafe.log",
+			},
+			msg: "file path",
+		},
+		{
+			accessLog: &v1alpha1.AccessLog{
+				File: "../../etc/nginx/conf.d/cafe.conf",
+			},
+			msg: "file path outside of the log directory",
+		},
+		{
+			accessLog: &v1alpha1.AccessLog{
+				File: "cafe..log",
+			},
+			msg: "file name with '..'",
+		},
+		{
+			accessLog: &v1alpha1.AccessLog{
+				File: "cafe.log;",
+			},
+			msg: "file name with a semicolon",
+		},
+		{
+			accessLog: &v1alpha1.AccessLog{
+				Syslog: "/dev/log\"",
+			},
+			msg: "syslog socket path with a quote",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateAccessLog(test.accessLog, field.NewPath("accessLog"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateAccessLog() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
 func TestValidateUpstreams(t *testing.T) {
 	tests := []struct {
 		upstreams             []v1alpha1.Upstream
@@ -382,6 +733,48 @@ func TestValidateUpstreamsFails(t *testing.T) {
 			},
 			msg: "invalid value for subselector",
 		},
+		{
+			upstreams: []v1alpha1.Upstream{
+				{
+					Name:                 "upstream1",
+					Service:              "test-1",
+					Port:                 80,
+					ClientBodyBufferSize: "16 kilobytes",
+				},
+			},
+			expectedUpstreamNames: map[string]sets.Empty{
+				"upstream1": {},
+			},
+			msg: "invalid client body buffer size",
+		},
+		{
+			upstreams: []v1alpha1.Upstream{
+				{
+					Name:              "upstream1",
+					Service:           "test-1",
+					Port:              80,
+					ClientBodyTimeout: "1 minute",
+				},
+			},
+			expectedUpstreamNames: map[string]sets.Empty{
+				"upstream1": {},
+			},
+			msg: "invalid client body timeout",
+		},
+		{
+			upstreams: []v1alpha1.Upstream{
+				{
+					Name:             "upstream1",
+					Service:          "test-1",
+					Port:             80,
+					ProxyHTTPVersion: "2.0",
+				},
+			},
+			expectedUpstreamNames: map[string]sets.Empty{
+				"upstream1": {},
+			},
+			msg: "invalid http version",
+		},
 	}
 
 	isPlus := false
@@ -434,6 +827,26 @@ func TestValidateNextUpstreamFails(t *testing.T) {
 	}
 }
 
+func TestValidateProxyHTTPVersion(t *testing.T) {
+	validInput := []string{"", "1.0", "1.1"}
+
+	for _, input := range validInput {
+		allErrs := validateProxyHTTPVersion(input, field.NewPath("http-version"))
+		if len(allErrs) > 0 {
+			t.Errorf("validateProxyHTTPVersion(%q) returned errors %v for valid input", input, allErrs)
+		}
+	}
+
+	invalidInput := []string{"1", "2.0", "HTTP/1.1"}
+
+	for _, input := range invalidInput {
+		allErrs := validateProxyHTTPVersion(input, field.NewPath("http-version"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateProxyHTTPVersion(%q) returned no errors for invalid input", input)
+		}
+	}
+}
+
 func TestValidateDNS1035Label(t *testing.T) {
 	validNames := []string{
 		"test",
@@ -759,33 +1172,635 @@ func TestValidateRouteFails(t *testing.T) {
 			isRouteFieldForbidden: true,
 			msg:                   "route field exists but is forbidden",
 		},
-	}
-
-	for _, test := range tests {
-		allErrs := validateRoute(test.route, field.NewPath("route"), test.upstreamNames, test.isRouteFieldForbidden)
-		if len(allErrs) == 0 {
-			t.Errorf("validateRoute() returned no errors for invalid input for the case of %s", test.msg)
-		}
-	}
-}
-
-func TestValidateRouteField(t *testing.T) {
-	validRouteFields := []string{
-		"coffee",
-		"default/coffee",
-	}
-
-	for _, rf := range validRouteFields {
-		allErrs := validateRouteField(rf, field.NewPath("route"))
-		if len(allErrs) > 0 {
-			t.Errorf("validRouteField(%q) returned errors %v for valid input", rf, allErrs)
-		}
-	}
-
-	invalidRouteFields := []string{
-		"-",
-		"/coffee",
-		"-/coffee",
+		{
+			route: v1alpha1.Route{
+				Path:  "/",
+				Route: "default/test",
+				ExternalAuth: &v1alpha1.ExternalAuth{
+					URL: "http://auth.example.com/auth",
+				},
+			},
+			upstreamNames:         map[string]sets.Empty{},
+			isRouteFieldForbidden: false,
+			msg:                   "external auth in a route that references a VirtualServerRoute",
+		},
+		{
+			route: v1alpha1.Route{
+				Path:  "/",
+				Route: "default/test",
+				BasicAuth: &v1alpha1.BasicAuth{
+					Secret: "htpasswd",
+				},
+			},
+			upstreamNames:         map[string]sets.Empty{},
+			isRouteFieldForbidden: false,
+			msg:                   "basic auth in a route that references a VirtualServerRoute",
+		},
+		{
+			route: v1alpha1.Route{
+				Path:  "/",
+				Route: "default/test",
+				CORS: &v1alpha1.CORS{
+					AllowOrigin: []string{"*"},
+				},
+			},
+			upstreamNames:         map[string]sets.Empty{},
+			isRouteFieldForbidden: false,
+			msg:                   "cors in a route that references a VirtualServerRoute",
+		},
+		{
+			route: v1alpha1.Route{
+				Path:  "/",
+				Route: "default/test",
+				Cache: &v1alpha1.Cache{
+					Zone: "cafe",
+				},
+			},
+			upstreamNames:         map[string]sets.Empty{},
+			isRouteFieldForbidden: false,
+			msg:                   "cache in a route that references a VirtualServerRoute",
+		},
+		{
+			route: v1alpha1.Route{
+				Path:  "/",
+				Route: "default/test",
+				Mirror: &v1alpha1.Mirror{
+					Upstream: "test",
+				},
+			},
+			upstreamNames: map[string]sets.Empty{
+				"test": {},
+			},
+			isRouteFieldForbidden: false,
+			msg:                   "mirror in a route that references a VirtualServerRoute",
+		},
+		{
+			route: v1alpha1.Route{
+				Path:  "/",
+				Route: "default/test",
+				Proxy: &v1alpha1.RouteProxy{
+					ProxyReadTimeout: "300s",
+				},
+			},
+			upstreamNames:         map[string]sets.Empty{},
+			isRouteFieldForbidden: false,
+			msg:                   "proxy in a route that references a VirtualServerRoute",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateRoute(test.route, field.NewPath("route"), test.upstreamNames, test.isRouteFieldForbidden)
+		if len(allErrs) == 0 {
+			t.Errorf("validateRoute() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateExternalAuth(t *testing.T) {
+	upstreamNames := map[string]sets.Empty{
+		"auth": {},
+	}
+
+	tests := []struct {
+		auth *v1alpha1.ExternalAuth
+		msg  string
+	}{
+		{
+			auth: &v1alpha1.ExternalAuth{
+				URL: "http://oauth2-proxy.default.svc.cluster.local:4180/oauth2/auth",
+			},
+			msg: "url",
+		},
+		{
+			auth: &v1alpha1.ExternalAuth{
+				Upstream:        "auth",
+				Path:            "/oauth2/auth",
+				ResponseHeaders: []string{"X-Auth-Request-User", "X-Auth-Request-Email"},
+				Signin:          "https://$host/oauth2/start?rd=$escaped_request_uri",
+			},
+			msg: "upstream with path, response headers and signin",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateExternalAuth(test.auth, field.NewPath("externalAuth"), upstreamNames)
+		if len(allErrs) > 0 {
+			t.Errorf("validateExternalAuth() returned errors %v for valid input for the case of %s", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateExternalAuthFails(t *testing.T) {
+	upstreamNames := map[string]sets.Empty{
+		"auth": {},
+	}
+
+	tests := []struct {
+		auth *v1alpha1.ExternalAuth
+		msg  string
+	}{
+		{
+			auth: &v1alpha1.ExternalAuth{},
+			msg:  "no url and upstream",
+		},
+		{
+			auth: &v1alpha1.ExternalAuth{
+				URL:      "http://auth.example.com/auth",
+				Upstream: "auth",
+			},
+			msg: "both url and upstream",
+		},
+		{
+			auth: &v1alpha1.ExternalAuth{
+				URL: "auth.example.com/auth",
+			},
+			msg: "url without scheme",
+		},
+		{
+			auth: &v1alpha1.ExternalAuth{
+				URL:  "http://auth.example.com",
+				Path: "/auth",
+			},
+			msg: "path with url",
+		},
+		{
+			auth: &v1alpha1.ExternalAuth{
+				Upstream: "not-existing",
+			},
+			msg: "non-existing upstream",
+		},
+		{
+			auth: &v1alpha1.ExternalAuth{
+				Upstream: "auth",
+				Path:     "auth;",
+			},
+			msg: "invalid path",
+		},
+		{
+			auth: &v1alpha1.ExternalAuth{
+				Upstream:        "auth",
+				ResponseHeaders: []string{"X User"},
+			},
+			msg: "invalid response header",
+		},
+		{
+			auth: &v1alpha1.ExternalAuth{
+				Upstream: "auth",
+				Signin:   "https://example.com/\"signin\"",
+			},
+			msg: "invalid signin",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateExternalAuth(test.auth, field.NewPath("externalAuth"), upstreamNames)
+		if len(allErrs) == 0 {
+			t.Errorf("validateExternalAuth() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateBasicAuth(t *testing.T) {
+	tests := []struct {
+		auth *v1alpha1.BasicAuth
+		msg  string
+	}{
+		{
+			auth: &v1alpha1.BasicAuth{
+				Secret: "cafe-htpasswd",
+			},
+			msg: "secret",
+		},
+		{
+			auth: &v1alpha1.BasicAuth{
+				Secret: "cafe-htpasswd",
+				Realm:  "Cafe App",
+			},
+			msg: "secret and realm",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateBasicAuth(test.auth, field.NewPath("basicAuth"))
+		if len(allErrs) > 0 {
+			t.Errorf("validateBasicAuth() returned errors %v for valid input for the case of %s", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateBasicAuthFails(t *testing.T) {
+	tests := []struct {
+		auth *v1alpha1.BasicAuth
+		msg  string
+	}{
+		{
+			auth: &v1alpha1.BasicAuth{},
+			msg:  "no secret",
+		},
+		{
+			auth: &v1alpha1.BasicAuth{
+				Secret: "cafe_htpasswd",
+			},
+			msg: "invalid secret name",
+		},
+		{
+			auth: &v1alpha1.BasicAuth{
+				Secret: "cafe-htpasswd",
+				Realm:  "off",
+			},
+			msg: "off realm",
+		},
+		{
+			auth: &v1alpha1.BasicAuth{
+				Secret: "cafe-htpasswd",
+				Realm:  `Cafe "App"`,
+			},
+			msg: "realm with quotes",
+		},
+		{
+			auth: &v1alpha1.BasicAuth{
+				Secret: "cafe-htpasswd",
+				Realm:  `Cafe\`,
+			},
+			msg: "realm with a backslash",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateBasicAuth(test.auth, field.NewPath("basicAuth"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateBasicAuth() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateCORS(t *testing.T) {
+	maxAge := 0
+
+	tests := []struct {
+		cors *v1alpha1.CORS
+		msg  string
+	}{
+		{
+			cors: &v1alpha1.CORS{
+				AllowOrigin: []string{"*"},
+			},
+			msg: "any origin",
+		},
+		{
+			cors: &v1alpha1.CORS{
+				AllowOrigin:      []string{"https://example.com", "https://*.example.com", `~^https://(www|app)\.example\.com$`},
+				AllowMethods:     []string{"GET", "POST"},
+				AllowHeaders:     []string{"Content-Type", "X-Requested-With"},
+				AllowCredentials: true,
+				MaxAge:           &maxAge,
+				ExposeHeaders:    []string{"X-Request-ID"},
+			},
+			msg: "origins with all fields",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateCORS(test.cors, field.NewPath("cors"))
+		if len(allErrs) > 0 {
+			t.Errorf("validateCORS() returned errors %v for valid input for the case of %s", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateCORSFails(t *testing.T) {
+	maxAge := -1
+
+	tests := []struct {
+		cors *v1alpha1.CORS
+		msg  string
+	}{
+		{
+			cors: &v1alpha1.CORS{},
+			msg:  "no origins",
+		},
+		{
+			cors: &v1alpha1.CORS{
+				AllowOrigin:      []string{"*"},
+				AllowCredentials: true,
+			},
+			msg: "any origin with credentials",
+		},
+		{
+			cors: &v1alpha1.CORS{
+				AllowOrigin: []string{"*", "https://example.com"},
+			},
+			msg: "any origin combined with another origin",
+		},
+		{
+			cors: &v1alpha1.CORS{
+				AllowOrigin: []string{"example.com"},
+			},
+			msg: "origin without a scheme",
+		},
+		{
+			cors: &v1alpha1.CORS{
+				AllowOrigin:  []string{"*"},
+				AllowMethods: []string{"get"},
+			},
+			msg: "invalid method",
+		},
+		{
+			cors: &v1alpha1.CORS{
+				AllowOrigin:  []string{"*"},
+				AllowHeaders: []string{"Content Type"},
+			},
+			msg: "invalid allowed header",
+		},
+		{
+			cors: &v1alpha1.CORS{
+				AllowOrigin:   []string{"*"},
+				ExposeHeaders: []string{"X-Request-ID;"},
+			},
+			msg: "invalid exposed header",
+		},
+		{
+			cors: &v1alpha1.CORS{
+				AllowOrigin: []string{"*"},
+				MaxAge:      &maxAge,
+			},
+			msg: "negative max age",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateCORS(test.cors, field.NewPath("cors"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateCORS() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateCache(t *testing.T) {
+	tests := []struct {
+		cache *v1alpha1.Cache
+		msg   string
+	}{
+		{
+			cache: &v1alpha1.Cache{
+				Zone: "cafe",
+			},
+			msg: "zone",
+		},
+		{
+			cache: &v1alpha1.Cache{
+				Zone:         "cafe",
+				Valid:        []string{"200 302 10m", "any 1m"},
+				Methods:      []string{"GET", "HEAD", "POST"},
+				Key:          "$scheme$host$request_uri",
+				Bypass:       []string{"$http_pragma"},
+				UseStale:     []string{"error", "timeout", "updating"},
+				StatusHeader: true,
+			},
+			msg: "all fields",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateCache(test.cache, field.NewPath("cache"))
+		if len(allErrs) > 0 {
+			t.Errorf("validateCache() returned errors %v for valid input for the case of %s", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateCacheFails(t *testing.T) {
+	tests := []struct {
+		cache *v1alpha1.Cache
+		msg   string
+	}{
+		{
+			cache: &v1alpha1.Cache{},
+			msg:   "no zone",
+		},
+		{
+			cache: &v1alpha1.Cache{
+				Zone: "cafe zone",
+			},
+			msg: "invalid zone",
+		},
+		{
+			cache: &v1alpha1.Cache{
+				Zone:  "cafe",
+				Valid: []string{"600 10m"},
+			},
+			msg: "invalid status code",
+		},
+		{
+			cache: &v1alpha1.Cache{
+				Zone:    "cafe",
+				Methods: []string{"PUT"},
+			},
+			msg: "invalid method",
+		},
+		{
+			cache: &v1alpha1.Cache{
+				Zone: "cafe",
+				Key:  `"$host"`,
+			},
+			msg: "key with quotes",
+		},
+		{
+			cache: &v1alpha1.Cache{
+				Zone:   "cafe",
+				Bypass: []string{"http_pragma"},
+			},
+			msg: "bypass without a variable",
+		},
+		{
+			cache: &v1alpha1.Cache{
+				Zone:     "cafe",
+				UseStale: []string{"off", "error"},
+			},
+			msg: "off combined with other values",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateCache(test.cache, field.NewPath("cache"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateCache() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateMirror(t *testing.T) {
+	percentage := 100
+	requestBody := false
+	upstreamNames := map[string]sets.Empty{
+		"shadow": {},
+	}
+
+	tests := []struct {
+		mirror *v1alpha1.Mirror
+		msg    string
+	}{
+		{
+			mirror: &v1alpha1.Mirror{
+				Upstream: "shadow",
+			},
+			msg: "upstream",
+		},
+		{
+			mirror: &v1alpha1.Mirror{
+				Upstream:    "shadow",
+				Percentage:  &percentage,
+				RequestBody: &requestBody,
+			},
+			msg: "all fields",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateMirror(test.mirror, field.NewPath("mirror"), upstreamNames)
+		if len(allErrs) > 0 {
+			t.Errorf("validateMirror() returned errors %v for valid input for the case of %s", allErrs, test.msg)
+		}
+	}
+}
+
+func TestValidateMirrorFails(t *testing.T) {
+	zero := 0
+	tooBig := 101
+	upstreamNames := map[string]sets.Empty{
+		"shadow": {},
+	}
+
+	tests := []struct {
+		mirror *v1alpha1.Mirror
+		msg    string
+	}{
+		{
+			mirror: &v1alpha1.Mirror{},
+			msg:    "no upstream",
+		},
+		{
+			mirror: &v1alpha1.Mirror{
+				Upstream: "test",
+			},
+			msg: "non-existing upstream",
+		},
+		{
+			mirror: &v1alpha1.Mirror{
+				Upstream:   "shadow",
+				Percentage: &zero,
+			},
+			msg: "zero percentage",
+		},
+		{
+			mirror: &v1alpha1.Mirror{
+				Upstream:   "shadow",
+				Percentage: &tooBig,
+			},
+			msg: "percentage greater than 100",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateMirror(test.mirror, field.NewPath("mirror"), upstreamNames)
+		if len(allErrs) == 0 {
+			t.Errorf("validateMirror() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateRouteProxy(t *testing.T) {
+	tries := 3
+	buffering := false
+	requestBuffering := false
+
+	proxy := &v1alpha1.RouteProxy{
+		ProxyConnectTimeout:      "10s",
+		ProxyReadTimeout:         "300s",
+		ProxySendTimeout:         "300s",
+		ProxyNextUpstream:        "error timeout",
+		ProxyNextUpstreamTimeout: "5s",
+		ProxyNextUpstreamTries:   &tries,
+		ProxyBuffering:           &buffering,
+		ProxyBuffers: &v1alpha1.UpstreamBuffers{
+			Number: 16,
+			Size:   "8k",
+		},
+		ProxyBufferSize:       "8k",
+		ProxyRequestBuffering: &requestBuffering,
+		ClientMaxBodySize:     "100m",
+	}
+
+	allErrs := validateRouteProxy(proxy, field.NewPath("proxy"))
+	if len(allErrs) > 0 {
+		t.Errorf("validateRouteProxy() returned errors %v for valid input", allErrs)
+	}
+}
+
+func TestValidateRouteProxyFails(t *testing.T) {
+	negativeTries := -1
+
+	tests := []struct {
+		proxy *v1alpha1.RouteProxy
+		msg   string
+	}{
+		{
+			proxy: &v1alpha1.RouteProxy{
+				ProxyReadTimeout: "5 minutes",
+			},
+			msg: "invalid read timeout",
+		},
+		{
+			proxy: &v1alpha1.RouteProxy{
+				ProxyNextUpstream: "error http_999",
+			},
+			msg: "invalid next upstream",
+		},
+		{
+			proxy: &v1alpha1.RouteProxy{
+				ProxyNextUpstreamTries: &negativeTries,
+			},
+			msg: "negative next upstream tries",
+		},
+		{
+			proxy: &v1alpha1.RouteProxy{
+				ProxyBufferSize: "8 kilobytes",
+			},
+			msg: "invalid buffer size",
+		},
+		{
+			proxy: &v1alpha1.RouteProxy{
+				ClientMaxBodySize: "100mb",
+			},
+			msg: "invalid client max body size",
+		},
+	}
+
+	for _, test := range tests {
+		allErrs := validateRouteProxy(test.proxy, field.NewPath("proxy"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateRouteProxy() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
+func TestValidateRouteField(t *testing.T) {
+	validRouteFields := []string{
+		"coffee",
+		"default/coffee",
+	}
+
+	for _, rf := range validRouteFields {
+		allErrs := validateRouteField(rf, field.NewPath("route"))
+		if len(allErrs) > 0 {
+			t.Errorf("validRouteField(%q) returned errors %v for valid input", rf, allErrs)
+		}
+	}
+
+	invalidRouteFields := []string{
+		"-",
+		"/coffee",
+		"-/coffee",
 	}
 
 	for _, rf := range invalidRouteFields {
@@ -1017,6 +2032,53 @@ func TestValidateRules(t *testing.T) {
 	}
 }
 
+func TestValidateRulesWithSplits(t *testing.T) {
+	rules := v1alpha1.Rules{
+		Conditions: []v1alpha1.Condition{
+			{
+				Header: "x-version",
+			},
+		},
+		Matches: []v1alpha1.Match{
+			{
+				Values: []string{
+					"beta",
+				},
+				Splits: []v1alpha1.Split{
+					{
+						Weight:   90,
+						Upstream: "test-1",
+					},
+					{
+						Weight:   10,
+						Upstream: "test-2",
+					},
+				},
+			},
+		},
+		DefaultSplits: []v1alpha1.Split{
+			{
+				Weight:   50,
+				Upstream: "test-1",
+			},
+			{
+				Weight:   50,
+				Upstream: "test-2",
+			},
+		},
+	}
+
+	upstreamNames := map[string]sets.Empty{
+		"test-1": {},
+		"test-2": {},
+	}
+
+	allErrs := validateRules(&rules, field.NewPath("rules"), upstreamNames)
+	if len(allErrs) > 0 {
+		t.Errorf("validateRules() returned errors %v for valid input", allErrs)
+	}
+}
+
 func TestValidateRulesFails(t *testing.T) {
 	tests := []struct {
 		rules         v1alpha1.Rules
@@ -1072,19 +2134,75 @@ func TestValidateRulesFails(t *testing.T) {
 						Upstream: "test-1",
 					},
 				},
-				DefaultUpstream: "",
+				DefaultUpstream: "",
+			},
+			upstreamNames: map[string]sets.Empty{
+				"test-1": {},
+			},
+			msg: "no default upstream",
+		},
+		{
+			rules: v1alpha1.Rules{
+				Conditions: []v1alpha1.Condition{
+					{
+						Header: "x-version",
+						Cookie: "user",
+					},
+				},
+				Matches: []v1alpha1.Match{
+					{
+						Values: []string{
+							"test-1",
+						},
+						Upstream: "test-1",
+					},
+				},
+				DefaultUpstream: "test",
+			},
+			upstreamNames: map[string]sets.Empty{
+				"test-1": {},
+				"test":   {},
+			},
+			msg: "invalid values in a match",
+		},
+		{
+			rules: v1alpha1.Rules{
+				Conditions: []v1alpha1.Condition{
+					{
+						Header: "x-version",
+					},
+				},
+				Matches: []v1alpha1.Match{
+					{
+						Values: []string{
+							"test-1",
+						},
+						Upstream: "test-1",
+					},
+				},
+				DefaultUpstream: "test-2",
+				DefaultSplits: []v1alpha1.Split{
+					{
+						Weight:   90,
+						Upstream: "test-1",
+					},
+					{
+						Weight:   10,
+						Upstream: "test-2",
+					},
+				},
 			},
 			upstreamNames: map[string]sets.Empty{
 				"test-1": {},
+				"test-2": {},
 			},
-			msg: "no default upstream",
+			msg: "both default upstream and default splits",
 		},
 		{
 			rules: v1alpha1.Rules{
 				Conditions: []v1alpha1.Condition{
 					{
 						Header: "x-version",
-						Cookie: "user",
 					},
 				},
 				Matches: []v1alpha1.Match{
@@ -1095,13 +2213,22 @@ func TestValidateRulesFails(t *testing.T) {
 						Upstream: "test-1",
 					},
 				},
-				DefaultUpstream: "test",
+				DefaultSplits: []v1alpha1.Split{
+					{
+						Weight:   90,
+						Upstream: "test-1",
+					},
+					{
+						Weight:   20,
+						Upstream: "test-2",
+					},
+				},
 			},
 			upstreamNames: map[string]sets.Empty{
 				"test-1": {},
-				"test":   {},
+				"test-2": {},
 			},
-			msg: "invalid values in a match",
+			msg: "invalid default splits",
 		},
 	}
 
@@ -1142,6 +2269,30 @@ func TestValidateCondition(t *testing.T) {
 			},
 			msg: "valid variable",
 		},
+		{
+			condition: v1alpha1.Condition{
+				ArgumentPresent: "debug",
+			},
+			msg: "valid argumentPresent",
+		},
+		{
+			condition: v1alpha1.Condition{
+				Method: true,
+			},
+			msg: "valid method",
+		},
+		{
+			condition: v1alpha1.Condition{
+				Path: true,
+			},
+			msg: "valid path",
+		},
+		{
+			condition: v1alpha1.Condition{
+				SourceIP: true,
+			},
+			msg: "valid sourceIP",
+		},
 	}
 
 	for _, test := range tests {
@@ -1194,6 +2345,26 @@ func TestValidateConditionFails(t *testing.T) {
 			},
 			msg: "invalid variable",
 		},
+		{
+			condition: v1alpha1.Condition{
+				ArgumentPresent: "my-arg",
+			},
+			msg: "invalid argumentPresent",
+		},
+		{
+			condition: v1alpha1.Condition{
+				Method: true,
+				Path:   true,
+			},
+			msg: "both method and path",
+		},
+		{
+			condition: v1alpha1.Condition{
+				Header:   "x-version",
+				SourceIP: true,
+			},
+			msg: "both header and sourceIP",
+		},
 	}
 
 	for _, test := range tests {
@@ -1291,12 +2462,51 @@ func TestValidateMatch(t *testing.T) {
 		},
 		Upstream: "test",
 	}
-	conditionsCount := 2
+	conditions := []v1alpha1.Condition{
+		{
+			Header: "x-version",
+		},
+		{
+			Cookie: "user",
+		},
+	}
 	upstreamNames := map[string]sets.Empty{
 		"test": {},
 	}
 
-	allErrs := validateMatch(match, field.NewPath("match"), conditionsCount, upstreamNames)
+	allErrs := validateMatch(match, field.NewPath("match"), conditions, upstreamNames)
+	if len(allErrs) > 0 {
+		t.Errorf("validateMatch() returned errors %v for valid input", allErrs)
+	}
+}
+
+func TestValidateMatchWithSplits(t *testing.T) {
+	match := v1alpha1.Match{
+		Values: []string{
+			"value1",
+		},
+		Splits: []v1alpha1.Split{
+			{
+				Weight:   90,
+				Upstream: "test-1",
+			},
+			{
+				Weight:   10,
+				Upstream: "test-2",
+			},
+		},
+	}
+	conditions := []v1alpha1.Condition{
+		{
+			Header: "x-version",
+		},
+	}
+	upstreamNames := map[string]sets.Empty{
+		"test-1": {},
+		"test-2": {},
+	}
+
+	allErrs := validateMatch(match, field.NewPath("match"), conditions, upstreamNames)
 	if len(allErrs) > 0 {
 		t.Errorf("validateMatch() returned errors %v for valid input", allErrs)
 	}
@@ -1304,17 +2514,21 @@ func TestValidateMatch(t *testing.T) {
 
 func TestValidateMatchFails(t *testing.T) {
 	tests := []struct {
-		match           v1alpha1.Match
-		conditionsCount int
-		upstreamNames   sets.String
-		msg             string
+		match         v1alpha1.Match
+		conditions    []v1alpha1.Condition
+		upstreamNames sets.String
+		msg           string
 	}{
 		{
 			match: v1alpha1.Match{
 				Values:   []string{},
 				Upstream: "test",
 			},
-			conditionsCount: 1,
+			conditions: []v1alpha1.Condition{
+				{
+					Header: "x-version",
+				},
+			},
 			upstreamNames: map[string]sets.Empty{
 				"test": {},
 			},
@@ -1327,7 +2541,11 @@ func TestValidateMatchFails(t *testing.T) {
 				},
 				Upstream: "test",
 			},
-			conditionsCount: 1,
+			conditions: []v1alpha1.Condition{
+				{
+					Header: "x-version",
+				},
+			},
 			upstreamNames: map[string]sets.Empty{
 				"test": {},
 			},
@@ -1340,20 +2558,197 @@ func TestValidateMatchFails(t *testing.T) {
 				},
 				Upstream: "-invalid",
 			},
-			conditionsCount: 1,
-			upstreamNames:   map[string]sets.Empty{},
-			msg:             "invalid upstream",
+			conditions: []v1alpha1.Condition{
+				{
+					Header: "x-version",
+				},
+			},
+			upstreamNames: map[string]sets.Empty{},
+			msg:           "invalid upstream",
+		},
+		{
+			match: v1alpha1.Match{
+				Values: []string{
+					"value",
+				},
+				Upstream: "test-1",
+				Splits: []v1alpha1.Split{
+					{
+						Weight:   90,
+						Upstream: "test-1",
+					},
+					{
+						Weight:   10,
+						Upstream: "test-2",
+					},
+				},
+			},
+			conditions: []v1alpha1.Condition{
+				{
+					Header: "x-version",
+				},
+			},
+			upstreamNames: map[string]sets.Empty{
+				"test-1": {},
+				"test-2": {},
+			},
+			msg: "both upstream and splits",
+		},
+		{
+			match: v1alpha1.Match{
+				Values: []string{
+					"value",
+				},
+				Splits: []v1alpha1.Split{
+					{
+						Weight:   100,
+						Upstream: "test-1",
+					},
+				},
+			},
+			conditions: []v1alpha1.Condition{
+				{
+					Header: "x-version",
+				},
+			},
+			upstreamNames: map[string]sets.Empty{
+				"test-1": {},
+			},
+			msg: "invalid splits",
 		},
 	}
 
 	for _, test := range tests {
-		allErrs := validateMatch(test.match, field.NewPath("match"), test.conditionsCount, test.upstreamNames)
+		allErrs := validateMatch(test.match, field.NewPath("match"), test.conditions, test.upstreamNames)
 		if len(allErrs) == 0 {
 			t.Errorf("validateMatch() returned no errors for invalid input for the case of %s", test.msg)
 		}
 	}
 }
 
+func TestIsValidMatchValueForCondition(t *testing.T) {
+	tests := []struct {
+		value     string
+		condition v1alpha1.Condition
+		msg       string
+	}{
+		{
+			value: "POST",
+			condition: v1alpha1.Condition{
+				Method: true,
+			},
+			msg: "method",
+		},
+		{
+			value: "!~^(GET|HEAD)$",
+			condition: v1alpha1.Condition{
+				Method: true,
+			},
+			msg: "negated method regex",
+		},
+		{
+			value: "~^/api/v[0-9]+/",
+			condition: v1alpha1.Condition{
+				Path: true,
+			},
+			msg: "path regex",
+		},
+		{
+			value: "10.0.0.0/8",
+			condition: v1alpha1.Condition{
+				SourceIP: true,
+			},
+			msg: "CIDR range",
+		},
+		{
+			value: "!192.168.1.1",
+			condition: v1alpha1.Condition{
+				SourceIP: true,
+			},
+			msg: "negated IP address",
+		},
+		{
+			value: "2001:db8::/32",
+			condition: v1alpha1.Condition{
+				SourceIP: true,
+			},
+			msg: "IPv6 CIDR range",
+		},
+		{
+			value: "true",
+			condition: v1alpha1.Condition{
+				ArgumentPresent: "debug",
+			},
+			msg: "argument present",
+		},
+		{
+			value: "!false",
+			condition: v1alpha1.Condition{
+				ArgumentPresent: "debug",
+			},
+			msg: "negated argument not present",
+		},
+		{
+			value: "anything",
+			condition: v1alpha1.Condition{
+				Header: "x-version",
+			},
+			msg: "header",
+		},
+	}
+
+	for _, test := range tests {
+		errs := isValidMatchValueForCondition(test.value, test.condition)
+		if len(errs) > 0 {
+			t.Errorf("isValidMatchValueForCondition() returned errors %v for valid input for the case of %s", errs, test.msg)
+		}
+	}
+}
+
+func TestIsValidMatchValueForConditionFails(t *testing.T) {
+	tests := []struct {
+		value     string
+		condition v1alpha1.Condition
+		msg       string
+	}{
+		{
+			value: "get",
+			condition: v1alpha1.Condition{
+				Method: true,
+			},
+			msg: "lowercase method",
+		},
+		{
+			value: "10.0.0.0/33",
+			condition: v1alpha1.Condition{
+				SourceIP: true,
+			},
+			msg: "invalid CIDR range",
+		},
+		{
+			value: "~^10\\.",
+			condition: v1alpha1.Condition{
+				SourceIP: true,
+			},
+			msg: "regex for sourceIP",
+		},
+		{
+			value: "yes",
+			condition: v1alpha1.Condition{
+				ArgumentPresent: "debug",
+			},
+			msg: "invalid argument presence",
+		},
+	}
+
+	for _, test := range tests {
+		errs := isValidMatchValueForCondition(test.value, test.condition)
+		if len(errs) == 0 {
+			t.Errorf("isValidMatchValueForCondition() returned no errors for invalid input for the case of %s", test.msg)
+		}
+	}
+}
+
 func TestIsValidMatchValue(t *testing.T) {
 	validValues := []string{
 		"abc",
@@ -1457,31 +2852,99 @@ func TestValidateVirtualServerRouteForVirtualServer(t *testing.T) {
 			},
 		},
 	}
-	virtualServerHost := "example.com"
+	virtualServerHosts := []string{"example.com", "www.example.com"}
 	pathPrefix := "/test"
 
 	isPlus := false
-	err := ValidateVirtualServerRouteForVirtualServer(&virtualServerRoute, virtualServerHost, pathPrefix, isPlus)
+	err := ValidateVirtualServerRouteForVirtualServer(&virtualServerRoute, virtualServerHosts, pathPrefix, isPlus)
 	if err != nil {
 		t.Errorf("ValidateVirtualServerRouteForVirtualServer() returned error %v for valid input %v", err, virtualServerRoute)
 	}
 }
 
 func TestValidateVirtualServerRouteHost(t *testing.T) {
-	virtualServerHost := "example.com"
+	virtualServerHosts := []string{"example.com", "*.apps.example.com"}
 
-	validHost := "example.com"
+	validHosts := []string{
+		"example.com",
+		"*.apps.example.com",
+		"foo.apps.example.com",
+	}
 
-	allErrs := validateVirtualServerRouteHost(validHost, virtualServerHost, field.NewPath("host"))
-	if len(allErrs) > 0 {
-		t.Errorf("validateVirtualServerRouteHost() returned errors %v for valid input", allErrs)
+	for _, h := range validHosts {
+		allErrs := validateVirtualServerRouteHost(h, virtualServerHosts, field.NewPath("host"))
+		if len(allErrs) > 0 {
+			t.Errorf("validateVirtualServerRouteHost(%q) returned errors %v for valid input", h, allErrs)
+		}
 	}
 
-	invalidHost := "foo.example.com"
+	invalidHosts := []string{
+		"foo.example.com",
+		"apps.example.com",
+		"bar.foo.example.com",
+	}
 
-	allErrs = validateVirtualServerRouteHost(invalidHost, virtualServerHost, field.NewPath("host"))
-	if len(allErrs) == 0 {
-		t.Errorf("validateVirtualServerRouteHost() returned no errors for invalid input")
+	for _, h := range invalidHosts {
+		allErrs := validateVirtualServerRouteHost(h, virtualServerHosts, field.NewPath("host"))
+		if len(allErrs) == 0 {
+			t.Errorf("validateVirtualServerRouteHost(%q) returned no errors for invalid input", h)
+		}
+	}
+}
+
+func TestHostMatches(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		host     string
+		expected bool
+	}{
+		{
+			pattern:  "example.com",
+			host:     "example.com",
+			expected: true,
+		},
+		{
+			pattern:  "example.com",
+			host:     "foo.example.com",
+			expected: false,
+		},
+		{
+			pattern:  "*.example.com",
+			host:     "*.example.com",
+			expected: true,
+		},
+		{
+			pattern:  "*.example.com",
+			host:     "foo.example.com",
+			expected: true,
+		},
+		{
+			pattern:  "*.example.com",
+			host:     "bar.foo.example.com",
+			expected: true,
+		},
+		{
+			pattern:  "*.example.com",
+			host:     "example.com",
+			expected: false,
+		},
+		{
+			pattern:  "*.example.com",
+			host:     "fooexample.com",
+			expected: false,
+		},
+		{
+			pattern:  "foo.example.com",
+			host:     "*.example.com",
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		result := HostMatches(test.pattern, test.host)
+		if result != test.expected {
+			t.Errorf("HostMatches(%q, %q) returned %v but expected %v", test.pattern, test.host, result, test.expected)
+		}
 	}
 }
 